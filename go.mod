@@ -255,8 +255,10 @@ require (
 	github.com/Azure/azure-sdk-for-go/sdk/azidentity v0.13.2
 	github.com/Azure/azure-sdk-for-go/sdk/keyvault/azkeys v0.4.0
 	github.com/Azure/go-autorest/autorest/adal v0.9.17
+	github.com/eclipse/paho.mqtt.golang v1.3.5
 	github.com/golang-migrate/migrate/v4 v4.7.0
 	github.com/grafana/dskit v0.0.0-20211011144203-3a88ec0b675f
+	github.com/rabbitmq/amqp091-go v1.3.0
 	gocloud.dev v0.24.0
 )
 
@@ -354,6 +354,7 @@ func (hs *HTTPServer) registerRoutes() {
 		apiRoute.Group("/dashboards", func(dashboardRoute routing.RouteRegister) {
 			dashboardRoute.Get("/uid/:uid", authorize(reqSignedIn, ac.EvalPermission(ac.ActionDashboardsRead)), routing.Wrap(hs.GetDashboard))
 			dashboardRoute.Delete("/uid/:uid", authorize(reqSignedIn, ac.EvalPermission(ac.ActionDashboardsDelete)), routing.Wrap(hs.DeleteDashboardByUID))
+			dashboardRoute.Post("/uid/:uid/snapshot", authorize(reqSignedIn, ac.EvalPermission(ac.ActionDashboardsRead)), routing.Wrap(hs.CreateDashboardSnapshotFromQueries))
 
 			if hs.ThumbService != nil {
 				dashboardRoute.Get("/uid/:uid/img/:kind/:theme", hs.ThumbService.GetImage)
@@ -391,11 +392,12 @@ func (hs *HTTPServer) registerRoutes() {
 		// Playlist
 		apiRoute.Group("/playlists", func(playlistRoute routing.RouteRegister) {
 			playlistRoute.Get("/", routing.Wrap(hs.SearchPlaylists))
-			playlistRoute.Get("/:id", hs.ValidateOrgPlaylist, routing.Wrap(hs.GetPlaylist))
-			playlistRoute.Get("/:id/items", hs.ValidateOrgPlaylist, routing.Wrap(hs.GetPlaylistItems))
-			playlistRoute.Get("/:id/dashboards", hs.ValidateOrgPlaylist, routing.Wrap(hs.GetPlaylistDashboards))
-			playlistRoute.Delete("/:id", reqEditorRole, hs.ValidateOrgPlaylist, routing.Wrap(hs.DeletePlaylist))
-			playlistRoute.Put("/:id", reqEditorRole, hs.ValidateOrgPlaylist, routing.Wrap(hs.UpdatePlaylist))
+			playlistRoute.Get("/:uid", hs.ValidateOrgPlaylist, routing.Wrap(hs.GetPlaylist))
+			playlistRoute.Get("/:uid/items", hs.ValidateOrgPlaylist, routing.Wrap(hs.GetPlaylistItems))
+			playlistRoute.Get("/:uid/dashboards", hs.ValidateOrgPlaylist, routing.Wrap(hs.GetPlaylistDashboards))
+			playlistRoute.Get("/:uid/play", hs.ValidateOrgPlaylist, routing.Wrap(hs.GetPlaylistPlayOrder))
+			playlistRoute.Delete("/:uid", reqEditorRole, hs.ValidateOrgPlaylist, routing.Wrap(hs.DeletePlaylist))
+			playlistRoute.Put("/:uid", reqEditorRole, hs.ValidateOrgPlaylist, routing.Wrap(hs.UpdatePlaylist))
 			playlistRoute.Post("/", reqEditorRole, routing.Wrap(hs.CreatePlaylist))
 		})
 
@@ -493,11 +495,20 @@ func (hs *HTTPServer) registerRoutes() {
 		})
 
 		// short urls
-		apiRoute.Post("/short-urls", routing.Wrap(hs.createShortURL))
+		apiRoute.Group("/short-urls", func(shortURLRoute routing.RouteRegister) {
+			shortURLRoute.Get("/", routing.Wrap(hs.getShortURLs))
+			shortURLRoute.Post("/", routing.Wrap(hs.createShortURL))
+			shortURLRoute.Get("/:uid", routing.Wrap(hs.getShortURL))
+			shortURLRoute.Put("/:uid", routing.Wrap(hs.updateShortURL))
+			shortURLRoute.Delete("/:uid", routing.Wrap(hs.deleteShortURL))
+		})
 
 		apiRoute.Group("/comments", func(commentRoute routing.RouteRegister) {
 			commentRoute.Post("/get", routing.Wrap(hs.commentsGet))
 			commentRoute.Post("/create", routing.Wrap(hs.commentsCreate))
+			commentRoute.Post("/update", routing.Wrap(hs.commentsUpdate))
+			commentRoute.Post("/delete", routing.Wrap(hs.commentsDelete))
+			commentRoute.Post("/revisions", routing.Wrap(hs.commentsGetRevisions))
 		})
 	}, reqSignedIn)
 
@@ -559,7 +570,9 @@ func (hs *HTTPServer) registerRoutes() {
 	// Snapshots
 	r.Post("/api/snapshots/", reqSnapshotPublicModeOrSignedIn, hs.CreateDashboardSnapshot)
 	r.Get("/api/snapshot/shared-options/", reqSignedIn, GetSharingOptions)
+	r.Post("/api/snapshots/import", reqSignedIn, routing.Wrap(hs.ImportDashboardSnapshot))
 	r.Get("/api/snapshots/:key", routing.Wrap(hs.GetDashboardSnapshot))
+	r.Get("/api/snapshots/:key/export", reqSnapshotPublicModeOrSignedIn, routing.Wrap(hs.ExportDashboardSnapshot))
 	r.Get("/api/snapshots-delete/:deleteKey", reqSnapshotPublicModeOrSignedIn, routing.Wrap(hs.DeleteDashboardSnapshotByDeleteKey))
 	r.Delete("/api/snapshots/:key", reqEditorRole, routing.Wrap(hs.DeleteDashboardSnapshot))
 
@@ -18,10 +18,7 @@ func (hs *HTTPServer) commentsGet(c *models.ReqContext) response.Response {
 	}
 	items, err := hs.commentsService.Get(c.Req.Context(), c.OrgId, c.SignedInUser, cmd)
 	if err != nil {
-		if errors.Is(err, comments.ErrPermissionDenied) {
-			return response.Error(http.StatusForbidden, "permission denied", err)
-		}
-		return response.Error(http.StatusInternalServerError, "internal error", err)
+		return commentsError(err)
 	}
 	return response.JSON(200, util.DynMap{
 		"comments": items,
@@ -38,12 +35,61 @@ func (hs *HTTPServer) commentsCreate(c *models.ReqContext) response.Response {
 	}
 	comment, err := hs.commentsService.Create(c.Req.Context(), c.OrgId, c.SignedInUser, cmd)
 	if err != nil {
-		if errors.Is(err, comments.ErrPermissionDenied) {
-			return response.Error(http.StatusForbidden, "permission denied", err)
-		}
-		return response.Error(http.StatusInternalServerError, "internal error", err)
+		return commentsError(err)
 	}
 	return response.JSON(200, util.DynMap{
 		"comment": comment,
 	})
 }
+
+func (hs *HTTPServer) commentsUpdate(c *models.ReqContext) response.Response {
+	cmd := comments.UpdateCmd{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	comment, err := hs.commentsService.Update(c.Req.Context(), c.OrgId, c.SignedInUser, cmd)
+	if err != nil {
+		return commentsError(err)
+	}
+	return response.JSON(200, util.DynMap{
+		"comment": comment,
+	})
+}
+
+func (hs *HTTPServer) commentsDelete(c *models.ReqContext) response.Response {
+	cmd := comments.DeleteCmd{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	comment, err := hs.commentsService.Delete(c.Req.Context(), c.OrgId, c.SignedInUser, cmd)
+	if err != nil {
+		return commentsError(err)
+	}
+	return response.JSON(200, util.DynMap{
+		"comment": comment,
+	})
+}
+
+func (hs *HTTPServer) commentsGetRevisions(c *models.ReqContext) response.Response {
+	cmd := comments.GetRevisionsCmd{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	revisions, err := hs.commentsService.GetRevisions(c.Req.Context(), c.OrgId, c.SignedInUser, cmd)
+	if err != nil {
+		return commentsError(err)
+	}
+	return response.JSON(200, util.DynMap{
+		"revisions": revisions,
+	})
+}
+
+func commentsError(err error) response.Response {
+	switch {
+	case errors.Is(err, comments.ErrPermissionDenied):
+		return response.Error(http.StatusForbidden, "permission denied", err)
+	case errors.Is(err, comments.ErrCommentNotFound):
+		return response.Error(http.StatusNotFound, "comment not found", err)
+	}
+	return response.Error(http.StatusInternalServerError, "internal error", err)
+}
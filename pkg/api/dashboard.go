@@ -361,6 +361,14 @@ func (hs *HTTPServer) postDashboard(c *models.ReqContext, cmd models.SaveDashboa
 
 	dashboard, err := hs.dashboardService.SaveDashboard(alerting.WithUAEnabled(ctx, hs.Cfg.UnifiedAlerting.IsEnabled()), dashItem, allowUiUpdate)
 
+	// someone else saved the dashboard in between, try to merge the changes of both saves
+	var conflicts []dashdiffs.Conflict
+	merged := false
+	if errors.Is(err, models.ErrDashboardVersionMismatch) && (dash.Id != 0 || dash.Uid != "") {
+		dashboard, conflicts, err = hs.saveMergedDashboard(alerting.WithUAEnabled(ctx, hs.Cfg.UnifiedAlerting.IsEnabled()), dashItem, allowUiUpdate)
+		merged = err == nil
+	}
+
 	if hs.Live != nil {
 		// Tell everyone listening that the dashboard changed
 		if dashboard == nil {
@@ -385,6 +393,14 @@ func (hs *HTTPServer) postDashboard(c *models.ReqContext, cmd models.SaveDashboa
 		}
 	}
 
+	if len(conflicts) > 0 {
+		return response.JSON(412, util.DynMap{
+			"status":    "version-mismatch",
+			"message":   err.Error(),
+			"conflicts": conflicts,
+		})
+	}
+
 	if err != nil {
 		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, err)
 	}
@@ -396,14 +412,66 @@ func (hs *HTTPServer) postDashboard(c *models.ReqContext, cmd models.SaveDashboa
 	}
 
 	c.TimeRequest(metrics.MApiDashboardSave)
-	return response.JSON(200, util.DynMap{
+	result := util.DynMap{
 		"status":  "success",
 		"slug":    dashboard.Slug,
 		"version": dashboard.Version,
 		"id":      dashboard.Id,
 		"uid":     dashboard.Uid,
 		"url":     dashboard.GetUrl(),
-	})
+	}
+	if merged {
+		// the saved dashboard is not the one of the request, the client must load it before saving again
+		result["merged"] = true
+		result["dashboard"] = dashboard.Data
+	}
+	return response.JSON(200, result)
+}
+
+// saveMergedDashboard merges the changes of a dashboard saved from an outdated version into
+// the current version, and saves the result. The changes are not merged if they overlap the
+// changes saved since, in which case the conflicting paths are returned.
+func (hs *HTTPServer) saveMergedDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO, allowUiUpdate bool) (*models.Dashboard, []dashdiffs.Conflict, error) {
+	dash := dto.Dashboard
+	current, rsp := hs.getDashboardHelper(ctx, dto.OrgId, dash.Id, dash.Uid)
+	if rsp != nil {
+		return nil, nil, models.ErrDashboardVersionMismatch
+	}
+
+	baseVersionQuery := models.GetDashboardVersionQuery{
+		DashboardId: current.Id,
+		Version:     dash.Version,
+		OrgId:       dto.OrgId,
+	}
+	if err := hs.SQLStore.GetDashboardVersion(ctx, &baseVersionQuery); err != nil || baseVersionQuery.Result.Data == nil {
+		// the version the dashboard was saved from is unknown, there is nothing to merge with
+		return nil, nil, models.ErrDashboardVersionMismatch
+	}
+
+	merged, conflicts, err := dashdiffs.Merge(baseVersionQuery.Result.Data, dash.Data, current.Data)
+	if err != nil {
+		hs.log.Warn("failed to merge dashboard changes", "uid", current.Uid, "error", err)
+		return nil, nil, models.ErrDashboardVersionMismatch
+	}
+	if len(conflicts) > 0 {
+		return nil, conflicts, models.ErrDashboardVersionMismatch
+	}
+
+	mergedDash := models.NewDashboardFromJson(merged)
+	mergedDash.SetId(current.Id)
+	mergedDash.SetUid(current.Uid)
+	mergedDash.SetVersion(current.Version)
+	mergedDash.UpdatedBy = dash.UpdatedBy
+	mergedDash.OrgId = dash.OrgId
+	mergedDash.PluginId = dash.PluginId
+	mergedDash.IsFolder = dash.IsFolder
+	mergedDash.FolderId = dash.FolderId
+	mergedDash.UpdateSlug()
+
+	mergedDTO := *dto
+	mergedDTO.Dashboard = mergedDash
+	dashboard, err := hs.dashboardService.SaveDashboard(ctx, &mergedDTO, allowUiUpdate)
+	return dashboard, nil, err
 }
 
 // GetHomeDashboard returns the home dashboard.
@@ -647,7 +715,7 @@ func (hs *HTTPServer) CalculateDashboardDiff(c *models.ReqContext) response.Resp
 		return response.Error(500, "Unable to compute diff", err)
 	}
 
-	if options.DiffType == dashdiffs.DiffDelta {
+	if options.DiffType == dashdiffs.DiffDelta || options.DiffType == dashdiffs.DiffStructural {
 		return response.Respond(200, result.Delta).SetHeader("Content-Type", "application/json")
 	}
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -173,6 +175,14 @@ func (hs *HTTPServer) GetDashboardSnapshot(c *models.ReqContext) response.Respon
 		return response.Error(404, "Dashboard snapshot not found", err)
 	}
 
+	frames, err := hs.DashboardsnapshotsService.GetSnapshotFrames(c.Req.Context(), snapshot)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get dashboard snapshot data", err)
+	}
+	if frames != nil {
+		dashboardsnapshots.EmbedPanelFrames(snapshot.Dashboard, frames)
+	}
+
 	dto := dtos.DashboardFullWithMeta{
 		Dashboard: snapshot.Dashboard,
 		Meta: dtos.DashboardMeta{
@@ -188,6 +198,142 @@ func (hs *HTTPServer) GetDashboardSnapshot(c *models.ReqContext) response.Respon
 	return response.JSON(200, dto).SetHeader("Cache-Control", "public, max-age=3600")
 }
 
+// POST /api/dashboards/uid/:uid/snapshot
+func (hs *HTTPServer) CreateDashboardSnapshotFromQueries(c *models.ReqContext) response.Response {
+	cmd := dtos.CreateDashboardSnapshotFromQueriesCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.OrgId, 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+	guardian := guardian.New(c.Req.Context(), dash.Id, c.OrgId, c.SignedInUser)
+	if canView, err := guardian.CanView(); err != nil || !canView {
+		return dashboardGuardianResponse(err)
+	}
+
+	dashboard := dash.Data
+	dashboard.Set("id", dash.Id)
+	dashboard.Set("uid", dash.Uid)
+	frames, err := hs.DashboardsnapshotsService.QueryPanelFrames(c.Req.Context(), c.SignedInUser, dashboard, cmd.From, cmd.To)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+
+	name := cmd.Name
+	if name == "" {
+		name = dash.Title
+	}
+	return hs.createDashboardSnapshotWithFrames(c, models.CreateDashboardSnapshotCommand{
+		Dashboard: dashboard,
+		Name:      name,
+		Expires:   cmd.Expires,
+	}, frames)
+}
+
+// GET /api/snapshots/:key/export
+func (hs *HTTPServer) ExportDashboardSnapshot(c *models.ReqContext) response.Response {
+	key := web.Params(c.Req)[":key"]
+	if len(key) == 0 {
+		return response.Error(http.StatusBadRequest, "Empty snapshot key", nil)
+	}
+
+	query := &models.GetDashboardSnapshotQuery{Key: key}
+	if err := hs.DashboardsnapshotsService.GetDashboardSnapshot(c.Req.Context(), query); err != nil {
+		if errors.Is(err, models.ErrDashboardSnapshotNotFound) {
+			return response.Error(http.StatusNotFound, "Failed to find dashboard snapshot", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get dashboard snapshot", err)
+	}
+
+	snapshot := query.Result
+	if snapshot.Expires.Before(time.Now()) {
+		return response.Error(http.StatusNotFound, "Dashboard snapshot not found", nil)
+	}
+	if snapshot.External {
+		return response.Error(http.StatusBadRequest, "External snapshots cannot be exported", nil)
+	}
+
+	frames, err := hs.DashboardsnapshotsService.GetSnapshotFrames(c.Req.Context(), snapshot)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get dashboard snapshot data", err)
+	}
+
+	var buf bytes.Buffer
+	if err := dashboardsnapshots.WriteArchive(&buf, &dashboardsnapshots.Archive{
+		Name:      snapshot.Name,
+		Created:   snapshot.Created,
+		Dashboard: snapshot.Dashboard,
+		Frames:    frames,
+	}); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to export dashboard snapshot", err)
+	}
+
+	return response.Respond(http.StatusOK, buf.Bytes()).
+		SetHeader("Content-Type", "application/zip").
+		SetHeader("Content-Disposition", fmt.Sprintf(`attachment; filename="snapshot-%s.zip"`, snapshot.Key))
+}
+
+// POST /api/snapshots/import
+func (hs *HTTPServer) ImportDashboardSnapshot(c *models.ReqContext) response.Response {
+	body, err := io.ReadAll(io.LimitReader(c.Req.Body, maxSnapshotArchiveSize+1))
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to read snapshot archive", err)
+	}
+	if len(body) > maxSnapshotArchiveSize {
+		return response.Error(http.StatusRequestEntityTooLarge, "Snapshot archive is too large", nil)
+	}
+
+	archive, err := dashboardsnapshots.ReadArchive(body)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+
+	name := c.Query("name")
+	if name == "" {
+		name = archive.Name
+	}
+	return hs.createDashboardSnapshotWithFrames(c, models.CreateDashboardSnapshotCommand{
+		Dashboard: archive.Dashboard,
+		Name:      name,
+		Expires:   c.QueryInt64("expires"),
+	}, archive.Frames)
+}
+
+// maxSnapshotArchiveSize is the maximum size of the snapshot archives that can be imported.
+const maxSnapshotArchiveSize = 512 << 20
+
+func (hs *HTTPServer) createDashboardSnapshotWithFrames(c *models.ReqContext, cmd models.CreateDashboardSnapshotCommand, frames []dashboardsnapshots.PanelFrames) response.Response {
+	if cmd.Name == "" {
+		cmd.Name = "Unnamed snapshot"
+	}
+	cmd.OrgId = c.OrgId
+	cmd.UserId = c.UserId
+
+	var err error
+	if cmd.Key, err = util.GetRandomString(32); err != nil {
+		return response.Error(http.StatusInternalServerError, "Could not generate random string", err)
+	}
+	if cmd.DeleteKey, err = util.GetRandomString(32); err != nil {
+		return response.Error(http.StatusInternalServerError, "Could not generate random string", err)
+	}
+
+	if err := hs.DashboardsnapshotsService.CreateDashboardSnapshotWithFrames(c.Req.Context(), &cmd, frames); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create snapshot", err)
+	}
+	metrics.MApiDashboardSnapshotCreate.Inc()
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"key":       cmd.Key,
+		"deleteKey": cmd.DeleteKey,
+		"url":       setting.ToAbsUrl("dashboard/snapshot/" + cmd.Key),
+		"deleteUrl": setting.ToAbsUrl("api/snapshots-delete/" + cmd.DeleteKey),
+		"id":        cmd.Result.Id,
+	})
+}
+
 func deleteExternalDashboardSnapshot(externalUrl string) error {
 	response, err := client.Get(externalUrl)
 	if err != nil {
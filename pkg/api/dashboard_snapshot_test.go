@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"net/http"
@@ -9,11 +10,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/guardian"
+	secretsDatabase "github.com/grafana/grafana/pkg/services/secrets/database"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/sqlstore/mockstore"
+	"github.com/grafana/grafana/pkg/web"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -302,3 +309,63 @@ func TestGetDashboardSnapshotFailure(t *testing.T) {
 			assert.Equal(t, http.StatusInternalServerError, sc.resp.Code)
 		}, sqlmock)
 }
+
+func TestDashboardSnapshotArchive(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	hs := &HTTPServer{DashboardsnapshotsService: &dashboardsnapshots.Service{
+		SQLStore:       sqlStore,
+		SecretsService: secretsManager.SetupTestService(t, secretsDatabase.ProvideSecretsStore(sqlStore)),
+	}}
+	reqContext := func(t *testing.T, method string, body []byte, params map[string]string) *models.ReqContext {
+		t.Helper()
+		req, err := http.NewRequest(method, "/", bytes.NewReader(body))
+		require.NoError(t, err)
+		req = web.SetURLParams(req, params)
+		return &models.ReqContext{
+			Context:      &web.Context{Req: req},
+			SignedInUser: &models.SignedInUser{OrgId: 1, UserId: 1},
+		}
+	}
+
+	var archive bytes.Buffer
+	require.NoError(t, dashboardsnapshots.WriteArchive(&archive, &dashboardsnapshots.Archive{
+		Name:      "Incident",
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": "Incident", "panels": []interface{}{map[string]interface{}{"id": 1}}}),
+		Frames: []dashboardsnapshots.PanelFrames{{
+			PanelID: 1,
+			Frames:  data.Frames{data.NewFrame("cpu", data.NewField("value", nil, []float64{0.5}))},
+		}},
+	}))
+
+	resp := hs.ImportDashboardSnapshot(reqContext(t, http.MethodPost, archive.Bytes(), nil))
+	require.Equal(t, http.StatusOK, resp.Status())
+	created, err := simplejson.NewJson(resp.Body())
+	require.NoError(t, err)
+	key := created.Get("key").MustString()
+	require.NotEmpty(t, key)
+
+	t.Run("the frames are embedded in the panels of the snapshot", func(t *testing.T) {
+		resp := hs.GetDashboardSnapshot(reqContext(t, http.MethodGet, nil, map[string]string{":key": key}))
+		require.Equal(t, http.StatusOK, resp.Status())
+		snapshot, err := simplejson.NewJson(resp.Body())
+		require.NoError(t, err)
+		frame := snapshot.GetPath("dashboard", "panels").GetIndex(0).Get("snapshotData").GetIndex(0)
+		require.Equal(t, "cpu", frame.Get("name").MustString())
+		require.Equal(t, 0.5, frame.Get("fields").GetIndex(0).Get("values").GetIndex(0).MustFloat64())
+	})
+
+	t.Run("the snapshot can be exported", func(t *testing.T) {
+		resp := hs.ExportDashboardSnapshot(reqContext(t, http.MethodGet, nil, map[string]string{":key": key}))
+		require.Equal(t, http.StatusOK, resp.Status())
+		exported, err := dashboardsnapshots.ReadArchive(resp.Body())
+		require.NoError(t, err)
+		require.Equal(t, "Incident", exported.Name)
+		require.Len(t, exported.Frames, 1)
+		require.Equal(t, 0.5, exported.Frames[0].Frames[0].Fields[0].At(0))
+	})
+
+	t.Run("invalid archives are rejected", func(t *testing.T) {
+		resp := hs.ImportDashboardSnapshot(reqContext(t, http.MethodPost, []byte("not a zip"), nil))
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -1156,3 +1157,112 @@ func (l *mockLibraryElementService) DisconnectElementsFromDashboard(c context.Co
 func (l *mockLibraryElementService) DeleteLibraryElementsInFolder(c context.Context, signedInUser *models.SignedInUser, folderUID string) error {
 	return nil
 }
+
+// versionMismatchDashboardService fails the saves of dashboards that are not at the current version.
+type versionMismatchDashboardService struct {
+	dashboards.FakeDashboardService
+	currentVersion int
+}
+
+func (s *versionMismatchDashboardService) SaveDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO, allowUiUpdate bool) (*models.Dashboard, error) {
+	if dto.Dashboard.Version != s.currentVersion {
+		return nil, models.ErrDashboardVersionMismatch
+	}
+	return s.FakeDashboardService.SaveDashboard(ctx, dto, allowUiUpdate)
+}
+
+func TestDashboardAPIEndpoint_SaveFromOutdatedVersion(t *testing.T) {
+	base := simplejson.NewFromAny(map[string]interface{}{
+		"id": 1, "uid": "uid", "title": "Dash", "version": 1,
+		"panels": []interface{}{
+			map[string]interface{}{"id": 1, "title": "CPU"},
+			map[string]interface{}{"id": 2, "title": "Memory"},
+		},
+	})
+	current := models.NewDashboardFromJson(simplejson.NewFromAny(map[string]interface{}{
+		"id": 1, "uid": "uid", "title": "Dash", "version": 2,
+		"panels": []interface{}{
+			map[string]interface{}{"id": 1, "title": "CPU"},
+			map[string]interface{}{"id": 2, "title": "Memory usage"},
+		},
+	}))
+	sqlStore := mockstore.NewSQLStoreMock()
+	sqlStore.ExpectedDashboard = current
+	sqlStore.ExpectedDashboardVersions = []*models.DashboardVersion{{DashboardId: 1, Version: 1, Data: base}}
+
+	save := func(t *testing.T, panels []interface{}) (*httptest.ResponseRecorder, *versionMismatchDashboardService) {
+		t.Helper()
+		cfg := setting.NewCfg()
+		dashboardService := &versionMismatchDashboardService{currentVersion: 2}
+		hs := HTTPServer{
+			Cfg:                          cfg,
+			ProvisioningService:          provisioning.NewProvisioningServiceMock(context.Background()),
+			QuotaService:                 &quota.QuotaService{Cfg: cfg},
+			pluginStore:                  &fakePluginStore{},
+			LibraryPanelService:          &mockLibraryPanelService{},
+			LibraryElementService:        &mockLibraryElementService{},
+			dashboardService:             dashboardService,
+			dashboardProvisioningService: mockDashboardProvisioningService{},
+			SQLStore:                     sqlStore,
+			Features:                     featuremgmt.WithFeatures(),
+		}
+		cmd := models.SaveDashboardCommand{
+			OrgId: 1,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{
+				"id": 1, "uid": "uid", "title": "Dash", "version": 1, "panels": panels,
+			}),
+		}
+
+		sc := setupScenarioContext(t, "/api/dashboards")
+		sc.defaultHandler = routing.Wrap(func(c *models.ReqContext) response.Response {
+			c.Req.Body = mockRequestBody(cmd)
+			c.Req.Header.Add("Content-Type", "application/json")
+			c.SignedInUser = &models.SignedInUser{OrgId: 1}
+			return hs.PostDashboard(c)
+		})
+		sc.m.Post("/api/dashboards", sc.defaultHandler)
+		callPostDashboard(sc)
+		return sc.resp, dashboardService
+	}
+
+	t.Run("merges changes that do not overlap", func(t *testing.T) {
+		resp, dashboardService := save(t, []interface{}{
+			map[string]interface{}{"id": 1, "title": "CPU usage"},
+			map[string]interface{}{"id": 2, "title": "Memory"},
+		})
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Len(t, dashboardService.SavedDashboards, 1)
+		saved := dashboardService.SavedDashboards[0].Dashboard
+		require.Equal(t, 2, saved.Version)
+		require.Equal(t, "CPU usage", saved.Data.Get("panels").GetIndex(0).Get("title").MustString())
+		require.Equal(t, "Memory usage", saved.Data.Get("panels").GetIndex(1).Get("title").MustString())
+
+		body, err := simplejson.NewJson(resp.Body.Bytes())
+		require.NoError(t, err)
+		require.True(t, body.Get("merged").MustBool())
+		require.Equal(t, "Memory usage", body.GetPath("dashboard", "panels").GetIndex(1).Get("title").MustString())
+	})
+
+	t.Run("reports conflicting changes", func(t *testing.T) {
+		resp, dashboardService := save(t, []interface{}{
+			map[string]interface{}{"id": 1, "title": "CPU"},
+			map[string]interface{}{"id": 2, "title": "RAM"},
+		})
+		require.Equal(t, http.StatusPreconditionFailed, resp.Code)
+		require.Empty(t, dashboardService.SavedDashboards)
+		var body struct {
+			Status    string `json:"status"`
+			Conflicts []struct {
+				Path   string `json:"path"`
+				Ours   string `json:"ours"`
+				Theirs string `json:"theirs"`
+			} `json:"conflicts"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.Equal(t, "version-mismatch", body.Status)
+		require.Len(t, body.Conflicts, 1)
+		require.Equal(t, "/panels/id=2/title", body.Conflicts[0].Path)
+		require.Equal(t, "RAM", body.Conflicts[0].Ours)
+		require.Equal(t, "Memory usage", body.Conflicts[0].Theirs)
+	})
+}
@@ -62,6 +62,11 @@ type ConflictError GenericError
 // swagger:response preconditionFailedError
 type PreconditionFailedError GenericError
 
+// ContentTooLargeError is returned when the request body is larger than accepted.
+//
+// swagger:response contentTooLargeError
+type ContentTooLargeError GenericError
+
 // UnprocessableEntityError
 //
 // swagger:response unprocessableEntityError
@@ -1,6 +1,7 @@
 package definitions
 
 import (
+	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/models"
 )
 
@@ -16,6 +17,47 @@ import (
 // 403: forbiddenError
 // 500: internalServerError
 
+// swagger:route POST /dashboards/uid/{uid}/snapshot snapshots createSnapshotFromQueries
+//
+// Create a snapshot of a dashboard by running the queries of its panels on the server.
+//
+// The data frames of the panels are stored with the snapshot.
+//
+// Responses:
+// 200: createSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+
+// swagger:route GET /snapshots/{key}/export snapshots exportSnapshot
+//
+// Export a snapshot as a zip archive that can be imported into another Grafana instance.
+//
+// Produces:
+// - application/zip
+//
+// Responses:
+// 200: exportSnapshotResponse
+// 400: badRequestError
+// 404: notFoundError
+// 500: internalServerError
+
+// swagger:route POST /snapshots/import snapshots importSnapshot
+//
+// Import a snapshot from an archive downloaded with the export endpoint.
+//
+// Consumes:
+// - application/zip
+//
+// Responses:
+// 200: createSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 413: contentTooLargeError
+// 500: internalServerError
+
 // swagger:route GET /dashboard/snapshots snapshots getSnapshots
 //
 // List snapshots.
@@ -72,6 +114,28 @@ type CreateSnapshotParam struct {
 	Body models.CreateDashboardSnapshotCommand `json:"body"`
 }
 
+// swagger:parameters createSnapshotFromQueries
+type CreateSnapshotFromQueriesParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	Body dtos.CreateDashboardSnapshotFromQueriesCommand `json:"body"`
+}
+
+// swagger:parameters importSnapshot
+type ImportSnapshotParams struct {
+	// Snapshot name, the name of the exported snapshot by default
+	// in:query
+	Name string `json:"name"`
+	// When the snapshot should expire in seconds
+	// in:query
+	Expires int64 `json:"expires"`
+	// in:body
+	// required:true
+	Body []byte `json:"body"`
+}
+
 // swagger:parameters getSnapshots
 type GetSnapshotsParams struct {
 	// Search Query
@@ -83,7 +147,7 @@ type GetSnapshotsParams struct {
 	Limit int64 `json:"limit"`
 }
 
-// swagger:parameters getSnapshotByKey deleteSnapshotByKey
+// swagger:parameters getSnapshotByKey deleteSnapshotByKey exportSnapshot
 type SnapshotByKeyParam struct {
 	// in:path
 	Key string `json:"key"`
@@ -116,6 +180,12 @@ type GetSnapshotsResponse struct {
 	Body []*models.DashboardSnapshotDTO `json:"body"`
 }
 
+// swagger:response exportSnapshotResponse
+type ExportSnapshotResponse struct {
+	// in:body
+	Body []byte `json:"body"`
+}
+
 // swagger:response snapshotResponse
 type SnapshotResponse DashboardResponse
 
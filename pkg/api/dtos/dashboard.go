@@ -71,6 +71,19 @@ type CalculateDiffTarget struct {
 	UnsavedDashboard *simplejson.Json `json:"unsavedDashboard"`
 }
 
+// CreateDashboardSnapshotFromQueriesCommand creates a snapshot of a dashboard by running the
+// queries of its panels.
+type CreateDashboardSnapshotFromQueriesCommand struct {
+	// Snapshot name, the title of the dashboard by default.
+	Name string `json:"name"`
+	// When the snapshot should expire in seconds. Default is never to expire.
+	Expires int64 `json:"expires"`
+	// Start of the time range of the queries, the time range of the dashboard by default.
+	From string `json:"from"`
+	// End of the time range of the queries.
+	To string `json:"to"`
+}
+
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
@@ -22,3 +22,22 @@ func (slice PlaylistDashboardsSlice) Less(i, j int) bool {
 func (slice PlaylistDashboardsSlice) Swap(i, j int) {
 	slice[i], slice[j] = slice[j], slice[i]
 }
+
+// PlaylistPlayOrder is the dashboards a playlist plays for the signed in user, in order.
+type PlaylistPlayOrder struct {
+	UID        string                  `json:"uid"`
+	Name       string                  `json:"name"`
+	Interval   string                  `json:"interval"`
+	Dashboards []PlaylistPlayDashboard `json:"dashboards"`
+}
+
+// PlaylistPlayDashboard is a dashboard of the play order of a playlist, with the interval it is
+// displayed for and the playlist item it comes from.
+type PlaylistPlayDashboard struct {
+	ID       int64  `json:"id"`
+	UID      string `json:"uid"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Interval string `json:"interval"`
+	ItemID   int64  `json:"itemId"`
+}
@@ -1,10 +1,30 @@
 package dtos
 
+import "time"
+
 type ShortURL struct {
-	UID string `json:"uid"`
-	URL string `json:"url"`
+	UID          string     `json:"uid"`
+	URL          string     `json:"url"`
+	Alias        string     `json:"alias,omitempty"`
+	Path         string     `json:"path"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	Expired      bool       `json:"expired"`
+	AccessCount  int64      `json:"accessCount"`
+	LastSeenAt   *time.Time `json:"lastSeenAt,omitempty"`
+	LastReferrer string     `json:"lastReferrer,omitempty"`
 }
 
 type CreateShortURLCmd struct {
 	Path string `json:"path"`
+	// Alias is an optional human-readable name to use instead of the generated UID.
+	Alias string `json:"alias"`
+	// ExpiresAt is the optional time the short URL stops redirecting.
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// UpdateShortURLCmd replaces the alias and the expiry of a short URL. Omitting them removes them.
+type UpdateShortURLCmd struct {
+	Alias     string     `json:"alias"`
+	ExpiresAt *time.Time `json:"expiresAt"`
 }
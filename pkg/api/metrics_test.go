@@ -204,6 +204,7 @@ func TestAPIEndpoint_Metrics_QueryMetricsFromDashboard(t *testing.T) {
 		fakes.NewFakeSecretsService(),
 		&dashboardFakePluginClient{},
 		&fakeOAuthTokenService{},
+		nil,
 	)
 
 	sc.hs.Features = featuremgmt.WithFeatures(featuremgmt.FlagValidatedQueries, true)
@@ -514,6 +515,7 @@ func TestAPIEndpoint_Metrics_QueryMetricsV2(t *testing.T) {
 			},
 		},
 		&fakeOAuthTokenService{},
+		nil,
 	)
 	serverFeatureEnabled := SetupAPITestServer(t, func(hs *HTTPServer) {
 		hs.queryDataService = qds
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -10,27 +11,43 @@ import (
 	"github.com/grafana/grafana/pkg/web"
 )
 
-func (hs *HTTPServer) ValidateOrgPlaylist(c *models.ReqContext) {
-	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
-	if err != nil {
-		c.JsonApiErr(http.StatusBadRequest, "id is invalid", nil)
-		return
+// getPlaylist returns the playlist of the organization with the UID of the request. Playlists
+// can also be referenced by their numeric ID, as before they had UIDs.
+func (hs *HTTPServer) getPlaylist(c *models.ReqContext) (*models.Playlist, error) {
+	uid := web.Params(c.Req)[":uid"]
+	query := models.GetPlaylistByUidQuery{UID: uid, OrgId: c.OrgId}
+	err := hs.SQLStore.GetPlaylistByUID(c.Req.Context(), &query)
+	if err == nil {
+		return query.Result, nil
+	}
+	if !errors.Is(err, models.ErrPlaylistNotFound) {
+		return nil, err
 	}
-	query := models.GetPlaylistByIdQuery{Id: id}
-	err = hs.SQLStore.GetPlaylist(c.Req.Context(), &query)
 
-	if err != nil {
-		c.JsonApiErr(404, "Playlist not found", err)
-		return
+	id, parseErr := strconv.ParseInt(uid, 10, 64)
+	if parseErr != nil {
+		return nil, err
 	}
+	byID := models.GetPlaylistByIdQuery{Id: id}
+	if err := hs.SQLStore.GetPlaylist(c.Req.Context(), &byID); err != nil {
+		return nil, err
+	}
+	if byID.Result.Id == 0 || byID.Result.OrgId != c.OrgId {
+		return nil, models.ErrPlaylistNotFound
+	}
+	return byID.Result, nil
+}
 
-	if query.Result.OrgId == 0 {
-		c.JsonApiErr(404, "Playlist not found", err)
-		return
+func playlistError(err error) response.Response {
+	if errors.Is(err, models.ErrPlaylistNotFound) {
+		return response.Error(404, "Playlist not found", err)
 	}
+	return response.Error(500, "Failed to get playlist", err)
+}
 
-	if query.Result.OrgId != c.OrgId {
-		c.JsonApiErr(403, "You are not allowed to edit/view playlist", nil)
+func (hs *HTTPServer) ValidateOrgPlaylist(c *models.ReqContext) {
+	if _, err := hs.getPlaylist(c); err != nil {
+		playlistError(err).WriteTo(c)
 		return
 	}
 }
@@ -58,23 +75,19 @@ func (hs *HTTPServer) SearchPlaylists(c *models.ReqContext) response.Response {
 }
 
 func (hs *HTTPServer) GetPlaylist(c *models.ReqContext) response.Response {
-	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	playlist, err := hs.getPlaylist(c)
 	if err != nil {
-		return response.Error(http.StatusBadRequest, "id is invalid", err)
+		return playlistError(err)
 	}
-	cmd := models.GetPlaylistByIdQuery{Id: id}
 
-	if err := hs.SQLStore.GetPlaylist(c.Req.Context(), &cmd); err != nil {
-		return response.Error(500, "Playlist not found", err)
-	}
-
-	playlistDTOs, _ := hs.LoadPlaylistItemDTOs(c.Req.Context(), id)
+	playlistDTOs, _ := hs.LoadPlaylistItemDTOs(c.Req.Context(), playlist.Id)
 
 	dto := &models.PlaylistDTO{
-		Id:       cmd.Result.Id,
-		Name:     cmd.Result.Name,
-		Interval: cmd.Result.Interval,
-		OrgId:    cmd.Result.OrgId,
+		Id:       playlist.Id,
+		UID:      playlist.UID,
+		Name:     playlist.Name,
+		Interval: playlist.Interval,
+		OrgId:    playlist.OrgId,
 		Items:    playlistDTOs,
 	}
 
@@ -98,6 +111,7 @@ func (hs *HTTPServer) LoadPlaylistItemDTOs(ctx context.Context, id int64) ([]mod
 			Value:      item.Value,
 			Order:      item.Order,
 			Title:      item.Title,
+			Interval:   item.Interval,
 		})
 	}
 
@@ -114,12 +128,12 @@ func (hs *HTTPServer) LoadPlaylistItems(ctx context.Context, id int64) ([]models
 }
 
 func (hs *HTTPServer) GetPlaylistItems(c *models.ReqContext) response.Response {
-	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	playlist, err := hs.getPlaylist(c)
 	if err != nil {
-		return response.Error(http.StatusBadRequest, "id is invalid", err)
+		return playlistError(err)
 	}
 
-	playlistDTOs, err := hs.LoadPlaylistItemDTOs(c.Req.Context(), id)
+	playlistDTOs, err := hs.LoadPlaylistItemDTOs(c.Req.Context(), playlist.Id)
 
 	if err != nil {
 		return response.Error(500, "Could not load playlist items", err)
@@ -129,12 +143,12 @@ func (hs *HTTPServer) GetPlaylistItems(c *models.ReqContext) response.Response {
 }
 
 func (hs *HTTPServer) GetPlaylistDashboards(c *models.ReqContext) response.Response {
-	playlistID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	playlist, err := hs.getPlaylist(c)
 	if err != nil {
-		return response.Error(http.StatusBadRequest, "id is invalid", err)
+		return playlistError(err)
 	}
 
-	playlists, err := hs.LoadPlaylistDashboards(c.Req.Context(), c.OrgId, c.SignedInUser, playlistID)
+	playlists, err := hs.LoadPlaylistDashboards(c.Req.Context(), c.SignedInUser, playlist)
 	if err != nil {
 		return response.Error(500, "Could not load dashboards", err)
 	}
@@ -142,13 +156,28 @@ func (hs *HTTPServer) GetPlaylistDashboards(c *models.ReqContext) response.Respo
 	return response.JSON(200, playlists)
 }
 
+// GetPlaylistPlayOrder returns the dashboards the playlist plays for the signed in user, in order.
+func (hs *HTTPServer) GetPlaylistPlayOrder(c *models.ReqContext) response.Response {
+	playlist, err := hs.getPlaylist(c)
+	if err != nil {
+		return playlistError(err)
+	}
+
+	playOrder, err := hs.LoadPlaylistPlayOrder(c.Req.Context(), c.SignedInUser, playlist)
+	if err != nil {
+		return response.Error(500, "Could not load dashboards", err)
+	}
+
+	return response.JSON(200, playOrder)
+}
+
 func (hs *HTTPServer) DeletePlaylist(c *models.ReqContext) response.Response {
-	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	playlist, err := hs.getPlaylist(c)
 	if err != nil {
-		return response.Error(http.StatusBadRequest, "id is invalid", err)
+		return playlistError(err)
 	}
 
-	cmd := models.DeletePlaylistCommand{Id: id, OrgId: c.OrgId}
+	cmd := models.DeletePlaylistCommand{Id: playlist.Id, OrgId: c.OrgId}
 	if err := hs.SQLStore.DeletePlaylist(c.Req.Context(), &cmd); err != nil {
 		return response.Error(500, "Failed to delete playlist", err)
 	}
@@ -163,7 +192,17 @@ func (hs *HTTPServer) CreatePlaylist(c *models.ReqContext) response.Response {
 	}
 	cmd.OrgId = c.OrgId
 
+	if err := validatePlaylistItems(cmd.Items); err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+
 	if err := hs.SQLStore.CreatePlaylist(c.Req.Context(), &cmd); err != nil {
+		switch {
+		case errors.Is(err, models.ErrPlaylistInvalidUID), errors.Is(err, models.ErrPlaylistUIDTooLong), errors.Is(err, models.ErrPlaylistNumericUID):
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		case errors.Is(err, models.ErrPlaylistWithSameUIDExists):
+			return response.Error(http.StatusConflict, err.Error(), err)
+		}
 		return response.Error(500, "Failed to create playlist", err)
 	}
 
@@ -176,11 +215,16 @@ func (hs *HTTPServer) UpdatePlaylist(c *models.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	cmd.OrgId = c.OrgId
-	var err error
-	cmd.Id, err = strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+
+	if err := validatePlaylistItems(cmd.Items); err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+
+	playlist, err := hs.getPlaylist(c)
 	if err != nil {
-		return response.Error(http.StatusBadRequest, "id is invalid", err)
+		return playlistError(err)
 	}
+	cmd.Id = playlist.Id
 
 	if err := hs.SQLStore.UpdatePlaylist(c.Req.Context(), &cmd); err != nil {
 		return response.Error(500, "Failed to save playlist", err)
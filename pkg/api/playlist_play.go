@@ -2,96 +2,257 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"sort"
 	"strconv"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
 	"github.com/grafana/grafana/pkg/api/dtos"
-	_ "github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/search"
 )
 
-func (hs *HTTPServer) populateDashboardsByID(ctx context.Context, dashboardByIDs []int64, dashboardIDOrder map[int64]int) (dtos.PlaylistDashboardsSlice, error) {
-	result := make(dtos.PlaylistDashboardsSlice, 0)
+const (
+	// playlistSearchLimit is the maximum number of dashboards of a playlist item.
+	playlistSearchLimit = 1000
+	// playlistGeneralFolderUID is the folder UID of playlist searches for the General folder.
+	playlistGeneralFolderUID = "general"
+)
+
+// playlistDashboard is a dashboard of the play order of a playlist and the item it comes from.
+type playlistDashboard struct {
+	hit  *models.Hit
+	item models.PlaylistItem
+}
 
-	if len(dashboardByIDs) > 0 {
-		dashboardQuery := models.GetDashboardsQuery{DashboardIds: dashboardByIDs}
-		if err := hs.SQLStore.GetDashboards(ctx, &dashboardQuery); err != nil {
-			return result, err
+// validatePlaylistItems checks the values and intervals of the items of a playlist.
+func validatePlaylistItems(items []models.PlaylistItemDTO) error {
+	for _, item := range items {
+		switch item.Type {
+		case models.PlaylistItemTypeDashboardByID:
+			if _, err := strconv.ParseInt(item.Value, 10, 64); err != nil {
+				return fmt.Errorf("invalid dashboard id %q", item.Value)
+			}
+		case models.PlaylistItemTypeDashboardByUID, models.PlaylistItemTypeDashboardByTag:
+			if item.Value == "" {
+				return fmt.Errorf("the value of %s items is required", item.Type)
+			}
+		case models.PlaylistItemTypeDashboardBySearch:
+			if _, err := parsePlaylistSearch(item.Value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown playlist item type %q", item.Type)
 		}
 
-		for _, item := range dashboardQuery.Result {
-			result = append(result, dtos.PlaylistDashboard{
-				Id:    item.Id,
-				Slug:  item.Slug,
-				Title: item.Title,
-				Uri:   "db/" + item.Slug,
-				Url:   models.GetDashboardUrl(item.Uid, item.Slug),
-				Order: dashboardIDOrder[item.Id],
-			})
+		if item.Interval != "" {
+			if _, err := gtime.ParseDuration(item.Interval); err != nil {
+				return fmt.Errorf("invalid interval %q", item.Interval)
+			}
 		}
 	}
+	return nil
+}
+
+// parsePlaylistSearch parses the value of a dashboard_by_search playlist item.
+func parsePlaylistSearch(value string) (*models.PlaylistSearch, error) {
+	var s models.PlaylistSearch
+	if err := json.Unmarshal([]byte(value), &s); err != nil {
+		return nil, fmt.Errorf("invalid playlist search: %w", err)
+	}
+	switch s.TagsMatch {
+	case "", models.PlaylistTagsMatchAnd, models.PlaylistTagsMatchOr:
+	default:
+		return nil, fmt.Errorf("invalid playlist search: unknown tags match %q", s.TagsMatch)
+	}
+	if s.Limit < 0 {
+		return nil, errors.New("invalid playlist search: the limit must be positive")
+	}
+	return &s, nil
+}
+
+// playlistPlayOrder evaluates the items of the playlist in order and returns the dashboards the
+// user can view. A dashboard matched by several items is only played for the first of them.
+func (hs *HTTPServer) playlistPlayOrder(ctx context.Context, user *models.SignedInUser, playlist *models.Playlist) ([]playlistDashboard, error) {
+	items, err := hs.LoadPlaylistItems(ctx, playlist.Id)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Order < items[j].Order })
 
+	result := make([]playlistDashboard, 0)
+	played := make(map[int64]bool)
+	for _, item := range items {
+		hits, err := hs.playlistItemDashboards(ctx, user, playlist.OrgId, item)
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range hits {
+			if played[hit.ID] {
+				continue
+			}
+			played[hit.ID] = true
+			result = append(result, playlistDashboard{hit: hit, item: item})
+		}
+	}
 	return result, nil
 }
 
-func (hs *HTTPServer) populateDashboardsByTag(ctx context.Context, orgID int64, signedInUser *models.SignedInUser, dashboardByTag []string, dashboardTagOrder map[string]int) dtos.PlaylistDashboardsSlice {
-	result := make(dtos.PlaylistDashboardsSlice, 0)
-
-	for _, tag := range dashboardByTag {
-		searchQuery := search.Query{
-			Title:        "",
-			Tags:         []string{tag},
-			SignedInUser: signedInUser,
-			Limit:        100,
-			IsStarred:    false,
-			OrgId:        orgID,
+// playlistItemDashboards returns the dashboards of a playlist item the user can view. Items with
+// invalid values, or that reference dashboards or folders that do not exist anymore, have no
+// dashboards.
+func (hs *HTTPServer) playlistItemDashboards(ctx context.Context, user *models.SignedInUser, orgID int64, item models.PlaylistItem) (models.HitList, error) {
+	switch item.Type {
+	case models.PlaylistItemTypeDashboardByID:
+		id, err := strconv.ParseInt(item.Value, 10, 64)
+		if err != nil {
+			hs.log.Warn("Invalid playlist item", "id", item.Id, "error", err)
+			return nil, nil
 		}
+		return hs.searchPlaylistDashboards(ctx, user, orgID, search.Query{DashboardIds: []int64{id}})
 
-		if err := hs.SearchService.SearchHandler(ctx, &searchQuery); err == nil {
-			for _, item := range searchQuery.Result {
-				result = append(result, dtos.PlaylistDashboard{
-					Id:    item.ID,
-					Slug:  item.Slug,
-					Title: item.Title,
-					Uri:   item.URI,
-					Url:   item.URL,
-					Order: dashboardTagOrder[tag],
-				})
+	case models.PlaylistItemTypeDashboardByUID:
+		query := models.GetDashboardQuery{Uid: item.Value, OrgId: orgID}
+		if err := hs.SQLStore.GetDashboard(ctx, &query); err != nil {
+			if errors.Is(err, models.ErrDashboardNotFound) {
+				return nil, nil
 			}
+			return nil, err
+		}
+		return hs.searchPlaylistDashboards(ctx, user, orgID, search.Query{DashboardIds: []int64{query.Result.Id}})
+
+	case models.PlaylistItemTypeDashboardByTag:
+		return hs.searchPlaylistDashboards(ctx, user, orgID, search.Query{Tags: []string{item.Value}})
+
+	case models.PlaylistItemTypeDashboardBySearch:
+		s, err := parsePlaylistSearch(item.Value)
+		if err != nil {
+			hs.log.Warn("Invalid playlist item", "id", item.Id, "error", err)
+			return nil, nil
 		}
+		return hs.searchPlaylistItem(ctx, user, orgID, s)
 	}
 
-	return result
+	hs.log.Warn("Unknown playlist item type", "id", item.Id, "type", item.Type)
+	return nil, nil
 }
 
-func (hs *HTTPServer) LoadPlaylistDashboards(ctx context.Context, orgID int64, signedInUser *models.SignedInUser, playlistID int64) (dtos.PlaylistDashboardsSlice, error) {
-	playlistItems, _ := hs.LoadPlaylistItems(ctx, playlistID)
+// searchPlaylistItem runs the saved search of a dashboard_by_search playlist item. Searches that
+// match any of their tags run a search per tag.
+func (hs *HTTPServer) searchPlaylistItem(ctx context.Context, user *models.SignedInUser, orgID int64, s *models.PlaylistSearch) (models.HitList, error) {
+	query := search.Query{Title: s.Query, Tags: s.Tags, IsStarred: s.Starred, Limit: s.Limit}
+	for _, uid := range s.FolderUIDs {
+		if uid == playlistGeneralFolderUID {
+			query.FolderIds = append(query.FolderIds, 0)
+			continue
+		}
+		folder, err := hs.folderService.GetFolderByUID(ctx, user, orgID, uid)
+		if err != nil {
+			if errors.Is(err, models.ErrFolderNotFound) || errors.Is(err, models.ErrFolderAccessDenied) {
+				continue
+			}
+			return nil, err
+		}
+		query.FolderIds = append(query.FolderIds, folder.Id)
+	}
+	// none of the folders of the search can be viewed, the search would otherwise match all folders
+	if len(s.FolderUIDs) > 0 && len(query.FolderIds) == 0 {
+		return nil, nil
+	}
 
-	dashboardByIDs := make([]int64, 0)
-	dashboardByTag := make([]string, 0)
-	dashboardIDOrder := make(map[int64]int)
-	dashboardTagOrder := make(map[string]int)
+	if s.TagsMatch != models.PlaylistTagsMatchOr || len(s.Tags) < 2 {
+		return hs.searchPlaylistDashboards(ctx, user, orgID, query)
+	}
 
-	for _, i := range playlistItems {
-		if i.Type == "dashboard_by_id" {
-			dashboardID, _ := strconv.ParseInt(i.Value, 10, 64)
-			dashboardByIDs = append(dashboardByIDs, dashboardID)
-			dashboardIDOrder[dashboardID] = i.Order
+	var hits models.HitList
+	matched := make(map[int64]bool)
+	for _, tag := range s.Tags {
+		tagQuery := query
+		tagQuery.Tags = []string{tag}
+		tagHits, err := hs.searchPlaylistDashboards(ctx, user, orgID, tagQuery)
+		if err != nil {
+			return nil, err
 		}
-
-		if i.Type == "dashboard_by_tag" {
-			dashboardByTag = append(dashboardByTag, i.Value)
-			dashboardTagOrder[i.Value] = i.Order
+		for _, hit := range tagHits {
+			if !matched[hit.ID] {
+				matched[hit.ID] = true
+				hits = append(hits, hit)
+			}
 		}
 	}
+	sort.Stable(hits)
+	if s.Limit > 0 && int64(len(hits)) > s.Limit {
+		hits = hits[:s.Limit]
+	}
+	return hits, nil
+}
 
-	result := make(dtos.PlaylistDashboardsSlice, 0)
+// searchPlaylistDashboards searches the dashboards the user can view.
+func (hs *HTTPServer) searchPlaylistDashboards(ctx context.Context, user *models.SignedInUser, orgID int64, query search.Query) (models.HitList, error) {
+	query.OrgId = orgID
+	query.SignedInUser = user
+	query.Type = string(models.DashHitDB)
+	query.Permission = models.PERMISSION_VIEW
+	if query.Limit == 0 || query.Limit > playlistSearchLimit {
+		query.Limit = playlistSearchLimit
+	}
 
-	var k, _ = hs.populateDashboardsByID(ctx, dashboardByIDs, dashboardIDOrder)
-	result = append(result, k...)
-	result = append(result, hs.populateDashboardsByTag(ctx, orgID, signedInUser, dashboardByTag, dashboardTagOrder)...)
+	if err := hs.SearchService.SearchHandler(ctx, &query); err != nil {
+		return nil, err
+	}
+	return query.Result, nil
+}
 
-	sort.Sort(result)
+// LoadPlaylistPlayOrder returns the play order of the playlist for the user. The dashboards use
+// the interval of their item, or the interval of the playlist if the item does not set one.
+func (hs *HTTPServer) LoadPlaylistPlayOrder(ctx context.Context, user *models.SignedInUser, playlist *models.Playlist) (*dtos.PlaylistPlayOrder, error) {
+	dashboards, err := hs.playlistPlayOrder(ctx, user, playlist)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dtos.PlaylistPlayOrder{
+		UID:        playlist.UID,
+		Name:       playlist.Name,
+		Interval:   playlist.Interval,
+		Dashboards: make([]dtos.PlaylistPlayDashboard, 0, len(dashboards)),
+	}
+	for _, d := range dashboards {
+		interval := d.item.Interval
+		if interval == "" {
+			interval = playlist.Interval
+		}
+		result.Dashboards = append(result.Dashboards, dtos.PlaylistPlayDashboard{
+			ID:       d.hit.ID,
+			UID:      d.hit.UID,
+			Title:    d.hit.Title,
+			URL:      d.hit.URL,
+			Interval: interval,
+			ItemID:   d.item.Id,
+		})
+	}
+	return result, nil
+}
+
+func (hs *HTTPServer) LoadPlaylistDashboards(ctx context.Context, signedInUser *models.SignedInUser, playlist *models.Playlist) (dtos.PlaylistDashboardsSlice, error) {
+	dashboards, err := hs.playlistPlayOrder(ctx, signedInUser, playlist)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(dtos.PlaylistDashboardsSlice, 0, len(dashboards))
+	for _, d := range dashboards {
+		result = append(result, dtos.PlaylistDashboard{
+			Id:    d.hit.ID,
+			Slug:  d.hit.Slug,
+			Title: d.hit.Title,
+			Uri:   d.hit.URI,
+			Url:   d.hit.URL,
+			Order: d.item.Order,
+		})
+	}
 	return result, nil
 }
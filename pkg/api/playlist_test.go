@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/sqlstore/mockstore"
+)
+
+// fakePlaylistSearchService returns the dashboards with the ids or the tag of the query. Dashboard
+// 4 is not visible to the user.
+type fakePlaylistSearchService struct {
+	queries []search.Query
+}
+
+func (f *fakePlaylistSearchService) SearchHandler(_ context.Context, q *search.Query) error {
+	f.queries = append(f.queries, *q)
+	dashboards := map[int64][]string{1: {"prod"}, 2: {"prod", "db"}, 3: {"db"}, 4: {"prod"}}
+	q.Result = models.HitList{}
+	for _, id := range []int64{1, 2, 3} {
+		tags := dashboards[id]
+		matches := len(q.DashboardIds) == 0 || q.DashboardIds[0] == id
+		for _, tag := range q.Tags {
+			found := false
+			for _, t := range tags {
+				found = found || t == tag
+			}
+			matches = matches && found
+		}
+		if matches {
+			q.Result = append(q.Result, &models.Hit{ID: id, Title: []string{"", "A", "C", "B"}[id], Type: models.DashHitDB})
+		}
+	}
+	return nil
+}
+
+func (f *fakePlaylistSearchService) SortOptions() []models.SortOption { return nil }
+
+func TestPlaylistPlayOrder(t *testing.T) {
+	store := mockstore.NewSQLStoreMock()
+	store.ExpectedPlaylist = &models.Playlist{Id: 1, UID: "noc", Name: "NOC", Interval: "5m", OrgId: testOrgID}
+	store.ExpectedPlaylistItems = []models.PlaylistItem{
+		{Id: 3, Type: models.PlaylistItemTypeDashboardBySearch, Value: `{"tags": ["prod", "db"], "tagsMatch": "or", "folderUids": ["infra", "missing"]}`, Order: 2, Interval: "30s"},
+		{Id: 1, Type: models.PlaylistItemTypeDashboardByID, Value: "4", Order: 0},
+		{Id: 2, Type: models.PlaylistItemTypeDashboardByTag, Value: "db", Order: 1},
+	}
+	searchService := &fakePlaylistSearchService{}
+	folderService := &dashboards.FakeFolderService{}
+	folderService.On("GetFolderByUID", mock.Anything, mock.Anything, testOrgID, "infra").Return(&models.Folder{Id: 7}, nil)
+	folderService.On("GetFolderByUID", mock.Anything, mock.Anything, testOrgID, "missing").Return(nil, models.ErrFolderNotFound)
+	hs := &HTTPServer{SQLStore: store, SearchService: searchService, folderService: folderService, log: log.New("test")}
+
+	loggedInUserScenarioWithRole(t, "When calling GET on", "GET", "/api/playlists/noc/play", "/api/playlists/:uid/play", models.ROLE_VIEWER, func(sc *scenarioContext) {
+		sc.handlerFunc = hs.GetPlaylistPlayOrder
+		sc.fakeReqWithParams("GET", sc.url, map[string]string{}).exec()
+		require.Equal(t, 200, sc.resp.Code)
+
+		var playOrder dtos.PlaylistPlayOrder
+		require.NoError(t, json.NewDecoder(sc.resp.Body).Decode(&playOrder))
+		require.Equal(t, "noc", playOrder.UID)
+		require.Equal(t, []dtos.PlaylistPlayDashboard{
+			{ID: 2, Title: "C", Interval: "5m", ItemID: 2},
+			{ID: 3, Title: "B", Interval: "5m", ItemID: 2},
+			{ID: 1, Title: "A", Interval: "30s", ItemID: 3},
+		}, playOrder.Dashboards)
+
+		// searches are run for the user, and the saved search runs a search per tag
+		require.Len(t, searchService.queries, 4)
+		for _, q := range searchService.queries {
+			require.Equal(t, testOrgID, q.OrgId)
+			require.NotNil(t, q.SignedInUser)
+			require.Equal(t, models.PERMISSION_VIEW, q.Permission)
+		}
+		require.Equal(t, []int64{7}, searchService.queries[2].FolderIds)
+		require.Equal(t, []string{"prod"}, searchService.queries[2].Tags)
+		require.Equal(t, []string{"db"}, searchService.queries[3].Tags)
+	}, store)
+}
+
+func TestValidatePlaylistItems(t *testing.T) {
+	valid := []models.PlaylistItemDTO{
+		{Type: models.PlaylistItemTypeDashboardByID, Value: "1"},
+		{Type: models.PlaylistItemTypeDashboardByUID, Value: "abc", Interval: "1m"},
+		{Type: models.PlaylistItemTypeDashboardByTag, Value: "prod"},
+		{Type: models.PlaylistItemTypeDashboardBySearch, Value: `{"query": "cpu", "starred": true}`},
+	}
+	require.NoError(t, validatePlaylistItems(valid))
+
+	for _, item := range []models.PlaylistItemDTO{
+		{Type: "dashboard_by_name", Value: "cpu"},
+		{Type: models.PlaylistItemTypeDashboardByID, Value: "abc"},
+		{Type: models.PlaylistItemTypeDashboardByTag},
+		{Type: models.PlaylistItemTypeDashboardBySearch, Value: `{"tagsMatch": "xor"}`},
+		{Type: models.PlaylistItemTypeDashboardBySearch, Value: `not json`},
+		{Type: models.PlaylistItemTypeDashboardByTag, Value: "prod", Interval: "soon"},
+	} {
+		require.Error(t, validatePlaylistItems([]models.PlaylistItemDTO{item}), item)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
@@ -15,6 +17,11 @@ import (
 	"github.com/grafana/grafana/pkg/web"
 )
 
+const (
+	defaultShortURLsLimit = 100
+	maxShortURLsLimit     = 1000
+)
+
 // createShortURL handles requests to create short URLs.
 func (hs *HTTPServer) createShortURL(c *models.ReqContext) response.Response {
 	cmd := dtos.CreateShortURLCmd{}
@@ -34,20 +41,83 @@ func (hs *HTTPServer) createShortURL(c *models.ReqContext) response.Response {
 		return response.Error(400, "Invalid path", nil)
 	}
 
-	shortURL, err := hs.ShortURLService.CreateShortURL(c.Req.Context(), c.SignedInUser, cmd.Path)
+	shortURL, err := hs.ShortURLService.CreateShortURL(c.Req.Context(), c.SignedInUser, &models.CreateShortURLCommand{
+		Path:      cmd.Path,
+		Alias:     strings.TrimSpace(cmd.Alias),
+		ExpiresAt: unixOrZero(cmd.ExpiresAt),
+	})
 	if err != nil {
-		return response.Error(500, "Failed to create short URL", err)
+		return shortURLError(err, "Failed to create short URL")
 	}
 
-	url := fmt.Sprintf("%s/goto/%s?orgId=%d", strings.TrimSuffix(setting.AppUrl, "/"), shortURL.Uid, c.OrgId)
-	c.Logger.Debug("Created short URL", "url", url)
+	dto := shortURLToDTO(shortURL, c.OrgId)
+	c.Logger.Debug("Created short URL", "url", dto.URL)
 
-	dto := dtos.ShortURL{
-		UID: shortURL.Uid,
-		URL: url,
+	return response.JSON(200, dto)
+}
+
+// getShortURLs handles requests to list the short URLs created by the user.
+func (hs *HTTPServer) getShortURLs(c *models.ReqContext) response.Response {
+	limit := c.QueryInt("limit")
+	if limit <= 0 {
+		limit = defaultShortURLsLimit
+	}
+	if limit > maxShortURLsLimit {
+		limit = maxShortURLsLimit
 	}
 
-	return response.JSON(200, dto)
+	query := models.GetShortURLsQuery{Limit: limit, Page: c.QueryInt("page")}
+	if err := hs.ShortURLService.GetShortURLs(c.Req.Context(), c.SignedInUser, &query); err != nil {
+		return response.Error(500, "Failed to get short URLs", err)
+	}
+
+	result := make([]dtos.ShortURL, 0, len(query.Result))
+	for _, shortURL := range query.Result {
+		result = append(result, shortURLToDTO(shortURL, c.OrgId))
+	}
+
+	return response.JSON(200, result)
+}
+
+// getShortURL handles requests to get a short URL the user can manage, with its access statistics.
+func (hs *HTTPServer) getShortURL(c *models.ReqContext) response.Response {
+	shortURL, err := hs.ShortURLService.GetShortURLByUID(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return shortURLError(err, "Failed to get short URL")
+	}
+	if !shortURL.CanManage(c.SignedInUser) {
+		return shortURLError(models.ErrShortURLAccessDenied, "")
+	}
+
+	return response.JSON(200, shortURLToDTO(shortURL, c.OrgId))
+}
+
+// updateShortURL handles requests to set the alias and the expiry of a short URL.
+func (hs *HTTPServer) updateShortURL(c *models.ReqContext) response.Response {
+	cmd := dtos.UpdateShortURLCmd{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	updateCmd := models.UpdateShortURLCommand{
+		UID:       web.Params(c.Req)[":uid"],
+		Alias:     strings.TrimSpace(cmd.Alias),
+		ExpiresAt: unixOrZero(cmd.ExpiresAt),
+	}
+	if err := hs.ShortURLService.UpdateShortURL(c.Req.Context(), c.SignedInUser, &updateCmd); err != nil {
+		return shortURLError(err, "Failed to update short URL")
+	}
+
+	return response.JSON(200, shortURLToDTO(updateCmd.Result, c.OrgId))
+}
+
+// deleteShortURL handles requests to delete a short URL.
+func (hs *HTTPServer) deleteShortURL(c *models.ReqContext) response.Response {
+	if err := hs.ShortURLService.DeleteShortURL(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"]); err != nil {
+		return shortURLError(err, "Failed to delete short URL")
+	}
+
+	return response.Success("Short URL deleted")
 }
 
 func (hs *HTTPServer) redirectFromShortURL(c *models.ReqContext) {
@@ -68,11 +138,79 @@ func (hs *HTTPServer) redirectFromShortURL(c *models.ReqContext) {
 		return
 	}
 
-	// Failure to update LastSeenAt should still allow to redirect
-	if err := hs.ShortURLService.UpdateLastSeenAt(c.Req.Context(), shortURL); err != nil {
-		hs.log.Error("Failed to update short URL last seen at", "error", err)
+	if shortURL.Expired(time.Now()) {
+		hs.log.Debug("Not redirecting short URL since expired", "uid", shortURL.Uid)
+		c.Handle(hs.Cfg, http.StatusGone, "This short URL has expired", nil)
+		return
+	}
+
+	// Failure to record the access should still allow to redirect
+	if err := hs.ShortURLService.RecordAccess(c.Req.Context(), shortURL, shortURLReferrer(c.Req.Referer())); err != nil {
+		hs.log.Error("Failed to record short URL access", "error", err)
 	}
 
 	hs.log.Debug("Redirecting short URL", "path", shortURL.Path)
 	c.Redirect(setting.ToAbsUrl(shortURL.Path), 302)
 }
+
+// shortURLReferrer returns the referrer of a short URL access without its query and fragment,
+// which can contain credentials.
+func shortURLReferrer(referrer string) string {
+	u, err := url.Parse(referrer)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+func shortURLToDTO(shortURL *models.ShortUrl, orgID int64) dtos.ShortURL {
+	name := shortURL.Uid
+	if shortURL.Alias != "" {
+		name = shortURL.Alias
+	}
+
+	dto := dtos.ShortURL{
+		UID:          shortURL.Uid,
+		URL:          fmt.Sprintf("%s/goto/%s?orgId=%d", strings.TrimSuffix(setting.AppUrl, "/"), name, orgID),
+		Alias:        shortURL.Alias,
+		Path:         shortURL.Path,
+		CreatedAt:    time.Unix(shortURL.CreatedAt, 0).UTC(),
+		Expired:      shortURL.Expired(time.Now()),
+		AccessCount:  shortURL.AccessCount,
+		LastReferrer: shortURL.LastReferrer,
+	}
+	if shortURL.ExpiresAt != 0 {
+		expiresAt := time.Unix(shortURL.ExpiresAt, 0).UTC()
+		dto.ExpiresAt = &expiresAt
+	}
+	if shortURL.LastSeenAt != 0 {
+		lastSeenAt := time.Unix(shortURL.LastSeenAt, 0).UTC()
+		dto.LastSeenAt = &lastSeenAt
+	}
+	return dto
+}
+
+func shortURLError(err error, message string) response.Response {
+	switch {
+	case errors.Is(err, models.ErrShortURLNotFound):
+		return response.Error(http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, models.ErrShortURLAccessDenied):
+		return response.Error(http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, models.ErrShortURLAliasExists):
+		return response.Error(http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, models.ErrShortURLInvalidAlias), errors.Is(err, models.ErrShortURLAliasTooLong),
+		errors.Is(err, models.ErrShortURLInvalidExpiry):
+		return response.Error(http.StatusBadRequest, err.Error(), nil)
+	}
+	return response.Error(500, message, err)
+}
+
+func unixOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
@@ -12,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/shorturls"
+	"github.com/grafana/grafana/pkg/services/sqlstore/mockstore"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/require"
 )
@@ -29,7 +31,7 @@ func TestShortURLAPIEndpoint(t *testing.T) {
 			Path:  cmd.Path,
 		}
 		service := &fakeShortURLService{
-			createShortURLFunc: func(ctx context.Context, user *models.SignedInUser, path string) (*models.ShortUrl, error) {
+			createShortURLFunc: func(ctx context.Context, user *models.SignedInUser, cmd *models.CreateShortURLCommand) (*models.ShortUrl, error) {
 				return createResp, nil
 			},
 		}
@@ -45,6 +47,72 @@ func TestShortURLAPIEndpoint(t *testing.T) {
 				require.Equal(t, fmt.Sprintf("/goto/%s?orgId=%d", createResp.Uid, createResp.OrgId), shortUrl.URL)
 			})
 	})
+
+	t.Run("Given a request for creating a shortUrl with an alias that already exists", func(t *testing.T) {
+		cmd := dtos.CreateShortURLCmd{Path: "d/TxKARsmGz/new-dashboard", Alias: "runbook"}
+		service := &fakeShortURLService{
+			createShortURLFunc: func(ctx context.Context, user *models.SignedInUser, cmd *models.CreateShortURLCommand) (*models.ShortUrl, error) {
+				require.Equal(t, "runbook", cmd.Alias)
+				return nil, models.ErrShortURLAliasExists
+			},
+		}
+
+		createShortURLScenario(t, "When calling POST on", "/api/short-urls", "/api/short-urls", cmd, service,
+			func(sc *scenarioContext) {
+				callCreateShortURL(sc)
+				require.Equal(t, 409, sc.resp.Code)
+			})
+	})
+}
+
+func TestShortURLRedirect(t *testing.T) {
+	shortURL := &models.ShortUrl{Id: 1, OrgId: testOrgID, Uid: "N1u6L4eGz", Alias: "runbook", Path: "d/TxKARsmGz/new-dashboard", CreatedBy: 2}
+	service := &fakeShortURLService{shortURL: shortURL}
+	hs := &HTTPServer{Cfg: setting.NewCfg(), ShortURLService: service, log: log.New("test")}
+	hs.Cfg.ErrTemplateName = "error-template"
+
+	sc := setupScenarioContext(t, "/goto/runbook")
+	sc.m.Get("/goto/:uid", func(c *models.ReqContext) {
+		c.SignedInUser = &models.SignedInUser{OrgId: testOrgID, UserId: testUserID}
+		hs.redirectFromShortURL(c)
+	})
+
+	t.Run("redirects and records the access without the query of the referrer", func(t *testing.T) {
+		req := sc.fakeReqNoAssertions("GET", sc.url)
+		req.req.Header.Set("Referer", "https://wiki.example.com/runbooks/db?token=secret#restart")
+		req.exec()
+		require.Equal(t, 302, sc.resp.Code)
+		require.Equal(t, []string{"https://wiki.example.com/runbooks/db"}, service.referrers)
+	})
+
+	t.Run("does not redirect expired short URLs", func(t *testing.T) {
+		shortURL.ExpiresAt = time.Now().Add(-time.Hour).Unix()
+		sc.fakeReqNoAssertions("GET", sc.url).exec()
+		require.Equal(t, 410, sc.resp.Code)
+		require.Len(t, service.referrers, 1)
+	})
+}
+
+func TestShortURLManagement(t *testing.T) {
+	shortURL := &models.ShortUrl{Id: 1, OrgId: testOrgID, Uid: "N1u6L4eGz", Path: "d/TxKARsmGz/new-dashboard", CreatedBy: 2, AccessCount: 3}
+	hs := &HTTPServer{ShortURLService: &fakeShortURLService{shortURL: shortURL}}
+
+	loggedInUserScenarioWithRole(t, "When calling GET on", "GET", "/api/short-urls/N1u6L4eGz", "/api/short-urls/:uid", models.ROLE_EDITOR, func(sc *scenarioContext) {
+		sc.handlerFunc = hs.getShortURL
+		sc.fakeReqWithParams("GET", sc.url, map[string]string{}).exec()
+		require.Equal(t, 403, sc.resp.Code)
+	}, mockstore.NewSQLStoreMock())
+
+	loggedInUserScenarioWithRole(t, "When calling GET on", "GET", "/api/short-urls/N1u6L4eGz", "/api/short-urls/:uid", models.ROLE_ADMIN, func(sc *scenarioContext) {
+		sc.handlerFunc = hs.getShortURL
+		sc.fakeReqWithParams("GET", sc.url, map[string]string{}).exec()
+		require.Equal(t, 200, sc.resp.Code)
+
+		dto := dtos.ShortURL{}
+		require.NoError(t, json.NewDecoder(sc.resp.Body).Decode(&dto))
+		require.Equal(t, int64(3), dto.AccessCount)
+		require.Nil(t, dto.ExpiresAt)
+	}, mockstore.NewSQLStoreMock())
 }
 
 func callCreateShortURL(sc *scenarioContext) {
@@ -76,25 +144,40 @@ func createShortURLScenario(t *testing.T, desc string, url string, routePattern
 }
 
 type fakeShortURLService struct {
-	createShortURLFunc func(ctx context.Context, user *models.SignedInUser, path string) (*models.ShortUrl, error)
+	createShortURLFunc func(ctx context.Context, user *models.SignedInUser, cmd *models.CreateShortURLCommand) (*models.ShortUrl, error)
+	shortURL           *models.ShortUrl
+	referrers          []string
 }
 
 func (s *fakeShortURLService) GetShortURLByUID(ctx context.Context, user *models.SignedInUser, uid string) (*models.ShortUrl, error) {
-	return nil, nil
+	if s.shortURL != nil && (s.shortURL.Uid == uid || s.shortURL.Alias == uid) {
+		return s.shortURL, nil
+	}
+	return nil, models.ErrShortURLNotFound
 }
 
-func (s *fakeShortURLService) CreateShortURL(ctx context.Context, user *models.SignedInUser, path string) (*models.ShortUrl, error) {
+func (s *fakeShortURLService) GetShortURLs(ctx context.Context, user *models.SignedInUser, query *models.GetShortURLsQuery) error {
+	return nil
+}
+
+func (s *fakeShortURLService) CreateShortURL(ctx context.Context, user *models.SignedInUser, cmd *models.CreateShortURLCommand) (*models.ShortUrl, error) {
 	if s.createShortURLFunc != nil {
-		return s.createShortURLFunc(ctx, user, path)
+		return s.createShortURLFunc(ctx, user, cmd)
 	}
 
 	return nil, nil
 }
 
-func (s *fakeShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *models.ShortUrl) error {
+func (s *fakeShortURLService) UpdateShortURL(ctx context.Context, user *models.SignedInUser, cmd *models.UpdateShortURLCommand) error {
 	return nil
 }
 
-func (s *fakeShortURLService) DeleteStaleShortURLs(ctx context.Context, cmd *models.DeleteShortUrlCommand) error {
+func (s *fakeShortURLService) DeleteShortURL(ctx context.Context, user *models.SignedInUser, uid string) error {
 	return nil
 }
+
+func (s *fakeShortURLService) RecordAccess(ctx context.Context, shortURL *models.ShortUrl, referrer string) error {
+	s.referrers = append(s.referrers, referrer)
+	return nil
+}
+
@@ -23,6 +23,7 @@ const (
 	DiffJSON DiffType = iota
 	DiffBasic
 	DiffDelta
+	DiffStructural
 )
 
 type Options struct {
@@ -50,6 +51,8 @@ func ParseDiffType(diff string) DiffType {
 		return DiffBasic
 	case "delta":
 		return DiffDelta
+	case "structural":
+		return DiffStructural
 	}
 	return DiffBasic
 }
@@ -57,6 +60,21 @@ func ParseDiffType(diff string) DiffType {
 // CompareDashboardVersionsCommand computes the JSON diff of two versions,
 // assigning the delta of the diff to the `Delta` field.
 func CalculateDiff(ctx context.Context, options *Options, baseData, newData *simplejson.Json) (*Result, error) {
+	if options.DiffType == DiffStructural {
+		changes, err := StructuralDiff(baseData, newData)
+		if err != nil {
+			return nil, err
+		}
+		if changes == nil {
+			changes = []Change{}
+		}
+		structuralOutput, err := json.Marshal(changes)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Delta: structuralOutput}, nil
+	}
+
 	left, jsonDiff, err := getDiff(baseData, newData)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,168 @@
+package dashdiffs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// ignoredMergePaths are the paths whose changes are not merged, as they are set
+// when the dashboard is saved.
+var ignoredMergePaths = map[string]bool{
+	"/version": true,
+}
+
+// Conflict is a path that two concurrent saves of a dashboard changed
+// differently, with its value in each version. A missing value is nil.
+type Conflict struct {
+	Path   string      `json:"path"`
+	Base   interface{} `json:"base"`
+	Ours   interface{} `json:"ours"`
+	Theirs interface{} `json:"theirs"`
+}
+
+// Merge applies the changes between base and ours to theirs, where ours and
+// theirs are two versions both saved from base. If a change of ours overlaps a
+// different change of theirs, nothing is merged and the conflicting paths are
+// returned instead.
+func Merge(base, ours, theirs *simplejson.Json) (*simplejson.Json, []Conflict, error) {
+	baseValue, err := toValue(base)
+	if err != nil {
+		return nil, nil, err
+	}
+	oursValue, err := toValue(ours)
+	if err != nil {
+		return nil, nil, err
+	}
+	theirsValue, err := toValue(theirs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ourChanges := diffValues(nil, "", baseValue, oursValue)
+	theirChanges := diffValues(nil, "", baseValue, theirsValue)
+
+	var conflicts []Conflict
+	conflicting := make(map[string]bool)
+	changes := make([]Change, 0, len(ourChanges))
+	for _, ourChange := range ourChanges {
+		if ignoredMergePaths[ourChange.Path] {
+			continue
+		}
+		applied, overlaps := false, false
+		for _, theirChange := range theirChanges {
+			if !overlappingPaths(ourChange.Path, theirChange.Path) {
+				continue
+			}
+			if ourChange.Path == theirChange.Path && ourChange.Op == theirChange.Op &&
+				reflect.DeepEqual(ourChange.NewValue, theirChange.NewValue) {
+				applied = true
+				continue
+			}
+			overlaps = true
+			path := ourChange.Path
+			if len(theirChange.Path) < len(path) {
+				path = theirChange.Path
+			}
+			if !conflicting[path] {
+				conflicting[path] = true
+				conflicts = append(conflicts, Conflict{
+					Path:   path,
+					Base:   lookup(baseValue, path),
+					Ours:   lookup(oursValue, path),
+					Theirs: lookup(theirsValue, path),
+				})
+			}
+		}
+		if !applied && !overlaps {
+			changes = append(changes, ourChange)
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Path < conflicts[j].Path })
+		return nil, conflicts, nil
+	}
+
+	merged := theirsValue
+	for _, c := range changes {
+		merged, err = applyChange(merged, splitPath(c.Path), c)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return simplejson.NewFromAny(merged), nil, nil
+}
+
+// overlappingPaths returns true if the paths are equal or one contains the other.
+func overlappingPaths(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return a == b || strings.HasPrefix(b, a+"/")
+}
+
+// applyChange applies a change to the value at the remaining segments of its
+// path, returning the updated value.
+func applyChange(value interface{}, segments []string, c Change) (interface{}, error) {
+	if len(segments) == 0 {
+		return c.NewValue, nil
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		name := segments[0]
+		if len(segments) == 1 && c.Op == ChangeRemove {
+			delete(v, name)
+			return v, nil
+		}
+		child, ok := v[name]
+		if !ok && len(segments) > 1 {
+			break
+		}
+		updated, err := applyChange(child, segments[1:], c)
+		if err != nil {
+			return nil, err
+		}
+		v[name] = updated
+		return v, nil
+	case []interface{}:
+		i, ok := findElement(v, segments[0])
+		if len(segments) == 1 && !ok && c.Op == ChangeAdd {
+			return append(v, c.NewValue), nil
+		}
+		if !ok {
+			break
+		}
+		if len(segments) == 1 && c.Op == ChangeRemove {
+			return append(v[:i], v[i+1:]...), nil
+		}
+		updated, err := applyChange(v[i], segments[1:], c)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = updated
+		return v, nil
+	}
+	return nil, fmt.Errorf("dashdiff: cannot apply the change of %s", c.Path)
+}
+
+// lookup returns the value at a path, or nil if there is none.
+func lookup(value interface{}, path string) interface{} {
+	for _, segment := range splitPath(path) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[segment]
+		case []interface{}:
+			i, ok := findElement(v, segment)
+			if !ok {
+				return nil
+			}
+			value = v[i]
+		default:
+			return nil
+		}
+	}
+	return value
+}
@@ -0,0 +1,148 @@
+package dashdiffs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const mergeBaseJSON = `{
+	"title": "Dashboard",
+	"version": 3,
+	"tags": ["a"],
+	"panels": [
+		{"id": 1, "title": "CPU", "targets": [{"refId": "A", "expr": "cpu"}]},
+		{"id": 2, "title": "Memory", "gridPos": {"x": 0, "y": 8}}
+	]
+}`
+
+func dashboardForTests(t *testing.T, s string) *simplejson.Json {
+	t.Helper()
+	j, err := simplejson.NewJson([]byte(s))
+	require.NoError(t, err)
+	return j
+}
+
+func TestStructuralDiff(t *testing.T) {
+	base := dashboardForTests(t, mergeBaseJSON)
+	next := dashboardForTests(t, `{
+		"title": "Dashboard",
+		"version": 4,
+		"tags": ["a", "b"],
+		"panels": [
+			{"id": 2, "title": "Memory", "gridPos": {"x": 0, "y": 8}},
+			{"id": 3, "title": "Disk"},
+			{"id": 1, "title": "CPU usage", "targets": [{"refId": "A", "expr": "cpu"}, {"refId": "B", "expr": "load"}]}
+		],
+		"refresh": "5s"
+	}`)
+
+	changes, err := StructuralDiff(base, next)
+	require.NoError(t, err)
+	require.Equal(t, []Change{
+		{Op: ChangeAdd, Path: "/panels/id=1/targets/refId=B", NewValue: map[string]interface{}{"refId": "B", "expr": "load"}},
+		{Op: ChangeReplace, Path: "/panels/id=1/title", OldValue: "CPU", NewValue: "CPU usage"},
+		{Op: ChangeAdd, Path: "/panels/id=3", NewValue: map[string]interface{}{"id": float64(3), "title": "Disk"}},
+		{Op: ChangeAdd, Path: "/refresh", NewValue: "5s"},
+		{Op: ChangeReplace, Path: "/tags", OldValue: []interface{}{"a"}, NewValue: []interface{}{"a", "b"}},
+		{Op: ChangeReplace, Path: "/version", OldValue: float64(3), NewValue: float64(4)},
+	}, changes)
+
+	changes, err = StructuralDiff(base, base)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestMerge(t *testing.T) {
+	base := dashboardForTests(t, mergeBaseJSON)
+
+	t.Run("merges changes that do not overlap", func(t *testing.T) {
+		ours := dashboardForTests(t, `{
+			"title": "Dashboard",
+			"version": 3,
+			"tags": ["a"],
+			"panels": [
+				{"id": 1, "title": "CPU usage", "targets": [{"refId": "A", "expr": "cpu"}]},
+				{"id": 2, "title": "Memory", "gridPos": {"x": 0, "y": 8}},
+				{"id": 3, "title": "Disk"}
+			]
+		}`)
+		theirs := dashboardForTests(t, `{
+			"title": "Dashboard",
+			"version": 4,
+			"tags": ["a"],
+			"panels": [
+				{"id": 1, "title": "CPU", "targets": [{"refId": "A", "expr": "cpu"}]},
+				{"id": 2, "title": "Memory", "gridPos": {"x": 12, "y": 8}}
+			],
+			"refresh": "5s"
+		}`)
+
+		merged, conflicts, err := Merge(base, ours, theirs)
+		require.NoError(t, err)
+		require.Empty(t, conflicts)
+		require.JSONEq(t, `{
+			"title": "Dashboard",
+			"version": 4,
+			"tags": ["a"],
+			"panels": [
+				{"id": 1, "title": "CPU usage", "targets": [{"refId": "A", "expr": "cpu"}]},
+				{"id": 2, "title": "Memory", "gridPos": {"x": 12, "y": 8}},
+				{"id": 3, "title": "Disk"}
+			],
+			"refresh": "5s"
+		}`, string(mustEncode(t, merged)))
+	})
+
+	t.Run("merges identical changes", func(t *testing.T) {
+		ours := dashboardForTests(t, `{"title": "Renamed", "version": 3, "tags": ["a"], "panels": []}`)
+		theirs := dashboardForTests(t, `{"title": "Renamed", "version": 4, "tags": ["a"], "panels": []}`)
+
+		merged, conflicts, err := Merge(base, ours, theirs)
+		require.NoError(t, err)
+		require.Empty(t, conflicts)
+		require.JSONEq(t, `{"title": "Renamed", "version": 4, "tags": ["a"], "panels": []}`, string(mustEncode(t, merged)))
+	})
+
+	t.Run("reports the paths of overlapping changes", func(t *testing.T) {
+		ours := dashboardForTests(t, `{
+			"title": "Ours",
+			"version": 3,
+			"tags": ["a"],
+			"panels": [
+				{"id": 1, "title": "CPU", "targets": [{"refId": "A", "expr": "cpu"}]},
+				{"id": 2, "title": "Memory usage", "gridPos": {"x": 0, "y": 8}}
+			]
+		}`)
+		theirs := dashboardForTests(t, `{
+			"title": "Theirs",
+			"version": 4,
+			"tags": ["a"],
+			"panels": [
+				{"id": 1, "title": "CPU", "targets": [{"refId": "A", "expr": "cpu"}]}
+			]
+		}`)
+
+		merged, conflicts, err := Merge(base, ours, theirs)
+		require.NoError(t, err)
+		require.Nil(t, merged)
+		require.Equal(t, []Conflict{
+			{
+				Path:   "/panels/id=2",
+				Base:   map[string]interface{}{"id": float64(2), "title": "Memory", "gridPos": map[string]interface{}{"x": float64(0), "y": float64(8)}},
+				Ours:   map[string]interface{}{"id": float64(2), "title": "Memory usage", "gridPos": map[string]interface{}{"x": float64(0), "y": float64(8)}},
+				Theirs: nil,
+			},
+			{Path: "/title", Base: "Dashboard", Ours: "Ours", Theirs: "Theirs"},
+		}, conflicts)
+	})
+}
+
+func mustEncode(t *testing.T, j *simplejson.Json) []byte {
+	t.Helper()
+	b, err := j.Encode()
+	require.NoError(t, err)
+	return b
+}
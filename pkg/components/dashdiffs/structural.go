@@ -0,0 +1,215 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// ChangeOp is the operation of a change of a structural diff.
+type ChangeOp string
+
+const (
+	ChangeAdd     ChangeOp = "add"
+	ChangeRemove  ChangeOp = "remove"
+	ChangeReplace ChangeOp = "replace"
+)
+
+// arrayKeys are the fields identifying the elements of an array, such as the
+// IDs of panels and the reference IDs of queries. The elements of an array are
+// compared by key if they all have a unique value for one of these fields, and
+// as a whole otherwise.
+var arrayKeys = []string{"id", "refId"}
+
+// Change is a change of a structural diff. The path is a JSON pointer, except
+// that the elements of arrays compared by key are selected with `key=value`
+// instead of their position, for example `/panels/id=2/title`.
+type Change struct {
+	Op       ChangeOp    `json:"op"`
+	Path     string      `json:"path"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue,omitempty"`
+}
+
+// StructuralDiff returns the changes between two dashboard versions, sorted by
+// path. The order of the elements of arrays compared by key is ignored, as the
+// layout of panels is defined by their grid position.
+func StructuralDiff(baseData, newData *simplejson.Json) ([]Change, error) {
+	base, err := toValue(baseData)
+	if err != nil {
+		return nil, err
+	}
+	next, err := toValue(newData)
+	if err != nil {
+		return nil, err
+	}
+	return diffValues(nil, "", base, next), nil
+}
+
+func diffValues(changes []Change, path string, base, next interface{}) []Change {
+	switch b := base.(type) {
+	case map[string]interface{}:
+		if n, ok := next.(map[string]interface{}); ok {
+			return diffObjects(changes, path, b, n)
+		}
+	case []interface{}:
+		if n, ok := next.([]interface{}); ok {
+			if key := arrayKey(b, n); key != "" {
+				return diffKeyedArrays(changes, path, key, b, n)
+			}
+		}
+	}
+	if !reflect.DeepEqual(base, next) {
+		changes = append(changes, Change{Op: ChangeReplace, Path: path, OldValue: base, NewValue: next})
+	}
+	return changes
+}
+
+func diffObjects(changes []Change, path string, base, next map[string]interface{}) []Change {
+	names := make([]string, 0, len(base)+len(next))
+	for name := range base {
+		names = append(names, name)
+	}
+	for name := range next {
+		if _, ok := base[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := path + "/" + escapePathSegment(name)
+		b, inBase := base[name]
+		n, inNext := next[name]
+		switch {
+		case !inNext:
+			changes = append(changes, Change{Op: ChangeRemove, Path: p, OldValue: b})
+		case !inBase:
+			changes = append(changes, Change{Op: ChangeAdd, Path: p, NewValue: n})
+		default:
+			changes = diffValues(changes, p, b, n)
+		}
+	}
+	return changes
+}
+
+func diffKeyedArrays(changes []Change, path, key string, base, next []interface{}) []Change {
+	baseByKey := make(map[string]interface{}, len(base))
+	for _, e := range base {
+		baseByKey[elementSelector(key, e)] = e
+	}
+	nextByKey := make(map[string]interface{}, len(next))
+	for _, e := range next {
+		nextByKey[elementSelector(key, e)] = e
+	}
+
+	for _, e := range base {
+		selector := elementSelector(key, e)
+		p := path + "/" + escapePathSegment(selector)
+		if n, ok := nextByKey[selector]; ok {
+			changes = diffValues(changes, p, e, n)
+		} else {
+			changes = append(changes, Change{Op: ChangeRemove, Path: p, OldValue: e})
+		}
+	}
+	for _, e := range next {
+		selector := elementSelector(key, e)
+		if _, ok := baseByKey[selector]; !ok {
+			changes = append(changes, Change{Op: ChangeAdd, Path: path + "/" + escapePathSegment(selector), NewValue: e})
+		}
+	}
+	return changes
+}
+
+// arrayKey returns the field identifying the elements of both arrays, or an
+// empty string if they must be compared by position.
+func arrayKey(base, next []interface{}) string {
+	if len(base) == 0 && len(next) == 0 {
+		return ""
+	}
+	for _, key := range arrayKeys {
+		if hasUniqueKey(key, base) && hasUniqueKey(key, next) {
+			return key
+		}
+	}
+	return ""
+}
+
+func hasUniqueKey(key string, elements []interface{}) bool {
+	seen := make(map[string]bool, len(elements))
+	for _, e := range elements {
+		obj, ok := e.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		switch obj[key].(type) {
+		case string, float64:
+		default:
+			return false
+		}
+		selector := elementSelector(key, e)
+		if seen[selector] {
+			return false
+		}
+		seen[selector] = true
+	}
+	return true
+}
+
+func elementSelector(key string, element interface{}) string {
+	switch v := element.(map[string]interface{})[key].(type) {
+	case float64:
+		return key + "=" + strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%s=%v", key, v)
+	}
+}
+
+// findElement returns the position of the element of an array selected by a
+// `key=value` path segment.
+func findElement(elements []interface{}, segment string) (int, bool) {
+	key := segment
+	if i := strings.Index(segment, "="); i >= 0 {
+		key = segment[:i]
+	}
+	for i, e := range elements {
+		if obj, ok := e.(map[string]interface{}); ok && obj[key] != nil && elementSelector(key, e) == segment {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func escapePathSegment(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	segments := strings.Split(path[1:], "/")
+	for i, s := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+	}
+	return segments
+}
+
+// toValue converts a dashboard to plain JSON values, so that equal numbers are
+// compared as equal however they were decoded.
+func toValue(data *simplejson.Json) (interface{}, error) {
+	b, err := data.Encode()
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -3,6 +3,7 @@ package expr
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -112,6 +113,20 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 		return nil, fmt.Errorf("expected reducer to be a string, got %T for refId %v", rawReducer, rn.RefID)
 	}
 
+	// the percentile reducer takes the percentile as a parameter and is turned into the
+	// short form (e.g. "p95") that the reduce functions understand.
+	if strings.ToLower(redFunc) == "percentile" {
+		rawParam, ok := rn.Query["parameter"]
+		if !ok {
+			return nil, fmt.Errorf("no parameter specified for percentile reducer for refId %v", rn.RefID)
+		}
+		param, ok := rawParam.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expected parameter to be a number, got %T for refId %v", rawParam, rn.RefID)
+		}
+		redFunc = "p" + strconv.FormatFloat(param, 'f', -1, 64)
+	}
+
 	var mapper mathexp.ReduceMapper = nil
 	settings, ok := rn.Query["settings"]
 	if ok {
@@ -262,6 +277,122 @@ func (gr *ResampleCommand) Execute(ctx context.Context, vars mathexp.Vars) (math
 	return newRes, nil
 }
 
+// JoinCommand is an expression command that matches the series or numbers of two
+// variables by a subset of their labels and performs a binary operation on each match.
+type JoinCommand struct {
+	Left     string
+	Right    string
+	Operator string
+	Mode     mathexp.JoinMode
+	Matcher  mathexp.JoinMatcher
+	refID    string
+}
+
+// NewJoinCommand creates a new JoinCommand.
+func NewJoinCommand(refID, left, right, operator string, mode mathexp.JoinMode, matcher mathexp.JoinMatcher) (*JoinCommand, error) {
+	if err := mathexp.ValidateBinaryOp(operator); err != nil {
+		return nil, err
+	}
+	if len(matcher.On) > 0 && len(matcher.Ignoring) > 0 {
+		return nil, fmt.Errorf("join for refId %v can not have both on and ignoring labels", refID)
+	}
+	return &JoinCommand{
+		Left:     left,
+		Right:    right,
+		Operator: operator,
+		Mode:     mode,
+		Matcher:  matcher,
+		refID:    refID,
+	}, nil
+}
+
+// UnmarshalJoinCommand creates a JoinCommand from Grafana's frontend query.
+func UnmarshalJoinCommand(rn *rawNode) (*JoinCommand, error) {
+	left, err := getJoinVar(rn, "left")
+	if err != nil {
+		return nil, err
+	}
+	right, err := getJoinVar(rn, "right")
+	if err != nil {
+		return nil, err
+	}
+
+	rawOperator, ok := rn.Query["operator"]
+	if !ok {
+		return nil, fmt.Errorf("no operator specified for join for refId %v", rn.RefID)
+	}
+	operator, ok := rawOperator.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected join operator to be a string, got %T for refId %v", rawOperator, rn.RefID)
+	}
+
+	rawMode := ""
+	if m, ok := rn.Query["mode"]; ok {
+		rawMode, ok = m.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected join mode to be a string, got %T for refId %v", m, rn.RefID)
+		}
+	}
+	mode, err := mathexp.ParseJoinMode(rawMode)
+	if err != nil {
+		return nil, fmt.Errorf("%w for refId %v", err, rn.RefID)
+	}
+
+	matcher := mathexp.JoinMatcher{}
+	if matcher.On, err = getJoinLabels(rn, "on"); err != nil {
+		return nil, err
+	}
+	if matcher.Ignoring, err = getJoinLabels(rn, "ignoring"); err != nil {
+		return nil, err
+	}
+
+	return NewJoinCommand(rn.RefID, left, right, operator, mode, matcher)
+}
+
+func getJoinVar(rn *rawNode, key string) (string, error) {
+	rawVar, ok := rn.Query[key]
+	if !ok {
+		return "", fmt.Errorf("no %s variable specified for join for refId %v", key, rn.RefID)
+	}
+	v, ok := rawVar.(string)
+	if !ok {
+		return "", fmt.Errorf("expected join %s variable to be a string, got %T for refId %v", key, rawVar, rn.RefID)
+	}
+	return strings.TrimPrefix(v, "$"), nil
+}
+
+func getJoinLabels(rn *rawNode, key string) ([]string, error) {
+	rawLabels, ok := rn.Query[key]
+	if !ok || rawLabels == nil {
+		return nil, nil
+	}
+	list, ok := rawLabels.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected join %s to be a list of label names, got %T for refId %v", key, rawLabels, rn.RefID)
+	}
+	labels := make([]string, 0, len(list))
+	for _, l := range list {
+		s, ok := l.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected join %s label name to be a string, got %T for refId %v", key, l, rn.RefID)
+		}
+		labels = append(labels, s)
+	}
+	return labels, nil
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (gj *JoinCommand) NeedsVars() []string {
+	return []string{gj.Left, gj.Right}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (gj *JoinCommand) Execute(ctx context.Context, vars mathexp.Vars) (mathexp.Results, error) {
+	return mathexp.Join(gj.refID, gj.Operator, gj.Mode, gj.Matcher, vars[gj.Left], vars[gj.Right])
+}
+
 // CommandType is the type of the expression command.
 type CommandType int
 
@@ -276,6 +407,10 @@ const (
 	TypeResample
 	// TypeClassicConditions is the CMDType for the classic condition operation.
 	TypeClassicConditions
+	// TypeJoin is the CMDType for joining two variables by labels.
+	TypeJoin
+	// TypeThreshold is the CMDType for checking if values meet a threshold.
+	TypeThreshold
 )
 
 func (gt CommandType) String() string {
@@ -288,6 +423,10 @@ func (gt CommandType) String() string {
 		return "resample"
 	case TypeClassicConditions:
 		return "classic_conditions"
+	case TypeJoin:
+		return "join"
+	case TypeThreshold:
+		return "threshold"
 	default:
 		return "unknown"
 	}
@@ -304,6 +443,10 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeResample, nil
 	case "classic_conditions":
 		return TypeClassicConditions, nil
+	case "join":
+		return TypeJoin, nil
+	case "threshold":
+		return TypeThreshold, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
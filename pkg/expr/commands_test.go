@@ -89,3 +89,143 @@ func Test_UnmarshalReduceCommand_Settings(t *testing.T) {
 		})
 	}
 }
+
+func Test_UnmarshalReduceCommand_Percentile(t *testing.T) {
+	var tests = []struct {
+		name            string
+		query           string
+		isError         bool
+		expectedReducer string
+	}{
+		{
+			name:            "percentile with parameter",
+			query:           `{ "expression" : "$A", "reducer": "percentile", "parameter": 95 }`,
+			expectedReducer: "p95",
+		},
+		{
+			name:            "percentile with fractional parameter",
+			query:           `{ "expression" : "$A", "reducer": "percentile", "parameter": 99.9 }`,
+			expectedReducer: "p99.9",
+		},
+		{
+			name:            "short form percentile",
+			query:           `{ "expression" : "$A", "reducer": "p90" }`,
+			expectedReducer: "p90",
+		},
+		{
+			name:    "error when percentile has no parameter",
+			query:   `{ "expression" : "$A", "reducer": "percentile" }`,
+			isError: true,
+		},
+		{
+			name:    "error when parameter is not a number",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "parameter": "95" }`,
+			isError: true,
+		},
+		{
+			name:    "error when percentile is out of range",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "parameter": 120 }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalReduceCommand(&rawNode{
+				RefID: "A",
+				Query: qmap,
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedReducer, cmd.Reducer)
+		})
+	}
+}
+
+func Test_UnmarshalJoinCommand(t *testing.T) {
+	var tests = []struct {
+		name     string
+		query    string
+		isError  bool
+		expected *JoinCommand
+	}{
+		{
+			name:  "join on labels",
+			query: `{ "left": "$A", "right": "$B", "operator": "/", "mode": "left", "on": ["service"] }`,
+			expected: &JoinCommand{
+				Left:     "A",
+				Right:    "B",
+				Operator: "/",
+				Mode:     mathexp.JoinLeft,
+				Matcher:  mathexp.JoinMatcher{On: []string{"service"}},
+				refID:    "C",
+			},
+		},
+		{
+			name:  "join ignoring labels defaults to inner",
+			query: `{ "left": "A", "right": "B", "operator": "-", "ignoring": ["job"] }`,
+			expected: &JoinCommand{
+				Left:     "A",
+				Right:    "B",
+				Operator: "-",
+				Mode:     mathexp.JoinInner,
+				Matcher:  mathexp.JoinMatcher{Ignoring: []string{"job"}},
+				refID:    "C",
+			},
+		},
+		{
+			name:    "error when both on and ignoring are set",
+			query:   `{ "left": "A", "right": "B", "operator": "-", "on": ["a"], "ignoring": ["b"] }`,
+			isError: true,
+		},
+		{
+			name:    "error when operator is unknown",
+			query:   `{ "left": "A", "right": "B", "operator": "^" }`,
+			isError: true,
+		},
+		{
+			name:    "error when mode is unknown",
+			query:   `{ "left": "A", "right": "B", "operator": "+", "mode": "cross" }`,
+			isError: true,
+		},
+		{
+			name:    "error when right is missing",
+			query:   `{ "left": "A", "operator": "+" }`,
+			isError: true,
+		},
+		{
+			name:    "error when labels are not strings",
+			query:   `{ "left": "A", "right": "B", "operator": "+", "on": [1] }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalJoinCommand(&rawNode{
+				RefID: "C",
+				Query: qmap,
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, cmd)
+			require.Equal(t, []string{"A", "B"}, cmd.NeedsVars())
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 
@@ -44,12 +45,28 @@ type Node interface {
 // DataPipeline is an ordered set of nodes returned from DPGraph processing.
 type DataPipeline []Node
 
+// NodeDurationObserver is called with the duration of the execution of each node of a pipeline.
+type NodeDurationObserver func(refID string, duration time.Duration)
+
+type nodeDurationObserverKey struct{}
+
+// WithNodeDurationObserver returns a context that makes the execution of pipelines report the duration
+// of each node to the observer.
+func WithNodeDurationObserver(ctx context.Context, observer NodeDurationObserver) context.Context {
+	return context.WithValue(ctx, nodeDurationObserverKey{}, observer)
+}
+
 // execute runs all the command/datasource requests in the pipeline return a
 // map of the refId of the of each command
 func (dp *DataPipeline) execute(c context.Context, s *Service) (mathexp.Vars, error) {
+	observer, _ := c.Value(nodeDurationObserverKey{}).(NodeDurationObserver)
 	vars := make(mathexp.Vars)
 	for _, node := range *dp {
+		start := time.Now()
 		res, err := node.Execute(c, vars, s)
+		if observer != nil {
+			observer(node.RefID(), time.Since(start))
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -250,74 +250,82 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	}
 	unions := union(ar, br)
 	for _, uni := range unions {
-		var value Value
-		switch at := uni.A.(type) {
+		value, err := e.biValues(uni.Labels, node.OpStr, uni.A, uni.B)
+		if err != nil {
+			return res, err
+		}
+		res.Values = append(res.Values, value)
+	}
+	return res, nil
+}
+
+// biValues performs the binary operation between two values of any type,
+// and returns a value with the given labels.
+func (e *State) biValues(labels data.Labels, op string, a, b Value) (Value, error) {
+	var value Value
+	var err error
+	switch at := a.(type) {
+	case Scalar:
+		aFloat := at.GetFloat64Value()
+		switch bt := b.(type) {
+		// Scalar op Scalar
 		case Scalar:
-			aFloat := at.GetFloat64Value()
-			switch bt := uni.B.(type) {
-			// Scalar op Scalar
-			case Scalar:
-				bFloat := bt.GetFloat64Value()
-				if aFloat == nil || bFloat == nil {
-					value = NewScalar(e.RefID, nil)
-					break
-				}
-				f := math.NaN()
-				if aFloat != nil && bFloat != nil {
-					f, err = binaryOp(node.OpStr, *aFloat, *bFloat)
-					if err != nil {
-						return res, err
-					}
+			bFloat := bt.GetFloat64Value()
+			if aFloat == nil || bFloat == nil {
+				value = NewScalar(e.RefID, nil)
+				break
+			}
+			f := math.NaN()
+			if aFloat != nil && bFloat != nil {
+				f, err = binaryOp(op, *aFloat, *bFloat)
+				if err != nil {
+					return value, err
 				}
-				value = NewScalar(e.RefID, &f)
-			// Scalar op Scalar
-			case Number:
-				value, err = e.biScalarNumber(uni.Labels, node.OpStr, bt, aFloat, false)
-			// Scalar op Series
-			case Series:
-				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, bt, aFloat, false)
-			default:
-				return res, fmt.Errorf("not implemented: binary %v on %T and %T", node.OpStr, uni.A, uni.B)
 			}
+			value = NewScalar(e.RefID, &f)
+		// Scalar op Scalar
+		case Number:
+			value, err = e.biScalarNumber(labels, op, bt, aFloat, false)
+		// Scalar op Series
 		case Series:
-			switch bt := uni.B.(type) {
-			// Series Op Scalar
-			case Scalar:
-				bFloat := bt.GetFloat64Value()
-				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, at, bFloat, true)
-			// case Series Op Number
-			case Number:
-				bFloat := bt.GetFloat64Value()
-				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, at, bFloat, true)
-			// case Series op Series
-			case Series:
-				value, err = e.biSeriesSeries(uni.Labels, node.OpStr, at, bt)
-			default:
-				return res, fmt.Errorf("not implemented: binary %v on %T and %T", node.OpStr, uni.A, uni.B)
-			}
+			value, err = e.biSeriesNumber(labels, op, bt, aFloat, false)
+		default:
+			return value, fmt.Errorf("not implemented: binary %v on %T and %T", op, a, b)
+		}
+	case Series:
+		switch bt := b.(type) {
+		// Series Op Scalar
+		case Scalar:
+			bFloat := bt.GetFloat64Value()
+			value, err = e.biSeriesNumber(labels, op, at, bFloat, true)
+		// case Series Op Number
 		case Number:
-			aFloat := at.GetFloat64Value()
-			switch bt := uni.B.(type) {
-			case Scalar:
-				bFloat := bt.GetFloat64Value()
-				value, err = e.biScalarNumber(uni.Labels, node.OpStr, at, bFloat, true)
-			case Number:
-				bFloat := bt.GetFloat64Value()
-				value, err = e.biScalarNumber(uni.Labels, node.OpStr, at, bFloat, true)
-			case Series:
-				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, bt, aFloat, false)
-			default:
-				return res, fmt.Errorf("not implemented: binary %v on %T and %T", node.OpStr, uni.A, uni.B)
-			}
+			bFloat := bt.GetFloat64Value()
+			value, err = e.biSeriesNumber(labels, op, at, bFloat, true)
+		// case Series op Series
+		case Series:
+			value, err = e.biSeriesSeries(labels, op, at, bt)
 		default:
-			return res, fmt.Errorf("not implemented: binary %v on %T and %T", node.OpStr, uni.A, uni.B)
+			return value, fmt.Errorf("not implemented: binary %v on %T and %T", op, a, b)
 		}
-		if err != nil {
-			return res, err
+	case Number:
+		aFloat := at.GetFloat64Value()
+		switch bt := b.(type) {
+		case Scalar:
+			bFloat := bt.GetFloat64Value()
+			value, err = e.biScalarNumber(labels, op, at, bFloat, true)
+		case Number:
+			bFloat := bt.GetFloat64Value()
+			value, err = e.biScalarNumber(labels, op, at, bFloat, true)
+		case Series:
+			value, err = e.biSeriesNumber(labels, op, bt, aFloat, false)
+		default:
+			return value, fmt.Errorf("not implemented: binary %v on %T and %T", op, a, b)
 		}
-		res.Values = append(res.Values, value)
+	default:
+		return value, fmt.Errorf("not implemented: binary %v on %T and %T", op, a, b)
 	}
-	return res, nil
+	return value, err
 }
 
 // binaryOp performs a binary operations (e.g. A+B or A>B) on two
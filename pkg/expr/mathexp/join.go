@@ -0,0 +1,157 @@
+package mathexp
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// JoinMode controls which items are kept when joining two results.
+type JoinMode string
+
+const (
+	// JoinInner keeps only the items that have a match on both sides.
+	JoinInner JoinMode = "inner"
+	// JoinLeft keeps all the items of the left side. Items without a match on
+	// the right side have null values.
+	JoinLeft JoinMode = "left"
+	// JoinOuter keeps all the items of both sides. Items without a match on
+	// the other side have null values.
+	JoinOuter JoinMode = "outer"
+)
+
+// ParseJoinMode returns a JoinMode from its string representation.
+// An empty string is the same as JoinInner.
+func ParseJoinMode(s string) (JoinMode, error) {
+	switch JoinMode(s) {
+	case "", JoinInner:
+		return JoinInner, nil
+	case JoinLeft:
+		return JoinLeft, nil
+	case JoinOuter:
+		return JoinOuter, nil
+	default:
+		return "", fmt.Errorf("join mode %q is not supported. Supported only: [inner,left,outer]", s)
+	}
+}
+
+// JoinMatcher selects the labels that are used to match the items of two results.
+// If On is not empty only the labels in On are used, otherwise all labels except
+// those in Ignoring are used.
+type JoinMatcher struct {
+	On       []string
+	Ignoring []string
+}
+
+// Labels returns the subset of labels that is used to match an item.
+func (m JoinMatcher) Labels(l data.Labels) data.Labels {
+	matched := data.Labels{}
+	if len(m.On) > 0 {
+		for _, k := range m.On {
+			if v, ok := l[k]; ok {
+				matched[k] = v
+			}
+		}
+		return matched
+	}
+	ignored := make(map[string]struct{}, len(m.Ignoring))
+	for _, k := range m.Ignoring {
+		ignored[k] = struct{}{}
+	}
+	for k, v := range l {
+		if _, ok := ignored[k]; !ok {
+			matched[k] = v
+		}
+	}
+	return matched
+}
+
+// ValidateBinaryOp returns an error if op is not a binary operator supported by math expressions.
+func ValidateBinaryOp(op string) error {
+	_, err := binaryOp(op, 1, 1)
+	return err
+}
+
+// Join matches the items of a and b on the labels selected by matcher and performs the binary
+// operation op between each matching pair. Each side must have at most one item for each set
+// of matching labels. The resulting items are labeled with the matching labels only.
+// Depending on mode, items without a match on the other side are dropped or kept with null values.
+func Join(refID, op string, mode JoinMode, matcher JoinMatcher, a, b Results) (Results, error) {
+	res := Results{Values: Values{}}
+	e := &State{RefID: refID}
+
+	aByKey, aKeys, err := groupByMatchedLabels(matcher, a, "left")
+	if err != nil {
+		return res, err
+	}
+	bByKey, bKeys, err := groupByMatchedLabels(matcher, b, "right")
+	if err != nil {
+		return res, err
+	}
+
+	for _, key := range aKeys {
+		aVal := aByKey[key]
+		labels := matcher.Labels(aVal.GetLabels())
+		bVal, ok := bByKey[key]
+		if !ok {
+			if mode == JoinInner {
+				continue
+			}
+			res.Values = append(res.Values, nullValueLike(refID, labels, aVal))
+			continue
+		}
+		value, err := e.biValues(labels, op, aVal, bVal)
+		if err != nil {
+			return res, err
+		}
+		res.Values = append(res.Values, value)
+	}
+
+	if mode == JoinOuter {
+		for _, key := range bKeys {
+			if _, ok := aByKey[key]; ok {
+				continue
+			}
+			bVal := bByKey[key]
+			res.Values = append(res.Values, nullValueLike(refID, matcher.Labels(bVal.GetLabels()), bVal))
+		}
+	}
+	return res, nil
+}
+
+// groupByMatchedLabels indexes the values of r by their matching labels. The keys are also
+// returned in order, so the result of the join is deterministic.
+func groupByMatchedLabels(matcher JoinMatcher, r Results, side string) (map[string]Value, []string, error) {
+	byKey := make(map[string]Value, len(r.Values))
+	keys := make([]string, 0, len(r.Values))
+	for _, v := range r.Values {
+		key := matcher.Labels(v.GetLabels()).String()
+		if _, ok := byKey[key]; ok {
+			return nil, nil, fmt.Errorf("found duplicate items for the matching labels {%s} on the %s side of the join", key, side)
+		}
+		byKey[key] = v
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return byKey, keys, nil
+}
+
+// nullValueLike returns a value of the same type as v where all values are null.
+// Series keep their timestamps.
+func nullValueLike(refID string, labels data.Labels, v Value) Value {
+	switch t := v.(type) {
+	case Series:
+		s := NewSeries(refID, labels, t.Len())
+		for i := 0; i < t.Len(); i++ {
+			s.SetPoint(i, t.GetTime(i), nil)
+		}
+		return s
+	case Number:
+		n := NewNumber(refID, labels)
+		n.SetValue(nil)
+		return n
+	default:
+		return NewScalar(refID, nil)
+	}
+}
@@ -0,0 +1,140 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoin(t *testing.T) {
+	errors := Results{
+		[]Value{
+			makeNumber("", data.Labels{"service": "api", "job": "prom"}, float64Pointer(5)),
+			makeNumber("", data.Labels{"service": "web", "job": "prom"}, float64Pointer(1)),
+		},
+	}
+	requests := Results{
+		[]Value{
+			makeNumber("", data.Labels{"service": "api", "source": "sql"}, float64Pointer(100)),
+			makeNumber("", data.Labels{"service": "db", "source": "sql"}, float64Pointer(50)),
+		},
+	}
+
+	var tests = []struct {
+		name    string
+		mode    JoinMode
+		matcher JoinMatcher
+		a, b    Results
+		errIs   require.ErrorAssertionFunc
+		results Results
+	}{
+		{
+			name:    "inner join on labels",
+			mode:    JoinInner,
+			matcher: JoinMatcher{On: []string{"service"}},
+			a:       errors,
+			b:       requests,
+			errIs:   require.NoError,
+			results: Results{
+				[]Value{
+					makeNumber("", data.Labels{"service": "api"}, float64Pointer(0.05)),
+				},
+			},
+		},
+		{
+			name:    "left join keeps unmatched left items as null",
+			mode:    JoinLeft,
+			matcher: JoinMatcher{On: []string{"service"}},
+			a:       errors,
+			b:       requests,
+			errIs:   require.NoError,
+			results: Results{
+				[]Value{
+					makeNumber("", data.Labels{"service": "api"}, float64Pointer(0.05)),
+					makeNumber("", data.Labels{"service": "web"}, nil),
+				},
+			},
+		},
+		{
+			name:    "outer join keeps unmatched items of both sides as null",
+			mode:    JoinOuter,
+			matcher: JoinMatcher{On: []string{"service"}},
+			a:       errors,
+			b:       requests,
+			errIs:   require.NoError,
+			results: Results{
+				[]Value{
+					makeNumber("", data.Labels{"service": "api"}, float64Pointer(0.05)),
+					makeNumber("", data.Labels{"service": "web"}, nil),
+					makeNumber("", data.Labels{"service": "db"}, nil),
+				},
+			},
+		},
+		{
+			name:    "join ignoring labels",
+			mode:    JoinInner,
+			matcher: JoinMatcher{Ignoring: []string{"job", "source"}},
+			a:       errors,
+			b:       requests,
+			errIs:   require.NoError,
+			results: Results{
+				[]Value{
+					makeNumber("", data.Labels{"service": "api"}, float64Pointer(0.05)),
+				},
+			},
+		},
+		{
+			name:    "join series with number",
+			mode:    JoinInner,
+			matcher: JoinMatcher{On: []string{"service"}},
+			a: Results{
+				[]Value{
+					makeSeries("", data.Labels{"service": "api", "job": "prom"},
+						tp{time.Unix(5, 0), float64Pointer(10)},
+						tp{time.Unix(10, 0), nil}),
+				},
+			},
+			b:     requests,
+			errIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", data.Labels{"service": "api"},
+						tp{time.Unix(5, 0), float64Pointer(0.1)},
+						tp{time.Unix(10, 0), nil}),
+				},
+			},
+		},
+		{
+			name:    "duplicate matching labels should error",
+			mode:    JoinInner,
+			matcher: JoinMatcher{On: []string{"job"}},
+			a:       errors,
+			b:       requests,
+			errIs:   require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Join("", "/", tt.mode, tt.matcher, tt.a, tt.b)
+			tt.errIs(t, err)
+			if err == nil {
+				require.Equal(t, tt.results, res)
+			}
+		})
+	}
+}
+
+func TestParseJoinMode(t *testing.T) {
+	mode, err := ParseJoinMode("")
+	require.NoError(t, err)
+	require.Equal(t, JoinInner, mode)
+
+	mode, err = ParseJoinMode("outer")
+	require.NoError(t, err)
+	require.Equal(t, JoinOuter, mode)
+
+	_, err = ParseJoinMode("cross")
+	require.Error(t, err)
+}
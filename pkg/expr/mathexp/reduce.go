@@ -3,6 +3,8 @@ package mathexp
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -81,7 +83,118 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// Diff returns the difference between the last and the first value of the series.
+func Diff(fv *Float64Field) *float64 {
+	nan := math.NaN()
+	if fv.Len() == 0 {
+		return &nan
+	}
+	first, last := fv.GetValue(0), fv.GetValue(fv.Len()-1)
+	if first == nil || last == nil {
+		return &nan
+	}
+	f := *last - *first
+	return &f
+}
+
+// Range returns the difference between the largest and the smallest value of the series.
+func Range(fv *Float64Field) *float64 {
+	min, max := Min(fv), Max(fv)
+	f := *max - *min
+	return &f
+}
+
+// CountNonNull returns the number of values in the series that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			continue
+		}
+		f++
+	}
+	return &f
+}
+
+// StdDev returns the population standard deviation of the values in the series.
+func StdDev(fv *Float64Field) *float64 {
+	nan := math.NaN()
+	if fv.Len() == 0 {
+		return &nan
+	}
+	mean := Avg(fv)
+	if math.IsNaN(*mean) {
+		return mean
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - *mean
+		sum += d * d
+	}
+	f := math.Sqrt(sum / float64(fv.Len()))
+	return &f
+}
+
+// Median returns the middle value of the series, which is the 50th percentile.
+func Median(fv *Float64Field) *float64 {
+	return Percentile(50)(fv)
+}
+
+// Percentile returns a reducer for the p-th percentile (0 <= p <= 100) of the values in the series.
+// When the percentile falls between two values, the result is linearly interpolated between them.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		nan := math.NaN()
+		if fv.Len() == 0 {
+			return &nan
+		}
+		values := make([]float64, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v := fv.GetValue(i)
+			if v == nil || math.IsNaN(*v) {
+				return &nan
+			}
+			values = append(values, *v)
+		}
+		sort.Float64s(values)
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
+// ParsePercentileReducer returns the percentile of a reducer name in the form of "p95" or "p99.9".
+// The second return value is false if the name is not a percentile reducer.
+func ParsePercentileReducer(rFunc string) (float64, bool) {
+	if len(rFunc) < 2 || (rFunc[0] != 'p' && rFunc[0] != 'P') {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(rFunc[1:], 64)
+	if err != nil || math.IsNaN(p) {
+		return 0, false
+	}
+	return p, true
+}
+
 func GetReduceFunc(rFunc string) (ReducerFunc, error) {
+	if p, ok := ParsePercentileReducer(rFunc); ok {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("percentile %v of reduction %v must be between 0 and 100", p, rFunc)
+		}
+		return Percentile(p), nil
+	}
 	switch strings.ToLower(rFunc) {
 	case "sum":
 		return Sum, nil
@@ -95,6 +208,18 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 		return Count, nil
 	case "last":
 		return Last, nil
+	case "first":
+		return First, nil
+	case "median":
+		return Median, nil
+	case "stddev":
+		return StdDev, nil
+	case "diff":
+		return Diff, nil
+	case "range":
+		return Range, nil
+	case "count_non_null":
+		return CountNonNull, nil
 	default:
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
@@ -514,3 +514,64 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesReduceStatistics(t *testing.T) {
+	fiveValues := makeSeries("temp", nil,
+		tp{time.Unix(5, 0), float64Pointer(4)},
+		tp{time.Unix(10, 0), float64Pointer(1)},
+		tp{time.Unix(15, 0), float64Pointer(5)},
+		tp{time.Unix(20, 0), float64Pointer(3)},
+		tp{time.Unix(25, 0), float64Pointer(2)},
+	)
+	withNil := seriesWithNil["A"].Values[0].(Series)
+	empty := seriesEmpty["A"].Values[0].(Series)
+
+	var tests = []struct {
+		name   string
+		red    string
+		series Series
+		errIs  require.ErrorAssertionFunc
+		result *float64
+	}{
+		{name: "median", red: "median", series: fiveValues, errIs: require.NoError, result: float64Pointer(3)},
+		{name: "median of even number of values", red: "median", series: aSeries["A"].Values[0].(Series), errIs: require.NoError, result: float64Pointer(1.5)},
+		{name: "p90 is interpolated", red: "p90", series: fiveValues, errIs: require.NoError, result: float64Pointer(4.6)},
+		{name: "p0 is the min", red: "p0", series: fiveValues, errIs: require.NoError, result: float64Pointer(1)},
+		{name: "p100 is the max", red: "P100", series: fiveValues, errIs: require.NoError, result: float64Pointer(5)},
+		{name: "percentile above 100 errors", red: "p101", series: fiveValues, errIs: require.Error},
+		{name: "stddev", red: "stddev", series: fiveValues, errIs: require.NoError, result: float64Pointer(math.Sqrt(2))},
+		{name: "first", red: "first", series: fiveValues, errIs: require.NoError, result: float64Pointer(4)},
+		{name: "diff", red: "diff", series: fiveValues, errIs: require.NoError, result: float64Pointer(-2)},
+		{name: "range", red: "range", series: fiveValues, errIs: require.NoError, result: float64Pointer(4)},
+		{name: "count_non_null", red: "count_non_null", series: withNil, errIs: require.NoError, result: float64Pointer(1)},
+		{name: "median with nil is NaN", red: "median", series: withNil, errIs: require.NoError, result: NaN},
+		{name: "stddev with nil is NaN", red: "stddev", series: withNil, errIs: require.NoError, result: NaN},
+		{name: "diff with nil is NaN", red: "diff", series: withNil, errIs: require.NoError, result: NaN},
+		{name: "range with nil is NaN", red: "range", series: withNil, errIs: require.NoError, result: NaN},
+		{name: "first of empty is NaN", red: "first", series: empty, errIs: require.NoError, result: NaN},
+		{name: "p95 of empty is NaN", red: "p95", series: empty, errIs: require.NoError, result: NaN},
+		{name: "count_non_null of empty", red: "count_non_null", series: empty, errIs: require.NoError, result: float64Pointer(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := tt.series.Reduce("", tt.red, nil)
+			tt.errIs(t, err)
+			if err != nil {
+				return
+			}
+			f := n.GetFloat64Value()
+			require.NotNil(t, f)
+			if math.IsNaN(*tt.result) {
+				require.True(t, math.IsNaN(*f))
+				return
+			}
+			require.InDelta(t, *tt.result, *f, 1e-9)
+		})
+	}
+
+	t.Run("median with dropNN ignores nil", func(t *testing.T) {
+		n, err := withNil.Reduce("", "median", DropNonNumber{})
+		require.NoError(t, err)
+		require.Equal(t, float64Pointer(2), n.GetFloat64Value())
+	})
+}
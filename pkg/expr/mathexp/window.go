@@ -0,0 +1,192 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// delta returns the difference between each point of a series and the point before it.
+// The first point of each series, and any point where it or its predecessor is null, is null.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "delta", varSet, func(s Series) (Series, error) {
+		return pairwise(e, s, func(_ time.Duration, prev, cur float64) float64 {
+			return cur - prev
+		}), nil
+	})
+}
+
+// increase is like delta but treats each series as a monotonic counter: when the value
+// decreases it is assumed the counter was reset, and the current value is used as the increase.
+func increase(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "increase", varSet, func(s Series) (Series, error) {
+		return pairwise(e, s, counterIncrease), nil
+	})
+}
+
+// rate returns the per-second increase of a counter series between each point and the point before it.
+// Counter resets are handled the same way as in increase.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "rate", varSet, func(s Series) (Series, error) {
+		return pairwise(e, s, func(d time.Duration, prev, cur float64) float64 {
+			if d <= 0 {
+				return math.NaN()
+			}
+			return counterIncrease(d, prev, cur) / d.Seconds()
+		}), nil
+	})
+}
+
+// cumsum returns the running total of each series. Null points stay null and
+// do not contribute to the total.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "cumsum", varSet, func(s Series) (Series, error) {
+		s = sortedCopy(e, s)
+		sum := float64(0)
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if f == nil {
+				continue
+			}
+			sum += *f
+			nF := sum
+			s.SetPoint(i, t, &nF)
+		}
+		return s, nil
+	})
+}
+
+// movingAvg returns the average of the last n points (including the current one) for each point of a series.
+// Null points are skipped when computing the average. If all points in the window are null, the result is null.
+func movingAvg(e *State, varSet Results, sizeRes Results) (Results, error) {
+	size, err := scalarArg("moving_avg", sizeRes)
+	if err != nil {
+		return Results{}, err
+	}
+	if size < 1 || size != math.Trunc(size) {
+		return Results{}, fmt.Errorf("moving_avg window must be a positive integer, got %v", size)
+	}
+	n := int(size)
+	return perSeries(e, "moving_avg", varSet, func(s Series) (Series, error) {
+		src := sortedCopy(e, s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			sum, count := float64(0), 0
+			for j := i - n + 1; j <= i; j++ {
+				if j < 0 {
+					continue
+				}
+				if f := src.GetValue(j); f != nil {
+					sum += *f
+					count++
+				}
+			}
+			var avg *float64
+			if count > 0 {
+				nF := sum / float64(count)
+				avg = &nF
+			}
+			newSeries.SetPoint(i, src.GetTime(i), avg)
+		}
+		return newSeries, nil
+	})
+}
+
+// shift moves every point of a series forward in time by the given duration (e.g. "1h").
+// A negative duration moves points backwards.
+func shift(e *State, varSet Results, rawDuration string) (Results, error) {
+	d, err := gtime.ParseDuration(rawDuration)
+	if err != nil {
+		return Results{}, fmt.Errorf("shift: failed to parse duration %q: %w", rawDuration, err)
+	}
+	return perSeries(e, "shift", varSet, func(s Series) (Series, error) {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(d), f)
+		}
+		return newSeries, nil
+	})
+}
+
+// perSeries applies seriesF to each Series in varSet. Functions that depend on the order
+// of points can only operate on series, so any other value type results in an error.
+func perSeries(e *State, name string, varSet Results, seriesF func(s Series) (Series, error)) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		s, ok := res.(Series)
+		if !ok {
+			return newRes, fmt.Errorf("%s can only be applied to a series, got type %v", name, res.Type())
+		}
+		newSeries, err := seriesF(s)
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newSeries)
+	}
+	return newRes, nil
+}
+
+// pairwise returns a copy of the series, sorted by time, where each point is the result of
+// pointF applied to the point and the point before it. pointF is not called if either value is null,
+// and the resulting point is null instead. The first point is always null.
+func pairwise(e *State, s Series, pointF func(d time.Duration, prev, cur float64) float64) Series {
+	src := sortedCopy(e, s)
+	newSeries := NewSeries(e.RefID, s.GetLabels(), src.Len())
+	for i := 0; i < src.Len(); i++ {
+		t, cur := src.GetPoint(i)
+		if i == 0 {
+			newSeries.SetPoint(i, t, nil)
+			continue
+		}
+		prevT, prev := src.GetPoint(i - 1)
+		if cur == nil || prev == nil {
+			newSeries.SetPoint(i, t, nil)
+			continue
+		}
+		nF := pointF(t.Sub(prevT), *prev, *cur)
+		newSeries.SetPoint(i, t, &nF)
+	}
+	return newSeries
+}
+
+// counterIncrease returns the increase between two samples of a counter, assuming a reset
+// if the current value is lower than the previous one.
+func counterIncrease(_ time.Duration, prev, cur float64) float64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
+
+// sortedCopy returns a copy of the series sorted by time from oldest to newest,
+// so the input series, which may be shared with other expressions, is not mutated.
+func sortedCopy(e *State, s Series) Series {
+	newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, f := s.GetPoint(i)
+		var nF *float64
+		if f != nil {
+			v := *f
+			nF = &v
+		}
+		newSeries.SetPoint(i, t, nF)
+	}
+	newSeries.SortByTime(false)
+	return newSeries
+}
+
+// scalarArg returns the non-null value of a scalar function argument.
+func scalarArg(name string, res Results) (float64, error) {
+	if len(res.Values) != 1 || res.Values[0].Type() != parse.TypeScalar {
+		return 0, fmt.Errorf("%s expects a single scalar argument", name)
+	}
+	f := res.Values[0].(Scalar).GetFloat64Value()
+	if f == nil {
+		return 0, fmt.Errorf("%s expects a non-null scalar argument", name)
+	}
+	return *f, nil
+}
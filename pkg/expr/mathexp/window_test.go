@@ -0,0 +1,203 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowFuncs(t *testing.T) {
+	counter := Vars{
+		"A": Results{
+			[]Value{
+				makeSeries("", nil,
+					tp{time.Unix(20, 0), float64Pointer(30)},
+					tp{time.Unix(0, 0), float64Pointer(0)},
+					tp{time.Unix(10, 0), float64Pointer(10)},
+					tp{time.Unix(30, 0), nil},
+					tp{time.Unix(40, 0), float64Pointer(5)},
+					tp{time.Unix(50, 0), float64Pointer(25)}),
+			},
+		},
+	}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "delta sorts the series and keeps nulls",
+			expr:      "delta($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), nil},
+						tp{time.Unix(10, 0), float64Pointer(10)},
+						tp{time.Unix(20, 0), float64Pointer(20)},
+						tp{time.Unix(30, 0), nil},
+						tp{time.Unix(40, 0), nil},
+						tp{time.Unix(50, 0), float64Pointer(20)}),
+				},
+			},
+		},
+		{
+			name: "increase handles counter resets",
+			expr: "increase($A)",
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeSeries("", nil,
+							tp{time.Unix(0, 0), float64Pointer(10)},
+							tp{time.Unix(10, 0), float64Pointer(40)},
+							tp{time.Unix(20, 0), float64Pointer(5)}),
+					},
+				},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), nil},
+						tp{time.Unix(10, 0), float64Pointer(30)},
+						tp{time.Unix(20, 0), float64Pointer(5)}),
+				},
+			},
+		},
+		{
+			name: "rate is the per second increase",
+			expr: "rate($A)",
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeSeries("", nil,
+							tp{time.Unix(0, 0), float64Pointer(10)},
+							tp{time.Unix(10, 0), float64Pointer(40)},
+							tp{time.Unix(30, 0), float64Pointer(20)}),
+					},
+				},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), nil},
+						tp{time.Unix(10, 0), float64Pointer(3)},
+						tp{time.Unix(30, 0), float64Pointer(1)}),
+				},
+			},
+		},
+		{
+			name:      "cumsum skips nulls",
+			expr:      "cumsum($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(0)},
+						tp{time.Unix(10, 0), float64Pointer(10)},
+						tp{time.Unix(20, 0), float64Pointer(40)},
+						tp{time.Unix(30, 0), nil},
+						tp{time.Unix(40, 0), float64Pointer(45)},
+						tp{time.Unix(50, 0), float64Pointer(70)}),
+				},
+			},
+		},
+		{
+			name:      "moving_avg ignores nulls in the window",
+			expr:      "moving_avg($A, 2)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(0)},
+						tp{time.Unix(10, 0), float64Pointer(5)},
+						tp{time.Unix(20, 0), float64Pointer(20)},
+						tp{time.Unix(30, 0), float64Pointer(30)},
+						tp{time.Unix(40, 0), float64Pointer(5)},
+						tp{time.Unix(50, 0), float64Pointer(15)}),
+				},
+			},
+		},
+		{
+			name:      "moving_avg with invalid window should error",
+			expr:      "moving_avg($A, 1.5)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name: "shift moves points in time",
+			expr: `shift($A, "1m")`,
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeSeries("", nil,
+							tp{time.Unix(0, 0), float64Pointer(1)},
+							tp{time.Unix(10, 0), nil}),
+					},
+				},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{
+				[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(60, 0), float64Pointer(1)},
+						tp{time.Unix(70, 0), nil}),
+				},
+			},
+		},
+		{
+			name:      "shift with invalid duration should error",
+			expr:      `shift($A, "soon")`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name: "rate on number should error",
+			expr: "rate($A)",
+			vars: Vars{
+				"A": Results{
+					[]Value{
+						makeNumber("", nil, float64Pointer(1)),
+					},
+				},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:     "rate on scalar should error",
+			expr:     "rate(1)",
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars)
+				tt.execErrIs(t, err)
+				if tt.results.Values != nil {
+					require.Equal(t, tt.results, res)
+				}
+			}
+		})
+	}
+}
@@ -89,6 +89,15 @@ func TestService(t *testing.T) {
 	if diff := cmp.Diff(expect, res, options...); diff != "" {
 		t.Errorf("Result mismatch (-want +got):\n%s", diff)
 	}
+
+	var observed []string
+	ctx := WithNodeDurationObserver(context.Background(), func(refID string, duration time.Duration) {
+		require.GreaterOrEqual(t, duration, time.Duration(0))
+		observed = append(observed, refID)
+	})
+	_, err = s.ExecutePipeline(ctx, pl)
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, observed)
 }
 
 func fp(f float64) *float64 {
@@ -0,0 +1,205 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// ThresholdCommand is an expression command that compares each number, or each point of
+// a series, against a threshold and returns 1 if the threshold is met and 0 otherwise.
+// If a recovery threshold is set, values keep returning 1 after the threshold was met until
+// the recovery threshold is met (hysteresis). The labels of the dimensions that met the threshold
+// in a previous evaluation are passed in LoadedDimensions. They may contain more labels than the
+// dimension itself, such as the labels of an alert instance. If States is set, the results are
+// mapped to the names of the states for display, and their values stay 0 and 1.
+type ThresholdCommand struct {
+	ReferenceVar     string
+	Trigger          ThresholdEvaluator
+	Recovery         *ThresholdEvaluator
+	States           *ThresholdStates
+	LoadedDimensions []data.Labels
+	refID            string
+}
+
+// ThresholdStates are the names of the results of a threshold, e.g. "Critical" and "OK".
+type ThresholdStates struct {
+	// Met is the name of the result 1, when the threshold is met.
+	Met string `json:"met"`
+	// NotMet is the name of the result 0, when the threshold is not met.
+	NotMet string `json:"notMet"`
+}
+
+// ThresholdEvaluator is a threshold function and its parameters, e.g. "gt" and [80].
+type ThresholdEvaluator struct {
+	Type   string    `json:"type"`
+	Params []float64 `json:"params"`
+}
+
+// ThresholdCommandJSON is the JSON model of a ThresholdCommand.
+type ThresholdCommandJSON struct {
+	Expression        string              `json:"expression"`
+	Evaluator         ThresholdEvaluator  `json:"evaluator"`
+	RecoveryEvaluator *ThresholdEvaluator `json:"recoveryEvaluator,omitempty"`
+	States            *ThresholdStates    `json:"states,omitempty"`
+	// LoadedDimensions contains the labels of the numbers or series that met the threshold in the
+	// previous evaluation. It is not set by users but by the alerting scheduler.
+	LoadedDimensions []data.Labels `json:"loadedDimensions,omitempty"`
+}
+
+// NewThresholdCommand creates a new ThresholdCommand.
+func NewThresholdCommand(refID, referenceVar string, trigger ThresholdEvaluator, recovery *ThresholdEvaluator, states *ThresholdStates, loaded []data.Labels) (*ThresholdCommand, error) {
+	if err := trigger.validate(); err != nil {
+		return nil, fmt.Errorf("invalid threshold for refId %v: %w", refID, err)
+	}
+	if recovery != nil {
+		if err := recovery.validate(); err != nil {
+			return nil, fmt.Errorf("invalid recovery threshold for refId %v: %w", refID, err)
+		}
+	}
+	if states != nil && (states.Met == "" || states.NotMet == "") {
+		return nil, fmt.Errorf("invalid states for refId %v: both states must have a name", refID)
+	}
+	return &ThresholdCommand{
+		ReferenceVar:     referenceVar,
+		Trigger:          trigger,
+		Recovery:         recovery,
+		States:           states,
+		LoadedDimensions: loaded,
+		refID:            refID,
+	}, nil
+}
+
+// UnmarshalThresholdCommand creates a ThresholdCommand from Grafana's frontend query.
+func UnmarshalThresholdCommand(rn *rawNode) (*ThresholdCommand, error) {
+	jsonFromM, err := json.Marshal(rn.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remarshal threshold command body: %w", err)
+	}
+	var tj ThresholdCommandJSON
+	if err = json.Unmarshal(jsonFromM, &tj); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal remarshaled threshold command body: %w", err)
+	}
+	if tj.Expression == "" {
+		return nil, fmt.Errorf("no variable specified to reference for refId %v", rn.RefID)
+	}
+	referenceVar := strings.TrimPrefix(tj.Expression, "$")
+	return NewThresholdCommand(rn.RefID, referenceVar, tj.Evaluator, tj.RecoveryEvaluator, tj.States, tj.LoadedDimensions)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (tc *ThresholdCommand) NeedsVars() []string {
+	return []string{tc.ReferenceVar}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (tc *ThresholdCommand) Execute(ctx context.Context, vars mathexp.Vars) (mathexp.Results, error) {
+	newRes := mathexp.Results{}
+	for _, val := range vars[tc.ReferenceVar].Values {
+		firing := tc.isLoaded(val.GetLabels())
+		switch v := val.(type) {
+		case mathexp.Number:
+			n := mathexp.NewNumber(tc.refID, v.GetLabels())
+			n.SetValue(tc.next(&firing, v.GetFloat64Value()))
+			tc.mapStates(n.Frame.Fields[0])
+			newRes.Values = append(newRes.Values, n)
+		case mathexp.Scalar:
+			s := mathexp.NewScalar(tc.refID, tc.next(&firing, v.GetFloat64Value()))
+			tc.mapStates(s.Frame.Fields[0])
+			newRes.Values = append(newRes.Values, s)
+		case mathexp.Series:
+			// points are evaluated in order so the hysteresis applies over time within the series.
+			s := mathexp.NewSeries(tc.refID, v.GetLabels(), 0)
+			for i := 0; i < v.Len(); i++ {
+				t, f := v.GetPoint(i)
+				s.AppendPoint(t, tc.next(&firing, f))
+			}
+			tc.mapStates(s.Frame.Fields[1])
+			newRes.Values = append(newRes.Values, s)
+		default:
+			return newRes, fmt.Errorf("can only apply a threshold to type number or series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// next evaluates a value given whether the threshold is currently met, updates
+// firing and returns 1 if the threshold is met or 0 if not. Null values return
+// null and do not change the state.
+func (tc *ThresholdCommand) next(firing *bool, f *float64) *float64 {
+	if f == nil {
+		return nil
+	}
+	if *firing && tc.Recovery != nil {
+		*firing = !tc.Recovery.eval(*f)
+	} else {
+		*firing = tc.Trigger.eval(*f)
+	}
+	r := float64(0)
+	if *firing {
+		r = 1
+	}
+	return &r
+}
+
+// mapStates maps the values of the result field to the names of the states, if they are set.
+func (tc *ThresholdCommand) mapStates(f *data.Field) {
+	if tc.States == nil {
+		return
+	}
+	if f.Config == nil {
+		f.Config = &data.FieldConfig{}
+	}
+	f.Config.Mappings = data.ValueMappings{data.ValueMapper{
+		"0": {Text: tc.States.NotMet, Index: 0},
+		"1": {Text: tc.States.Met, Index: 1},
+	}}
+}
+
+func (tc *ThresholdCommand) isLoaded(labels data.Labels) bool {
+	for _, l := range tc.LoadedDimensions {
+		if l.Contains(labels) {
+			return true
+		}
+	}
+	return false
+}
+
+func (te ThresholdEvaluator) validate() error {
+	switch te.Type {
+	case "gt", "lt":
+		if len(te.Params) != 1 {
+			return fmt.Errorf("threshold '%v' requires 1 parameter", te.Type)
+		}
+	case "within_range", "outside_range":
+		if len(te.Params) != 2 {
+			return fmt.Errorf("threshold '%v' requires 2 parameters", te.Type)
+		}
+	default:
+		return fmt.Errorf("threshold type '%v' is not supported. Supported only: [gt,lt,within_range,outside_range]", te.Type)
+	}
+	return nil
+}
+
+// eval returns true if f meets the threshold. NaN never meets it.
+// Ranges are exclusive and the order of the range parameters does not matter.
+func (te ThresholdEvaluator) eval(f float64) bool {
+	switch te.Type {
+	case "gt":
+		return f > te.Params[0]
+	case "lt":
+		return f < te.Params[0]
+	case "within_range":
+		return (te.Params[0] < f && te.Params[1] > f) || (te.Params[1] < f && te.Params[0] > f)
+	case "outside_range":
+		return (te.Params[1] < f && te.Params[0] < f) || (te.Params[1] > f && te.Params[0] > f)
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+func TestThresholdCommand(t *testing.T) {
+	one, zero := float64(1), float64(0)
+	value := func(f float64) *float64 { return &f }
+
+	var tests = []struct {
+		name     string
+		trigger  ThresholdEvaluator
+		recovery *ThresholdEvaluator
+		loaded   []data.Labels
+		labels   data.Labels
+		input    *float64
+		expected *float64
+	}{
+		{name: "gt met", trigger: ThresholdEvaluator{Type: "gt", Params: []float64{80}}, input: value(81), expected: &one},
+		{name: "gt not met", trigger: ThresholdEvaluator{Type: "gt", Params: []float64{80}}, input: value(80), expected: &zero},
+		{name: "lt met", trigger: ThresholdEvaluator{Type: "lt", Params: []float64{10}}, input: value(9), expected: &one},
+		{name: "within_range met", trigger: ThresholdEvaluator{Type: "within_range", Params: []float64{10, 5}}, input: value(7), expected: &one},
+		{name: "within_range not met", trigger: ThresholdEvaluator{Type: "within_range", Params: []float64{5, 10}}, input: value(10), expected: &zero},
+		{name: "outside_range met", trigger: ThresholdEvaluator{Type: "outside_range", Params: []float64{5, 10}}, input: value(11), expected: &one},
+		{name: "null stays null", trigger: ThresholdEvaluator{Type: "gt", Params: []float64{80}}, input: nil, expected: nil},
+		{
+			name:     "loaded dimension keeps firing above recovery threshold",
+			trigger:  ThresholdEvaluator{Type: "gt", Params: []float64{80}},
+			recovery: &ThresholdEvaluator{Type: "lt", Params: []float64{70}},
+			loaded:   []data.Labels{{"host": "a", "alertname": "cpu"}},
+			labels:   data.Labels{"host": "a"},
+			input:    value(75),
+			expected: &one,
+		},
+		{
+			name:     "loaded dimension recovers below recovery threshold",
+			trigger:  ThresholdEvaluator{Type: "gt", Params: []float64{80}},
+			recovery: &ThresholdEvaluator{Type: "lt", Params: []float64{70}},
+			loaded:   []data.Labels{{"host": "a"}},
+			labels:   data.Labels{"host": "a"},
+			input:    value(69),
+			expected: &zero,
+		},
+		{
+			name:     "dimension that is not loaded uses trigger threshold",
+			trigger:  ThresholdEvaluator{Type: "gt", Params: []float64{80}},
+			recovery: &ThresholdEvaluator{Type: "lt", Params: []float64{70}},
+			loaded:   []data.Labels{{"host": "b"}},
+			labels:   data.Labels{"host": "a"},
+			input:    value(75),
+			expected: &zero,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := NewThresholdCommand("B", "A", tt.trigger, tt.recovery, nil, tt.loaded)
+			require.NoError(t, err)
+
+			vars := mathexp.Vars{
+				"A": mathexp.Results{Values: mathexp.Values{makeNumber(tt.labels, tt.input)}},
+			}
+			res, err := cmd.Execute(context.Background(), vars)
+			require.NoError(t, err)
+			require.Len(t, res.Values, 1)
+			require.Equal(t, tt.expected, res.Values[0].(mathexp.Number).GetFloat64Value())
+		})
+	}
+}
+
+func TestThresholdCommandSeriesHysteresis(t *testing.T) {
+	cmd, err := NewThresholdCommand("B", "A",
+		ThresholdEvaluator{Type: "gt", Params: []float64{80}},
+		&ThresholdEvaluator{Type: "lt", Params: []float64{70}},
+		nil, nil)
+	require.NoError(t, err)
+
+	input := mathexp.NewSeries("A", nil, 0)
+	expected := mathexp.NewSeries("B", nil, 0)
+	points := []struct {
+		in, out *float64
+	}{
+		{fp(75), fp(0)},
+		{fp(85), fp(1)},
+		{fp(75), fp(1)},
+		{nil, nil},
+		{fp(72), fp(1)},
+		{fp(65), fp(0)},
+		{fp(75), fp(0)},
+	}
+	for i, p := range points {
+		ts := time.Unix(int64(i), 0)
+		input.AppendPoint(ts, p.in)
+		expected.AppendPoint(ts, p.out)
+	}
+
+	res, err := cmd.Execute(context.Background(), mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{input}}})
+	require.NoError(t, err)
+	require.Equal(t, mathexp.Values{expected}, res.Values)
+}
+
+func TestThresholdCommandStates(t *testing.T) {
+	states := &ThresholdStates{Met: "Critical", NotMet: "OK"}
+	cmd, err := NewThresholdCommand("B", "A", ThresholdEvaluator{Type: "gt", Params: []float64{80}}, nil, states, nil)
+	require.NoError(t, err)
+
+	series := mathexp.NewSeries("A", nil, 0)
+	series.AppendPoint(time.Unix(0, 0), fp(75))
+	series.AppendPoint(time.Unix(1, 0), fp(85))
+	vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{
+		makeNumber(data.Labels{"host": "a"}, fp(85)),
+		mathexp.NewScalar("A", fp(75)),
+		series,
+	}}}
+	res, err := cmd.Execute(context.Background(), vars)
+	require.NoError(t, err)
+	require.Len(t, res.Values, 3)
+
+	expected := data.ValueMappings{data.ValueMapper{
+		"0": {Text: "OK", Index: 0},
+		"1": {Text: "Critical", Index: 1},
+	}}
+	number := res.Values[0].(mathexp.Number)
+	require.Equal(t, fp(1), number.GetFloat64Value())
+	require.Equal(t, expected, number.Frame.Fields[0].Config.Mappings)
+	scalar := res.Values[1].(mathexp.Scalar)
+	require.Equal(t, fp(0), scalar.GetFloat64Value())
+	require.Equal(t, expected, scalar.Frame.Fields[0].Config.Mappings)
+	s := res.Values[2].(mathexp.Series)
+	_, last := s.GetPoint(1)
+	require.Equal(t, fp(1), last)
+	require.Equal(t, expected, s.Frame.Fields[1].Config.Mappings)
+
+	_, err = NewThresholdCommand("B", "A", ThresholdEvaluator{Type: "gt", Params: []float64{80}}, nil, &ThresholdStates{Met: "Critical"}, nil)
+	require.Error(t, err)
+}
+
+func TestUnmarshalThresholdCommand(t *testing.T) {
+	var tests = []struct {
+		name    string
+		query   string
+		isError bool
+	}{
+		{name: "threshold", query: `{ "expression": "$A", "evaluator": { "type": "gt", "params": [80] } }`},
+		{name: "threshold with recovery and loaded dimensions", query: `{ "expression": "A", "evaluator": { "type": "gt", "params": [80] }, "recoveryEvaluator": { "type": "lt", "params": [70] }, "loadedDimensions": [{"host": "a"}] }`},
+		{name: "threshold with states", query: `{ "expression": "$A", "evaluator": { "type": "gt", "params": [80] }, "states": { "met": "Critical", "notMet": "OK" } }`},
+		{name: "error when a state has no name", query: `{ "expression": "$A", "evaluator": { "type": "gt", "params": [80] }, "states": { "met": "Critical" } }`, isError: true},
+		{name: "error when expression is missing", query: `{ "evaluator": { "type": "gt", "params": [80] } }`, isError: true},
+		{name: "error when type is unknown", query: `{ "expression": "$A", "evaluator": { "type": "eq", "params": [80] } }`, isError: true},
+		{name: "error when range has one parameter", query: `{ "expression": "$A", "evaluator": { "type": "within_range", "params": [80] } }`, isError: true},
+		{name: "error when recovery is invalid", query: `{ "expression": "$A", "evaluator": { "type": "gt", "params": [80] }, "recoveryEvaluator": { "type": "lt" } }`, isError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", Query: qmap})
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{"A"}, cmd.NeedsVars())
+		})
+	}
+}
+
+func makeNumber(labels data.Labels, f *float64) mathexp.Number {
+	n := mathexp.NewNumber("A", labels)
+	n.SetValue(f)
+	return n
+}
@@ -24,6 +24,7 @@ type DashboardSnapshot struct {
 
 	Dashboard          *simplejson.Json
 	DashboardEncrypted []byte
+	FramesEncrypted    []byte
 }
 
 // DashboardSnapshotDTO without dashboard map
@@ -76,6 +77,7 @@ type CreateDashboardSnapshotCommand struct {
 	UserId int64 `json:"-"`
 
 	DashboardEncrypted []byte `json:"-"`
+	FramesEncrypted    []byte `json:"-"`
 
 	Result *DashboardSnapshot
 }
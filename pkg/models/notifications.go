@@ -1,6 +1,9 @@
 package models
 
-import "errors"
+import (
+	"crypto/tls"
+	"errors"
+)
 
 var ErrInvalidEmailCode = errors.New("invalid or expired email code")
 var ErrSmtpNotEnabled = errors.New("SMTP not configured, check your grafana.ini config file's [smtp] section")
@@ -38,6 +41,8 @@ type SendWebhookSync struct {
 	HttpHeader  map[string]string
 	ContentType string
 	Validation  func(body []byte, statusCode int) error
+	// TLSConfig is the TLS configuration of the client, e.g. with a client certificate. The default configuration is used if it is nil.
+	TLSConfig *tls.Config
 }
 
 type SendResetPasswordEmailCommand struct {
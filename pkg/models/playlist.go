@@ -6,12 +6,31 @@ import (
 
 // Typed errors
 var (
-	ErrPlaylistNotFound = errors.New("Playlist not found")
+	ErrPlaylistNotFound          = errors.New("Playlist not found")
+	ErrPlaylistInvalidUID        = errors.New("uid contains illegal characters")
+	ErrPlaylistUIDTooLong        = errors.New("uid too long, max 40 characters")
+	ErrPlaylistNumericUID        = errors.New("uid cannot consist of digits only, as it is ambiguous with a playlist id")
+	ErrPlaylistWithSameUIDExists = errors.New("a playlist with the same uid already exists")
+)
+
+// Playlist item types
+const (
+	PlaylistItemTypeDashboardByID     = "dashboard_by_id"
+	PlaylistItemTypeDashboardByUID    = "dashboard_by_uid"
+	PlaylistItemTypeDashboardByTag    = "dashboard_by_tag"
+	PlaylistItemTypeDashboardBySearch = "dashboard_by_search"
+)
+
+// Tag match modes of playlist searches
+const (
+	PlaylistTagsMatchAnd = "and"
+	PlaylistTagsMatchOr  = "or"
 )
 
 // Playlist model
 type Playlist struct {
 	Id       int64  `json:"id"`
+	UID      string `json:"uid" xorm:"uid"`
 	Name     string `json:"name"`
 	Interval string `json:"interval"`
 	OrgId    int64  `json:"-"`
@@ -19,6 +38,7 @@ type Playlist struct {
 
 type PlaylistDTO struct {
 	Id       int64             `json:"id"`
+	UID      string            `json:"uid"`
 	Name     string            `json:"name"`
 	Interval string            `json:"interval"`
 	OrgId    int64             `json:"-"`
@@ -32,6 +52,7 @@ type PlaylistItemDTO struct {
 	Title      string `json:"title"`
 	Value      string `json:"value"`
 	Order      int    `json:"order"`
+	Interval   string `json:"interval,omitempty"`
 }
 
 type PlaylistItem struct {
@@ -41,6 +62,21 @@ type PlaylistItem struct {
 	Value      string
 	Order      int
 	Title      string
+	// Interval overrides the interval of the playlist for the dashboards of the item.
+	Interval string
+}
+
+// PlaylistSearch is the saved search of a playlist item of type dashboard_by_search, stored as
+// JSON in the value of the item.
+type PlaylistSearch struct {
+	Query string   `json:"query,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	// TagsMatch is "and" to match the dashboards that have all the tags, the default, or "or" to
+	// match the dashboards that have any of them.
+	TagsMatch  string   `json:"tagsMatch,omitempty"`
+	FolderUIDs []string `json:"folderUids,omitempty"`
+	Starred    bool     `json:"starred,omitempty"`
+	Limit      int64    `json:"limit,omitempty"`
 }
 
 type Playlists []*Playlist
@@ -60,6 +96,7 @@ type UpdatePlaylistCommand struct {
 }
 
 type CreatePlaylistCommand struct {
+	UID      string            `json:"uid"`
 	Name     string            `json:"name" binding:"Required"`
 	Interval string            `json:"interval"`
 	Items    []PlaylistItemDTO `json:"items"`
@@ -90,6 +127,12 @@ type GetPlaylistByIdQuery struct {
 	Result *Playlist
 }
 
+type GetPlaylistByUidQuery struct {
+	UID    string
+	OrgId  int64
+	Result *Playlist
+}
+
 type GetPlaylistItemsByIdQuery struct {
 	PlaylistId int64
 	Result     *[]PlaylistItem
@@ -6,7 +6,12 @@ import (
 )
 
 var (
-	ErrShortURLNotFound = errors.New("short URL not found")
+	ErrShortURLNotFound      = errors.New("short URL not found")
+	ErrShortURLAccessDenied  = errors.New("access denied to short URL")
+	ErrShortURLAliasExists   = errors.New("a short URL with the same alias already exists")
+	ErrShortURLInvalidAlias  = errors.New("alias contains illegal characters")
+	ErrShortURLAliasTooLong  = errors.New("alias too long, max 40 characters")
+	ErrShortURLInvalidExpiry = errors.New("the expiry of a short URL must be in the future")
 )
 
 type ShortUrl struct {
@@ -17,10 +22,46 @@ type ShortUrl struct {
 	CreatedBy  int64
 	CreatedAt  int64
 	LastSeenAt int64
+	// Alias is an optional human-readable name the short URL can also be resolved by.
+	Alias string
+	// ExpiresAt is the time the short URL stops redirecting, or 0 if it never expires.
+	ExpiresAt    int64
+	AccessCount  int64
+	LastReferrer string
 }
 
-type DeleteShortUrlCommand struct {
-	OlderThan time.Time
+// Expired returns true if the short URL has an expiry that is before now.
+func (s *ShortUrl) Expired(now time.Time) bool {
+	return s.ExpiresAt != 0 && s.ExpiresAt <= now.Unix()
+}
+
+// CanManage returns true if the user can update or delete the short URL: the users that created
+// it and the admins of its organization can manage it.
+func (s *ShortUrl) CanManage(user *SignedInUser) bool {
+	return s.OrgId == user.OrgId && (s.CreatedBy == user.UserId || user.HasRole(ROLE_ADMIN))
+}
+
+// CreateShortURLCommand creates a short URL for the path. Alias and ExpiresAt are optional.
+type CreateShortURLCommand struct {
+	Path      string
+	Alias     string
+	ExpiresAt int64
+}
 
-	NumDeleted int64
+// UpdateShortURLCommand sets the alias and the expiry of a short URL. Empty values remove them.
+type UpdateShortURLCommand struct {
+	UID       string
+	Alias     string
+	ExpiresAt int64
+
+	Result *ShortUrl
 }
+
+// GetShortURLsQuery returns the short URLs created by the user, most recent first.
+type GetShortURLsQuery struct {
+	Limit int
+	Page  int
+
+	Result []*ShortUrl
+}
+
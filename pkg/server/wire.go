@@ -194,6 +194,7 @@ var wireBasicSet = wire.NewSet(
 	oauthtoken.ProvideService,
 	wire.Bind(new(oauthtoken.OAuthTokenService), new(*oauthtoken.Service)),
 	tempo.ProvideService,
+	tempo.ProvideQueryDataServiceRegistration,
 	loki.ProvideService,
 	graphite.ProvideService,
 	prometheus.ProvideService,
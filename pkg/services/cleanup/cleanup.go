@@ -8,7 +8,6 @@ import (
 	"path"
 	"time"
 
-	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -18,12 +17,10 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
-	shortURLService shorturls.Service, store sqlstore.Store) *CleanUpService {
+func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService, store sqlstore.Store) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:               cfg,
 		ServerLockService: serverLockService,
-		ShortURLService:   shortURLService,
 		store:             store,
 		log:               log.New("cleanup"),
 	}
@@ -35,7 +32,6 @@ type CleanUpService struct {
 	store             sqlstore.Store
 	Cfg               *setting.Cfg
 	ServerLockService *serverlock.ServerLockService
-	ShortURLService   shorturls.Service
 }
 
 func (srv *CleanUpService) Run(ctx context.Context) error {
@@ -53,7 +49,6 @@ func (srv *CleanUpService) Run(ctx context.Context) error {
 			srv.deleteExpiredDashboardVersions(ctx)
 			srv.cleanUpOldAnnotations(ctxWithTimeout)
 			srv.expireOldUserInvites(ctx)
-			srv.deleteStaleShortURLs(ctx)
 			err := srv.ServerLockService.LockAndExecute(ctx, "delete old login attempts",
 				time.Minute*10, func(context.Context) {
 					srv.deleteOldLoginAttempts(ctx)
@@ -173,14 +168,3 @@ func (srv *CleanUpService) expireOldUserInvites(ctx context.Context) {
 		srv.log.Debug("Expired user invites", "rows affected", cmd.NumExpired)
 	}
 }
-
-func (srv *CleanUpService) deleteStaleShortURLs(ctx context.Context) {
-	cmd := models.DeleteShortUrlCommand{
-		OlderThan: time.Now().Add(-time.Hour * 24 * 7),
-	}
-	if err := srv.ShortURLService.DeleteStaleShortURLs(ctx, &cmd); err != nil {
-		srv.log.Error("Problem deleting stale short urls", "error", err.Error())
-	} else {
-		srv.log.Debug("Deleted short urls", "rows affected", cmd.NumDeleted)
-	}
-}
@@ -1,13 +1,39 @@
 package commentmodel
 
+import "fmt"
+
 type EventType string
 
 const (
-	EventCommentCreated EventType = "commentCreated"
+	EventCommentCreated   EventType = "commentCreated"
+	EventCommentUpdated   EventType = "commentUpdated"
+	EventCommentDeleted   EventType = "commentDeleted"
+	EventCommentMentioned EventType = "commentMentioned"
 )
 
+// MentionChannelPrefix is the first part of the path of the comment channels on which users are
+// notified of the comments that mention them, as opposed to the channels of the comments of an
+// object.
+const MentionChannelPrefix = "user"
+
+// MentionChannel returns the channel on which the user is notified of the comments that mention
+// them.
+func MentionChannel(userID int64) string {
+	return fmt.Sprintf("grafana/comment/%s/%d", MentionChannelPrefix, userID)
+}
+
 // Event represents comment event structure.
 type Event struct {
-	Event          EventType   `json:"event"`
-	CommentCreated *CommentDto `json:"commentCreated"`
+	Event            EventType   `json:"event"`
+	CommentCreated   *CommentDto `json:"commentCreated,omitempty"`
+	CommentUpdated   *CommentDto `json:"commentUpdated,omitempty"`
+	CommentDeleted   *CommentDto `json:"commentDeleted,omitempty"`
+	CommentMentioned *Mention    `json:"commentMentioned,omitempty"`
+}
+
+// Mention is the event of a user mentioned in a comment of an object.
+type Mention struct {
+	ObjectType string      `json:"objectType"`
+	ObjectID   string      `json:"objectId"`
+	Comment    *CommentDto `json:"comment"`
 }
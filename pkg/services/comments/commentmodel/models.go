@@ -67,11 +67,50 @@ func (s *Settings) Scan(value interface{}) error {
 type Comment struct {
 	Id      int64
 	GroupId int64
-	UserId  int64
-	Content string
+	// ParentId is the ID of the comment starting the thread of a reply, or 0 for the comments that
+	// start a thread.
+	ParentId int64
+	UserId   int64
+	Content  string
 
 	Created int64
 	Updated int64
+	// Deleted is the time the comment was deleted, or 0. The content of deleted comments is kept
+	// but never returned.
+	Deleted int64
+
+	// ReplyCount is the number of replies to the comment, for the comments that start a thread.
+	ReplyCount int64 `xorm:"-"`
+}
+
+// CommentRevision is a previous content of a comment, saved when the comment is edited.
+type CommentRevision struct {
+	Id        int64
+	CommentId int64
+	UserId    int64
+	Content   string
+
+	Created int64
+}
+
+func (i CommentRevision) TableName() string {
+	return "comment_revision"
+}
+
+type CommentRevisionDto struct {
+	Id      int64  `json:"id"`
+	UserId  int64  `json:"userId"`
+	Content string `json:"content"`
+	Created int64  `json:"created"`
+}
+
+func (i CommentRevision) ToDTO() *CommentRevisionDto {
+	return &CommentRevisionDto{
+		Id:      i.Id,
+		UserId:  i.UserId,
+		Content: i.Content,
+		Created: i.Created,
+	}
 }
 
 type CommentUser struct {
@@ -83,21 +122,35 @@ type CommentUser struct {
 }
 
 type CommentDto struct {
-	Id      int64        `json:"id"`
-	UserId  int64        `json:"userId"`
-	Content string       `json:"content"`
-	Created int64        `json:"created"`
-	User    *CommentUser `json:"user,omitempty"`
+	Id         int64        `json:"id"`
+	ParentId   int64        `json:"parentId"`
+	UserId     int64        `json:"userId"`
+	Content    string       `json:"content"`
+	Created    int64        `json:"created"`
+	Updated    int64        `json:"updated"`
+	Edited     bool         `json:"edited"`
+	Deleted    bool         `json:"deleted"`
+	ReplyCount int64        `json:"replyCount"`
+	User       *CommentUser `json:"user,omitempty"`
 }
 
 func (i Comment) ToDTO(user *CommentUser) *CommentDto {
-	return &CommentDto{
-		Id:      i.Id,
-		UserId:  i.UserId,
-		Content: i.Content,
-		Created: i.Created,
-		User:    user,
+	dto := &CommentDto{
+		Id:         i.Id,
+		ParentId:   i.ParentId,
+		UserId:     i.UserId,
+		Content:    i.Content,
+		Created:    i.Created,
+		Updated:    i.Updated,
+		Edited:     i.Updated != i.Created,
+		Deleted:    i.Deleted != 0,
+		ReplyCount: i.ReplyCount,
+		User:       user,
+	}
+	if dto.Deleted {
+		dto.Content = ""
 	}
+	return dto
 }
 
 func (i Comment) TableName() string {
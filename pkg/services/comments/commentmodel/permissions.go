@@ -137,3 +137,66 @@ func (c *PermissionChecker) CheckWritePermissions(ctx context.Context, orgId int
 	}
 	return true, nil
 }
+
+// CheckEditPermissions checks that the user can edit a comment of an object. Comments can only be
+// edited by their authors, as long as they can still write comments on the object.
+func (c *PermissionChecker) CheckEditPermissions(ctx context.Context, orgId int64, signedInUser *models.SignedInUser, objectType string, objectID string, comment *Comment) (bool, error) {
+	if comment.UserId <= 0 || comment.UserId != signedInUser.UserId {
+		return false, nil
+	}
+	return c.CheckWritePermissions(ctx, orgId, signedInUser, objectType, objectID)
+}
+
+// CheckDeletePermissions checks that the user can delete a comment of an object. Comments can be
+// deleted by their authors if they can edit them, and by the users that can administer the object.
+func (c *PermissionChecker) CheckDeletePermissions(ctx context.Context, orgId int64, signedInUser *models.SignedInUser, objectType string, objectID string, comment *Comment) (bool, error) {
+	if ok, err := c.CheckEditPermissions(ctx, orgId, signedInUser, objectType, objectID, comment); err != nil || ok {
+		return ok, err
+	}
+	return c.checkAdminPermissions(ctx, orgId, signedInUser, objectType, objectID)
+}
+
+func (c *PermissionChecker) checkAdminPermissions(ctx context.Context, orgId int64, signedInUser *models.SignedInUser, objectType string, objectID string) (bool, error) {
+	switch objectType {
+	case ObjectTypeDashboard:
+		if !c.features.IsEnabled(featuremgmt.FlagDashboardComments) {
+			return false, nil
+		}
+		dash, err := c.getDashboardByUid(ctx, orgId, objectID)
+		if err != nil {
+			return false, err
+		}
+		guard := guardian.New(ctx, dash.Id, orgId, signedInUser)
+		if ok, err := guard.CanAdmin(); err != nil || !ok {
+			return false, nil
+		}
+	case ObjectTypeAnnotation:
+		if !c.features.IsEnabled(featuremgmt.FlagAnnotationComments) {
+			return false, nil
+		}
+		repo := annotations.GetRepository()
+		annotationID, err := strconv.ParseInt(objectID, 10, 64)
+		if err != nil {
+			return false, nil
+		}
+		items, err := repo.Find(ctx, &annotations.ItemQuery{AnnotationId: annotationID, OrgId: orgId, SignedInUser: signedInUser})
+		if err != nil || len(items) != 1 {
+			return false, nil
+		}
+		dashboardID := items[0].DashboardId
+		if dashboardID == 0 {
+			return false, nil
+		}
+		dash, err := c.getDashboardById(ctx, orgId, dashboardID)
+		if err != nil {
+			return false, nil
+		}
+		guard := guardian.New(ctx, dash.Id, orgId, signedInUser)
+		if ok, err := guard.CanAdmin(); err != nil || !ok {
+			return false, nil
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
@@ -76,12 +76,35 @@ type GetCmd struct {
 	ObjectID   string `json:"objectId"`
 	Limit      uint   `json:"limit"`
 	BeforeId   int64  `json:"beforeId"`
+	// ParentId gets the replies to the comment instead of the comments that start a thread.
+	ParentId int64 `json:"parentId"`
 }
 
 type CreateCmd struct {
 	ObjectType string `json:"objectType"`
 	ObjectID   string `json:"objectId"`
 	Content    string `json:"content"`
+	// ParentId creates a reply to the comment.
+	ParentId int64 `json:"parentId"`
+}
+
+type UpdateCmd struct {
+	ObjectType string `json:"objectType"`
+	ObjectID   string `json:"objectId"`
+	CommentId  int64  `json:"commentId"`
+	Content    string `json:"content"`
+}
+
+type DeleteCmd struct {
+	ObjectType string `json:"objectType"`
+	ObjectID   string `json:"objectId"`
+	CommentId  int64  `json:"commentId"`
+}
+
+type GetRevisionsCmd struct {
+	ObjectType string `json:"objectType"`
+	ObjectID   string `json:"objectId"`
+	CommentId  int64  `json:"commentId"`
 }
 
 var ErrPermissionDenied = errors.New("permission denied")
@@ -95,33 +118,55 @@ func (s *Service) Create(ctx context.Context, orgID int64, signedInUser *models.
 		return nil, ErrPermissionDenied
 	}
 
-	userMap := make(map[int64]*commentmodel.CommentUser, 1)
-	if signedInUser.UserId > 0 {
-		userMap[signedInUser.UserId] = &commentmodel.CommentUser{
-			Id:        signedInUser.UserId,
-			Name:      signedInUser.Name,
-			Login:     signedInUser.Login,
-			Email:     signedInUser.Email,
-			AvatarUrl: dtos.GetGravatarUrl(signedInUser.Email),
-		}
-	}
-
-	m, err := s.storage.Create(ctx, orgID, cmd.ObjectType, cmd.ObjectID, signedInUser.UserId, cmd.Content)
+	m, err := s.storage.Create(ctx, orgID, cmd.ObjectType, cmd.ObjectID, signedInUser.UserId, cmd.Content, cmd.ParentId)
 	if err != nil {
 		return nil, err
 	}
-	mDto := commentToDto(m, userMap)
-	e := commentmodel.Event{
+	mDto := commentToDto(m, signedInUserMap(signedInUser))
+	s.publish(orgID, cmd.ObjectType, cmd.ObjectID, commentmodel.Event{
 		Event:          commentmodel.EventCommentCreated,
 		CommentCreated: mDto,
+	})
+	s.notifyMentions(ctx, orgID, signedInUser, cmd.ObjectType, cmd.ObjectID, mDto, parseMentions(cmd.Content))
+	return mDto, nil
+}
+
+// Update edits the content of a comment of the user. Only the users mentioned by the new content
+// that were not mentioned before are notified.
+func (s *Service) Update(ctx context.Context, orgID int64, signedInUser *models.SignedInUser, cmd UpdateCmd) (*commentmodel.CommentDto, error) {
+	comment, err := s.storage.GetByID(ctx, orgID, cmd.ObjectType, cmd.ObjectID, cmd.CommentId)
+	if err != nil {
+		return nil, err
 	}
-	eventJSON, _ := json.Marshal(e)
-	_ = s.live.Publish(orgID, fmt.Sprintf("grafana/comment/%s/%s", cmd.ObjectType, cmd.ObjectID), eventJSON)
+	ok, err := s.permissions.CheckEditPermissions(ctx, orgID, signedInUser, cmd.ObjectType, cmd.ObjectID, comment)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPermissionDenied
+	}
+
+	previousContent := comment.Content
+	m, err := s.storage.Update(ctx, orgID, cmd.ObjectType, cmd.ObjectID, cmd.CommentId, cmd.Content)
+	if err != nil {
+		return nil, err
+	}
+	mDto := commentToDto(m, signedInUserMap(signedInUser))
+	s.publish(orgID, cmd.ObjectType, cmd.ObjectID, commentmodel.Event{
+		Event:          commentmodel.EventCommentUpdated,
+		CommentUpdated: mDto,
+	})
+	s.notifyMentions(ctx, orgID, signedInUser, cmd.ObjectType, cmd.ObjectID, mDto, newMentions(previousContent, cmd.Content))
 	return mDto, nil
 }
 
-func (s *Service) Get(ctx context.Context, orgID int64, signedInUser *models.SignedInUser, cmd GetCmd) ([]*commentmodel.CommentDto, error) {
-	ok, err := s.permissions.CheckReadPermissions(ctx, orgID, signedInUser, cmd.ObjectType, cmd.ObjectID)
+// Delete soft deletes a comment.
+func (s *Service) Delete(ctx context.Context, orgID int64, signedInUser *models.SignedInUser, cmd DeleteCmd) (*commentmodel.CommentDto, error) {
+	comment, err := s.storage.GetByID(ctx, orgID, cmd.ObjectType, cmd.ObjectID, cmd.CommentId)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.permissions.CheckDeletePermissions(ctx, orgID, signedInUser, cmd.ObjectType, cmd.ObjectID, comment)
 	if err != nil {
 		return nil, err
 	}
@@ -129,16 +174,67 @@ func (s *Service) Get(ctx context.Context, orgID int64, signedInUser *models.Sig
 		return nil, ErrPermissionDenied
 	}
 
-	messages, err := s.storage.Get(ctx, orgID, cmd.ObjectType, cmd.ObjectID, GetFilter{
-		Limit:    cmd.Limit,
-		BeforeID: cmd.BeforeId,
+	m, err := s.storage.Delete(ctx, orgID, cmd.ObjectType, cmd.ObjectID, cmd.CommentId)
+	if err != nil {
+		return nil, err
+	}
+	userMap, err := s.getUserMap(ctx, []*commentmodel.Comment{m})
+	if err != nil {
+		return nil, err
+	}
+	mDto := commentToDto(m, userMap)
+	s.publish(orgID, cmd.ObjectType, cmd.ObjectID, commentmodel.Event{
+		Event:          commentmodel.EventCommentDeleted,
+		CommentDeleted: mDto,
 	})
+	return mDto, nil
+}
+
+// GetRevisions returns the edit history of a comment, most recent first.
+func (s *Service) GetRevisions(ctx context.Context, orgID int64, signedInUser *models.SignedInUser, cmd GetRevisionsCmd) ([]*commentmodel.CommentRevisionDto, error) {
+	ok, err := s.permissions.CheckReadPermissions(ctx, orgID, signedInUser, cmd.ObjectType, cmd.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPermissionDenied
+	}
+
+	revisions, err := s.storage.GetRevisions(ctx, orgID, cmd.ObjectType, cmd.ObjectID, cmd.CommentId)
 	if err != nil {
 		return nil, err
 	}
+	result := make([]*commentmodel.CommentRevisionDto, 0, len(revisions))
+	for _, r := range revisions {
+		result = append(result, r.ToDTO())
+	}
+	return result, nil
+}
+
+// publish sends a comment event to the subscribers of the comments of the object.
+func (s *Service) publish(orgID int64, objectType string, objectID string, e commentmodel.Event) {
+	eventJSON, _ := json.Marshal(e)
+	_ = s.live.Publish(orgID, fmt.Sprintf("grafana/comment/%s/%s", objectType, objectID), eventJSON)
+}
+
+func signedInUserMap(signedInUser *models.SignedInUser) map[int64]*commentmodel.CommentUser {
+	userMap := make(map[int64]*commentmodel.CommentUser, 1)
+	if signedInUser.UserId > 0 {
+		userMap[signedInUser.UserId] = &commentmodel.CommentUser{
+			Id:        signedInUser.UserId,
+			Name:      signedInUser.Name,
+			Login:     signedInUser.Login,
+			Email:     signedInUser.Email,
+			AvatarUrl: dtos.GetGravatarUrl(signedInUser.Email),
+		}
+	}
+	return userMap
+}
 
-	userIds := make([]int64, 0, len(messages))
-	for _, m := range messages {
+// getUserMap returns the authors of the comments.
+func (s *Service) getUserMap(ctx context.Context, comments []*commentmodel.Comment) (map[int64]*commentmodel.CommentUser, error) {
+	userIds := make([]int64, 0, len(comments))
+	for _, m := range comments {
 		if m.UserId <= 0 {
 			continue
 		}
@@ -155,6 +251,31 @@ func (s *Service) Get(ctx context.Context, orgID int64, signedInUser *models.Sig
 	for _, v := range query.Result.Users {
 		userMap[v.Id] = searchUserToCommentUser(v)
 	}
+	return userMap, nil
+}
+
+func (s *Service) Get(ctx context.Context, orgID int64, signedInUser *models.SignedInUser, cmd GetCmd) ([]*commentmodel.CommentDto, error) {
+	ok, err := s.permissions.CheckReadPermissions(ctx, orgID, signedInUser, cmd.ObjectType, cmd.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPermissionDenied
+	}
+
+	messages, err := s.storage.Get(ctx, orgID, cmd.ObjectType, cmd.ObjectID, GetFilter{
+		Limit:    cmd.Limit,
+		BeforeID: cmd.BeforeId,
+		ParentID: cmd.ParentId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userMap, err := s.getUserMap(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
 
 	result := commentsToDto(messages, userMap)
 	sort.Slice(result, func(i, j int) bool {
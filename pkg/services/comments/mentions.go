@@ -0,0 +1,191 @@
+package comments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/comments/commentmodel"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// maxMentions is the maximum number of users and teams mentioned in a comment that are notified.
+	maxMentions = 20
+	// maxMentionedUsers is the maximum number of users notified of a comment, teams included.
+	maxMentionedUsers = 100
+
+	mentionEmailTemplate = "comment_mention"
+)
+
+// mentionRegexp matches the logins of users and the names of teams prefixed with @. Mentions must
+// start a word, so that email addresses are not mentions.
+var mentionRegexp = regexp.MustCompile(`(?:^|[^\w@.])@(\w[\w.\-@]*)`)
+
+// parseMentions returns the unique names mentioned in the content of a comment, in order.
+func parseMentions(content string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range mentionRegexp.FindAllStringSubmatch(content, -1) {
+		// trailing punctuation ends the sentence, not the name
+		name := strings.TrimRight(match[1], ".-@")
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+		if len(names) == maxMentions {
+			break
+		}
+	}
+	return names
+}
+
+// newMentions returns the names mentioned in the content that were not mentioned in the previous
+// content of a comment.
+func newMentions(previous string, content string) []string {
+	mentioned := make(map[string]bool)
+	for _, name := range parseMentions(previous) {
+		mentioned[name] = true
+	}
+	var names []string
+	for _, name := range parseMentions(content) {
+		if !mentioned[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// resolveMentions returns the users of the organization mentioned by their login, or by the name
+// of one of their teams. Names are resolved as logins first. The author of the comment is never
+// mentioned.
+func (s *Service) resolveMentions(ctx context.Context, orgID int64, author *models.SignedInUser, names []string) ([]*models.SignedInUser, error) {
+	var users []*models.SignedInUser
+	mentioned := map[int64]bool{author.UserId: true}
+	add := func(user *models.SignedInUser) {
+		if user == nil || mentioned[user.UserId] || len(users) == maxMentionedUsers {
+			return
+		}
+		mentioned[user.UserId] = true
+		users = append(users, user)
+	}
+
+	for _, name := range names {
+		user, err := s.getOrgUser(ctx, &models.GetSignedInUserQuery{Login: name, OrgId: orgID})
+		if err != nil {
+			return nil, err
+		}
+		if user != nil {
+			add(user)
+			continue
+		}
+
+		// only the teams the author can see can be mentioned
+		teamsQuery := &models.SearchTeamsQuery{Name: name, OrgId: orgID, SignedInUser: author, UserIdFilter: models.FilterIgnoreUser, Limit: 1, Page: 1}
+		if err := s.sqlStore.SearchTeams(ctx, teamsQuery); err != nil {
+			return nil, err
+		}
+		if len(teamsQuery.Result.Teams) == 0 {
+			continue
+		}
+		membersQuery := &models.GetTeamMembersQuery{OrgId: orgID, TeamId: teamsQuery.Result.Teams[0].Id, SignedInUser: author}
+		if err := s.sqlStore.GetTeamMembers(ctx, membersQuery); err != nil {
+			return nil, err
+		}
+		for _, member := range membersQuery.Result {
+			user, err := s.getOrgUser(ctx, &models.GetSignedInUserQuery{UserId: member.UserId, OrgId: orgID})
+			if err != nil {
+				return nil, err
+			}
+			add(user)
+		}
+	}
+	return users, nil
+}
+
+// getOrgUser returns the user of the query if they are a member of the organization of the query,
+// or nil.
+func (s *Service) getOrgUser(ctx context.Context, query *models.GetSignedInUserQuery) (*models.SignedInUser, error) {
+	if err := s.sqlStore.GetSignedInUser(ctx, query); err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if query.Result.OrgId != query.OrgId {
+		return nil, nil
+	}
+	return query.Result, nil
+}
+
+// notifyMentions notifies the users mentioned in a comment that can read the comments of the
+// object, with a live event and an email. Failures to notify a user are logged and do not fail
+// the comment.
+func (s *Service) notifyMentions(ctx context.Context, orgID int64, author *models.SignedInUser, objectType string, objectID string, comment *commentmodel.CommentDto, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	users, err := s.resolveMentions(ctx, orgID, author, names)
+	if err != nil {
+		s.log.Error("Failed to resolve comment mentions", "error", err)
+		return
+	}
+
+	eventJSON, _ := json.Marshal(commentmodel.Event{
+		Event: commentmodel.EventCommentMentioned,
+		CommentMentioned: &commentmodel.Mention{
+			ObjectType: objectType,
+			ObjectID:   objectID,
+			Comment:    comment,
+		},
+	})
+	mentionedBy := author.Name
+	if mentionedBy == "" {
+		mentionedBy = author.Login
+	}
+
+	for _, user := range users {
+		ok, err := s.permissions.CheckReadPermissions(ctx, orgID, user, objectType, objectID)
+		if err != nil {
+			s.log.Error("Failed to check comment read permissions", "user", user.UserId, "error", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		if err := s.live.Publish(orgID, commentmodel.MentionChannel(user.UserId), eventJSON); err != nil {
+			s.log.Warn("Failed to publish comment mention", "user", user.UserId, "error", err)
+		}
+
+		if user.Email == "" || s.notifications == nil {
+			continue
+		}
+		err = s.notifications.SendEmailCommandHandler(ctx, &models.SendEmailCommand{
+			To:       []string{user.Email},
+			Template: mentionEmailTemplate,
+			Data: map[string]interface{}{
+				"Name":        user.NameOrFallback(),
+				"MentionedBy": mentionedBy,
+				"Content":     comment.Content,
+				"Link":        commentLink(orgID, objectType, objectID),
+			},
+		})
+		if err != nil && !errors.Is(err, models.ErrSmtpNotEnabled) {
+			s.log.Warn("Failed to send comment mention email", "user", user.UserId, "error", err)
+		}
+	}
+}
+
+// commentLink returns the URL of the page showing the comments of an object.
+func commentLink(orgID int64, objectType string, objectID string) string {
+	if objectType == commentmodel.ObjectTypeDashboard {
+		return fmt.Sprintf("%sd/%s?orgId=%d", setting.AppUrl, objectID, orgID)
+	}
+	return fmt.Sprintf("%s?orgId=%d", setting.AppUrl, orgID)
+}
@@ -0,0 +1,19 @@
+package comments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMentions(t *testing.T) {
+	require.Empty(t, parseMentions("no mentions, mail ops@example.com"))
+	require.Equal(t, []string{"alice", "sre-team", "bob@example.com"},
+		parseMentions("@alice can you check with @sre-team and @bob@example.com? Thanks @alice."))
+	require.Equal(t, []string{"carol"}, parseMentions("(@carol)"))
+}
+
+func TestNewMentions(t *testing.T) {
+	require.Equal(t, []string{"bob"}, newMentions("@alice look", "@alice and @bob look"))
+	require.Empty(t, newMentions("@alice and @bob look", "@bob look"))
+}
@@ -3,27 +3,34 @@ package comments
 import (
 	"context"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/comments/commentmodel"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/live"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 type Service struct {
-	cfg         *setting.Cfg
-	live        *live.GrafanaLive
-	sqlStore    *sqlstore.SQLStore
-	storage     Storage
-	permissions *commentmodel.PermissionChecker
+	cfg           *setting.Cfg
+	log           log.Logger
+	live          *live.GrafanaLive
+	sqlStore      *sqlstore.SQLStore
+	storage       Storage
+	permissions   *commentmodel.PermissionChecker
+	notifications notifications.Service
 }
 
-func ProvideService(cfg *setting.Cfg, store *sqlstore.SQLStore, live *live.GrafanaLive, features featuremgmt.FeatureToggles, accessControl accesscontrol.AccessControl) *Service {
+func ProvideService(cfg *setting.Cfg, store *sqlstore.SQLStore, live *live.GrafanaLive, features featuremgmt.FeatureToggles,
+	accessControl accesscontrol.AccessControl, notificationService notifications.Service) *Service {
 	s := &Service{
-		cfg:      cfg,
-		live:     live,
-		sqlStore: store,
+		cfg:           cfg,
+		log:           log.New("comments"),
+		live:          live,
+		sqlStore:      store,
+		notifications: notificationService,
 		storage: &sqlStorage{
 			sql: store,
 		},
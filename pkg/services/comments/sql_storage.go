@@ -21,7 +21,49 @@ func checkObjectID(objectID string) bool {
 	return objectID != ""
 }
 
-func (s *sqlStorage) Create(ctx context.Context, orgID int64, objectType string, objectID string, userID int64, content string) (*commentmodel.Comment, error) {
+func checkObject(objectType string, objectID string) error {
+	if !checkObjectType(objectType) {
+		return errUnknownObjectType
+	}
+	if !checkObjectID(objectID) {
+		return errEmptyObjectID
+	}
+	return nil
+}
+
+func getGroup(dbSession *sqlstore.DBSession, orgID int64, objectType string, objectID string) (*commentmodel.CommentGroup, bool, error) {
+	var group commentmodel.CommentGroup
+	has, err := dbSession.NoAutoCondition().Where(
+		"org_id=? AND object_type=? AND object_id=?",
+		orgID, objectType, objectID,
+	).Get(&group)
+	return &group, has, err
+}
+
+// getComment returns the comment of the object with the ID, or ErrCommentNotFound if the object
+// has no such comment.
+func getComment(dbSession *sqlstore.DBSession, orgID int64, objectType string, objectID string, id int64) (*commentmodel.Comment, error) {
+	group, has, err := getGroup(dbSession, orgID, objectType, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrCommentNotFound
+	}
+	var comment commentmodel.Comment
+	has, err = dbSession.NoAutoCondition().Where("id=? AND group_id=?", id, group.Id).Get(&comment)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrCommentNotFound
+	}
+	return &comment, nil
+}
+
+// Create creates a comment, or a reply to the thread of the comment with parentID. Replies to
+// replies are attached to the comment that started their thread.
+func (s *sqlStorage) Create(ctx context.Context, orgID int64, objectType string, objectID string, userID int64, content string, parentID int64) (*commentmodel.Comment, error) {
 	if !checkObjectType(objectType) {
 		return nil, errUnknownObjectType
 	}
@@ -44,6 +86,22 @@ func (s *sqlStorage) Create(ctx context.Context, orgID int64, objectType string,
 			return err
 		}
 
+		if parentID > 0 {
+			if !has {
+				return ErrCommentNotFound
+			}
+			parent, err := getComment(dbSession, orgID, objectType, objectID, parentID)
+			if err != nil {
+				return err
+			}
+			if parent.Deleted != 0 {
+				return ErrCommentNotFound
+			}
+			if parent.ParentId != 0 {
+				parentID = parent.ParentId
+			}
+		}
+
 		nowUnix := time.Now().Unix()
 
 		groupID := group.Id
@@ -61,11 +119,12 @@ func (s *sqlStorage) Create(ctx context.Context, orgID int64, objectType string,
 			groupID = group.Id
 		}
 		message := commentmodel.Comment{
-			GroupId: groupID,
-			UserId:  userID,
-			Content: content,
-			Created: nowUnix,
-			Updated: nowUnix,
+			GroupId:  groupID,
+			ParentId: parentID,
+			UserId:   userID,
+			Content:  content,
+			Created:  nowUnix,
+			Updated:  nowUnix,
 		}
 		_, err = dbSession.Insert(&message)
 		if err != nil {
@@ -108,10 +167,146 @@ func (s *sqlStorage) Get(ctx context.Context, orgID int64, objectType string, ob
 		if !has {
 			return nil
 		}
-		clause := dbSession.Where("group_id=?", group.Id)
+		clause := dbSession.Where("group_id=? AND parent_id=?", group.Id, filter.ParentID)
 		if filter.BeforeID > 0 {
 			clause.Where("id < ?", filter.BeforeID)
 		}
-		return clause.OrderBy("id desc").Limit(limit).Find(&result)
+		if err := clause.OrderBy("id desc").Limit(limit).Find(&result); err != nil {
+			return err
+		}
+		if filter.ParentID != 0 || len(result) == 0 {
+			return nil
+		}
+		return countReplies(dbSession, result)
+	})
+}
+
+// countReplies sets the number of replies of the comments that start a thread.
+func countReplies(dbSession *sqlstore.DBSession, comments []*commentmodel.Comment) error {
+	ids := make([]int64, 0, len(comments))
+	for _, c := range comments {
+		ids = append(ids, c.Id)
+	}
+	var counts []struct {
+		ParentId int64 `xorm:"parent_id"`
+		Count    int64 `xorm:"count"`
+	}
+	err := dbSession.Table("comment").Select("parent_id, COUNT(*) AS count").
+		In("parent_id", ids).Where("deleted = 0").GroupBy("parent_id").Find(&counts)
+	if err != nil {
+		return err
+	}
+	countByID := make(map[int64]int64, len(counts))
+	for _, c := range counts {
+		countByID[c.ParentId] = c.Count
+	}
+	for _, c := range comments {
+		c.ReplyCount = countByID[c.Id]
+	}
+	return nil
+}
+
+func (s *sqlStorage) GetByID(ctx context.Context, orgID int64, objectType string, objectID string, id int64) (*commentmodel.Comment, error) {
+	if err := checkObject(objectType, objectID); err != nil {
+		return nil, err
+	}
+
+	var result *commentmodel.Comment
+	err := s.sql.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		var err error
+		result, err = getComment(dbSession, orgID, objectType, objectID, id)
+		return err
+	})
+	return result, err
+}
+
+// Update sets the content of a comment, and saves its previous content as a revision.
+func (s *sqlStorage) Update(ctx context.Context, orgID int64, objectType string, objectID string, id int64, content string) (*commentmodel.Comment, error) {
+	if err := checkObject(objectType, objectID); err != nil {
+		return nil, err
+	}
+	if content == "" {
+		return nil, errEmptyContent
+	}
+
+	var result *commentmodel.Comment
+	err := s.sql.WithTransactionalDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		comment, err := getComment(dbSession, orgID, objectType, objectID, id)
+		if err != nil {
+			return err
+		}
+		if comment.Deleted != 0 {
+			return ErrCommentNotFound
+		}
+		if comment.Content == content {
+			result = comment
+			return nil
+		}
+
+		revision := commentmodel.CommentRevision{
+			CommentId: comment.Id,
+			UserId:    comment.UserId,
+			Content:   comment.Content,
+			Created:   comment.Updated,
+		}
+		if _, err := dbSession.Insert(&revision); err != nil {
+			return err
+		}
+
+		comment.Content = content
+		comment.Updated = time.Now().Unix()
+		if _, err := dbSession.ID(comment.Id).Cols("content", "updated").Update(comment); err != nil {
+			return err
+		}
+		result = comment
+		return nil
+	})
+	return result, err
+}
+
+// Delete soft deletes a comment. The replies to deleted comments are kept.
+func (s *sqlStorage) Delete(ctx context.Context, orgID int64, objectType string, objectID string, id int64) (*commentmodel.Comment, error) {
+	if err := checkObject(objectType, objectID); err != nil {
+		return nil, err
+	}
+
+	var result *commentmodel.Comment
+	err := s.sql.WithTransactionalDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		comment, err := getComment(dbSession, orgID, objectType, objectID, id)
+		if err != nil {
+			return err
+		}
+		if comment.Deleted != 0 {
+			return ErrCommentNotFound
+		}
+
+		comment.Deleted = time.Now().Unix()
+		if _, err := dbSession.ID(comment.Id).Cols("deleted").Update(comment); err != nil {
+			return err
+		}
+		result = comment
+		return nil
+	})
+	return result, err
+}
+
+// GetRevisions returns the previous contents of a comment, most recent first. The revisions of
+// deleted comments are not returned.
+func (s *sqlStorage) GetRevisions(ctx context.Context, orgID int64, objectType string, objectID string, id int64) ([]*commentmodel.CommentRevision, error) {
+	if err := checkObject(objectType, objectID); err != nil {
+		return nil, err
+	}
+
+	result := make([]*commentmodel.CommentRevision, 0)
+	err := s.sql.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		comment, err := getComment(dbSession, orgID, objectType, objectID, id)
+		if err != nil {
+			return err
+		}
+		if comment.Deleted != 0 {
+			return ErrCommentNotFound
+		}
+		return dbSession.Where("comment_id=?", comment.Id).OrderBy("id desc").Find(&result)
 	})
+	return result, err
 }
@@ -29,7 +29,7 @@ func TestSqlStorage(t *testing.T) {
 	numComments := 10
 
 	for i := 0; i < numComments; i++ {
-		comment, err := s.Create(ctx, 1, commentmodel.ObjectTypeOrg, "2", 1, "test"+strconv.Itoa(i))
+		comment, err := s.Create(ctx, 1, commentmodel.ObjectTypeOrg, "2", 1, "test"+strconv.Itoa(i), 0)
 		require.NoError(t, err)
 		require.NotNil(t, comment)
 		require.True(t, comment.Id > 0)
@@ -74,3 +74,92 @@ func TestSqlStorage(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, items, 0)
 }
+
+func TestSqlStorageThreads(t *testing.T) {
+	s := createSqlStorage(t)
+	ctx := context.Background()
+
+	root, err := s.Create(ctx, 1, commentmodel.ObjectTypeOrg, "2", 1, "root", 0)
+	require.NoError(t, err)
+	other, err := s.Create(ctx, 1, commentmodel.ObjectTypeOrg, "2", 1, "other", 0)
+	require.NoError(t, err)
+
+	reply, err := s.Create(ctx, 1, commentmodel.ObjectTypeOrg, "2", 2, "reply", root.Id)
+	require.NoError(t, err)
+	require.Equal(t, root.Id, reply.ParentId)
+
+	// Replies to replies belong to the thread of the root comment.
+	nested, err := s.Create(ctx, 1, commentmodel.ObjectTypeOrg, "2", 1, "nested", reply.Id)
+	require.NoError(t, err)
+	require.Equal(t, root.Id, nested.ParentId)
+
+	// Parents must belong to the same object.
+	_, err = s.Create(ctx, 1, commentmodel.ObjectTypeOrg, "3", 1, "reply", root.Id)
+	require.ErrorIs(t, err, ErrCommentNotFound)
+
+	items, err := s.Get(ctx, 1, commentmodel.ObjectTypeOrg, "2", GetFilter{})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, other.Id, items[0].Id)
+	require.Equal(t, int64(0), items[0].ReplyCount)
+	require.Equal(t, root.Id, items[1].Id)
+	require.Equal(t, int64(2), items[1].ReplyCount)
+
+	items, err = s.Get(ctx, 1, commentmodel.ObjectTypeOrg, "2", GetFilter{ParentID: root.Id})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, "nested", items[0].Content)
+	require.Equal(t, "reply", items[1].Content)
+
+	// Deleted replies are not counted, and deleted comments cannot be replied to.
+	_, err = s.Delete(ctx, 1, commentmodel.ObjectTypeOrg, "2", nested.Id)
+	require.NoError(t, err)
+	items, err = s.Get(ctx, 1, commentmodel.ObjectTypeOrg, "2", GetFilter{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), items[1].ReplyCount)
+	_, err = s.Create(ctx, 1, commentmodel.ObjectTypeOrg, "2", 1, "reply", nested.Id)
+	require.ErrorIs(t, err, ErrCommentNotFound)
+}
+
+func TestSqlStorageUpdateDelete(t *testing.T) {
+	s := createSqlStorage(t)
+	ctx := context.Background()
+
+	comment, err := s.Create(ctx, 1, commentmodel.ObjectTypeOrg, "2", 1, "first", 0)
+	require.NoError(t, err)
+
+	_, err = s.Update(ctx, 1, commentmodel.ObjectTypeOrg, "3", comment.Id, "second")
+	require.ErrorIs(t, err, ErrCommentNotFound)
+	_, err = s.Update(ctx, 1, commentmodel.ObjectTypeOrg, "2", comment.Id, "")
+	require.ErrorIs(t, err, errEmptyContent)
+
+	updated, err := s.Update(ctx, 1, commentmodel.ObjectTypeOrg, "2", comment.Id, "second")
+	require.NoError(t, err)
+	require.Equal(t, "second", updated.Content)
+	_, err = s.Update(ctx, 1, commentmodel.ObjectTypeOrg, "2", comment.Id, "third")
+	require.NoError(t, err)
+
+	revisions, err := s.GetRevisions(ctx, 1, commentmodel.ObjectTypeOrg, "2", comment.Id)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, "second", revisions[0].Content)
+	require.Equal(t, "first", revisions[1].Content)
+	require.Equal(t, int64(1), revisions[1].UserId)
+
+	deleted, err := s.Delete(ctx, 1, commentmodel.ObjectTypeOrg, "2", comment.Id)
+	require.NoError(t, err)
+	require.NotZero(t, deleted.Deleted)
+	require.Empty(t, deleted.ToDTO(nil).Content)
+
+	// Deleted comments are still listed, but cannot be changed anymore.
+	items, err := s.Get(ctx, 1, commentmodel.ObjectTypeOrg, "2", GetFilter{})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.True(t, items[0].ToDTO(nil).Deleted)
+	_, err = s.Update(ctx, 1, commentmodel.ObjectTypeOrg, "2", comment.Id, "fourth")
+	require.ErrorIs(t, err, ErrCommentNotFound)
+	_, err = s.Delete(ctx, 1, commentmodel.ObjectTypeOrg, "2", comment.Id)
+	require.ErrorIs(t, err, ErrCommentNotFound)
+	_, err = s.GetRevisions(ctx, 1, commentmodel.ObjectTypeOrg, "2", comment.Id)
+	require.ErrorIs(t, err, ErrCommentNotFound)
+}
@@ -10,9 +10,16 @@ import (
 type GetFilter struct {
 	Limit    uint
 	BeforeID int64
+	// ParentID returns the replies of the thread started by the comment instead of the comments
+	// that start a thread.
+	ParentID int64
 }
 
 var (
+	// ErrCommentNotFound is returned for comments, or parents of replies, that do not belong to the
+	// object or were deleted.
+	ErrCommentNotFound = errors.New("comment not found")
+
 	errUnknownObjectType = errors.New("unknown object type")
 	errEmptyObjectID     = errors.New("empty object id")
 	errEmptyContent      = errors.New("empty comment content")
@@ -20,5 +27,9 @@ var (
 
 type Storage interface {
 	Get(ctx context.Context, orgID int64, objectType string, objectID string, filter GetFilter) ([]*commentmodel.Comment, error)
-	Create(ctx context.Context, orgID int64, objectType string, objectID string, userID int64, content string) (*commentmodel.Comment, error)
+	GetByID(ctx context.Context, orgID int64, objectType string, objectID string, id int64) (*commentmodel.Comment, error)
+	Create(ctx context.Context, orgID int64, objectType string, objectID string, userID int64, content string, parentID int64) (*commentmodel.Comment, error)
+	Update(ctx context.Context, orgID int64, objectType string, objectID string, id int64, content string) (*commentmodel.Comment, error)
+	Delete(ctx context.Context, orgID int64, objectType string, objectID string, id int64) (*commentmodel.Comment, error)
+	GetRevisions(ctx context.Context, orgID int64, objectType string, objectID string, id int64) ([]*commentmodel.CommentRevision, error)
 }
@@ -0,0 +1,193 @@
+package dashboardsnapshots
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const (
+	archiveFormatVersion = 1
+	archiveManifestName  = "snapshot.json"
+	// maxArchiveFileSize is the maximum size of a file of an archive once decompressed.
+	maxArchiveFileSize = 256 << 20
+	// maxArchiveSize is the maximum size of all the files of an archive once decompressed.
+	maxArchiveSize = 1 << 30
+	// maxArchiveFrames is the maximum number of frames of an archive.
+	maxArchiveFrames = 10000
+)
+
+// ErrInvalidArchive is returned when a snapshot archive cannot be imported.
+var ErrInvalidArchive = errors.New("invalid snapshot archive")
+
+// Archive is a self-contained snapshot that can be imported into another Grafana instance.
+type Archive struct {
+	Name      string
+	Created   time.Time
+	Dashboard *simplejson.Json
+	Frames    []PanelFrames
+}
+
+// archiveManifest is the snapshot.json file of an archive. The frames of each panel are Arrow
+// files of the archive.
+type archiveManifest struct {
+	FormatVersion int                  `json:"formatVersion"`
+	Name          string               `json:"name"`
+	Created       time.Time            `json:"created"`
+	Dashboard     *simplejson.Json     `json:"dashboard"`
+	Panels        []archivePanelFrames `json:"panels"`
+}
+
+type archivePanelFrames struct {
+	PanelID int64    `json:"panelId"`
+	Frames  []string `json:"frames"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// WriteArchive writes the snapshot as a zip archive.
+func WriteArchive(w io.Writer, a *Archive) error {
+	zw := zip.NewWriter(w)
+	manifest := archiveManifest{
+		FormatVersion: archiveFormatVersion,
+		Name:          a.Name,
+		Created:       a.Created,
+		Dashboard:     a.Dashboard,
+		Panels:        make([]archivePanelFrames, 0, len(a.Frames)),
+	}
+	for _, f := range a.Frames {
+		encoded, err := f.Frames.MarshalArrow()
+		if err != nil {
+			return fmt.Errorf("failed to encode the frames of panel %d: %w", f.PanelID, err)
+		}
+		panel := archivePanelFrames{PanelID: f.PanelID, Error: f.Error, Frames: make([]string, 0, len(encoded))}
+		for i, b := range encoded {
+			name := fmt.Sprintf("frames/%d/%d.arrow", f.PanelID, i)
+			fw, err := zw.Create(name)
+			if err != nil {
+				return err
+			}
+			if _, err := fw.Write(b); err != nil {
+				return err
+			}
+			panel.Frames = append(panel.Frames, name)
+		}
+		manifest.Panels = append(manifest.Panels, panel)
+	}
+
+	fw, err := zw.Create(archiveManifestName)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(fw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadArchive reads a snapshot written by WriteArchive. Each file of the archive can only be read
+// once, and the number of frames and the decompressed size of the archive are limited, so that a small
+// archive cannot expand to an unbounded amount of memory.
+func ReadArchive(b []byte) (*Archive, error) {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArchive, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		if _, ok := files[f.Name]; ok {
+			return nil, fmt.Errorf("%w: %s is duplicated", ErrInvalidArchive, f.Name)
+		}
+		files[f.Name] = f
+	}
+	remaining := int64(maxArchiveSize)
+
+	var manifest archiveManifest
+	mf, ok := files[archiveManifestName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is missing", ErrInvalidArchive, archiveManifestName)
+	}
+	mb, err := readArchiveFile(mf, &remaining)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(mb, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArchive, err)
+	}
+	if manifest.FormatVersion != archiveFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidArchive, manifest.FormatVersion)
+	}
+	if manifest.Dashboard == nil {
+		return nil, fmt.Errorf("%w: the dashboard is missing", ErrInvalidArchive)
+	}
+
+	a := &Archive{
+		Name:      manifest.Name,
+		Created:   manifest.Created,
+		Dashboard: manifest.Dashboard,
+		Frames:    make([]PanelFrames, 0, len(manifest.Panels)),
+	}
+	read := map[string]bool{archiveManifestName: true}
+	for _, p := range manifest.Panels {
+		panel := PanelFrames{PanelID: p.PanelID, Error: p.Error}
+		for _, name := range p.Frames {
+			if read[name] {
+				return nil, fmt.Errorf("%w: %s is referenced more than once", ErrInvalidArchive, name)
+			}
+			if len(read) > maxArchiveFrames {
+				return nil, fmt.Errorf("%w: more than %d frames", ErrInvalidArchive, maxArchiveFrames)
+			}
+			read[name] = true
+			f, ok := files[name]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s is missing", ErrInvalidArchive, name)
+			}
+			fb, err := readArchiveFile(f, &remaining)
+			if err != nil {
+				return nil, err
+			}
+			frame, err := data.UnmarshalArrowFrame(fb)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %s", ErrInvalidArchive, name, err)
+			}
+			panel.Frames = append(panel.Frames, frame)
+		}
+		a.Frames = append(a.Frames, panel)
+	}
+	return a, nil
+}
+
+// readArchiveFile reads a file of an archive, and subtracts its size from the remaining size of the archive.
+func readArchiveFile(f *zip.File, remaining *int64) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidArchive, f.Name, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	limit := int64(maxArchiveFileSize)
+	if *remaining < limit {
+		limit = *remaining
+	}
+	b, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidArchive, f.Name, err)
+	}
+	if int64(len(b)) > limit {
+		if limit < maxArchiveFileSize {
+			return nil, fmt.Errorf("%w: the archive is larger than %d bytes", ErrInvalidArchive, int64(maxArchiveSize))
+		}
+		return nil, fmt.Errorf("%w: %s is too large", ErrInvalidArchive, f.Name)
+	}
+	*remaining -= int64(len(b))
+	return b, nil
+}
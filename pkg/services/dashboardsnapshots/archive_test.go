@@ -0,0 +1,101 @@
+package dashboardsnapshots
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestArchive(t *testing.T) {
+	created := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	archive := &Archive{
+		Name:      "Incident",
+		Created:   created,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": "Incident"}),
+		Frames: []PanelFrames{
+			{
+				PanelID: 1,
+				Frames: data.Frames{
+					data.NewFrame("cpu",
+						data.NewField("time", nil, []time.Time{created}),
+						data.NewField("value", data.Labels{"host": "a"}, []float64{0.5}),
+					),
+					data.NewFrame("memory", data.NewField("value", nil, []int64{1024})),
+				},
+			},
+			{PanelID: 2, Error: "the data source is unavailable"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteArchive(&buf, archive))
+
+	read, err := ReadArchive(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "Incident", read.Name)
+	require.True(t, created.Equal(read.Created))
+	require.Equal(t, "Incident", read.Dashboard.Get("title").MustString())
+	require.Len(t, read.Frames, 2)
+	require.Len(t, read.Frames[0].Frames, 2)
+	require.Equal(t, "cpu", read.Frames[0].Frames[0].Name)
+	require.Equal(t, data.Labels{"host": "a"}, read.Frames[0].Frames[0].Fields[1].Labels)
+	require.Equal(t, 0.5, read.Frames[0].Frames[0].Fields[1].At(0))
+	require.True(t, created.Equal(read.Frames[0].Frames[0].Fields[0].At(0).(time.Time)))
+	require.Empty(t, read.Frames[1].Frames)
+	require.Equal(t, "the data source is unavailable", read.Frames[1].Error)
+
+	t.Run("returns an error for invalid archives", func(t *testing.T) {
+		_, err := ReadArchive([]byte("not a zip"))
+		require.ErrorIs(t, err, ErrInvalidArchive)
+
+		var empty bytes.Buffer
+		require.NoError(t, zip.NewWriter(&empty).Close())
+		_, err = ReadArchive(empty.Bytes())
+		require.ErrorIs(t, err, ErrInvalidArchive)
+
+		var invalid bytes.Buffer
+		zw := zip.NewWriter(&invalid)
+		w, err := zw.Create(archiveManifestName)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(`{"formatVersion": 1, "dashboard": {}, "panels": [{"panelId": 1, "frames": ["frames/1/0.arrow"]}]}`))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+		_, err = ReadArchive(invalid.Bytes())
+		require.ErrorIs(t, err, ErrInvalidArchive)
+	})
+
+	t.Run("returns an error for archives that read a file more than once", func(t *testing.T) {
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+
+		var duplicated bytes.Buffer
+		zw := zip.NewWriter(&duplicated)
+		for _, f := range append(zr.File, zr.File[0]) {
+			require.NoError(t, zw.Copy(f))
+		}
+		require.NoError(t, zw.Close())
+		_, err = ReadArchive(duplicated.Bytes())
+		require.EqualError(t, err, "invalid snapshot archive: frames/1/0.arrow is duplicated")
+
+		var repeated bytes.Buffer
+		zw = zip.NewWriter(&repeated)
+		for _, f := range zr.File {
+			if f.Name != archiveManifestName {
+				require.NoError(t, zw.Copy(f))
+			}
+		}
+		w, err := zw.Create(archiveManifestName)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(`{"formatVersion": 1, "dashboard": {}, "panels": [{"panelId": 1, "frames": ["frames/1/0.arrow", "frames/1/0.arrow"]}]}`))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+		_, err = ReadArchive(repeated.Bytes())
+		require.EqualError(t, err, "invalid snapshot archive: frames/1/0.arrow is referenced more than once")
+	})
+}
@@ -5,19 +5,22 @@ import (
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 )
 
 type Service struct {
-	SQLStore       sqlstore.Store
-	SecretsService secrets.Service
+	SQLStore         sqlstore.Store
+	SecretsService   secrets.Service
+	QueryDataService QueryDataService
 }
 
-func ProvideService(store sqlstore.Store, secretsService secrets.Service) *Service {
+func ProvideService(store sqlstore.Store, secretsService secrets.Service, queryDataService *query.Service) *Service {
 	s := &Service{
-		SQLStore:       store,
-		SecretsService: secretsService,
+		SQLStore:         store,
+		SecretsService:   secretsService,
+		QueryDataService: queryDataService,
 	}
 
 	return s
@@ -39,6 +42,39 @@ func (s *Service) CreateDashboardSnapshot(ctx context.Context, cmd *models.Creat
 	return s.SQLStore.CreateDashboardSnapshot(ctx, cmd)
 }
 
+// CreateDashboardSnapshotWithFrames creates a snapshot that stores the frames of its panels
+// encrypted, in addition to the dashboard.
+func (s *Service) CreateDashboardSnapshotWithFrames(ctx context.Context, cmd *models.CreateDashboardSnapshotCommand, frames []PanelFrames) error {
+	encodedFrames, err := encodeFrames(frames)
+	if err != nil {
+		return err
+	}
+
+	encryptedFrames, err := s.SecretsService.Encrypt(ctx, encodedFrames, secrets.WithoutScope())
+	if err != nil {
+		return err
+	}
+
+	cmd.FramesEncrypted = encryptedFrames
+
+	return s.CreateDashboardSnapshot(ctx, cmd)
+}
+
+// GetSnapshotFrames returns the frames of the panels of a snapshot, or nil if the snapshot was
+// created by the frontend and its data is in the dashboard.
+func (s *Service) GetSnapshotFrames(ctx context.Context, snapshot *models.DashboardSnapshot) ([]PanelFrames, error) {
+	if snapshot.FramesEncrypted == nil {
+		return nil, nil
+	}
+
+	decryptedFrames, err := s.SecretsService.Decrypt(ctx, snapshot.FramesEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeFrames(decryptedFrames)
+}
+
 func (s *Service) GetDashboardSnapshot(ctx context.Context, query *models.GetDashboardSnapshotQuery) error {
 	err := s.SQLStore.GetDashboardSnapshot(query)
 	if err != nil {
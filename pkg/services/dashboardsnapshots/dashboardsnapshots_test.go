@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/secrets/database"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -69,4 +71,37 @@ func TestDashboardSnapshotsService(t *testing.T) {
 
 		require.Equal(t, rawDashboard, decrypted)
 	})
+
+	t.Run("create dashboard snapshot with frames should encrypt the frames", func(t *testing.T) {
+		ctx := context.Background()
+
+		cmd := models.CreateDashboardSnapshotCommand{
+			Key:       "frames",
+			DeleteKey: "frames-delete",
+			Dashboard: dashboard,
+		}
+		frames := []PanelFrames{{PanelID: 1, Frames: data.Frames{data.NewFrame("cpu", data.NewField("value", nil, []float64{0.5}))}}}
+
+		err := s.CreateDashboardSnapshotWithFrames(ctx, &cmd, frames)
+		require.NoError(t, err)
+
+		query := models.GetDashboardSnapshotQuery{Key: "frames"}
+		err = s.GetDashboardSnapshot(ctx, &query)
+		require.NoError(t, err)
+
+		stored, err := s.GetSnapshotFrames(ctx, query.Result)
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		require.Equal(t, 0.5, stored[0].Frames[0].Fields[0].At(0))
+	})
+
+	t.Run("get snapshot frames should return nil for snapshots without frames", func(t *testing.T) {
+		query := models.GetDashboardSnapshotQuery{Key: dashboardKey}
+		err := s.GetDashboardSnapshot(context.Background(), &query)
+		require.NoError(t, err)
+
+		stored, err := s.GetSnapshotFrames(context.Background(), query.Result)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+	})
 }
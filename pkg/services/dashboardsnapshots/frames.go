@@ -0,0 +1,354 @@
+package dashboardsnapshots
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+)
+
+const (
+	// defaultMaxDataPoints is the number of data points of the queries of the panels that do not set it.
+	defaultMaxDataPoints = 1000
+	mixedDataSourceUID   = "-- Mixed --"
+)
+
+// QueryDataService runs the queries of the panels of snapshots created on the server.
+type QueryDataService interface {
+	QueryData(ctx context.Context, user *models.SignedInUser, skipCache bool, reqDTO dtos.MetricRequest, handleExpressions bool) (*backend.QueryDataResponse, error)
+}
+
+// PanelFrames are the data frames returned by the queries of a panel of a snapshot. Error is set
+// if some of the queries failed.
+type PanelFrames struct {
+	PanelID int64
+	Frames  data.Frames
+	Error   string
+}
+
+// encodedPanelFrames are the frames of a panel encoded with Arrow, as stored in the database.
+type encodedPanelFrames struct {
+	PanelID int64    `json:"panelId"`
+	Frames  [][]byte `json:"frames"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// QueryPanelFrames runs the queries of the panels of the dashboard over the time range from and
+// to, or over the time range of the dashboard if they are empty, and returns the frames of each
+// panel. The time range of the dashboard is set to the absolute time range of the queries, and the
+// queries are removed from the panels as for the snapshots created by the frontend.
+func (s *Service) QueryPanelFrames(ctx context.Context, user *models.SignedInUser, dashboard *simplejson.Json, from, to string) ([]PanelFrames, error) {
+	if from == "" || to == "" {
+		from = dashboard.GetPath("time", "from").MustString("now-6h")
+		to = dashboard.GetPath("time", "to").MustString("now")
+	}
+	timeRange := legacydata.NewDataTimeRange(from, to)
+	start, err := timeRange.ParseFrom()
+	if err != nil {
+		return nil, fmt.Errorf("invalid start of the time range: %w", err)
+	}
+	end, err := timeRange.ParseTo()
+	if err != nil {
+		return nil, fmt.Errorf("invalid end of the time range: %w", err)
+	}
+	if !start.Before(end) {
+		return nil, fmt.Errorf("the start of the time range must be before its end")
+	}
+
+	defaultDataSource, err := s.defaultDataSource(ctx, user.OrgId)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := templateVariables(dashboard)
+	var result []PanelFrames
+	for _, panel := range dashboardPanels(dashboard) {
+		if _, ok := panel.CheckGet("targets"); !ok {
+			continue
+		}
+		frames := PanelFrames{PanelID: panel.Get("id").MustInt64()}
+		var errs []string
+		for _, req := range panelRequests(panel, variables, defaultDataSource, start, end) {
+			if req.err != nil {
+				errs = append(errs, req.err.Error())
+				continue
+			}
+			resp, err := s.QueryDataService.QueryData(ctx, user, false, req.metricRequest, req.hasExpression)
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			refIDs := make([]string, 0, len(resp.Responses))
+			for refID := range resp.Responses {
+				refIDs = append(refIDs, refID)
+			}
+			sort.Strings(refIDs)
+			for _, refID := range refIDs {
+				res := resp.Responses[refID]
+				if res.Error != nil {
+					errs = append(errs, fmt.Sprintf("%s: %s", refID, res.Error))
+				}
+				for _, frame := range res.Frames {
+					if frame.RefID == "" {
+						frame.RefID = refID
+					}
+					frames.Frames = append(frames.Frames, frame)
+				}
+			}
+		}
+		frames.Error = strings.Join(errs, "; ")
+		result = append(result, frames)
+
+		panel.Set("targets", []interface{}{})
+		panel.Set("datasource", nil)
+	}
+
+	dashboard.Set("time", map[string]interface{}{
+		"from": start.UTC().Format(time.RFC3339Nano),
+		"to":   end.UTC().Format(time.RFC3339Nano),
+	})
+	dashboard.Set("refresh", "")
+	return result, nil
+}
+
+// panelRequest is a query request for the queries of a panel that use the same data source.
+type panelRequest struct {
+	metricRequest dtos.MetricRequest
+	hasExpression bool
+	err           error
+}
+
+// defaultDataSource returns the reference to the default data source of the organization, or nil if
+// it has none.
+func (s *Service) defaultDataSource(ctx context.Context, orgID int64) (interface{}, error) {
+	query := &models.GetDefaultDataSourceQuery{OrgId: orgID}
+	if err := s.SQLStore.GetDefaultDataSource(ctx, query); err != nil {
+		if errors.Is(err, models.ErrDataSourceNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the default data source: %w", err)
+	}
+	if query.Result == nil {
+		return nil, nil
+	}
+	return map[string]interface{}{"uid": query.Result.Uid, "type": query.Result.Type}, nil
+}
+
+// panelRequests returns a request per data source of the visible queries of the panel, or a single
+// request with all the queries if some of them are expressions. The template variables are replaced
+// with their current values in the queries. The panels and queries without data source use the
+// default data source, as in the frontend.
+func panelRequests(panel *simplejson.Json, variables map[string]templateVariable, defaultDataSource interface{}, start, end time.Time) []panelRequest {
+	panelDataSource := panel.Get("datasource").Interface()
+	if panelDataSource == nil {
+		panelDataSource = defaultDataSource
+	}
+	maxDataPoints := panel.Get("maxDataPoints").MustInt64(defaultMaxDataPoints)
+	if maxDataPoints <= 0 {
+		maxDataPoints = defaultMaxDataPoints
+	}
+	intervalMs := end.Sub(start).Milliseconds() / maxDataPoints
+	if intervalMs < 1 {
+		intervalMs = 1
+	}
+
+	var (
+		requests      []panelRequest
+		hasExpression bool
+		byDataSource  = map[string]int{}
+	)
+	for _, target := range panel.Get("targets").MustArray() {
+		query := simplejson.NewFromAny(target)
+		if query.Get("hide").MustBool() {
+			continue
+		}
+		if _, ok := query.CheckGet("datasource"); !ok || query.Get("datasource").Interface() == nil {
+			query.Set("datasource", panelDataSource)
+			if dataSourceUID(query.Get("datasource")) == mixedDataSourceUID {
+				query.Set("datasource", defaultDataSource)
+			}
+		}
+		interpolated, err := interpolateVariables(query.Interface(), variables)
+		if err != nil {
+			return []panelRequest{{err: fmt.Errorf("query %s: %w", query.Get("refId").MustString("A"), err)}}
+		}
+		query = simplejson.NewFromAny(interpolated)
+		uid := dataSourceUID(query.Get("datasource"))
+		if uid == "" || uid == mixedDataSourceUID {
+			return []panelRequest{{err: fmt.Errorf("the data source of query %s is not set", query.Get("refId").MustString("A"))}}
+		}
+		if _, ok := query.CheckGet("maxDataPoints"); !ok {
+			query.Set("maxDataPoints", maxDataPoints)
+		}
+		if _, ok := query.CheckGet("intervalMs"); !ok {
+			query.Set("intervalMs", intervalMs)
+		}
+		if expr.IsDataSource(uid) {
+			hasExpression = true
+		}
+
+		i, ok := byDataSource[uid]
+		if !ok {
+			i = len(requests)
+			byDataSource[uid] = i
+			requests = append(requests, panelRequest{metricRequest: dtos.MetricRequest{
+				From: fmt.Sprintf("%d", start.UnixMilli()),
+				To:   fmt.Sprintf("%d", end.UnixMilli()),
+			}})
+		}
+		requests[i].metricRequest.Queries = append(requests[i].metricRequest.Queries, query)
+	}
+
+	if hasExpression && len(requests) > 1 {
+		merged := panelRequest{metricRequest: requests[0].metricRequest, hasExpression: true}
+		merged.metricRequest.Queries = nil
+		for _, req := range requests {
+			merged.metricRequest.Queries = append(merged.metricRequest.Queries, req.metricRequest.Queries...)
+		}
+		return []panelRequest{merged}
+	}
+	for i := range requests {
+		requests[i].hasExpression = hasExpression
+	}
+	return requests
+}
+
+// dataSourceUID returns the UID of a data source reference, or its name for dashboards created
+// before references had UIDs.
+func dataSourceUID(ref *simplejson.Json) string {
+	if uid := ref.Get("uid").MustString(); uid != "" {
+		return uid
+	}
+	return ref.MustString()
+}
+
+// dashboardPanels returns the panels of the dashboard, including the panels of collapsed rows.
+func dashboardPanels(dashboard *simplejson.Json) []*simplejson.Json {
+	var panels []*simplejson.Json
+	for i := range dashboard.Get("panels").MustArray() {
+		panel := dashboard.Get("panels").GetIndex(i)
+		panels = append(panels, panel)
+		for j := range panel.Get("panels").MustArray() {
+			panels = append(panels, panel.Get("panels").GetIndex(j))
+		}
+	}
+	return panels
+}
+
+// EmbedPanelFrames sets the frames of the panels of the dashboard as their snapshot data, the way
+// the frontend stores the data of the snapshots it creates.
+func EmbedPanelFrames(dashboard *simplejson.Json, frames []PanelFrames) {
+	byID := make(map[int64]PanelFrames, len(frames))
+	for _, f := range frames {
+		byID[f.PanelID] = f
+	}
+	for _, panel := range dashboardPanels(dashboard) {
+		f, ok := byID[panel.Get("id").MustInt64()]
+		if !ok {
+			continue
+		}
+		snapshotData := make([]interface{}, 0, len(f.Frames))
+		for _, frame := range f.Frames {
+			snapshotData = append(snapshotData, frameToDTO(frame))
+		}
+		panel.Set("snapshotData", snapshotData)
+	}
+}
+
+// frameToDTO converts a frame to the data frame DTO of the frontend, with times in milliseconds.
+func frameToDTO(frame *data.Frame) map[string]interface{} {
+	fields := make([]interface{}, 0, len(frame.Fields))
+	for _, field := range frame.Fields {
+		values := make([]interface{}, field.Len())
+		for i := range values {
+			v, ok := field.ConcreteAt(i)
+			if !ok {
+				continue
+			}
+			if t, isTime := v.(time.Time); isTime {
+				v = t.UnixMilli()
+			}
+			values[i] = v
+		}
+		dto := map[string]interface{}{
+			"name":   field.Name,
+			"type":   fieldTypeName(field.Type()),
+			"values": values,
+		}
+		if field.Labels != nil {
+			dto["labels"] = field.Labels
+		}
+		if field.Config != nil {
+			dto["config"] = field.Config
+		} else {
+			dto["config"] = map[string]interface{}{}
+		}
+		fields = append(fields, dto)
+	}
+
+	dto := map[string]interface{}{
+		"name":   frame.Name,
+		"refId":  frame.RefID,
+		"fields": fields,
+	}
+	if frame.Meta != nil {
+		dto["meta"] = frame.Meta
+	}
+	return dto
+}
+
+func fieldTypeName(t data.FieldType) string {
+	switch {
+	case t.Time():
+		return "time"
+	case t.Numeric():
+		return "number"
+	case t == data.FieldTypeString || t == data.FieldTypeNullableString:
+		return "string"
+	case t == data.FieldTypeBool || t == data.FieldTypeNullableBool:
+		return "boolean"
+	}
+	return "other"
+}
+
+// encodeFrames encodes the frames of the panels of a snapshot with Arrow.
+func encodeFrames(frames []PanelFrames) ([]byte, error) {
+	encoded := make([]encodedPanelFrames, 0, len(frames))
+	for _, f := range frames {
+		b, err := f.Frames.MarshalArrow()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the frames of panel %d: %w", f.PanelID, err)
+		}
+		encoded = append(encoded, encodedPanelFrames{PanelID: f.PanelID, Frames: b, Error: f.Error})
+	}
+	return json.Marshal(encoded)
+}
+
+// decodeFrames decodes the frames of the panels of a snapshot encoded by encodeFrames.
+func decodeFrames(b []byte) ([]PanelFrames, error) {
+	var encoded []encodedPanelFrames
+	if err := json.Unmarshal(b, &encoded); err != nil {
+		return nil, err
+	}
+	frames := make([]PanelFrames, 0, len(encoded))
+	for _, e := range encoded {
+		f, err := data.UnmarshalArrowFrames(e.Frames)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the frames of panel %d: %w", e.PanelID, err)
+		}
+		frames = append(frames, PanelFrames{PanelID: e.PanelID, Frames: f, Error: e.Error})
+	}
+	return frames, nil
+}
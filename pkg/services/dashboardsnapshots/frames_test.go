@@ -0,0 +1,202 @@
+package dashboardsnapshots
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore/mockstore"
+)
+
+type fakeQueryDataService struct {
+	requests []dtos.MetricRequest
+}
+
+func (f *fakeQueryDataService) QueryData(_ context.Context, _ *models.SignedInUser, _ bool, reqDTO dtos.MetricRequest, _ bool) (*backend.QueryDataResponse, error) {
+	f.requests = append(f.requests, reqDTO)
+	resp := backend.NewQueryDataResponse()
+	for _, q := range reqDTO.Queries {
+		refID := q.Get("refId").MustString()
+		if q.Get("datasource").Get("uid").MustString() == "broken" {
+			return nil, errors.New("data source is unavailable")
+		}
+		resp.Responses[refID] = backend.DataResponse{Frames: data.Frames{
+			data.NewFrame("",
+				data.NewField("time", nil, []time.Time{time.UnixMilli(1000)}),
+				data.NewField("value", data.Labels{"ref": refID}, []float64{1}),
+			),
+		}}
+	}
+	return resp, nil
+}
+
+func testDashboard(t *testing.T) *simplejson.Json {
+	t.Helper()
+	dashboard, err := simplejson.NewJson([]byte(`{
+		"time": {"from": "now-1h", "to": "now"},
+		"refresh": "5s",
+		"panels": [
+			{
+				"id": 1,
+				"datasource": {"type": "prometheus", "uid": "prom"},
+				"targets": [{"refId": "A", "expr": "up"}, {"refId": "B", "expr": "down", "hide": true}]
+			},
+			{"id": 2, "type": "text"},
+			{
+				"id": 3,
+				"type": "row",
+				"panels": [{
+					"id": 4,
+					"datasource": {"uid": "-- Mixed --"},
+					"targets": [
+						{"refId": "A", "datasource": {"type": "prometheus", "uid": "prom"}},
+						{"refId": "B", "datasource": {"type": "loki", "uid": "loki"}}
+					]
+				}]
+			},
+			{
+				"id": 5,
+				"datasource": {"uid": "broken"},
+				"targets": [{"refId": "A"}]
+			},
+			{
+				"id": 6,
+				"datasource": null,
+				"targets": [{"refId": "A", "expr": "up"}]
+			}
+		]
+	}`))
+	require.NoError(t, err)
+	return dashboard
+}
+
+func TestQueryPanelFrames(t *testing.T) {
+	queryDataService := &fakeQueryDataService{}
+	sqlStore := mockstore.NewSQLStoreMock()
+	sqlStore.ExpectedDatasource = &models.DataSource{Uid: "default-prom", Type: "prometheus"}
+	s := &Service{SQLStore: sqlStore, QueryDataService: queryDataService}
+	dashboard := testDashboard(t)
+
+	frames, err := s.QueryPanelFrames(context.Background(), &models.SignedInUser{OrgId: 1}, dashboard, "1000", "61000")
+	require.NoError(t, err)
+	require.Len(t, frames, 4)
+
+	require.Equal(t, int64(1), frames[0].PanelID)
+	require.Len(t, frames[0].Frames, 1)
+	require.Equal(t, "A", frames[0].Frames[0].RefID)
+	require.Empty(t, frames[0].Error)
+
+	require.Equal(t, int64(4), frames[1].PanelID)
+	require.Len(t, frames[1].Frames, 2)
+
+	require.Equal(t, int64(5), frames[2].PanelID)
+	require.Equal(t, "data source is unavailable", frames[2].Error)
+
+	// the panels without data source use the default data source
+	require.Equal(t, int64(6), frames[3].PanelID)
+	require.Empty(t, frames[3].Error)
+	require.Len(t, frames[3].Frames, 1)
+	require.Equal(t, "default-prom", queryDataService.requests[4].Queries[0].Get("datasource").Get("uid").MustString())
+
+	// the queries of mixed panels are run per data source, without the hidden queries
+	require.Len(t, queryDataService.requests, 5)
+	require.Len(t, queryDataService.requests[0].Queries, 1)
+	require.Equal(t, "1000", queryDataService.requests[0].From)
+	require.Equal(t, "61000", queryDataService.requests[0].To)
+	require.Equal(t, int64(60), queryDataService.requests[0].Queries[0].Get("intervalMs").MustInt64())
+
+	require.Equal(t, "1970-01-01T00:00:01Z", dashboard.GetPath("time", "from").MustString())
+	require.Equal(t, "1970-01-01T00:01:01Z", dashboard.GetPath("time", "to").MustString())
+	require.Empty(t, dashboard.Get("refresh").MustString())
+	require.Empty(t, dashboard.Get("panels").GetIndex(0).Get("targets").MustArray())
+
+	t.Run("uses the default number of data points of the panels without one", func(t *testing.T) {
+		panel := simplejson.NewFromAny(map[string]interface{}{
+			"maxDataPoints": 0,
+			"datasource":    map[string]interface{}{"uid": "prom"},
+			"targets":       []interface{}{map[string]interface{}{"refId": "A"}},
+		})
+		requests := panelRequests(panel, nil, nil, time.UnixMilli(0), time.UnixMilli(60000))
+		require.Len(t, requests, 1)
+		require.NoError(t, requests[0].err)
+		require.Equal(t, int64(defaultMaxDataPoints), requests[0].metricRequest.Queries[0].Get("maxDataPoints").MustInt64())
+		require.Equal(t, int64(60), requests[0].metricRequest.Queries[0].Get("intervalMs").MustInt64())
+	})
+
+	t.Run("replaces the template variables of the queries", func(t *testing.T) {
+		queryDataService.requests = nil
+		dashboard, err := simplejson.NewJson([]byte(`{
+			"templating": {"list": [
+				{"name": "ds", "current": {"value": "prom"}},
+				{"name": "job", "current": {"value": "api"}},
+				{"name": "hosts", "current": {"value": ["a", "b"]}}
+			]},
+			"panels": [
+				{"id": 1, "datasource": {"uid": "${ds}"}, "targets": [{"refId": "A", "expr": "up{job=\"$job\"}"}]},
+				{"id": 2, "datasource": {"uid": "prom"}, "targets": [{"refId": "A", "expr": "up{host=~\"$hosts\"}"}]}
+			]
+		}`))
+		require.NoError(t, err)
+
+		frames, err := s.QueryPanelFrames(context.Background(), &models.SignedInUser{OrgId: 1}, dashboard, "1000", "61000")
+		require.NoError(t, err)
+		require.Len(t, queryDataService.requests, 1)
+		require.Equal(t, "prom", queryDataService.requests[0].Queries[0].Get("datasource").Get("uid").MustString())
+		require.Equal(t, `up{job="api"}`, queryDataService.requests[0].Queries[0].Get("expr").MustString())
+		require.Equal(t, "query A: the template variable hosts has several values, which is not supported", frames[1].Error)
+	})
+
+	t.Run("returns an error for invalid time ranges", func(t *testing.T) {
+		_, err := s.QueryPanelFrames(context.Background(), &models.SignedInUser{OrgId: 1}, testDashboard(t), "now", "now-1h")
+		require.Error(t, err)
+	})
+}
+
+func TestEmbedPanelFrames(t *testing.T) {
+	dashboard := testDashboard(t)
+	EmbedPanelFrames(dashboard, []PanelFrames{{
+		PanelID: 4,
+		Frames: data.Frames{
+			data.NewFrame("cpu",
+				data.NewField("time", nil, []time.Time{time.UnixMilli(1000)}),
+				data.NewField("value", nil, []*float64{nil}),
+			),
+		},
+	}})
+
+	snapshotData := dashboard.Get("panels").GetIndex(2).Get("panels").GetIndex(0).Get("snapshotData")
+	require.Len(t, snapshotData.MustArray(), 1)
+	frame := snapshotData.GetIndex(0)
+	require.Equal(t, "cpu", frame.Get("name").MustString())
+	require.Equal(t, "time", frame.Get("fields").GetIndex(0).Get("type").MustString())
+	require.Equal(t, []interface{}{int64(1000)}, frame.Get("fields").GetIndex(0).Get("values").MustArray())
+	require.Equal(t, "number", frame.Get("fields").GetIndex(1).Get("type").MustString())
+	require.Equal(t, []interface{}{nil}, frame.Get("fields").GetIndex(1).Get("values").MustArray())
+
+	_, ok := dashboard.Get("panels").GetIndex(0).CheckGet("snapshotData")
+	require.False(t, ok)
+}
+
+func TestEncodeFrames(t *testing.T) {
+	frames := []PanelFrames{
+		{PanelID: 1, Frames: data.Frames{data.NewFrame("a", data.NewField("value", nil, []float64{1, 2}))}},
+		{PanelID: 2, Error: "failed"},
+	}
+	b, err := encodeFrames(frames)
+	require.NoError(t, err)
+
+	decoded, err := decodeFrames(b)
+	require.NoError(t, err)
+	require.Len(t, decoded, 2)
+	require.Equal(t, int64(1), decoded[0].PanelID)
+	require.Equal(t, 2, decoded[0].Frames[0].Rows())
+	require.Equal(t, "failed", decoded[1].Error)
+}
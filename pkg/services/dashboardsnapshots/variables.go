@@ -0,0 +1,160 @@
+package dashboardsnapshots
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// allValue is the value of the variables set to All.
+const allValue = "$__all"
+
+// templateVariable is the current value of a template variable of a dashboard.
+type templateVariable struct {
+	values []string
+	text   string
+}
+
+// variablePattern matches the $name, ${name}, ${name:format} and [[name]] syntaxes of the template variables.
+var variablePattern = regexp.MustCompile(`\$(\w+)|\$\{(\w+)(?::([^}]+))?\}|\[\[(\w+)(?::([^\]]+))?\]\]`)
+
+// templateVariables returns the current values of the template variables of the dashboard, by name.
+// Variables set to All have the all value of the variable, or no value if it has none.
+func templateVariables(dashboard *simplejson.Json) map[string]templateVariable {
+	variables := map[string]templateVariable{}
+	for i := range dashboard.GetPath("templating", "list").MustArray() {
+		v := dashboard.GetPath("templating", "list").GetIndex(i)
+		name := v.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+
+		current := v.Get("current")
+		var values []string
+		if s, err := current.Get("value").String(); err == nil {
+			values = []string{s}
+		} else {
+			values = current.Get("value").MustStringArray()
+		}
+		for _, value := range values {
+			if value == allValue {
+				values = nil
+				if all := v.Get("allValue").MustString(); all != "" {
+					values = []string{all}
+				}
+				break
+			}
+		}
+
+		text, err := current.Get("text").String()
+		if err != nil {
+			text = strings.Join(current.Get("text").MustStringArray(), " + ")
+		}
+		variables[name] = templateVariable{values: values, text: text}
+	}
+	return variables
+}
+
+// interpolateVariables replaces the template variables of the dashboard in the strings of the query with their
+// current values. Variables with several values, or set to All without an all value, cannot be formatted
+// like the data source of the query would, so an error is returned for them. Other variables, such as the
+// global $__interval, are left to the data source.
+func interpolateVariables(query interface{}, variables map[string]templateVariable) (interface{}, error) {
+	switch v := query.(type) {
+	case string:
+		return interpolateString(v, variables)
+	case map[string]interface{}:
+		for key, value := range v {
+			interpolated, err := interpolateVariables(value, variables)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = interpolated
+		}
+		return v, nil
+	case []interface{}:
+		for i, value := range v {
+			interpolated, err := interpolateVariables(value, variables)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = interpolated
+		}
+		return v, nil
+	default:
+		return query, nil
+	}
+}
+
+func interpolateString(s string, variables map[string]templateVariable) (string, error) {
+	var err error
+	result := variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variablePattern.FindStringSubmatch(match)
+		name, format := groups[1], ""
+		if groups[2] != "" {
+			name, format = groups[2], groups[3]
+		} else if groups[4] != "" {
+			name, format = groups[4], groups[5]
+		}
+		variable, ok := variables[name]
+		if !ok || err != nil {
+			return match
+		}
+
+		switch len(variable.values) {
+		case 0:
+			err = fmt.Errorf("the template variable %s is set to All, which is only supported with an all value", name)
+			return match
+		case 1:
+		default:
+			err = fmt.Errorf("the template variable %s has several values, which is not supported", name)
+			return match
+		}
+
+		var formatted string
+		formatted, err = formatValue(variable, format)
+		if err != nil {
+			err = fmt.Errorf("the template variable %s: %w", name, err)
+			return match
+		}
+		return formatted
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// formatValue returns the single value of the variable with a format of the advanced variable formatting options.
+func formatValue(variable templateVariable, format string) (string, error) {
+	value := variable.values[0]
+	switch format {
+	case "", "raw", "csv", "pipe", "glob", "distributed":
+		return value, nil
+	case "text":
+		return variable.text, nil
+	case "regex":
+		return regexp.QuoteMeta(value), nil
+	case "singlequote":
+		return "'" + strings.ReplaceAll(value, "'", `\'`) + "'", nil
+	case "doublequote":
+		return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`, nil
+	case "sqlstring":
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'", nil
+	case "percentencode":
+		// as encodeURIComponent, which encodes spaces as %20
+		return strings.ReplaceAll(url.QueryEscape(value), "+", "%20"), nil
+	case "json":
+		b, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("the format %s is not supported", format)
+	}
+}
@@ -0,0 +1,52 @@
+package dashboardsnapshots
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestInterpolateVariables(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(`{
+		"templating": {"list": [
+			{"name": "job", "current": {"text": "API server", "value": "api"}},
+			{"name": "instance", "current": {"text": ["host:9090"], "value": ["host:9090"]}},
+			{"name": "ds", "current": {"text": "Prometheus", "value": "prom"}},
+			{"name": "env", "current": {"text": ["All"], "value": ["$__all"]}, "allValue": ".*"},
+			{"name": "team", "current": {"text": "All", "value": "$__all"}},
+			{"name": "hosts", "current": {"text": ["a", "b"], "value": ["a", "b"]}}
+		]}
+	}`))
+	require.NoError(t, err)
+	variables := templateVariables(dashboard)
+
+	t.Run("replaces the variables with their current values", func(t *testing.T) {
+		query := map[string]interface{}{
+			"expr":       `up{job="$job", instance="${instance:regex}", env=~"[[env]]"} / $__interval`,
+			"legend":     "${job:text} ${job:percentencode}",
+			"datasource": map[string]interface{}{"uid": "${ds}"},
+			"steps":      []interface{}{"$jobs", 1.0},
+		}
+		interpolated, err := interpolateVariables(query, variables)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"expr":       `up{job="api", instance="host:9090", env=~".*"} / $__interval`,
+			"legend":     "API server api",
+			"datasource": map[string]interface{}{"uid": "prom"},
+			"steps":      []interface{}{"$jobs", 1.0},
+		}, interpolated)
+	})
+
+	t.Run("returns an error for the values that cannot be formatted", func(t *testing.T) {
+		_, err := interpolateVariables("$team", variables)
+		require.EqualError(t, err, "the template variable team is set to All, which is only supported with an all value")
+
+		_, err = interpolateVariables("${hosts:csv}", variables)
+		require.EqualError(t, err, "the template variable hosts has several values, which is not supported")
+
+		_, err = interpolateVariables("${job:lucene}", variables)
+		require.EqualError(t, err, "the template variable job: the format lucene is not supported")
+	})
+}
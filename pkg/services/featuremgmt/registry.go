@@ -88,10 +88,9 @@ var (
 			State:       FeatureStateBeta,
 		},
 		{
-			Name:         "tempoServiceGraph",
-			Description:  "show service",
-			State:        FeatureStateBeta,
-			FrontendOnly: true,
+			Name:        "tempoServiceGraph",
+			Description: "show service",
+			State:       FeatureStateBeta,
 		},
 		{
 			Name:         "lokiBackendMode",
@@ -2,6 +2,7 @@ package features
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/models"
@@ -32,6 +33,13 @@ func (h *CommentHandler) OnSubscribe(ctx context.Context, user *models.SignedInU
 	}
 	objectType := parts[0]
 	objectID := parts[1]
+	if objectType == commentmodel.MentionChannelPrefix {
+		// users can only subscribe to the channel of their own mentions
+		if objectID != strconv.FormatInt(user.UserId, 10) || user.UserId <= 0 {
+			return models.SubscribeReply{}, backend.SubscribeStreamStatusPermissionDenied, nil
+		}
+		return models.SubscribeReply{}, backend.SubscribeStreamStatusOK, nil
+	}
 	ok, err := h.permissionChecker.CheckReadPermissions(ctx, user.OrgId, user, objectType, objectID)
 	if err != nil {
 		return models.SubscribeReply{}, 0, err
@@ -32,6 +32,7 @@ var timeNow = time.Now
 type Scheduler interface {
 	AlertmanagersFor(orgID int64) []*url.URL
 	DroppedAlertmanagersFor(orgID int64) []*url.URL
+	RuleEvaluationStats(orgID int64) []schedule.RuleEvaluationStats
 }
 
 type Alertmanager interface {
@@ -52,6 +53,12 @@ type Alertmanager interface {
 
 	// Testing
 	TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error)
+
+	// Notification log
+	ReplayNotification(ctx context.Context, id int64) (*models.NotificationLogEntry, error)
+
+	// Templates
+	PreviewTemplates(ctx context.Context, c apimodels.TemplatePreviewRequest, alerts apimodels.PostableAlerts) (*apimodels.TemplatePreviewResult, error)
 }
 
 type AlertingStore interface {
@@ -75,6 +82,9 @@ type API struct {
 	DataProxy            *datasourceproxy.DataSourceProxyService
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	StateManager         *state.Manager
+	StateHistoryStore    store.StateHistoryStore
+	RecordedSampleStore  store.RecordedSampleStore
+	NotificationLogStore store.NotificationLogStore
 	SecretsService       secrets.Service
 	AccessControl        accesscontrol.AccessControl
 	Policies             *provisioning.NotificationPolicyService
@@ -127,6 +137,33 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			log:               logger,
 			accessControl:     api.AccessControl,
 			evaluator:         eval.NewEvaluator(api.Cfg, log.New("ngalert.eval"), api.DatasourceCache, api.SecretsService),
+			cfg:               &api.Cfg.UnifiedAlerting,
+			appURL:            api.Cfg.AppURL,
+		}), m)
+	api.RegisterHistoryApiEndpoints(NewForkedHistoryApi(
+		&HistorySrv{
+			log:          logger,
+			ruleStore:    api.RuleStore,
+			historyStore: api.StateHistoryStore,
+		}), m)
+	api.RegisterRecordingApiEndpoints(NewForkedRecordingApi(
+		&RecordingSrv{
+			log:         logger,
+			ruleStore:   api.RuleStore,
+			sampleStore: api.RecordedSampleStore,
+		}), m)
+	api.RegisterNotificationLogApiEndpoints(NewForkedNotificationLogApi(
+		&NotificationLogSrv{
+			log:      logger,
+			mam:      api.MultiOrgAlertmanager,
+			logStore: api.NotificationLogStore,
+		}), m)
+	api.RegisterTemplatePreviewApiEndpoints(NewForkedTemplatePreviewApi(
+		&TemplatePreviewSrv{
+			log:     logger,
+			mam:     api.MultiOrgAlertmanager,
+			manager: api.StateManager,
+			appURL:  api.Cfg.AppURL,
 		}), m)
 	api.RegisterConfigurationApiEndpoints(NewForkedConfiguration(
 		&AdminSrv{
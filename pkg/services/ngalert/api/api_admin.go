@@ -2,19 +2,31 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/util"
 
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 )
 
+const (
+	defaultRuleStatsLimit = 20
+	maxRuleStatsLimit     = 1000
+
+	ruleStatsSortDuration = "duration"
+	ruleStatsSortFailures = "failures"
+)
+
 type AdminSrv struct {
 	scheduler Scheduler
 	store     store.AdminConfigurationStore
@@ -110,3 +122,69 @@ func (srv AdminSrv) RouteDeleteNGalertConfig(c *models.ReqContext) response.Resp
 
 	return response.JSON(http.StatusOK, util.DynMap{"message": "admin configuration deleted"})
 }
+
+func (srv AdminSrv) RouteGetRuleStats(c *models.ReqContext) response.Response {
+	sortBy := c.Query("sort")
+	if sortBy == "" {
+		sortBy = ruleStatsSortDuration
+	}
+	if sortBy != ruleStatsSortDuration && sortBy != ruleStatsSortFailures {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("sort must be one of [%s, %s]", ruleStatsSortDuration, ruleStatsSortFailures), "")
+	}
+	limit := defaultRuleStatsLimit
+	if s := c.Query("limit"); s != "" {
+		l, err := strconv.Atoi(s)
+		if err != nil || l <= 0 || l > maxRuleStatsLimit {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("limit must be a number between 1 and %d", maxRuleStatsLimit), "")
+		}
+		limit = l
+	}
+
+	stats := srv.scheduler.RuleEvaluationStats(c.OrgId)
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if sortBy == ruleStatsSortFailures && a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		if a.LastDuration != b.LastDuration {
+			return a.LastDuration > b.LastDuration
+		}
+		return a.UID < b.UID
+	})
+	if len(stats) > limit {
+		stats = stats[:limit]
+	}
+
+	result := apimodels.RuleEvaluationStatsList{Rules: make([]apimodels.RuleEvaluationStats, 0, len(stats))}
+	for _, s := range stats {
+		result.Rules = append(result.Rules, toRuleEvaluationStats(s))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func toRuleEvaluationStats(s schedule.RuleEvaluationStats) apimodels.RuleEvaluationStats {
+	r := apimodels.RuleEvaluationStats{
+		UID:                    s.UID,
+		Title:                  s.Title,
+		FolderUID:              s.NamespaceUID,
+		RuleGroup:              s.RuleGroup,
+		Evaluations:            s.Evaluations,
+		Failures:               s.Failures,
+		LastEvaluation:         s.LastEvaluation,
+		LastEvaluationDuration: s.LastDuration.Seconds(),
+		QueryDurations:         make(map[string]float64, len(s.QueryDurations)),
+		ResultSeries:           s.ResultSeries,
+		LastError:              s.LastError,
+	}
+	if s.Evaluations > 0 {
+		r.AverageEvaluationDuration = s.TotalDuration.Seconds() / float64(s.Evaluations)
+	}
+	for refID, d := range s.QueryDurations {
+		r.QueryDurations[refID] = d.Seconds()
+	}
+	if !s.LastErrorTime.IsZero() {
+		t := s.LastErrorTime
+		r.LastErrorTime = &t
+	}
+	return r
+}
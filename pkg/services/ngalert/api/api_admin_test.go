@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	models2 "github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRouteGetRuleStats(t *testing.T) {
+	const orgID int64 = 1
+	evaluatedAt := time.Unix(1640995200, 0)
+
+	scheduler := &schedule.FakeScheduleService{}
+	scheduler.On("RuleEvaluationStats", orgID).Return(func(int64) []schedule.RuleEvaluationStats {
+		return []schedule.RuleEvaluationStats{
+			{
+				OrgID:          orgID,
+				UID:            "fast",
+				Title:          "Fast rule",
+				Evaluations:    10,
+				LastEvaluation: evaluatedAt,
+				LastDuration:   100 * time.Millisecond,
+				TotalDuration:  time.Second,
+				QueryDurations: map[string]time.Duration{"A": 80 * time.Millisecond},
+				ResultSeries:   2,
+			},
+			{
+				OrgID:          orgID,
+				UID:            "slow",
+				Title:          "Slow rule",
+				Evaluations:    4,
+				Failures:       1,
+				LastEvaluation: evaluatedAt,
+				LastDuration:   5 * time.Second,
+				TotalDuration:  10 * time.Second,
+				ResultSeries:   300,
+			},
+			{
+				OrgID:          orgID,
+				UID:            "failing",
+				Title:          "Failing rule",
+				Evaluations:    4,
+				Failures:       4,
+				LastEvaluation: evaluatedAt,
+				LastDuration:   time.Second,
+				TotalDuration:  4 * time.Second,
+				LastError:      errors.New("query failed").Error(),
+				LastErrorTime:  evaluatedAt,
+			},
+		}
+	})
+
+	srv := AdminSrv{scheduler: scheduler, log: log.NewNopLogger()}
+	request := func(t *testing.T, query string) response.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/ngalert/rule_stats?"+query, nil)
+		require.NoError(t, err)
+		c := &models2.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &models2.SignedInUser{OrgId: orgID}}
+		return srv.RouteGetRuleStats(c)
+	}
+	uids := func(t *testing.T, r response.Response) []string {
+		t.Helper()
+		require.Equal(t, http.StatusOK, r.Status())
+		var stats apimodels.RuleEvaluationStatsList
+		require.NoError(t, json.Unmarshal(r.Body(), &stats))
+		result := make([]string, 0, len(stats.Rules))
+		for _, s := range stats.Rules {
+			result = append(result, s.UID)
+		}
+		return result
+	}
+
+	t.Run("returns the slowest rules first", func(t *testing.T) {
+		r := request(t, "")
+		require.Equal(t, []string{"slow", "failing", "fast"}, uids(t, r))
+
+		var stats apimodels.RuleEvaluationStatsList
+		require.NoError(t, json.Unmarshal(r.Body(), &stats))
+		require.Equal(t, 5.0, stats.Rules[0].LastEvaluationDuration)
+		require.Equal(t, 2.5, stats.Rules[0].AverageEvaluationDuration)
+		require.Equal(t, 300, stats.Rules[0].ResultSeries)
+		require.Nil(t, stats.Rules[0].LastErrorTime)
+		require.Equal(t, "query failed", stats.Rules[1].LastError)
+		require.NotNil(t, stats.Rules[1].LastErrorTime)
+		require.Equal(t, map[string]float64{"A": 0.08}, stats.Rules[2].QueryDurations)
+	})
+
+	t.Run("returns the rules with the most failures first", func(t *testing.T) {
+		require.Equal(t, []string{"failing", "slow", "fast"}, uids(t, request(t, "sort=failures")))
+	})
+
+	t.Run("limits the number of rules", func(t *testing.T) {
+		require.Equal(t, []string{"slow"}, uids(t, request(t, "limit=1")))
+	})
+
+	t.Run("returns 400 if the query is invalid", func(t *testing.T) {
+		for _, query := range []string{
+			"sort=title",
+			"limit=0",
+			"limit=many",
+			"limit=100000",
+		} {
+			require.Equal(t, http.StatusBadRequest, request(t, query).Status(), query)
+		}
+	})
+}
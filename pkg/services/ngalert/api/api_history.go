@@ -0,0 +1,126 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	defaultStateHistoryLimit = 100
+	maxStateHistoryLimit     = 5000
+)
+
+type HistorySrv struct {
+	log          log.Logger
+	ruleStore    store.RuleStore
+	historyStore store.StateHistoryStore
+}
+
+func (srv HistorySrv) RouteGetStateHistory(c *models.ReqContext) response.Response {
+	if srv.historyStore == nil {
+		return ErrResp(http.StatusNotFound, errors.New("state history is disabled"), "")
+	}
+
+	query, err := parseStateHistoryQuery(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	namespaceMap, err := srv.ruleStore.GetUserVisibleNamespaces(c.Req.Context(), c.OrgId, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	result := apimodels.StateHistory{Entries: []apimodels.StateHistoryEntry{}}
+	if len(namespaceMap) == 0 {
+		srv.log.Debug("User does not have access to any namespaces")
+		return response.JSON(http.StatusOK, result)
+	}
+	query.NamespaceUIDs = make([]string, 0, len(namespaceMap))
+	for uid := range namespaceMap {
+		query.NamespaceUIDs = append(query.NamespaceUIDs, uid)
+	}
+
+	if err := srv.historyStore.GetStateHistory(c.Req.Context(), query); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get state history")
+	}
+
+	for _, e := range query.Result {
+		result.Entries = append(result.Entries, apimodels.StateHistoryEntry{
+			RuleUID:       e.RuleUID,
+			Labels:        e.Labels,
+			PreviousState: string(e.PreviousState),
+			State:         string(e.State),
+			Values:        e.Values,
+			Error:         e.Error,
+			EvaluatedAt:   e.EvaluatedAt,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func parseStateHistoryQuery(c *models.ReqContext) (*ngmodels.GetStateHistoryQuery, error) {
+	query := &ngmodels.GetStateHistoryQuery{
+		OrgID:   c.OrgId,
+		RuleUID: c.Query("ruleUID"),
+		Limit:   defaultStateHistoryLimit,
+	}
+
+	for _, s := range c.QueryStrings("labels") {
+		m, err := labels.ParseMatcher(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label matcher %q: %w", s, err)
+		}
+		query.Matchers = append(query.Matchers, m)
+	}
+
+	if s := c.Query("state"); s != "" {
+		state := ngmodels.InstanceStateType(s)
+		if !state.IsValid() {
+			return nil, fmt.Errorf("invalid state %q", s)
+		}
+		query.State = state
+	}
+
+	var err error
+	if query.From, err = parseUnixMilli(c.Query("from")); err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+	if query.To, err = parseUnixMilli(c.Query("to")); err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return nil, errors.New("from must be before to")
+	}
+
+	if s := c.Query("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit <= 0 || limit > maxStateHistoryLimit {
+			return nil, fmt.Errorf("limit must be a number between 1 and %d", maxStateHistoryLimit)
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
+
+func parseUnixMilli(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, ms*int64(time.Millisecond)), nil
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	models2 "github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRouteGetStateHistory(t *testing.T) {
+	const orgID int64 = 1
+	evaluatedAt := time.Unix(1640995200, 0)
+
+	ruleStore := store.NewFakeRuleStore(t)
+	rule := models.AlertRuleGen(func(r *models.AlertRule) { r.OrgID = orgID })()
+	ruleStore.PutRule(context.Background(), rule)
+
+	historyStore := store.NewMemoryStateHistoryStore()
+	require.NoError(t, historyStore.SaveStateHistory(context.Background(), []models.StateHistoryEntry{
+		{
+			OrgID:         orgID,
+			RuleUID:       rule.UID,
+			NamespaceUID:  rule.NamespaceUID,
+			Labels:        models.InstanceLabels{"team": "ops"},
+			PreviousState: models.InstanceStateNormal,
+			State:         models.InstanceStateFiring,
+			EvaluatedAt:   evaluatedAt,
+		},
+		{
+			OrgID:         orgID,
+			RuleUID:       rule.UID,
+			NamespaceUID:  rule.NamespaceUID,
+			Labels:        models.InstanceLabels{"team": "dev"},
+			PreviousState: models.InstanceStateNormal,
+			State:         models.InstanceStateFiring,
+			EvaluatedAt:   evaluatedAt.Add(time.Minute),
+		},
+		{
+			OrgID:         orgID,
+			RuleUID:       "rule-in-another-folder",
+			NamespaceUID:  "another-folder",
+			Labels:        models.InstanceLabels{"team": "ops"},
+			PreviousState: models.InstanceStateNormal,
+			State:         models.InstanceStateFiring,
+			EvaluatedAt:   evaluatedAt,
+		},
+	}))
+
+	srv := HistorySrv{
+		log:          log.NewNopLogger(),
+		ruleStore:    ruleStore,
+		historyStore: historyStore,
+	}
+	request := func(t *testing.T, srv HistorySrv, query string) response.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/rules/history?"+query, nil)
+		require.NoError(t, err)
+		c := &models2.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &models2.SignedInUser{OrgId: orgID}}
+		return srv.RouteGetStateHistory(c)
+	}
+
+	t.Run("returns the history of the rules the user can access", func(t *testing.T) {
+		r := request(t, srv, "")
+		require.Equal(t, http.StatusOK, r.Status())
+		var history apimodels.StateHistory
+		require.NoError(t, json.Unmarshal(r.Body(), &history))
+		require.Len(t, history.Entries, 2)
+		require.Equal(t, "dev", history.Entries[0].Labels["team"])
+		require.Equal(t, "ops", history.Entries[1].Labels["team"])
+		require.Equal(t, rule.UID, history.Entries[1].RuleUID)
+		require.Equal(t, "Normal", history.Entries[1].PreviousState)
+		require.Equal(t, "Alerting", history.Entries[1].State)
+	})
+
+	t.Run("filters the history", func(t *testing.T) {
+		r := request(t, srv, `labels=team%3D"ops"&state=Alerting&ruleUID=`+rule.UID)
+		require.Equal(t, http.StatusOK, r.Status())
+		var history apimodels.StateHistory
+		require.NoError(t, json.Unmarshal(r.Body(), &history))
+		require.Len(t, history.Entries, 1)
+		require.Equal(t, "ops", history.Entries[0].Labels["team"])
+
+		r = request(t, srv, "from=1640995260000")
+		require.Equal(t, http.StatusOK, r.Status())
+		require.NoError(t, json.Unmarshal(r.Body(), &history))
+		require.Len(t, history.Entries, 1)
+		require.Equal(t, "dev", history.Entries[0].Labels["team"])
+	})
+
+	t.Run("returns 400 if the query is invalid", func(t *testing.T) {
+		for _, query := range []string{
+			"labels=team",
+			"state=Unknown",
+			"from=yesterday",
+			"from=2000&to=1000",
+			"limit=0",
+			"limit=100000",
+		} {
+			require.Equal(t, http.StatusBadRequest, request(t, srv, query).Status(), query)
+		}
+	})
+
+	t.Run("returns 404 if the state history is disabled", func(t *testing.T) {
+		disabled := HistorySrv{log: log.NewNopLogger(), ruleStore: ruleStore}
+		require.Equal(t, http.StatusNotFound, request(t, disabled, "").Status())
+	})
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	defaultNotificationLogLimit = 100
+	maxNotificationLogLimit     = 5000
+)
+
+type NotificationLogSrv struct {
+	log      log.Logger
+	mam      *notifier.MultiOrgAlertmanager
+	logStore store.NotificationLogStore
+}
+
+func (srv NotificationLogSrv) RouteGetNotificationLog(c *models.ReqContext) response.Response {
+	if srv.logStore == nil {
+		return ErrResp(http.StatusNotFound, notifier.ErrNotificationLogDisabled, "")
+	}
+
+	query, err := parseNotificationLogQuery(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err := srv.logStore.GetNotificationLog(c.Req.Context(), query); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get notification log")
+	}
+
+	result := apimodels.NotificationLog{Entries: make([]apimodels.NotificationLogEntry, 0, len(query.Result))}
+	for _, e := range query.Result {
+		result.Entries = append(result.Entries, newNotificationLogEntry(e))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv NotificationLogSrv) RouteReplayNotification(c *models.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":NotificationID"], 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, errors.New("notification ID must be a number"), "")
+	}
+
+	am, errResp := AlertmanagerSrv{mam: srv.mam, log: srv.log}.AlertmanagerFor(c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+	entry, err := am.ReplayNotification(c.Req.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, notifier.ErrNotificationLogDisabled), errors.Is(err, store.ErrNotificationLogEntryNotFound):
+			return ErrResp(http.StatusNotFound, err, "")
+		case errors.Is(err, notifier.ErrNotificationNotFailed), errors.Is(err, notifier.ErrIntegrationNotFound):
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		var invalidReceiverErr notifier.InvalidReceiverError
+		if errors.As(err, &invalidReceiverErr) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to replay notification")
+	}
+	return response.JSON(http.StatusOK, newNotificationLogEntry(entry))
+}
+
+func parseNotificationLogQuery(c *models.ReqContext) (*ngmodels.GetNotificationLogQuery, error) {
+	query := &ngmodels.GetNotificationLogQuery{
+		OrgID:           c.OrgId,
+		Receiver:        c.Query("receiver"),
+		IntegrationType: c.Query("integration"),
+		IntegrationUID:  c.Query("integrationUID"),
+		Limit:           defaultNotificationLogLimit,
+	}
+
+	if s := c.Query("status"); s != "" {
+		status := ngmodels.NotificationStatus(s)
+		if !status.IsValid() {
+			return nil, fmt.Errorf("invalid status %q, it must be %s or %s", s, ngmodels.NotificationStatusSuccess, ngmodels.NotificationStatusFailed)
+		}
+		query.Status = status
+	}
+
+	var err error
+	if query.From, err = parseUnixMilli(c.Query("from")); err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+	if query.To, err = parseUnixMilli(c.Query("to")); err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return nil, errors.New("from must be before to")
+	}
+
+	if s := c.Query("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit <= 0 || limit > maxNotificationLogLimit {
+			return nil, fmt.Errorf("limit must be a number between 1 and %d", maxNotificationLogLimit)
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
+
+func newNotificationLogEntry(e *ngmodels.NotificationLogEntry) apimodels.NotificationLogEntry {
+	entry := apimodels.NotificationLogEntry{
+		ID:              e.ID,
+		Receiver:        e.Receiver,
+		IntegrationType: e.IntegrationType,
+		IntegrationName: e.IntegrationName,
+		IntegrationUID:  e.IntegrationUID,
+		GroupKey:        e.GroupKey,
+		GroupLabels:     make(map[string]string, len(e.GroupLabels)),
+		Status:          string(e.Status),
+		StatusCode:      e.StatusCode,
+		Error:           e.Error,
+		DurationMs:      e.Duration.Milliseconds(),
+		Payload:         e.Payload,
+		Alerts:          make([]apimodels.NotificationLogAlert, 0, len(e.Alerts)),
+		ReplayOf:        e.ReplayOf,
+		SentAt:          e.SentAt,
+	}
+	for k, v := range e.GroupLabels {
+		entry.GroupLabels[string(k)] = string(v)
+	}
+	for _, a := range e.Alerts {
+		alert := apimodels.NotificationLogAlert{
+			Labels:      make(map[string]string, len(a.Labels)),
+			Annotations: make(map[string]string, len(a.Annotations)),
+			StartsAt:    a.StartsAt,
+			EndsAt:      a.EndsAt,
+		}
+		for k, v := range a.Labels {
+			alert.Labels[string(k)] = string(v)
+		}
+		for k, v := range a.Annotations {
+			alert.Annotations[string(k)] = string(v)
+		}
+		entry.Alerts = append(entry.Alerts, alert)
+	}
+	return entry
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	models2 "github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRouteGetNotificationLog(t *testing.T) {
+	const orgID int64 = 1
+	sentAt := time.Unix(1640995200, 0)
+	alerts := []*types.Alert{{Alert: model.Alert{Labels: model.LabelSet{"alertname": "HighLatency"}, StartsAt: sentAt}}}
+
+	logStore := &store.FakeNotificationLogStore{}
+	for _, e := range []*models.NotificationLogEntry{
+		{
+			OrgID:           orgID,
+			Receiver:        "ops",
+			IntegrationType: "webhook",
+			IntegrationUID:  "uid-1",
+			Status:          models.NotificationStatusFailed,
+			StatusCode:      503,
+			Error:           "webhook response status 503 Service Unavailable",
+			Duration:        150 * time.Millisecond,
+			Alerts:          alerts,
+			SentAt:          sentAt,
+		},
+		{
+			OrgID:           orgID,
+			Receiver:        "dev",
+			IntegrationType: "email",
+			IntegrationUID:  "uid-2",
+			Status:          models.NotificationStatusSuccess,
+			Alerts:          alerts,
+			SentAt:          sentAt.Add(time.Minute),
+		},
+		{
+			OrgID:           2,
+			Receiver:        "ops",
+			IntegrationType: "webhook",
+			Status:          models.NotificationStatusFailed,
+			Alerts:          alerts,
+			SentAt:          sentAt,
+		},
+	} {
+		require.NoError(t, logStore.SaveNotificationLogEntry(context.Background(), e))
+	}
+
+	srv := NotificationLogSrv{log: log.NewNopLogger(), logStore: logStore}
+	request := func(t *testing.T, srv NotificationLogSrv, query string) response.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "/api/alertmanager/grafana/api/v1/notifications?"+query, nil)
+		require.NoError(t, err)
+		c := &models2.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &models2.SignedInUser{OrgId: orgID}}
+		return srv.RouteGetNotificationLog(c)
+	}
+
+	t.Run("returns the notification log of the organization", func(t *testing.T) {
+		r := request(t, srv, "")
+		require.Equal(t, http.StatusOK, r.Status())
+		var notificationLog apimodels.NotificationLog
+		require.NoError(t, json.Unmarshal(r.Body(), &notificationLog))
+		require.Len(t, notificationLog.Entries, 2)
+		require.Equal(t, "dev", notificationLog.Entries[0].Receiver)
+		failed := notificationLog.Entries[1]
+		require.Equal(t, "failed", failed.Status)
+		require.Equal(t, 503, failed.StatusCode)
+		require.Equal(t, int64(150), failed.DurationMs)
+		require.Len(t, failed.Alerts, 1)
+		require.Equal(t, "HighLatency", failed.Alerts[0].Labels["alertname"])
+	})
+
+	t.Run("filters the notification log", func(t *testing.T) {
+		var notificationLog apimodels.NotificationLog
+		r := request(t, srv, "status=failed&receiver=ops&integration=webhook")
+		require.Equal(t, http.StatusOK, r.Status())
+		require.NoError(t, json.Unmarshal(r.Body(), &notificationLog))
+		require.Len(t, notificationLog.Entries, 1)
+		require.Equal(t, "uid-1", notificationLog.Entries[0].IntegrationUID)
+
+		r = request(t, srv, "from=1640995260000")
+		require.Equal(t, http.StatusOK, r.Status())
+		require.NoError(t, json.Unmarshal(r.Body(), &notificationLog))
+		require.Len(t, notificationLog.Entries, 1)
+		require.Equal(t, "dev", notificationLog.Entries[0].Receiver)
+	})
+
+	t.Run("returns 400 if the query is invalid", func(t *testing.T) {
+		for _, query := range []string{
+			"status=pending",
+			"from=yesterday",
+			"from=2000&to=1000",
+			"limit=0",
+			"limit=100000",
+		} {
+			require.Equal(t, http.StatusBadRequest, request(t, srv, query).Status(), query)
+		}
+	})
+
+	t.Run("returns 404 if the notification log is disabled", func(t *testing.T) {
+		disabled := NotificationLogSrv{log: log.NewNopLogger()}
+		require.Equal(t, http.StatusNotFound, request(t, disabled, "").Status())
+	})
+}
+
+func TestRouteReplayNotification(t *testing.T) {
+	srv := NotificationLogSrv{log: log.NewNopLogger(), mam: createMultiOrgAlertmanager(t)}
+	request := func(t *testing.T, orgID int64, id string) response.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, "/api/alertmanager/grafana/api/v1/notifications/"+id+"/replay", nil)
+		require.NoError(t, err)
+		req = web.SetURLParams(req, map[string]string{":NotificationID": id})
+		c := &models2.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &models2.SignedInUser{OrgId: orgID}}
+		return srv.RouteReplayNotification(c)
+	}
+
+	t.Run("returns 400 if the ID is not a number", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, request(t, 1, "abc").Status())
+	})
+
+	t.Run("returns 404 if the organization has no Alertmanager", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, request(t, 10, "1").Status())
+	})
+
+	t.Run("returns 404 if the notification log is disabled", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, request(t, 1, "1").Status())
+	})
+}
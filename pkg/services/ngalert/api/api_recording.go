@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	defaultRecordedSamplesLimit = 100
+	maxRecordedSamplesLimit     = 5000
+)
+
+type RecordingSrv struct {
+	log         log.Logger
+	ruleStore   store.RuleStore
+	sampleStore store.RecordedSampleStore
+}
+
+func (srv RecordingSrv) RouteGetRecordedSamples(c *models.ReqContext) response.Response {
+	if srv.sampleStore == nil {
+		return ErrResp(http.StatusNotFound, errors.New("recording rules are disabled or do not write to the internal target"), "")
+	}
+
+	query, err := parseRecordedSamplesQuery(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	namespaceMap, err := srv.ruleStore.GetUserVisibleNamespaces(c.Req.Context(), c.OrgId, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	result := apimodels.RecordedSamples{Samples: []apimodels.RecordedSample{}}
+	if len(namespaceMap) == 0 {
+		srv.log.Debug("User does not have access to any namespaces")
+		return response.JSON(http.StatusOK, result)
+	}
+	query.NamespaceUIDs = make([]string, 0, len(namespaceMap))
+	for uid := range namespaceMap {
+		query.NamespaceUIDs = append(query.NamespaceUIDs, uid)
+	}
+
+	if err := srv.sampleStore.GetRecordedSamples(c.Req.Context(), query); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get recorded samples")
+	}
+
+	for _, s := range query.Result {
+		result.Samples = append(result.Samples, apimodels.RecordedSample{
+			RuleUID:   s.RuleUID,
+			Metric:    s.Metric,
+			Labels:    s.Labels,
+			Value:     s.Value,
+			Timestamp: s.Timestamp,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func parseRecordedSamplesQuery(c *models.ReqContext) (*ngmodels.GetRecordedSamplesQuery, error) {
+	query := &ngmodels.GetRecordedSamplesQuery{
+		OrgID:  c.OrgId,
+		Metric: c.Query("metric"),
+		Limit:  defaultRecordedSamplesLimit,
+	}
+
+	for _, s := range c.QueryStrings("labels") {
+		m, err := labels.ParseMatcher(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label matcher %q: %w", s, err)
+		}
+		query.Matchers = append(query.Matchers, m)
+	}
+
+	var err error
+	if query.From, err = parseUnixMilli(c.Query("from")); err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+	if query.To, err = parseUnixMilli(c.Query("to")); err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return nil, errors.New("from must be before to")
+	}
+
+	if s := c.Query("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit <= 0 || limit > maxRecordedSamplesLimit {
+			return nil, fmt.Errorf("limit must be a number between 1 and %d", maxRecordedSamplesLimit)
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
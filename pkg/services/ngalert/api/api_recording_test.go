@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	models2 "github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRouteGetRecordedSamples(t *testing.T) {
+	const orgID int64 = 1
+	evaluatedAt := time.Unix(1640995200, 0)
+
+	ruleStore := store.NewFakeRuleStore(t)
+	rule := models.AlertRuleGen(func(r *models.AlertRule) { r.OrgID = orgID })()
+	ruleStore.PutRule(context.Background(), rule)
+
+	sampleStore := &store.FakeRecordedSampleStore{}
+	require.NoError(t, sampleStore.SaveRecordedSamples(context.Background(), []models.RecordedSample{
+		{
+			OrgID:        orgID,
+			RuleUID:      rule.UID,
+			NamespaceUID: rule.NamespaceUID,
+			Metric:       "job:requests:rate5m",
+			Labels:       models.InstanceLabels{"job": "api"},
+			Value:        1,
+			Timestamp:    evaluatedAt,
+		},
+		{
+			OrgID:        orgID,
+			RuleUID:      rule.UID,
+			NamespaceUID: rule.NamespaceUID,
+			Metric:       "job:requests:rate5m",
+			Labels:       models.InstanceLabels{"job": "web"},
+			Value:        2,
+			Timestamp:    evaluatedAt.Add(time.Minute),
+		},
+		{
+			OrgID:        orgID,
+			RuleUID:      "rule-in-another-folder",
+			NamespaceUID: "another-folder",
+			Metric:       "job:requests:rate5m",
+			Labels:       models.InstanceLabels{"job": "api"},
+			Value:        3,
+			Timestamp:    evaluatedAt,
+		},
+	}))
+
+	srv := RecordingSrv{
+		log:         log.NewNopLogger(),
+		ruleStore:   ruleStore,
+		sampleStore: sampleStore,
+	}
+	request := func(t *testing.T, srv RecordingSrv, query string) response.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/rules/recordings?"+query, nil)
+		require.NoError(t, err)
+		c := &models2.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &models2.SignedInUser{OrgId: orgID}}
+		return srv.RouteGetRecordedSamples(c)
+	}
+
+	t.Run("returns the samples of the rules the user can access", func(t *testing.T) {
+		r := request(t, srv, "")
+		require.Equal(t, http.StatusOK, r.Status())
+		var samples apimodels.RecordedSamples
+		require.NoError(t, json.Unmarshal(r.Body(), &samples))
+		require.Len(t, samples.Samples, 2)
+		require.Equal(t, "web", samples.Samples[0].Labels["job"])
+		require.Equal(t, float64(2), samples.Samples[0].Value)
+		require.Equal(t, "api", samples.Samples[1].Labels["job"])
+		require.Equal(t, rule.UID, samples.Samples[1].RuleUID)
+		require.Equal(t, "job:requests:rate5m", samples.Samples[1].Metric)
+	})
+
+	t.Run("filters the samples", func(t *testing.T) {
+		r := request(t, srv, `metric=job:requests:rate5m&labels=job%3D"api"`)
+		require.Equal(t, http.StatusOK, r.Status())
+		var samples apimodels.RecordedSamples
+		require.NoError(t, json.Unmarshal(r.Body(), &samples))
+		require.Len(t, samples.Samples, 1)
+		require.Equal(t, "api", samples.Samples[0].Labels["job"])
+
+		r = request(t, srv, "from=1640995260000")
+		require.Equal(t, http.StatusOK, r.Status())
+		require.NoError(t, json.Unmarshal(r.Body(), &samples))
+		require.Len(t, samples.Samples, 1)
+		require.Equal(t, "web", samples.Samples[0].Labels["job"])
+
+		r = request(t, srv, "metric=job:errors:rate5m")
+		require.Equal(t, http.StatusOK, r.Status())
+		require.NoError(t, json.Unmarshal(r.Body(), &samples))
+		require.Empty(t, samples.Samples)
+	})
+
+	t.Run("returns 400 if the query is invalid", func(t *testing.T) {
+		for _, query := range []string{
+			"labels=job",
+			"from=yesterday",
+			"from=2000&to=1000",
+			"limit=0",
+			"limit=100000",
+		} {
+			require.Equal(t, http.StatusBadRequest, request(t, srv, query).Status(), query)
+		}
+	})
+
+	t.Run("returns 404 if the internal target is not used", func(t *testing.T) {
+		disabled := RecordingSrv{log: log.NewNopLogger(), ruleStore: ruleStore}
+		require.Equal(t, http.StatusNotFound, request(t, disabled, "").Status())
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -366,6 +367,19 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *models.ReqContext, ruleGroupConf
 	return srv.updateAlertRulesInGroup(c, namespace, ruleGroupConfig.Name, rules)
 }
 
+// checkDependencyCycles returns an error if the alert rules of the organization depend on each other in a cycle.
+// Such rules would inhibit each other, so they are rejected when they are saved.
+func (srv RulerSrv) checkDependencyCycles(ctx context.Context, orgID int64) error {
+	q := ngmodels.ListAlertRulesQuery{OrgID: orgID}
+	if err := srv.store.ListAlertRules(ctx, &q); err != nil {
+		return fmt.Errorf("failed to get the alert rules of the organization: %w", err)
+	}
+	if cycle := ngmodels.FindDependencyCycle(q.Result); cycle != nil {
+		return fmt.Errorf("%w: the rules %s depend on each other in a cycle", ngmodels.ErrAlertRuleFailedValidation, strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
 // updateAlertRulesInGroup calculates changes (rules to add,update,delete), verifies that the user is authorized to do the calculated changes and updates database.
 // All operations are performed in a single transaction
 //nolint: gocyclo
@@ -465,6 +479,9 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *models.ReqContext, namespace *mod
 			if err != nil {
 				return fmt.Errorf("failed to update rules: %w", err)
 			}
+			if err = srv.checkDependencyCycles(tranCtx, c.SignedInUser.OrgId); err != nil {
+				return err
+			}
 		}
 
 		if len(finalChanges.Delete) > 0 {
@@ -549,6 +566,8 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provena
 			NoDataState:     apimodels.NoDataState(r.NoDataState),
 			ExecErrState:    apimodels.ExecutionErrorState(r.ExecErrState),
 			Provenance:      provenance,
+			Record:          r.Record,
+			DependsOn:       r.DependsOn,
 		},
 	}
 	gettableExtendedRuleNode.ApiRuleNode = &apimodels.ApiRuleNode{
@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/prometheus/common/model"
+
 	"github.com/grafana/grafana/pkg/models"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -86,9 +88,23 @@ func validateRuleNode(
 		}
 	}
 
+	record, err := validateRecord(ruleNode, canPatch, cfg)
+	if err != nil {
+		return nil, err
+	}
+	dependsOn, err := validateDependsOn(ruleNode, record)
+	if err != nil {
+		return nil, err
+	}
+	condition := ruleNode.GrafanaManagedAlert.Condition
+	if record != nil {
+		// recording rules are evaluated like alert rules but the condition is the recorded query or expression
+		condition = record.From
+	}
+
 	if len(ruleNode.GrafanaManagedAlert.Data) != 0 {
 		cond := ngmodels.Condition{
-			Condition: ruleNode.GrafanaManagedAlert.Condition,
+			Condition: condition,
 			OrgID:     orgId,
 			Data:      ruleNode.GrafanaManagedAlert.Data,
 		}
@@ -100,7 +116,7 @@ func validateRuleNode(
 	newAlertRule := ngmodels.AlertRule{
 		OrgID:           orgId,
 		Title:           ruleNode.GrafanaManagedAlert.Title,
-		Condition:       ruleNode.GrafanaManagedAlert.Condition,
+		Condition:       condition,
 		Data:            ruleNode.GrafanaManagedAlert.Data,
 		UID:             ruleNode.GrafanaManagedAlert.UID,
 		IntervalSeconds: intervalSeconds,
@@ -108,6 +124,8 @@ func validateRuleNode(
 		RuleGroup:       groupName,
 		NoDataState:     noDataState,
 		ExecErrState:    errorState,
+		Record:          record,
+		DependsOn:       dependsOn,
 	}
 
 	if ruleNode.ApiRuleNode != nil {
@@ -135,6 +153,61 @@ func validateRuleNode(
 	return &newAlertRule, nil
 }
 
+// validateRecord validates the recording rule part of API model (definitions.PostableExtendedRuleNode) and converts it to models.Record.
+// Returns nil if the rule is not a recording rule.
+func validateRecord(ruleNode *apimodels.PostableExtendedRuleNode, canPatch bool, cfg *setting.UnifiedAlertingSettings) (*ngmodels.Record, error) {
+	r := ruleNode.GrafanaManagedAlert.Record
+	if r == nil {
+		return nil, nil
+	}
+	if !cfg.RecordingRules.Enabled {
+		return nil, fmt.Errorf("%w: recording rules are disabled", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	if len(ruleNode.GrafanaManagedAlert.Data) == 0 && canPatch {
+		return nil, fmt.Errorf("%w: record is specified but query is not. You must specify both query and record to update existing recording rule", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	if !model.IsValidMetricName(model.LabelValue(r.Metric)) {
+		return nil, fmt.Errorf("%w: metric name %q of recording rule is not a valid Prometheus metric name", ngmodels.ErrAlertRuleFailedValidation, r.Metric)
+	}
+	if r.From == "" {
+		return nil, fmt.Errorf("%w: recording rule must specify the query or expression to record", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	if ruleNode.GrafanaManagedAlert.Condition != "" && ruleNode.GrafanaManagedAlert.Condition != r.From {
+		return nil, fmt.Errorf("%w: condition of recording rule must be either empty or the recorded query or expression %s", ngmodels.ErrAlertRuleFailedValidation, r.From)
+	}
+	if ruleNode.ApiRuleNode != nil && ruleNode.ApiRuleNode.For != 0 {
+		return nil, fmt.Errorf("%w: recording rule cannot have a pending period", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	return &ngmodels.Record{Metric: r.Metric, From: r.From}, nil
+}
+
+// validateDependsOn validates the dependencies of API model (definitions.PostableExtendedRuleNode) and converts them to models.RuleDependencies.
+// Returns nil if the rule does not depend on other rules.
+func validateDependsOn(ruleNode *apimodels.PostableExtendedRuleNode, record *ngmodels.Record) (*ngmodels.RuleDependencies, error) {
+	d := ruleNode.GrafanaManagedAlert.DependsOn
+	if d == nil {
+		return nil, nil
+	}
+	if record != nil {
+		return nil, fmt.Errorf("%w: recording rule cannot depend on other rules", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	if len(d.RuleUIDs) == 0 && len(d.Matchers) == 0 {
+		return nil, fmt.Errorf("%w: dependencies must specify either rule UIDs or matchers", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	for _, uid := range d.RuleUIDs {
+		if uid == "" {
+			return nil, fmt.Errorf("%w: dependencies cannot contain an empty rule UID", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		if uid == ruleNode.GrafanaManagedAlert.UID {
+			return nil, fmt.Errorf("%w: rule cannot depend on itself", ngmodels.ErrAlertRuleFailedValidation)
+		}
+	}
+	if _, err := d.LabelMatchers(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err.Error())
+	}
+	return &ngmodels.RuleDependencies{RuleUIDs: d.RuleUIDs, Matchers: d.Matchers}, nil
+}
+
 // validateRuleGroup validates API model (definitions.PostableRuleGroupConfig) and converts it to a collection of models.AlertRule.
 // Returns a slice that contains all rules described by API model or error if either group specification or an alert definition is not valid.
 func validateRuleGroup(
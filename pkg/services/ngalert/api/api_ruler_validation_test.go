@@ -714,3 +714,158 @@ func TestValidateRuleNodeIntervalFailures(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRuleNode_RecordingRule(t *testing.T) {
+	orgId := rand.Int63()
+	folder := randFolder()
+	cfg := config(t)
+	cfg.RecordingRules.Enabled = true
+	successValidation := func(condition models.Condition) error {
+		return nil
+	}
+	recordingRule := func() *apimodels.PostableExtendedRuleNode {
+		r := validRule()
+		r.ApiRuleNode.For = 0
+		r.GrafanaManagedAlert.Condition = ""
+		r.GrafanaManagedAlert.Record = &models.Record{Metric: "job:requests:rate5m", From: "A"}
+		return &r
+	}
+
+	t.Run("converts api model to recording rule", func(t *testing.T) {
+		api := recordingRule()
+		var validated models.Condition
+		rule, err := validateRuleNode(api, "", cfg.BaseInterval, orgId, folder, func(condition models.Condition) error {
+			validated = condition
+			return nil
+		}, cfg)
+		require.NoError(t, err)
+		require.True(t, rule.IsRecordingRule())
+		require.Equal(t, *api.GrafanaManagedAlert.Record, *rule.Record)
+		require.Equal(t, "A", rule.Condition)
+		require.Equal(t, "A", validated.Condition)
+	})
+
+	testCases := []struct {
+		name   string
+		mutate func(r *apimodels.PostableExtendedRuleNode, cfg *setting.UnifiedAlertingSettings)
+	}{
+		{
+			name: "fail if recording rules are disabled",
+			mutate: func(r *apimodels.PostableExtendedRuleNode, cfg *setting.UnifiedAlertingSettings) {
+				cfg.RecordingRules.Enabled = false
+			},
+		},
+		{
+			name: "fail if metric name is not valid",
+			mutate: func(r *apimodels.PostableExtendedRuleNode, cfg *setting.UnifiedAlertingSettings) {
+				r.GrafanaManagedAlert.Record.Metric = "requests-rate"
+			},
+		},
+		{
+			name: "fail if recorded query is not specified",
+			mutate: func(r *apimodels.PostableExtendedRuleNode, cfg *setting.UnifiedAlertingSettings) {
+				r.GrafanaManagedAlert.Record.From = ""
+			},
+		},
+		{
+			name: "fail if condition is not the recorded query",
+			mutate: func(r *apimodels.PostableExtendedRuleNode, cfg *setting.UnifiedAlertingSettings) {
+				r.GrafanaManagedAlert.Condition = "B"
+			},
+		},
+		{
+			name: "fail if pending period is specified",
+			mutate: func(r *apimodels.PostableExtendedRuleNode, cfg *setting.UnifiedAlertingSettings) {
+				r.ApiRuleNode.For = model.Duration(time.Minute)
+			},
+		},
+		{
+			name: "fail if record is specified without data to update existing rule",
+			mutate: func(r *apimodels.PostableExtendedRuleNode, cfg *setting.UnifiedAlertingSettings) {
+				r.GrafanaManagedAlert.Data = nil
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := recordingRule()
+			c := *cfg
+			testCase.mutate(r, &c)
+			_, err := validateRuleNode(r, "", c.BaseInterval, orgId, folder, successValidation, &c)
+			require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		})
+	}
+}
+
+func TestValidateRuleNode_DependsOn(t *testing.T) {
+	orgId := rand.Int63()
+	folder := randFolder()
+	cfg := config(t)
+	cfg.RecordingRules.Enabled = true
+	successValidation := func(condition models.Condition) error {
+		return nil
+	}
+	dependentRule := func() *apimodels.PostableExtendedRuleNode {
+		r := validRule()
+		r.GrafanaManagedAlert.DependsOn = &models.RuleDependencies{
+			RuleUIDs: []string{"parent"},
+			Matchers: []string{`alertname="DatabaseDown"`},
+		}
+		return &r
+	}
+
+	t.Run("converts api model to rule with dependencies", func(t *testing.T) {
+		api := dependentRule()
+		rule, err := validateRuleNode(api, "", cfg.BaseInterval, orgId, folder, successValidation, cfg)
+		require.NoError(t, err)
+		require.Equal(t, *api.GrafanaManagedAlert.DependsOn, *rule.DependsOn)
+	})
+
+	testCases := []struct {
+		name   string
+		mutate func(r *apimodels.PostableExtendedRuleNode)
+	}{
+		{
+			name: "fail if dependencies are empty",
+			mutate: func(r *apimodels.PostableExtendedRuleNode) {
+				r.GrafanaManagedAlert.DependsOn = &models.RuleDependencies{}
+			},
+		},
+		{
+			name: "fail if rule UID is empty",
+			mutate: func(r *apimodels.PostableExtendedRuleNode) {
+				r.GrafanaManagedAlert.DependsOn.RuleUIDs = []string{""}
+			},
+		},
+		{
+			name: "fail if rule depends on itself",
+			mutate: func(r *apimodels.PostableExtendedRuleNode) {
+				r.GrafanaManagedAlert.DependsOn.RuleUIDs = []string{r.GrafanaManagedAlert.UID}
+			},
+		},
+		{
+			name: "fail if matcher is not valid",
+			mutate: func(r *apimodels.PostableExtendedRuleNode) {
+				r.GrafanaManagedAlert.DependsOn.Matchers = []string{"alertname"}
+			},
+		},
+		{
+			name: "fail if rule is a recording rule",
+			mutate: func(r *apimodels.PostableExtendedRuleNode) {
+				r.ApiRuleNode.For = 0
+				r.GrafanaManagedAlert.Condition = ""
+				r.GrafanaManagedAlert.Record = &models.Record{Metric: "job:requests:rate5m", From: "A"}
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := dependentRule()
+			testCase.mutate(r)
+			_, err := validateRuleNode(r, "", cfg.BaseInterval, orgId, folder, successValidation, cfg)
+			require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+type TemplatePreviewSrv struct {
+	log     log.Logger
+	mam     *notifier.MultiOrgAlertmanager
+	manager *state.Manager
+	appURL  string
+}
+
+func (srv TemplatePreviewSrv) RoutePostTemplatePreview(c *models.ReqContext, body apimodels.TemplatePreviewRequest) response.Response {
+	if body.RuleUID == "" && len(body.Matchers) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("either a rule UID or label matchers must be set"), "")
+	}
+	matchers := make(labels.Matchers, 0, len(body.Matchers))
+	for _, s := range body.Matchers {
+		m, err := labels.ParseMatcher(s)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid label matcher %q: %w", s, err), "")
+		}
+		matchers = append(matchers, m)
+	}
+
+	var states []*state.State
+	if body.RuleUID != "" {
+		states = srv.manager.GetStatesForRuleUID(c.OrgId, body.RuleUID)
+	} else {
+		states = srv.manager.GetAll(c.OrgId)
+	}
+	firing := make([]*state.State, 0, len(states))
+	for _, s := range states {
+		if s.State == eval.Alerting && matchesAll(matchers, s.Labels) {
+			firing = append(firing, s)
+		}
+	}
+	if len(firing) == 0 {
+		return ErrResp(http.StatusNotFound, errors.New("no firing alerts match the rule or the label matchers"), "")
+	}
+
+	appURL, err := url.Parse(srv.appURL)
+	if err != nil {
+		srv.log.Error("failed to parse application URL", "url", srv.appURL, "err", err)
+		appURL = nil
+	}
+	alerts := schedule.StatesToPostableAlerts(firing, appURL)
+
+	am, errResp := AlertmanagerSrv{mam: srv.mam, log: srv.log}.AlertmanagerFor(c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+	result, err := am.PreviewTemplates(c.Req.Context(), body, alerts)
+	if err != nil {
+		var invalidTemplateErr notifier.InvalidTemplateError
+		switch {
+		case errors.Is(err, notifier.ErrReceiverNotFound):
+			return ErrResp(http.StatusNotFound, err, "")
+		case errors.As(err, &invalidTemplateErr):
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to preview templates")
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// matchesAll returns true if the labels match all the matchers.
+func matchesAll(matchers labels.Matchers, lbs map[string]string) bool {
+	for _, m := range matchers {
+		if !m.Matches(lbs[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	models2 "github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRoutePostTemplatePreview(t *testing.T) {
+	manager := state.NewManager(log.New("test"), metrics.NewNGAlert(prometheus.NewRegistry()).GetStateMetrics(), nil, nil, nil, nil, nil)
+	t.Cleanup(manager.Close)
+	manager.Put([]*state.State{
+		{
+			OrgID:        1,
+			AlertRuleUID: "rule-1",
+			CacheId:      "a",
+			State:        eval.Alerting,
+			Labels:       data.Labels{"alertname": "HighLatency", "team": "ops"},
+			StartsAt:     time.Now(),
+		},
+		{
+			OrgID:        1,
+			AlertRuleUID: "rule-1",
+			CacheId:      "b",
+			State:        eval.Normal,
+			Labels:       data.Labels{"alertname": "HighLatency", "team": "dev"},
+		},
+		{
+			OrgID:        1,
+			AlertRuleUID: "rule-2",
+			CacheId:      "c",
+			State:        eval.Alerting,
+			Labels:       data.Labels{"alertname": "HighErrorRate", "team": "dev"},
+			StartsAt:     time.Now(),
+		},
+	})
+
+	srv := TemplatePreviewSrv{
+		log:     log.NewNopLogger(),
+		mam:     createMultiOrgAlertmanager(t),
+		manager: manager,
+		appURL:  "http://localhost:3000",
+	}
+	request := func(t *testing.T, body apimodels.TemplatePreviewRequest) response.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, "/api/alertmanager/grafana/config/api/v1/templates/preview", nil)
+		require.NoError(t, err)
+		c := &models2.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &models2.SignedInUser{OrgId: 1}}
+		return srv.RoutePostTemplatePreview(c, body)
+	}
+
+	t.Run("previews the firing alerts of a rule", func(t *testing.T) {
+		r := request(t, apimodels.TemplatePreviewRequest{RuleUID: "rule-1", Integrations: []string{"webhook"}})
+		require.Equal(t, http.StatusOK, r.Status())
+		var result apimodels.TemplatePreviewResult
+		require.NoError(t, json.Unmarshal(r.Body(), &result))
+		require.Equal(t, 1, result.Alerts)
+		require.Len(t, result.Previews, 1)
+		require.Equal(t, "[FIRING:1] HighLatency ops ", result.Previews[0].Title)
+	})
+
+	t.Run("previews the firing alerts that match the label matchers", func(t *testing.T) {
+		r := request(t, apimodels.TemplatePreviewRequest{Matchers: []string{"team=dev"}, Integrations: []string{"webhook"}})
+		require.Equal(t, http.StatusOK, r.Status())
+		var result apimodels.TemplatePreviewResult
+		require.NoError(t, json.Unmarshal(r.Body(), &result))
+		require.Equal(t, 1, result.Alerts)
+		require.Equal(t, "[FIRING:1] HighErrorRate dev ", result.Previews[0].Title)
+	})
+
+	t.Run("returns 400 if the request is invalid", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, request(t, apimodels.TemplatePreviewRequest{}).Status())
+		require.Equal(t, http.StatusBadRequest, request(t, apimodels.TemplatePreviewRequest{Matchers: []string{"team"}}).Status())
+		require.Equal(t, http.StatusBadRequest, request(t, apimodels.TemplatePreviewRequest{RuleUID: "rule-1", Template: `{{ define "x" }}`}).Status())
+	})
+
+	t.Run("returns 404 if no firing alerts match", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, request(t, apimodels.TemplatePreviewRequest{RuleUID: "rule-1", Matchers: []string{"team=dev"}}).Status())
+	})
+
+	t.Run("returns 404 if the contact point does not exist", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, request(t, apimodels.TemplatePreviewRequest{RuleUID: "rule-1", Receiver: "unknown"}).Status())
+	})
+}
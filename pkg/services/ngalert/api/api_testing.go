@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -19,6 +20,8 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -30,8 +33,13 @@ type TestingApiSrv struct {
 	log               log.Logger
 	accessControl     accesscontrol.AccessControl
 	evaluator         eval.Evaluator
+	cfg               *setting.UnifiedAlertingSettings
+	appURL            string
 }
 
+// maxBacktestEvaluations is the maximum number of evaluations of all rules in a single backtest request.
+const maxBacktestEvaluations = 1000
+
 func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *models.ReqContext, body apimodels.TestRulePayload) response.Response {
 	if body.Type() != apimodels.GrafanaBackend || body.GrafanaManagedCondition == nil {
 		return ErrResp(http.StatusBadRequest, errors.New("unexpected payload"), "")
@@ -58,7 +66,7 @@ func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *models.ReqContext, body a
 		now = timeNow()
 	}
 
-	evalResults, err := srv.evaluator.ConditionEval(&evalCond, now, srv.ExpressionService)
+	evalResults, err := srv.evaluator.ConditionEval(c.Req.Context(), &evalCond, now, srv.ExpressionService)
 	if err != nil {
 		return ErrResp(http.StatusBadRequest, err, "Failed to evaluate conditions")
 	}
@@ -127,10 +135,98 @@ func (srv TestingApiSrv) RouteEvalQueries(c *models.ReqContext, cmd apimodels.Ev
 		return ErrResp(http.StatusBadRequest, err, "invalid queries or expressions")
 	}
 
-	evalResults, err := srv.evaluator.QueriesAndExpressionsEval(c.SignedInUser.OrgId, cmd.Data, now, srv.ExpressionService)
+	evalResults, err := srv.evaluator.QueriesAndExpressionsEval(c.Req.Context(), c.SignedInUser.OrgId, cmd.Data, now, srv.ExpressionService)
 	if err != nil {
 		return ErrResp(http.StatusBadRequest, err, "Failed to evaluate queries and expressions")
 	}
 
 	return response.JSONStreaming(http.StatusOK, evalResults)
 }
+
+func (srv TestingApiSrv) RouteBacktestRuleGroup(c *models.ReqContext, body apimodels.BacktestPayload) response.Response {
+	if body.From.IsZero() || body.To.IsZero() || !body.From.Before(body.To) {
+		return ErrResp(http.StatusBadRequest, errors.New("from must be before to"), "invalid time range")
+	}
+
+	rules, err := validateRuleGroup(&body.RuleGroup, c.SignedInUser.OrgId, &models.Folder{}, conditionValidator(c, srv.DatasourceCache), srv.cfg)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid rule group")
+	}
+
+	for _, rule := range rules {
+		if rule.IsRecordingRule() {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("rule '%s' is a recording rule, only alert rules can be backtested", rule.Title), "invalid rule group")
+		}
+		if !authorizeDatasourceAccessForRule(rule, func(evaluator accesscontrol.Evaluator) bool {
+			return accesscontrol.HasAccess(srv.accessControl, c)(accesscontrol.ReqSignedIn, evaluator)
+		}) {
+			return ErrResp(http.StatusUnauthorized, fmt.Errorf("%w to query one or many data sources used by the rule", ErrAuthorization), "")
+		}
+	}
+
+	interval := time.Duration(body.Interval)
+	if interval == 0 && len(rules) > 0 {
+		interval = time.Duration(rules[0].IntervalSeconds) * time.Second
+	}
+	if interval <= 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("interval must be a positive duration"), "invalid interval")
+	}
+	// every rule is evaluated at the start of each interval between from and to, including from itself.
+	if evaluations := (int64(body.To.Sub(body.From)/interval) + 1) * int64(len(rules)); evaluations > maxBacktestEvaluations {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("the time range and interval require %d evaluations of %d rules, the maximum is %d", evaluations, len(rules), maxBacktestEvaluations), "too many evaluations")
+	}
+
+	appURL, err := url.Parse(srv.appURL)
+	if err != nil {
+		srv.log.Warn("failed to parse application URL. Continue without it.", "err", err)
+		appURL = nil
+	}
+	backtester := state.NewBacktester(srv.log, appURL)
+
+	result := apimodels.BacktestResponse{Rules: make([]apimodels.BacktestRuleResult, 0, len(rules))}
+	for idx, rule := range rules {
+		if rule.UID == "" {
+			// instances of different rules must not share the same state.
+			rule.UID = fmt.Sprintf("backtest-%d", idx)
+		}
+		// backtesting uses the interval of the request so that stale instances are detected correctly.
+		rule.IntervalSeconds = int64(interval.Seconds())
+		condition := ngmodels.Condition{
+			Condition: rule.Condition,
+			OrgID:     rule.OrgID,
+			Data:      rule.Data,
+		}
+		ruleResult := apimodels.BacktestRuleResult{
+			Title:       rule.Title,
+			Transitions: []apimodels.BacktestStateTransition{},
+		}
+		for now := body.From; !now.After(body.To); now = now.Add(interval) {
+			queries, err := ngmodels.PatchThresholdLoadedDimensions(rule.Data, backtester.FiringDimensions(rule))
+			if err != nil {
+				return ErrResp(http.StatusBadRequest, err, "failed to evaluate rule %s", rule.Title)
+			}
+			condition.Data = queries
+			results, err := srv.evaluator.ConditionEval(c.Req.Context(), &condition, now, srv.ExpressionService)
+			if err != nil {
+				return ErrResp(http.StatusBadRequest, err, "failed to evaluate rule %s", rule.Title)
+			}
+			ruleResult.Evaluations++
+			for _, t := range backtester.ProcessEvalResults(c.Req.Context(), rule, results) {
+				transition := apimodels.BacktestStateTransition{
+					EvaluatedAt:   t.EvaluatedAt,
+					Labels:        t.Labels,
+					PreviousState: t.PreviousState.String(),
+					State:         t.State.String(),
+					Values:        t.Values,
+				}
+				if t.Error != nil {
+					transition.Error = t.Error.Error()
+				}
+				ruleResult.Transitions = append(ruleResult.Transitions, transition)
+			}
+		}
+		result.Rules = append(result.Rules, ruleResult)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -68,7 +69,7 @@ func TestRouteTestGrafanaRuleConfig(t *testing.T) {
 
 			evaluator := &eval.FakeEvaluator{}
 			var result []eval.Result
-			evaluator.EXPECT().ConditionEval(mock.Anything, mock.Anything, mock.Anything).Return(result, nil)
+			evaluator.EXPECT().ConditionEval(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(result, nil)
 
 			srv := createTestingApiSrv(ds, ac, evaluator)
 
@@ -83,7 +84,7 @@ func TestRouteTestGrafanaRuleConfig(t *testing.T) {
 
 			require.Equal(t, http.StatusOK, response.Status())
 
-			evaluator.AssertCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything)
+			evaluator.AssertCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 		})
 	})
 
@@ -108,7 +109,7 @@ func TestRouteTestGrafanaRuleConfig(t *testing.T) {
 
 			evaluator := &eval.FakeEvaluator{}
 			var result []eval.Result
-			evaluator.EXPECT().ConditionEval(mock.Anything, mock.Anything, mock.Anything).Return(result, nil)
+			evaluator.EXPECT().ConditionEval(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(result, nil)
 
 			srv := createTestingApiSrv(ds, ac, evaluator)
 
@@ -122,7 +123,7 @@ func TestRouteTestGrafanaRuleConfig(t *testing.T) {
 			})
 
 			require.Equal(t, http.StatusUnauthorized, response.Status())
-			evaluator.AssertNotCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything)
+			evaluator.AssertNotCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 
 			rc.IsSignedIn = true
 
@@ -137,7 +138,7 @@ func TestRouteTestGrafanaRuleConfig(t *testing.T) {
 
 			require.Equal(t, http.StatusOK, response.Status())
 
-			evaluator.AssertCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything)
+			evaluator.AssertCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 		})
 	})
 }
@@ -196,7 +197,7 @@ func TestRouteEvalQueries(t *testing.T) {
 					},
 				},
 			}
-			evaluator.EXPECT().QueriesAndExpressionsEval(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(result, nil)
+			evaluator.EXPECT().QueriesAndExpressionsEval(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(result, nil)
 
 			srv := createTestingApiSrv(ds, ac, evaluator)
 
@@ -207,7 +208,7 @@ func TestRouteEvalQueries(t *testing.T) {
 
 			require.Equal(t, http.StatusOK, response.Status())
 
-			evaluator.AssertCalled(t, "QueriesAndExpressionsEval", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			evaluator.AssertCalled(t, "QueriesAndExpressionsEval", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 		})
 	})
 
@@ -239,7 +240,7 @@ func TestRouteEvalQueries(t *testing.T) {
 					},
 				},
 			}
-			evaluator.EXPECT().QueriesAndExpressionsEval(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(result, nil)
+			evaluator.EXPECT().QueriesAndExpressionsEval(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(result, nil)
 
 			srv := createTestingApiSrv(ds, ac, evaluator)
 
@@ -249,7 +250,7 @@ func TestRouteEvalQueries(t *testing.T) {
 			})
 
 			require.Equal(t, http.StatusUnauthorized, response.Status())
-			evaluator.AssertNotCalled(t, "QueriesAndExpressionsEval", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			evaluator.AssertNotCalled(t, "QueriesAndExpressionsEval", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 
 			rc.IsSignedIn = true
 
@@ -260,11 +261,69 @@ func TestRouteEvalQueries(t *testing.T) {
 
 			require.Equal(t, http.StatusOK, response.Status())
 
-			evaluator.AssertCalled(t, "QueriesAndExpressionsEval", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			evaluator.AssertCalled(t, "QueriesAndExpressionsEval", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 		})
 	})
 }
 
+func TestRouteBacktestRuleGroup(t *testing.T) {
+	rc := &models2.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &models2.SignedInUser{
+			OrgId: 1,
+		},
+	}
+
+	t.Run("should return 400 if the time range is invalid", func(t *testing.T) {
+		evaluator := &eval.FakeEvaluator{}
+		srv := createTestingApiSrv(nil, nil, evaluator)
+		now := time.Now()
+
+		testCases := map[string]definitions.BacktestPayload{
+			"empty":   {},
+			"no from": {To: now},
+			"no to":   {From: now},
+			"reverse": {From: now, To: now.Add(-time.Hour)},
+			"equal":   {From: now, To: now},
+		}
+		for name, payload := range testCases {
+			t.Run(name, func(t *testing.T) {
+				response := srv.RouteBacktestRuleGroup(rc, payload)
+				require.Equal(t, http.StatusBadRequest, response.Status())
+			})
+		}
+		evaluator.AssertNotCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 400 if the evaluations of all rules exceed the maximum", func(t *testing.T) {
+		ds := &datasources.FakeCacheService{DataSources: []*models2.DataSource{
+			{Uid: "DATASOURCE_TEST"},
+		}}
+		evaluator := &eval.FakeEvaluator{}
+		srv := createTestingApiSrv(ds, nil, evaluator)
+		srv.cfg = config(t)
+		from := time.Now()
+
+		// each rule is evaluated fewer times than the maximum, but both rules are evaluated more often.
+		rc := &models2.ReqContext{
+			Context:      &web.Context{Req: &http.Request{}},
+			IsSignedIn:   true,
+			SignedInUser: &models2.SignedInUser{OrgId: 1},
+		}
+		response := srv.RouteBacktestRuleGroup(rc, definitions.BacktestPayload{
+			From:      from,
+			To:        from.Add(maxBacktestEvaluations / 2 * time.Minute),
+			Interval:  model.Duration(time.Minute),
+			RuleGroup: validGroup(srv.cfg, validRule(), validRule()),
+		})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		require.Contains(t, string(response.Body()), "too many evaluations")
+		evaluator.AssertNotCalled(t, "ConditionEval", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 func createTestingApiSrv(ds *datasources.FakeCacheService, ac *acMock.Mock, evaluator *eval.FakeEvaluator) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New().WithDisabled()
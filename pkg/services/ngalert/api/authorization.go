@@ -65,11 +65,23 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodGet + "/api/prometheus/grafana/api/v1/rules":
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 
+	// Grafana State History Paths
+	case http.MethodGet + "/api/v1/rules/history":
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+
+	// Grafana Recorded Samples Paths
+	case http.MethodGet + "/api/v1/rules/recordings":
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+
 	// Grafana Rules Testing Paths
 	case http.MethodPost + "/api/v1/rule/test/grafana":
 		fallback = middleware.ReqSignedIn
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/backtest":
+		fallback = middleware.ReqSignedIn
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 	case http.MethodPost + "/api/v1/eval":
 		fallback = middleware.ReqSignedIn
 		// additional authorization is done in the request handler
@@ -161,6 +173,13 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/receivers/test":
 		fallback = middleware.ReqEditorRole
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
+	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/templates/preview":
+		fallback = middleware.ReqEditorRole
+		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
+	case http.MethodGet + "/api/alertmanager/grafana/api/v1/notifications":
+		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
+	case http.MethodPost + "/api/alertmanager/grafana/api/v1/notifications/{NotificationID}/replay":
+		eval = ac.EvalPermission(ac.ActionAlertingNotificationsWrite)
 
 	// External Alertmanager Paths
 	case http.MethodDelete + "/api/alertmanager/{Recipient}/config/api/v1/alerts":
@@ -178,7 +197,8 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodDelete + "/api/v1/ngalert/admin_config",
 		http.MethodGet + "/api/v1/ngalert/admin_config",
 		http.MethodPost + "/api/v1/ngalert/admin_config",
-		http.MethodGet + "/api/v1/ngalert/alertmanagers":
+		http.MethodGet + "/api/v1/ngalert/alertmanagers",
+		http.MethodGet + "/api/v1/ngalert/rule_stats":
 		return middleware.ReqOrgAdmin
 
 	// Grafana-only Provisioning Read Paths
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// ForkedNotificationLogApi always forwards requests to grafana backend
+type ForkedNotificationLogApi struct {
+	grafana *NotificationLogSrv
+}
+
+// NewForkedNotificationLogApi creates a new ForkedNotificationLogApi instance
+func NewForkedNotificationLogApi(grafana *NotificationLogSrv) *ForkedNotificationLogApi {
+	return &ForkedNotificationLogApi{
+		grafana: grafana,
+	}
+}
+
+func (f *ForkedNotificationLogApi) forkRouteGetNotificationLog(c *models.ReqContext) response.Response {
+	return f.grafana.RouteGetNotificationLog(c)
+}
+
+func (f *ForkedNotificationLogApi) forkRouteReplayNotification(c *models.ReqContext) response.Response {
+	return f.grafana.RouteReplayNotification(c)
+}
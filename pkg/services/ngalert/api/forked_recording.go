@@ -0,0 +1,22 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// ForkedRecordingApi always forwards requests to grafana backend
+type ForkedRecordingApi struct {
+	grafana *RecordingSrv
+}
+
+// NewForkedRecordingApi creates a new ForkedRecordingApi instance
+func NewForkedRecordingApi(grafana *RecordingSrv) *ForkedRecordingApi {
+	return &ForkedRecordingApi{
+		grafana: grafana,
+	}
+}
+
+func (f *ForkedRecordingApi) forkRouteGetRecordedSamples(c *models.ReqContext) response.Response {
+	return f.grafana.RouteGetRecordedSamples(c)
+}
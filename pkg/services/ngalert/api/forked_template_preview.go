@@ -0,0 +1,23 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// ForkedTemplatePreviewApi always forwards requests to grafana backend
+type ForkedTemplatePreviewApi struct {
+	grafana *TemplatePreviewSrv
+}
+
+// NewForkedTemplatePreviewApi creates a new ForkedTemplatePreviewApi instance
+func NewForkedTemplatePreviewApi(grafana *TemplatePreviewSrv) *ForkedTemplatePreviewApi {
+	return &ForkedTemplatePreviewApi{
+		grafana: grafana,
+	}
+}
+
+func (f *ForkedTemplatePreviewApi) forkRoutePostTemplatePreview(c *models.ReqContext, body apimodels.TemplatePreviewRequest) response.Response {
+	return f.grafana.RoutePostTemplatePreview(c, body)
+}
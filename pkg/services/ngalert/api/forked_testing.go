@@ -29,3 +29,7 @@ func (f *ForkedTestingApi) forkRouteTestRuleGrafanaConfig(c *models.ReqContext,
 func (f *ForkedTestingApi) forkRouteEvalQueries(c *models.ReqContext, body apimodels.EvalQueriesPayload) response.Response {
 	return f.svc.RouteEvalQueries(c, body)
 }
+
+func (f *ForkedTestingApi) forkRouteBacktestRuleGroup(c *models.ReqContext, body apimodels.BacktestPayload) response.Response {
+	return f.svc.RouteBacktestRuleGroup(c, body)
+}
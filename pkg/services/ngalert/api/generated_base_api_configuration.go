@@ -23,6 +23,7 @@ type ConfigurationApiForkingService interface {
 	RouteDeleteNGalertConfig(*models.ReqContext) response.Response
 	RouteGetAlertmanagers(*models.ReqContext) response.Response
 	RouteGetNGalertConfig(*models.ReqContext) response.Response
+	RouteGetRuleStats(*models.ReqContext) response.Response
 	RoutePostNGalertConfig(*models.ReqContext) response.Response
 }
 
@@ -38,6 +39,10 @@ func (f *ForkedConfigurationApi) RouteGetNGalertConfig(ctx *models.ReqContext) r
 	return f.forkRouteGetNGalertConfig(ctx)
 }
 
+func (f *ForkedConfigurationApi) RouteGetRuleStats(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetRuleStats(ctx)
+}
+
 func (f *ForkedConfigurationApi) RoutePostNGalertConfig(ctx *models.ReqContext) response.Response {
 	conf := apimodels.PostableNGalertConfig{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -78,6 +83,16 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApiForkingSer
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/rule_stats"),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/rule_stats"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/rule_stats",
+				srv.RouteGetRuleStats,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			api.authorize(http.MethodPost, "/api/v1/ngalert/admin_config"),
@@ -0,0 +1,56 @@
+/*Package api contains base API implementation of unified alerting
+ *
+ *Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ *
+ *Do not manually edit these files, please find ngalert/api/swagger-codegen/ for commands on how to generate them.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+type NotificationLogApiForkingService interface {
+	RouteGetNotificationLog(*models.ReqContext) response.Response
+	RouteReplayNotification(*models.ReqContext) response.Response
+}
+
+func (f *ForkedNotificationLogApi) RouteGetNotificationLog(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetNotificationLog(ctx)
+}
+
+func (f *ForkedNotificationLogApi) RouteReplayNotification(ctx *models.ReqContext) response.Response {
+	return f.forkRouteReplayNotification(ctx)
+}
+
+func (api *API) RegisterNotificationLogApiEndpoints(srv NotificationLogApiForkingService, m *metrics.API) {
+	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Get(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/notifications"),
+			api.authorize(http.MethodGet, "/api/alertmanager/grafana/api/v1/notifications"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/alertmanager/grafana/api/v1/notifications",
+				srv.RouteGetNotificationLog,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/notifications/{NotificationID}/replay"),
+			api.authorize(http.MethodPost, "/api/alertmanager/grafana/api/v1/notifications/{NotificationID}/replay"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/alertmanager/grafana/api/v1/notifications/{NotificationID}/replay",
+				srv.RouteReplayNotification,
+				m,
+			),
+		)
+	}, middleware.ReqSignedIn)
+}
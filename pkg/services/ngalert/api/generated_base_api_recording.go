@@ -0,0 +1,41 @@
+/*Package api contains base API implementation of unified alerting
+ *
+ *Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ *
+ *Do not manually edit these files, please find ngalert/api/swagger-codegen/ for commands on how to generate them.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+type RecordingApiForkingService interface {
+	RouteGetRecordedSamples(*models.ReqContext) response.Response
+}
+
+func (f *ForkedRecordingApi) RouteGetRecordedSamples(ctx *models.ReqContext) response.Response {
+	return f.forkRouteGetRecordedSamples(ctx)
+}
+
+func (api *API) RegisterRecordingApiEndpoints(srv RecordingApiForkingService, m *metrics.API) {
+	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Get(
+			toMacaronPath("/api/v1/rules/recordings"),
+			api.authorize(http.MethodGet, "/api/v1/rules/recordings"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/rules/recordings",
+				srv.RouteGetRecordedSamples,
+				m,
+			),
+		)
+	}, middleware.ReqSignedIn)
+}
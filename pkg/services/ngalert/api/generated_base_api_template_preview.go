@@ -0,0 +1,47 @@
+/*Package api contains base API implementation of unified alerting
+ *
+ *Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ *
+ *Do not manually edit these files, please find ngalert/api/swagger-codegen/ for commands on how to generate them.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type TemplatePreviewApiForkingService interface {
+	RoutePostTemplatePreview(*models.ReqContext) response.Response
+}
+
+func (f *ForkedTemplatePreviewApi) RoutePostTemplatePreview(ctx *models.ReqContext) response.Response {
+	conf := apimodels.TemplatePreviewRequest{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRoutePostTemplatePreview(ctx, conf)
+}
+
+func (api *API) RegisterTemplatePreviewApiEndpoints(srv TemplatePreviewApiForkingService, m *metrics.API) {
+	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Post(
+			toMacaronPath("/api/alertmanager/grafana/config/api/v1/templates/preview"),
+			api.authorize(http.MethodPost, "/api/alertmanager/grafana/config/api/v1/templates/preview"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/alertmanager/grafana/config/api/v1/templates/preview",
+				srv.RoutePostTemplatePreview,
+				m,
+			),
+		)
+	}, middleware.ReqSignedIn)
+}
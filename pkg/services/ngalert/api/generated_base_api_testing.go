@@ -20,11 +20,20 @@ import (
 )
 
 type TestingApiForkingService interface {
+	RouteBacktestRuleGroup(*models.ReqContext) response.Response
 	RouteEvalQueries(*models.ReqContext) response.Response
 	RouteTestRuleConfig(*models.ReqContext) response.Response
 	RouteTestRuleGrafanaConfig(*models.ReqContext) response.Response
 }
 
+func (f *ForkedTestingApi) RouteBacktestRuleGroup(ctx *models.ReqContext) response.Response {
+	conf := apimodels.BacktestPayload{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.forkRouteBacktestRuleGroup(ctx, conf)
+}
+
 func (f *ForkedTestingApi) RouteEvalQueries(ctx *models.ReqContext) response.Response {
 	conf := apimodels.EvalQueriesPayload{}
 	if err := web.Bind(ctx.Req, &conf); err != nil {
@@ -51,6 +60,16 @@ func (f *ForkedTestingApi) RouteTestRuleGrafanaConfig(ctx *models.ReqContext) re
 
 func (api *API) RegisterTestingApiEndpoints(srv TestingApiForkingService, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Post(
+			toMacaronPath("/api/v1/rule/backtest"),
+			api.authorize(http.MethodPost, "/api/v1/rule/backtest"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/backtest",
+				srv.RouteBacktestRuleGroup,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/eval"),
 			api.authorize(http.MethodPost, "/api/v1/eval"),
@@ -1,6 +1,8 @@
 package definitions
 
 import (
+	"time"
+
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 )
 
@@ -48,6 +50,17 @@ import (
 //       200: Ack
 //       500: Failure
 
+// swagger:route GET /api/v1/ngalert/rule_stats configuration RouteGetRuleStats
+//
+// Get the evaluation statistics of the alert rules of the user's organization evaluated by this Grafana instance, to find the slowest and most error-prone rules.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleEvaluationStatsList
+//       400: ValidationError
+
 // swagger:parameters RoutePostNGalertConfig
 type NGalertConfig struct {
 	// in:body
@@ -80,3 +93,47 @@ type GettableAlertmanagers struct {
 	Status string                 `json:"status"`
 	Data   v1.AlertManagersResult `json:"data"`
 }
+
+// swagger:parameters RouteGetRuleStats
+type RuleStatsParams struct {
+	// Sort the rules by the duration of their last evaluation, or by their number of failures.
+	// in: query
+	// required: false
+	// enum: duration,failures
+	// default: duration
+	Sort string `json:"sort"`
+
+	// Maximum number of rules to return.
+	// in: query
+	// required: false
+	// default: 20
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type RuleEvaluationStatsList struct {
+	Rules []RuleEvaluationStats `json:"rules"`
+}
+
+// RuleEvaluationStats are the statistics of the evaluations of an alert rule since Grafana started.
+type RuleEvaluationStats struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid"`
+	RuleGroup string `json:"ruleGroup"`
+	// Evaluations is the number of evaluations.
+	Evaluations int64 `json:"evaluations"`
+	// Failures is the number of evaluations that failed or had an error result.
+	Failures       int64     `json:"failures"`
+	LastEvaluation time.Time `json:"lastEvaluation"`
+	// LastEvaluationDuration is the duration of the last evaluation in seconds.
+	LastEvaluationDuration float64 `json:"lastEvaluationDuration"`
+	// AverageEvaluationDuration is the average duration of the evaluations in seconds.
+	AverageEvaluationDuration float64 `json:"averageEvaluationDuration"`
+	// QueryDurations are the durations in seconds of the queries and expressions of the last evaluation by RefID.
+	QueryDurations map[string]float64 `json:"queryDurations"`
+	// ResultSeries is the number of series of the result of the last evaluation.
+	ResultSeries  int        `json:"resultSeries"`
+	LastError     string     `json:"lastError,omitempty"`
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+}
@@ -0,0 +1,66 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/v1/rules/history history RouteGetStateHistory
+//
+// Get the history of the state transitions of the alert instances of the user's organization, from the most recent to the oldest.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: StateHistory
+//       400: ValidationError
+
+// swagger:parameters RouteGetStateHistory
+type StateHistoryParams struct {
+	// Filter the history to the alert instances of the rule with this UID.
+	// in: query
+	// required: false
+	RuleUID string `json:"ruleUID"`
+
+	// Filter the history by the labels of the alert instances, e.g. team="ops". All matchers must match.
+	// in: query
+	// required: false
+	Labels []string `json:"labels"`
+
+	// Filter the history to the transitions to this state.
+	// in: query
+	// required: false
+	State string `json:"state"`
+
+	// Start of the time range as a Unix timestamp in milliseconds.
+	// in: query
+	// required: false
+	From int64 `json:"from"`
+
+	// End of the time range as a Unix timestamp in milliseconds.
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+
+	// Maximum number of entries to return.
+	// in: query
+	// required: false
+	// default: 100
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type StateHistory struct {
+	Entries []StateHistoryEntry `json:"entries"`
+}
+
+// StateHistoryEntry is a transition of an alert instance from one state to another.
+type StateHistoryEntry struct {
+	RuleUID       string              `json:"ruleUID"`
+	Labels        map[string]string   `json:"labels"`
+	PreviousState string              `json:"previousState"`
+	State         string              `json:"state"`
+	Values        map[string]*float64 `json:"values,omitempty"`
+	Error         string              `json:"error,omitempty"`
+	EvaluatedAt   time.Time           `json:"evaluatedAt"`
+}
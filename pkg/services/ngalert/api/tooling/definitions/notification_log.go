@@ -0,0 +1,110 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/alertmanager/grafana/api/v1/notifications notification_log RouteGetNotificationLog
+//
+// Get the attempts to deliver notifications to the contact points of the user's organization, from the most recent to the oldest.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: NotificationLog
+//       400: ValidationError
+
+// swagger:route POST /api/alertmanager/grafana/api/v1/notifications/{NotificationID}/replay notification_log RouteReplayNotification
+//
+// Send a failed notification again through the same integration, with its current configuration.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: NotificationLogEntry
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:parameters RouteGetNotificationLog
+type NotificationLogParams struct {
+	// Filter the log to the notifications of this contact point.
+	// in: query
+	// required: false
+	Receiver string `json:"receiver"`
+
+	// Filter the log to the notifications of this type of integration, e.g. webhook.
+	// in: query
+	// required: false
+	Integration string `json:"integration"`
+
+	// Filter the log to the notifications of the integration with this UID.
+	// in: query
+	// required: false
+	IntegrationUID string `json:"integrationUID"`
+
+	// Filter the log to the notifications with this status, either success or failed.
+	// in: query
+	// required: false
+	Status string `json:"status"`
+
+	// Start of the time range as a Unix timestamp in milliseconds.
+	// in: query
+	// required: false
+	From int64 `json:"from"`
+
+	// End of the time range as a Unix timestamp in milliseconds.
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+
+	// Maximum number of entries to return.
+	// in: query
+	// required: false
+	// default: 100
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters RouteReplayNotification
+type NotificationIDParam struct {
+	// in: path
+	// required: true
+	NotificationID int64
+}
+
+// swagger:model
+type NotificationLog struct {
+	Entries []NotificationLogEntry `json:"entries"`
+}
+
+// NotificationLogEntry is an attempt to deliver a notification through an integration of a contact point.
+// swagger:model
+type NotificationLogEntry struct {
+	ID              int64             `json:"id"`
+	Receiver        string            `json:"receiver"`
+	IntegrationType string            `json:"integrationType"`
+	IntegrationName string            `json:"integrationName"`
+	IntegrationUID  string            `json:"integrationUID"`
+	GroupKey        string            `json:"groupKey"`
+	GroupLabels     map[string]string `json:"groupLabels"`
+	Status          string            `json:"status"`
+	// StatusCode is the HTTP status code of the response, or 0 if the integration does not use HTTP.
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	// Payload is the body of the request, truncated to the configured size.
+	Payload string                 `json:"payload,omitempty"`
+	Alerts  []NotificationLogAlert `json:"alerts"`
+	// ReplayOf is the ID of the entry this attempt replays.
+	ReplayOf int64     `json:"replayOf,omitempty"`
+	SentAt   time.Time `json:"sentAt"`
+}
+
+// NotificationLogAlert is an alert of a notification.
+type NotificationLogAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
@@ -96,6 +96,8 @@ func (e *EmbeddedContactPoint) SecretKeys() ([]string, error) {
 	switch e.Type {
 	case "alertmanager":
 		return []string{"basicAuthPassword"}, nil
+	case "amqp":
+		return []string{"password", "tlsClientKey"}, nil
 	case "dingding":
 		return []string{}, nil
 	case "discord":
@@ -108,6 +110,8 @@ func (e *EmbeddedContactPoint) SecretKeys() ([]string, error) {
 		return []string{}, nil
 	case "line":
 		return []string{"token"}, nil
+	case "mqtt":
+		return []string{"password", "tlsClientKey"}, nil
 	case "opsgenie":
 		return []string{"apiKey"}, nil
 	case "pagerduty":
@@ -127,7 +131,7 @@ func (e *EmbeddedContactPoint) SecretKeys() ([]string, error) {
 	case "victorops":
 		return []string{}, nil
 	case "webhook":
-		return []string{}, nil
+		return []string{"hmacSecret", "tlsClientKey"}, nil
 	case "wecom":
 		return []string{"url"}, nil
 	}
@@ -0,0 +1,59 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/v1/rules/recordings recording RouteGetRecordedSamples
+//
+// Get the samples written by the recording rules of the user's organization to the internal target, from the most recent to the oldest.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RecordedSamples
+//       400: ValidationError
+
+// swagger:parameters RouteGetRecordedSamples
+type RecordedSamplesParams struct {
+	// Filter the samples to the series of this metric.
+	// in: query
+	// required: false
+	Metric string `json:"metric"`
+
+	// Filter the samples by the labels of their series, e.g. job="api". All matchers must match.
+	// in: query
+	// required: false
+	Labels []string `json:"labels"`
+
+	// Start of the time range as a Unix timestamp in milliseconds.
+	// in: query
+	// required: false
+	From int64 `json:"from"`
+
+	// End of the time range as a Unix timestamp in milliseconds.
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+
+	// Maximum number of samples to return.
+	// in: query
+	// required: false
+	// default: 100
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type RecordedSamples struct {
+	Samples []RecordedSample `json:"samples"`
+}
+
+// RecordedSample is a sample of a metric series written by a recording rule.
+type RecordedSample struct {
+	RuleUID   string            `json:"ruleUID"`
+	Metric    string            `json:"metric"`
+	Labels    map[string]string `json:"labels"`
+	Value     float64           `json:"value"`
+	Timestamp time.Time         `json:"timestamp"`
+}
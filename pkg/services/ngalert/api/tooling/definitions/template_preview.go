@@ -0,0 +1,57 @@
+package definitions
+
+// swagger:route POST /api/alertmanager/grafana/config/api/v1/templates/preview template_preview RoutePostTemplatePreview
+//
+// Render the notifications of the firing alerts of a rule, or of the firing alerts that match label matchers, without sending them.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: TemplatePreviewResult
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:parameters RoutePostTemplatePreview
+type TemplatePreviewParams struct {
+	// in:body
+	Body TemplatePreviewRequest
+}
+
+// swagger:model
+type TemplatePreviewRequest struct {
+	// Template contains template definitions that are added to the templates of the configuration, and override
+	// the definitions with the same names. It is used to preview a template before saving it.
+	Template string `json:"template,omitempty"`
+	// RuleUID selects the firing alerts of the rule.
+	RuleUID string `json:"ruleUID,omitempty"`
+	// Matchers select the firing alerts whose labels match all of them, e.g. team=ops.
+	Matchers []string `json:"matchers,omitempty"`
+	// Receiver is the name of the contact point whose integrations are previewed with their settings. If it is
+	// empty, the notifications of every integration type are previewed with the default templates.
+	Receiver string `json:"receiver,omitempty"`
+	// Integrations restricts the preview to these types of integrations, e.g. slack.
+	Integrations []string `json:"integrations,omitempty"`
+}
+
+// swagger:model
+type TemplatePreviewResult struct {
+	// Alerts is the number of alerts in the notifications.
+	Alerts   int               `json:"alerts"`
+	Previews []TemplatePreview `json:"previews"`
+}
+
+// TemplatePreview is the notification of an integration.
+type TemplatePreview struct {
+	Receiver        string `json:"receiver,omitempty"`
+	IntegrationName string `json:"integrationName,omitempty"`
+	IntegrationUID  string `json:"integrationUID,omitempty"`
+	IntegrationType string `json:"integrationType"`
+	Title           string `json:"title"`
+	Body            string `json:"body"`
+	// Error is the error of the templates of the integration, if any.
+	Error string `json:"error,omitempty"`
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/promql"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -52,6 +53,20 @@ import (
 //     Responses:
 //       200: EvalQueriesResponse
 
+// swagger:route Post /api/v1/rule/backtest testing RouteBacktestRuleGroup
+//
+// Replay the evaluations of a Grafana rule group over a time range and return the state transitions of its alert instances
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestResponse
+//       400: ValidationError
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -84,6 +99,46 @@ type EvalQueriesPayload struct {
 	Now  time.Time           `json:"now"`
 }
 
+// swagger:parameters RouteBacktestRuleGroup
+type BacktestRequest struct {
+	// in:body
+	Body BacktestPayload
+}
+
+// swagger:model
+type BacktestPayload struct {
+	// From is the time of the first evaluation.
+	From time.Time `json:"from"`
+	// To is the time after which there are no more evaluations.
+	To time.Time `json:"to"`
+	// Interval is the time between evaluations. It defaults to the interval of the rule group.
+	Interval model.Duration `json:"interval,omitempty"`
+	// RuleGroup is the rule group to backtest. It can contain a single rule.
+	RuleGroup PostableRuleGroupConfig `json:"rule_group"`
+}
+
+// swagger:model
+type BacktestResponse struct {
+	Rules []BacktestRuleResult `json:"rules"`
+}
+
+// BacktestRuleResult contains the state transitions of the alert instances of a rule.
+type BacktestRuleResult struct {
+	Title       string                    `json:"title"`
+	Evaluations int                       `json:"evaluations"`
+	Transitions []BacktestStateTransition `json:"transitions"`
+}
+
+// BacktestStateTransition is a change of the state of an alert instance.
+type BacktestStateTransition struct {
+	EvaluatedAt   time.Time           `json:"evaluatedAt"`
+	Labels        map[string]string   `json:"labels"`
+	PreviousState string              `json:"previousState"`
+	State         string              `json:"state"`
+	Values        map[string]*float64 `json:"values,omitempty"`
+	Error         string              `json:"error,omitempty"`
+}
+
 func (p *TestRulePayload) UnmarshalJSON(b []byte) error {
 	type plain TestRulePayload
 	if err := json.Unmarshal(b, (*plain)(p)); err != nil {
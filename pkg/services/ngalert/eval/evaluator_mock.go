@@ -3,7 +3,10 @@
 package eval
 
 import (
+	context "context"
+
 	backend "github.com/grafana/grafana-plugin-sdk-go/backend"
+
 	expr "github.com/grafana/grafana/pkg/expr"
 
 	mock "github.com/stretchr/testify/mock"
@@ -26,13 +29,13 @@ func (_m *FakeEvaluator) EXPECT() *FakeEvaluator_Expecter {
 	return &FakeEvaluator_Expecter{mock: &_m.Mock}
 }
 
-// ConditionEval provides a mock function with given fields: condition, now, expressionService
-func (_m *FakeEvaluator) ConditionEval(condition *models.Condition, now time.Time, expressionService *expr.Service) (Results, error) {
-	ret := _m.Called(condition, now, expressionService)
+// ConditionEval provides a mock function with given fields: ctx, condition, now, expressionService
+func (_m *FakeEvaluator) ConditionEval(ctx context.Context, condition *models.Condition, now time.Time, expressionService *expr.Service) (Results, error) {
+	ret := _m.Called(ctx, condition, now, expressionService)
 
 	var r0 Results
-	if rf, ok := ret.Get(0).(func(*models.Condition, time.Time, *expr.Service) Results); ok {
-		r0 = rf(condition, now, expressionService)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Condition, time.Time, *expr.Service) Results); ok {
+		r0 = rf(ctx, condition, now, expressionService)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(Results)
@@ -40,8 +43,8 @@ func (_m *FakeEvaluator) ConditionEval(condition *models.Condition, now time.Tim
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*models.Condition, time.Time, *expr.Service) error); ok {
-		r1 = rf(condition, now, expressionService)
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Condition, time.Time, *expr.Service) error); ok {
+		r1 = rf(ctx, condition, now, expressionService)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -55,16 +58,17 @@ type FakeEvaluator_ConditionEval_Call struct {
 }
 
 // ConditionEval is a helper method to define mock.On call
+//  - ctx context.Context
 //  - condition *models.Condition
 //  - now time.Time
 //  - expressionService *expr.Service
-func (_e *FakeEvaluator_Expecter) ConditionEval(condition interface{}, now interface{}, expressionService interface{}) *FakeEvaluator_ConditionEval_Call {
-	return &FakeEvaluator_ConditionEval_Call{Call: _e.mock.On("ConditionEval", condition, now, expressionService)}
+func (_e *FakeEvaluator_Expecter) ConditionEval(ctx interface{}, condition interface{}, now interface{}, expressionService interface{}) *FakeEvaluator_ConditionEval_Call {
+	return &FakeEvaluator_ConditionEval_Call{Call: _e.mock.On("ConditionEval", ctx, condition, now, expressionService)}
 }
 
-func (_c *FakeEvaluator_ConditionEval_Call) Run(run func(condition *models.Condition, now time.Time, expressionService *expr.Service)) *FakeEvaluator_ConditionEval_Call {
+func (_c *FakeEvaluator_ConditionEval_Call) Run(run func(ctx context.Context, condition *models.Condition, now time.Time, expressionService *expr.Service)) *FakeEvaluator_ConditionEval_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*models.Condition), args[1].(time.Time), args[2].(*expr.Service))
+		run(args[0].(context.Context), args[1].(*models.Condition), args[2].(time.Time), args[3].(*expr.Service))
 	})
 	return _c
 }
@@ -74,13 +78,13 @@ func (_c *FakeEvaluator_ConditionEval_Call) Return(_a0 Results, _a1 error) *Fake
 	return _c
 }
 
-// QueriesAndExpressionsEval provides a mock function with given fields: orgID, data, now, expressionService
-func (_m *FakeEvaluator) QueriesAndExpressionsEval(orgID int64, data []models.AlertQuery, now time.Time, expressionService *expr.Service) (*backend.QueryDataResponse, error) {
-	ret := _m.Called(orgID, data, now, expressionService)
+// QueriesAndExpressionsEval provides a mock function with given fields: ctx, orgID, data, now, expressionService
+func (_m *FakeEvaluator) QueriesAndExpressionsEval(ctx context.Context, orgID int64, data []models.AlertQuery, now time.Time, expressionService *expr.Service) (*backend.QueryDataResponse, error) {
+	ret := _m.Called(ctx, orgID, data, now, expressionService)
 
 	var r0 *backend.QueryDataResponse
-	if rf, ok := ret.Get(0).(func(int64, []models.AlertQuery, time.Time, *expr.Service) *backend.QueryDataResponse); ok {
-		r0 = rf(orgID, data, now, expressionService)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []models.AlertQuery, time.Time, *expr.Service) *backend.QueryDataResponse); ok {
+		r0 = rf(ctx, orgID, data, now, expressionService)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*backend.QueryDataResponse)
@@ -88,8 +92,8 @@ func (_m *FakeEvaluator) QueriesAndExpressionsEval(orgID int64, data []models.Al
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int64, []models.AlertQuery, time.Time, *expr.Service) error); ok {
-		r1 = rf(orgID, data, now, expressionService)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []models.AlertQuery, time.Time, *expr.Service) error); ok {
+		r1 = rf(ctx, orgID, data, now, expressionService)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -103,17 +107,18 @@ type FakeEvaluator_QueriesAndExpressionsEval_Call struct {
 }
 
 // QueriesAndExpressionsEval is a helper method to define mock.On call
+//  - ctx context.Context
 //  - orgID int64
 //  - data []models.AlertQuery
 //  - now time.Time
 //  - expressionService *expr.Service
-func (_e *FakeEvaluator_Expecter) QueriesAndExpressionsEval(orgID interface{}, data interface{}, now interface{}, expressionService interface{}) *FakeEvaluator_QueriesAndExpressionsEval_Call {
-	return &FakeEvaluator_QueriesAndExpressionsEval_Call{Call: _e.mock.On("QueriesAndExpressionsEval", orgID, data, now, expressionService)}
+func (_e *FakeEvaluator_Expecter) QueriesAndExpressionsEval(ctx interface{}, orgID interface{}, data interface{}, now interface{}, expressionService interface{}) *FakeEvaluator_QueriesAndExpressionsEval_Call {
+	return &FakeEvaluator_QueriesAndExpressionsEval_Call{Call: _e.mock.On("QueriesAndExpressionsEval", ctx, orgID, data, now, expressionService)}
 }
 
-func (_c *FakeEvaluator_QueriesAndExpressionsEval_Call) Run(run func(orgID int64, data []models.AlertQuery, now time.Time, expressionService *expr.Service)) *FakeEvaluator_QueriesAndExpressionsEval_Call {
+func (_c *FakeEvaluator_QueriesAndExpressionsEval_Call) Run(run func(ctx context.Context, orgID int64, data []models.AlertQuery, now time.Time, expressionService *expr.Service)) *FakeEvaluator_QueriesAndExpressionsEval_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64), args[1].([]models.AlertQuery), args[2].(time.Time), args[3].(*expr.Service))
+		run(args[0].(context.Context), args[1].(int64), args[2].([]models.AlertQuery), args[3].(time.Time), args[4].(*expr.Service))
 	})
 	return _c
 }
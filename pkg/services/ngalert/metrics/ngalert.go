@@ -54,6 +54,11 @@ type Scheduler struct {
 	AlertRules               prometheus.Gauge
 	AlertRulesHash           prometheus.Gauge
 	UpdateAlertRulesDuration prometheus.Histogram
+	// The following metrics have series for each alert rule, they are only updated if per-rule metrics are enabled.
+	RuleEvalDuration  *prometheus.GaugeVec
+	RuleQueryDuration *prometheus.GaugeVec
+	RuleResultSeries  *prometheus.GaugeVec
+	RuleEvalFailed    *prometheus.GaugeVec
 }
 
 type MultiOrgAlertmanager struct {
@@ -196,6 +201,42 @@ func newSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 				Buckets:   []float64{0.1, 0.25, 0.5, 1, 2, 5, 10},
 			},
 		),
+		RuleEvalDuration: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_last_evaluation_duration_seconds",
+				Help:      "The duration of the last evaluation of the rule.",
+			},
+			[]string{"org", "rule_uid"},
+		),
+		RuleQueryDuration: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_last_query_duration_seconds",
+				Help:      "The duration of each query and expression of the last evaluation of the rule.",
+			},
+			[]string{"org", "rule_uid", "ref_id"},
+		),
+		RuleResultSeries: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_last_evaluation_result_series",
+				Help:      "The number of series of the result of the last evaluation of the rule.",
+			},
+			[]string{"org", "rule_uid"},
+		),
+		RuleEvalFailed: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_last_evaluation_failed",
+				Help:      "Whether the last evaluation of the rule failed (1) or not (0).",
+			},
+			[]string{"org", "rule_uid"},
+		),
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/expr"
 )
 
@@ -238,6 +239,39 @@ func (aq *AlertQuery) setQueryType() error {
 	return nil
 }
 
+// PatchThresholdLoadedDimensions returns a copy of the queries in which the model of every threshold
+// expression has its loaded dimensions set to the given labels. The threshold expressions use them to
+// apply their recovery threshold to the dimensions that met the threshold in the previous evaluation.
+func PatchThresholdLoadedDimensions(queries []AlertQuery, loaded []data.Labels) ([]AlertQuery, error) {
+	if len(loaded) == 0 {
+		return queries, nil
+	}
+	result := make([]AlertQuery, 0, len(queries))
+	for _, q := range queries {
+		if !expr.IsDataSource(q.DatasourceUID) {
+			result = append(result, q)
+			continue
+		}
+		props := make(map[string]interface{})
+		if err := json.Unmarshal(q.Model, &props); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal query model: %w", err)
+		}
+		if t, _ := props["type"].(string); t != "threshold" {
+			result = append(result, q)
+			continue
+		}
+		props["loadedDimensions"] = loaded
+		model, err := json.Marshal(props)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal query model: %w", err)
+		}
+		q.Model = model
+		q.modelProps = nil
+		result = append(result, q)
+	}
+	return result, nil
+}
+
 // PreSave sets query's properties.
 // It should be called before being saved.
 func (aq *AlertQuery) PreSave() error {
@@ -7,8 +7,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
 )
 
 func TestAlertQuery(t *testing.T) {
@@ -281,3 +284,26 @@ func TestAlertQuery_GetQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestPatchThresholdLoadedDimensions(t *testing.T) {
+	queries := []AlertQuery{
+		{RefID: "A", DatasourceUID: "prom", Model: json.RawMessage(`{"expr": "up", "type": "threshold"}`)},
+		{RefID: "B", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type": "reduce", "expression": "A", "reducer": "last"}`)},
+		{RefID: "C", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type": "threshold", "expression": "B"}`)},
+	}
+
+	t.Run("queries are not changed without loaded dimensions", func(t *testing.T) {
+		patched, err := PatchThresholdLoadedDimensions(queries, nil)
+		require.NoError(t, err)
+		require.Equal(t, queries, patched)
+	})
+
+	t.Run("only threshold expressions are patched", func(t *testing.T) {
+		patched, err := PatchThresholdLoadedDimensions(queries, []data.Labels{{"host": "a"}})
+		require.NoError(t, err)
+		require.Equal(t, queries[0], patched[0])
+		require.Equal(t, queries[1], patched[1])
+		require.JSONEq(t, `{"type": "threshold", "expression": "B", "loadedDimensions": [{"host": "a"}]}`, string(patched[2].Model))
+		require.JSONEq(t, `{"type": "threshold", "expression": "B"}`, string(queries[2].Model))
+	})
+}
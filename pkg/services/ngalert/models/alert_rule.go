@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/util/cmputil"
 )
@@ -111,6 +114,138 @@ type AlertRule struct {
 	For         time.Duration
 	Annotations map[string]string
 	Labels      map[string]string
+	// Record is set if the rule is a recording rule.
+	Record *Record `xorm:"JSON"`
+	// DependsOn is set if the rule is inhibited while the rules it depends on are firing.
+	DependsOn *RuleDependencies `xorm:"JSON"`
+}
+
+// Record configures a recording rule. Instead of alerting, a recording rule writes
+// the result of its queries and expressions as a new metric series.
+type Record struct {
+	// Metric is the name of the metric the series are written to.
+	Metric string `json:"metric"`
+	// From is the RefID of the query or expression the series are read from.
+	From string `json:"from"`
+}
+
+// IsRecordingRule returns true if the rule is a recording rule.
+func (alertRule *AlertRule) IsRecordingRule() bool {
+	return alertRule.Record != nil
+}
+
+// RuleDependencies selects the parent rules of a rule. While an alert instance of a parent rule is firing,
+// the alert instances of the rule are inhibited.
+type RuleDependencies struct {
+	// RuleUIDs are the UIDs of the parent rules.
+	RuleUIDs []string `json:"rule_uids,omitempty"`
+	// Matchers select the parent rules by the labels of their alert instances, e.g. alertname="DatabaseDown".
+	// All matchers must match.
+	Matchers []string `json:"matchers,omitempty"`
+}
+
+// LabelMatchers parses the matchers of the dependencies.
+func (d *RuleDependencies) LabelMatchers() (labels.Matchers, error) {
+	matchers := make(labels.Matchers, 0, len(d.Matchers))
+	for _, s := range d.Matchers {
+		m, err := labels.ParseMatcher(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher %q: %w", s, err)
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// FindDependencyCycle returns the UIDs of rules that depend on each other in a cycle, starting and ending with
+// the same rule, or nil if the rules have no cycle. A rule depends on the rules selected by UID, and on the rules
+// that certainly match its matchers: the labels of their alert instances always include the rule labels, the
+// title as the alert name, and the UIDs of the rule and of its folder.
+func FindDependencyCycle(rules []*AlertRule) []string {
+	byUID := make(map[string]*AlertRule, len(rules))
+	for _, rule := range rules {
+		byUID[rule.UID] = rule
+	}
+
+	parents := func(rule *AlertRule) []string {
+		if rule.DependsOn == nil {
+			return nil
+		}
+		var result []string
+		for _, uid := range rule.DependsOn.RuleUIDs {
+			if _, ok := byUID[uid]; ok && uid != rule.UID {
+				result = append(result, uid)
+			}
+		}
+		matchers, err := rule.DependsOn.LabelMatchers()
+		if err != nil || len(matchers) == 0 {
+			return result
+		}
+		for _, parent := range rules {
+			if parent.UID != rule.UID && matchStaticLabels(matchers, parent) {
+				result = append(result, parent.UID)
+			}
+		}
+		return result
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	marks := make(map[string]int, len(rules))
+	var path []string
+	var visit func(uid string) []string
+	visit = func(uid string) []string {
+		switch marks[uid] {
+		case visited:
+			return nil
+		case visiting:
+			for i, p := range path {
+				if p == uid {
+					return append(append([]string{}, path[i:]...), uid)
+				}
+			}
+		}
+		marks[uid] = visiting
+		path = append(path, uid)
+		for _, parent := range parents(byUID[uid]) {
+			if cycle := visit(parent); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		marks[uid] = visited
+		return nil
+	}
+	for _, rule := range rules {
+		if cycle := visit(rule.UID); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// matchStaticLabels returns true if the labels that every alert instance of the rule has match all the matchers.
+// A matcher of another label may or may not match the labels of the instances, so it does not match.
+func matchStaticLabels(matchers labels.Matchers, rule *AlertRule) bool {
+	lbs := make(map[string]string, len(rule.Labels)+3)
+	for k, v := range rule.Labels {
+		// templated labels are expanded with the labels of each instance
+		if !strings.Contains(v, "{{") {
+			lbs[k] = v
+		}
+	}
+	lbs[model.AlertNameLabel] = rule.Title
+	lbs[RuleUIDLabel] = rule.UID
+	lbs[NamespaceUIDLabel] = rule.NamespaceUID
+	for _, m := range matchers {
+		v, ok := lbs[m.Name]
+		if !ok || !m.Matches(v) {
+			return false
+		}
+	}
+	return true
 }
 
 type LabelOption func(map[string]string)
@@ -217,6 +352,8 @@ type AlertRuleVersion struct {
 	For         time.Duration
 	Annotations map[string]string
 	Labels      map[string]string
+	Record      *Record           `xorm:"JSON"`
+	DependsOn   *RuleDependencies `xorm:"JSON"`
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -293,8 +430,9 @@ func (c Condition) IsValid() bool {
 // There are several exceptions:
 // 1. Following fields are not patched and therefore will be ignored: AlertRule.ID, AlertRule.OrgID, AlertRule.Updated, AlertRule.Version, AlertRule.UID, AlertRule.DashboardUID, AlertRule.PanelID, AlertRule.Annotations and AlertRule.Labels
 // 2. There are fields that are patched together:
-//    - AlertRule.Condition and AlertRule.Data
-// If either of the pair is specified, neither is patched.
+//   - AlertRule.Condition, AlertRule.Data and AlertRule.Record
+//
+// If either AlertRule.Condition or AlertRule.Data is specified, none of them is patched.
 func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRule) {
 	if ruleToPatch.Title == "" {
 		ruleToPatch.Title = existingRule.Title
@@ -302,6 +440,7 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRule) {
 	if ruleToPatch.Condition == "" || len(ruleToPatch.Data) == 0 {
 		ruleToPatch.Condition = existingRule.Condition
 		ruleToPatch.Data = existingRule.Data
+		ruleToPatch.Record = existingRule.Record
 	}
 	if ruleToPatch.IntervalSeconds == 0 {
 		ruleToPatch.IntervalSeconds = existingRule.IntervalSeconds
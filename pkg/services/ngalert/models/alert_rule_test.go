@@ -144,6 +144,23 @@ func TestPatchPartialAlertRule(t *testing.T) {
 		}
 	})
 
+	t.Run("patches record of recording rule together with data", func(t *testing.T) {
+		existing := AlertRuleGen(func(rule *AlertRule) {
+			rule.Record = &Record{Metric: "requests:rate5m", From: rule.Condition}
+		})()
+		patch := *existing
+		patch.Condition = ""
+		patch.Data = nil
+		patch.Record = nil
+		PatchPartialAlertRule(existing, &patch)
+		require.Equal(t, existing.Record, patch.Record)
+
+		patch = *existing
+		patch.Record = nil
+		PatchPartialAlertRule(existing, &patch)
+		require.Nil(t, patch.Record)
+	})
+
 	t.Run("does not patch", func(t *testing.T) {
 		testCases := []struct {
 			name    string
@@ -518,3 +535,42 @@ func TestDiff(t *testing.T) {
 		})
 	})
 }
+
+func TestFindDependencyCycle(t *testing.T) {
+	rule := func(uid string, labels map[string]string, dependsOn *RuleDependencies) *AlertRule {
+		return &AlertRule{UID: uid, Title: uid, NamespaceUID: "folder", Labels: labels, DependsOn: dependsOn}
+	}
+
+	t.Run("rules without cycles", func(t *testing.T) {
+		require.Nil(t, FindDependencyCycle([]*AlertRule{
+			rule("db", nil, nil),
+			rule("app", nil, &RuleDependencies{RuleUIDs: []string{"db"}}),
+			rule("web", nil, &RuleDependencies{RuleUIDs: []string{"app", "db"}}),
+			rule("self", map[string]string{"team": "a"}, &RuleDependencies{Matchers: []string{`team="a"`}}),
+		}))
+	})
+
+	t.Run("cycle of rule UIDs", func(t *testing.T) {
+		require.Equal(t, []string{"a", "b", "c", "a"}, FindDependencyCycle([]*AlertRule{
+			rule("a", nil, &RuleDependencies{RuleUIDs: []string{"b"}}),
+			rule("b", nil, &RuleDependencies{RuleUIDs: []string{"c"}}),
+			rule("c", nil, &RuleDependencies{RuleUIDs: []string{"a"}}),
+		}))
+	})
+
+	t.Run("cycle of matchers of the static labels", func(t *testing.T) {
+		require.Equal(t, []string{"a", "b", "a"}, FindDependencyCycle([]*AlertRule{
+			rule("a", map[string]string{"team": "a"}, &RuleDependencies{Matchers: []string{`alertname="b"`}}),
+			rule("b", nil, &RuleDependencies{Matchers: []string{`team=~"a|c"`}}),
+		}))
+	})
+
+	t.Run("matchers of labels that the instances may not have", func(t *testing.T) {
+		require.Nil(t, FindDependencyCycle([]*AlertRule{
+			rule("a", map[string]string{"team": "{{ $labels.team }}"}, &RuleDependencies{RuleUIDs: []string{"b"}}),
+			rule("b", nil, &RuleDependencies{Matchers: []string{`team="a"`}}),
+			rule("c", nil, &RuleDependencies{Matchers: []string{`instance!="db"`}}),
+			rule("d", nil, &RuleDependencies{RuleUIDs: []string{"c"}}),
+		}))
+	})
+}
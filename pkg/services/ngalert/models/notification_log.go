@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+)
+
+// NotificationStatus is the outcome of an attempt to deliver a notification.
+type NotificationStatus string
+
+const (
+	NotificationStatusSuccess NotificationStatus = "success"
+	NotificationStatusFailed  NotificationStatus = "failed"
+)
+
+// IsValid returns true if the status is one of the known statuses.
+func (s NotificationStatus) IsValid() bool {
+	return s == NotificationStatusSuccess || s == NotificationStatusFailed
+}
+
+// NotificationLogEntry is an attempt to deliver a notification through an integration of a contact point.
+type NotificationLogEntry struct {
+	ID       int64
+	OrgID    int64
+	Receiver string
+	// IntegrationType, IntegrationName and IntegrationUID identify the integration of the receiver, e.g. a webhook.
+	IntegrationType string
+	IntegrationName string
+	IntegrationUID  string
+	// GroupKey and GroupLabels identify the group of alerts of the notification.
+	GroupKey    string
+	GroupLabels model.LabelSet
+	Status      NotificationStatus
+	// StatusCode is the HTTP status code of the response, or 0 if the integration does not use HTTP
+	// or the request failed before a response was received.
+	StatusCode int
+	Error      string
+	Duration   time.Duration
+	// Payload is the body of the request, truncated to the configured size.
+	Payload string
+	// Alerts are the alerts of the notification. They are kept to replay the notification.
+	Alerts []*types.Alert
+	// ReplayOf is the ID of the entry this attempt replays, or 0 if it is not a replay.
+	ReplayOf int64
+	SentAt   time.Time
+}
+
+// GetNotificationLogQuery is the query for the notification log of an organization.
+// Entries are returned from the most recent to the oldest.
+type GetNotificationLogQuery struct {
+	OrgID           int64
+	Receiver        string
+	IntegrationType string
+	IntegrationUID  string
+	Status          NotificationStatus
+	// From and To select the entries sent in [From, To). Zero values are not applied.
+	From  time.Time
+	To    time.Time
+	Limit int
+
+	Result []*NotificationLogEntry
+}
+
+// Matches returns true if the entry is selected by the query.
+func (q *GetNotificationLogQuery) Matches(e *NotificationLogEntry) bool {
+	if e.OrgID != q.OrgID {
+		return false
+	}
+	if q.Receiver != "" && e.Receiver != q.Receiver {
+		return false
+	}
+	if q.IntegrationType != "" && e.IntegrationType != q.IntegrationType {
+		return false
+	}
+	if q.IntegrationUID != "" && e.IntegrationUID != q.IntegrationUID {
+		return false
+	}
+	if q.Status != "" && e.Status != q.Status {
+		return false
+	}
+	if !q.From.IsZero() && e.SentAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !e.SentAt.Before(q.To) {
+		return false
+	}
+	return true
+}
+
+// GetNotificationLogEntryQuery is the query for a single entry of the notification log of an organization.
+type GetNotificationLogEntryQuery struct {
+	OrgID int64
+	ID    int64
+
+	Result *NotificationLogEntry
+}
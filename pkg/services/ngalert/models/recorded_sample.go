@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+)
+
+// RecordedSample is a sample of a metric series written by a recording rule.
+type RecordedSample struct {
+	ID           int64
+	OrgID        int64
+	RuleUID      string
+	NamespaceUID string
+	Metric       string
+	Labels       InstanceLabels
+	Value        float64
+	Timestamp    time.Time
+}
+
+// GetRecordedSamplesQuery is the query for the samples written by the recording rules of an organization.
+// Samples are returned from the most recent to the oldest.
+type GetRecordedSamplesQuery struct {
+	OrgID  int64
+	Metric string
+	// Matchers select the samples by the labels of their series.
+	Matchers []*labels.Matcher
+	// NamespaceUIDs, if not nil, select the samples written by the rules in these namespaces.
+	NamespaceUIDs []string
+	// From and To select the samples in [From, To). Zero values are not applied.
+	From  time.Time
+	To    time.Time
+	Limit int
+
+	Result []*RecordedSample
+}
+
+// Matches returns true if the sample is selected by the query.
+func (q *GetRecordedSamplesQuery) Matches(s *RecordedSample) bool {
+	if s.OrgID != q.OrgID {
+		return false
+	}
+	if q.Metric != "" && s.Metric != q.Metric {
+		return false
+	}
+	if q.NamespaceUIDs != nil && !containsString(q.NamespaceUIDs, s.NamespaceUID) {
+		return false
+	}
+	if !q.From.IsZero() && s.Timestamp.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !s.Timestamp.Before(q.To) {
+		return false
+	}
+	return q.MatchesLabels(s.Labels)
+}
+
+// MatchesLabels returns true if the labels match all matchers of the query.
+func (q *GetRecordedSamplesQuery) MatchesLabels(l InstanceLabels) bool {
+	for _, m := range q.Matchers {
+		if !m.Matches(l[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+)
+
+// StateHistoryEntry is a transition of an alert instance from one state to another.
+type StateHistoryEntry struct {
+	ID            int64
+	OrgID         int64
+	RuleUID       string
+	NamespaceUID  string
+	Labels        InstanceLabels
+	PreviousState InstanceStateType
+	State         InstanceStateType
+	// Values are the values of the expressions of the rule at the time of the evaluation.
+	// NaN and infinite values are not supported and must be nil.
+	Values      map[string]*float64
+	Error       string
+	EvaluatedAt time.Time
+}
+
+// GetStateHistoryQuery is the query for the state history of the alert instances of an organization.
+// Entries are returned from the most recent to the oldest.
+type GetStateHistoryQuery struct {
+	OrgID   int64
+	RuleUID string
+	// Matchers select the entries by the labels of the alert instance.
+	Matchers []*labels.Matcher
+	// NamespaceUIDs, if not nil, select the entries of the rules in these namespaces.
+	NamespaceUIDs []string
+	State         InstanceStateType
+	// From and To select the entries evaluated in [From, To). Zero values are not applied.
+	From  time.Time
+	To    time.Time
+	Limit int
+
+	Result []*StateHistoryEntry
+}
+
+// HasLabelFilters returns true if the query selects entries by their labels.
+func (q *GetStateHistoryQuery) HasLabelFilters() bool {
+	return len(q.Matchers) > 0
+}
+
+// Matches returns true if the entry is selected by the query.
+func (q *GetStateHistoryQuery) Matches(e *StateHistoryEntry) bool {
+	if e.OrgID != q.OrgID {
+		return false
+	}
+	if q.RuleUID != "" && e.RuleUID != q.RuleUID {
+		return false
+	}
+	if q.NamespaceUIDs != nil && !containsString(q.NamespaceUIDs, e.NamespaceUID) {
+		return false
+	}
+	if q.State != "" && e.State != q.State {
+		return false
+	}
+	if !q.From.IsZero() && e.EvaluatedAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !e.EvaluatedAt.Before(q.To) {
+		return false
+	}
+	return q.MatchesLabels(e.Labels)
+}
+
+// MatchesLabels returns true if the labels match all matchers of the query.
+func (q *GetStateHistoryQuery) MatchesLabels(l InstanceLabels) bool {
+	for _, m := range q.Matchers {
+		if !m.Matches(l[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
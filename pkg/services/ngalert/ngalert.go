@@ -3,6 +3,7 @@ package ngalert
 import (
 	"context"
 	"net/url"
+	"time"
 
 	"github.com/benbjohnson/clock"
 	"golang.org/x/sync/errgroup"
@@ -20,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/recording"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
@@ -64,21 +66,24 @@ func ProvideService(cfg *setting.Cfg, dataSourceCache datasources.CacheService,
 
 // AlertNG is the service for evaluating the condition of an alert definition.
 type AlertNG struct {
-	Cfg                 *setting.Cfg
-	DataSourceCache     datasources.CacheService
-	RouteRegister       routing.RouteRegister
-	SQLStore            *sqlstore.SQLStore
-	KVStore             kvstore.KVStore
-	ExpressionService   *expr.Service
-	DataProxy           *datasourceproxy.DataSourceProxyService
-	QuotaService        *quota.QuotaService
-	SecretsService      secrets.Service
-	Metrics             *metrics.NGAlert
-	NotificationService notifications.Service
-	Log                 log.Logger
-	schedule            schedule.ScheduleService
-	stateManager        *state.Manager
-	folderService       dashboards.FolderService
+	Cfg                  *setting.Cfg
+	DataSourceCache      datasources.CacheService
+	RouteRegister        routing.RouteRegister
+	SQLStore             *sqlstore.SQLStore
+	KVStore              kvstore.KVStore
+	ExpressionService    *expr.Service
+	DataProxy            *datasourceproxy.DataSourceProxyService
+	QuotaService         *quota.QuotaService
+	SecretsService       secrets.Service
+	Metrics              *metrics.NGAlert
+	NotificationService  notifications.Service
+	Log                  log.Logger
+	schedule             schedule.ScheduleService
+	stateManager         *state.Manager
+	stateHistoryStore    store.StateHistoryStore
+	recordedSampleStore  store.RecordedSampleStore
+	notificationLogStore store.NotificationLogStore
+	folderService        dashboards.FolderService
 
 	// Alerting notification services
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
@@ -99,7 +104,10 @@ func (ng *AlertNG) init() error {
 
 	decryptFn := ng.SecretsService.GetDecryptedValue
 	multiOrgMetrics := ng.Metrics.GetMultiOrgAlertmanagerMetrics()
-	ng.MultiOrgAlertmanager, err = notifier.NewMultiOrgAlertmanager(ng.Cfg, store, store, ng.KVStore, store, decryptFn, multiOrgMetrics, ng.NotificationService, log.New("ngalert.multiorg.alertmanager"), ng.SecretsService)
+	if ng.Cfg.UnifiedAlerting.NotificationLog.Enabled {
+		ng.notificationLogStore = store
+	}
+	ng.MultiOrgAlertmanager, err = notifier.NewMultiOrgAlertmanager(ng.Cfg, store, store, ng.KVStore, store, decryptFn, multiOrgMetrics, ng.NotificationService, log.New("ngalert.multiorg.alertmanager"), ng.SecretsService, ng.notificationLogStore)
 	if err != nil {
 		return err
 	}
@@ -124,6 +132,19 @@ func (ng *AlertNG) init() error {
 		AdminConfigPollInterval: ng.Cfg.UnifiedAlerting.AdminConfigPollInterval,
 		DisabledOrgs:            ng.Cfg.UnifiedAlerting.DisabledOrgs,
 		MinRuleInterval:         ng.Cfg.UnifiedAlerting.MinInterval,
+		RecordingWriter:         recording.NewWriter(ng.Cfg.UnifiedAlerting.RecordingRules, store),
+		RuleMetrics:             ng.Cfg.UnifiedAlerting.RuleMetrics,
+	}
+
+	if ng.Cfg.UnifiedAlerting.HAEvaluationSharding {
+		schedCfg.Sharder = schedule.NewEvaluationSharder(
+			ng.Cfg.UnifiedAlerting.HAInstanceID,
+			store,
+			ng.Cfg.UnifiedAlerting.HAHeartbeatInterval,
+			ng.Cfg.UnifiedAlerting.HAHeartbeatTimeout,
+			schedCfg.C,
+			log.New("ngalert.scheduler.sharding"),
+		)
 	}
 
 	appUrl, err := url.Parse(ng.Cfg.AppURL)
@@ -131,7 +152,9 @@ func (ng *AlertNG) init() error {
 		ng.Log.Error("Failed to parse application URL. Continue without it.", "error", err)
 		appUrl = nil
 	}
-	stateManager := state.NewManager(ng.Log, ng.Metrics.GetStateMetrics(), appUrl, store, store, ng.SQLStore)
+	ng.stateHistoryStore = newStateHistoryStore(ng.Cfg.UnifiedAlerting.StateHistory, store)
+	ng.recordedSampleStore = newRecordedSampleStore(ng.Cfg.UnifiedAlerting.RecordingRules, store)
+	stateManager := state.NewManager(ng.Log, ng.Metrics.GetStateMetrics(), appUrl, store, store, ng.SQLStore, ng.stateHistoryStore)
 	scheduler := schedule.NewScheduler(schedCfg, ng.ExpressionService, appUrl, stateManager)
 
 	ng.stateManager = stateManager
@@ -160,6 +183,9 @@ func (ng *AlertNG) init() error {
 		ProvenanceStore:      store,
 		MultiOrgAlertmanager: ng.MultiOrgAlertmanager,
 		StateManager:         ng.stateManager,
+		StateHistoryStore:    ng.stateHistoryStore,
+		RecordedSampleStore:  ng.recordedSampleStore,
+		NotificationLogStore: ng.notificationLogStore,
 		AccessControl:        ng.accesscontrol,
 		Policies:             policyService,
 		ContactPointService:  contactPointService,
@@ -186,9 +212,72 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.MultiOrgAlertmanager.Run(subCtx)
 	})
+	if ng.stateHistoryStore != nil {
+		children.Go(func() error {
+			ng.cleanUp(subCtx, "state history", ng.Cfg.UnifiedAlerting.StateHistory.Retention, ng.stateHistoryStore.DeleteStateHistory)
+			return nil
+		})
+	}
+	if ng.recordedSampleStore != nil {
+		children.Go(func() error {
+			ng.cleanUp(subCtx, "recorded samples", ng.Cfg.UnifiedAlerting.RecordingRules.Retention, ng.recordedSampleStore.DeleteRecordedSamples)
+			return nil
+		})
+	}
+	if ng.notificationLogStore != nil {
+		children.Go(func() error {
+			ng.cleanUp(subCtx, "notification log", ng.Cfg.UnifiedAlerting.NotificationLog.Retention, ng.notificationLogStore.DeleteNotificationLog)
+			return nil
+		})
+	}
 	return children.Wait()
 }
 
+// newStateHistoryStore returns the store of the state history for the configured backend,
+// or nil if the state history is disabled.
+func newStateHistoryStore(cfg setting.UnifiedAlertingStateHistorySettings, db *store.DBstore) store.StateHistoryStore {
+	if !cfg.Enabled {
+		return nil
+	}
+	switch cfg.Backend {
+	case setting.StateHistoryBackendMemory:
+		return store.NewMemoryStateHistoryStore()
+	default:
+		return db
+	}
+}
+
+// newRecordedSampleStore returns the internal store of the samples of recording rules,
+// or nil if recording rules are disabled or write to another target.
+func newRecordedSampleStore(cfg setting.UnifiedAlertingRecordingRulesSettings, db *store.DBstore) store.RecordedSampleStore {
+	if !cfg.Enabled || cfg.Target != setting.RecordingRulesTargetInternal {
+		return nil
+	}
+	return db
+}
+
+// cleanUpInterval is how often the data that is older than its retention is deleted.
+const cleanUpInterval = time.Hour
+
+// cleanUp deletes the data that is older than the retention using the delete function until the context is canceled.
+func (ng *AlertNG) cleanUp(ctx context.Context, what string, retention time.Duration, deleteFn func(context.Context, time.Time) (int64, error)) {
+	ticker := time.NewTicker(cleanUpInterval)
+	defer ticker.Stop()
+	for {
+		deleted, err := deleteFn(ctx, time.Now().Add(-retention))
+		if err != nil {
+			ng.Log.Error("failed to delete old "+what, "err", err)
+		} else if deleted > 0 {
+			ng.Log.Debug("deleted old "+what, "count", deleted)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // IsDisabled returns true if the alerting service is disable for this instance.
 func (ng *AlertNG) IsDisabled() bool {
 	if ng.Cfg == nil {
@@ -126,10 +126,13 @@ type Alertmanager struct {
 	orgID           int64
 
 	decryptFn channels.GetDecryptedValueFn
+
+	// notificationLogStore is nil if the notification log is disabled.
+	notificationLogStore store.NotificationLogStore
 }
 
 func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store store.AlertingStore, kvStore kvstore.KVStore,
-	peer ClusterPeer, decryptFn channels.GetDecryptedValueFn, ns notifications.Service, m *metrics.Alertmanager) (*Alertmanager, error) {
+	peer ClusterPeer, decryptFn channels.GetDecryptedValueFn, ns notifications.Service, m *metrics.Alertmanager, logStore store.NotificationLogStore) (*Alertmanager, error) {
 	am := &Alertmanager{
 		Settings:            cfg,
 		stopc:               make(chan struct{}),
@@ -144,6 +147,8 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store s
 		NotificationService: ns,
 		orgID:               orgID,
 		decryptFn:           decryptFn,
+
+		notificationLogStore: logStore,
 	}
 
 	am.fileStore = NewFileStore(am.orgID, kvStore, am.WorkingDirPath())
@@ -317,17 +322,18 @@ func (am *Alertmanager) ApplyConfig(dbCfg *ngmodels.AlertConfiguration) error {
 	return nil
 }
 
-func (am *Alertmanager) getTemplate() (*template.Template, error) {
+// getTemplate returns the templates of the configuration, followed by the templates in extraPaths if any.
+func (am *Alertmanager) getTemplate(extraPaths ...string) (*template.Template, error) {
 	am.reloadConfigMtx.RLock()
 	defer am.reloadConfigMtx.RUnlock()
 	if !am.ready() {
 		return nil, errors.New("alertmanager is not initialized")
 	}
-	paths := make([]string, 0, len(am.config.TemplateFiles))
+	paths := make([]string, 0, len(am.config.TemplateFiles)+len(extraPaths))
 	for name := range am.config.TemplateFiles {
 		paths = append(paths, filepath.Join(am.WorkingDirPath(), name))
 	}
-	return am.templateFromPaths(paths...)
+	return am.templateFromPaths(append(paths, extraPaths...)...)
 }
 
 func (am *Alertmanager) templateFromPaths(paths ...string) (*template.Template, error) {
@@ -468,7 +474,11 @@ func (am *Alertmanager) buildReceiverIntegrations(receiver *apimodels.PostableAp
 		if err != nil {
 			return nil, err
 		}
-		integrations = append(integrations, notify.NewIntegration(n, n, r.Type, i))
+		var notifier notify.Notifier = n
+		if am.notificationLogStore != nil {
+			notifier = &loggingNotifier{am: am, notifier: n, cfg: r}
+		}
+		integrations = append(integrations, notify.NewIntegration(notifier, n, r.Type, i))
 	}
 	return integrations, nil
 }
@@ -529,44 +539,7 @@ func (am *Alertmanager) PutAlerts(postableAlerts apimodels.PostableAlerts) error
 	alerts := make([]*types.Alert, 0, len(postableAlerts.PostableAlerts))
 	var validationErr *AlertValidationError
 	for _, a := range postableAlerts.PostableAlerts {
-		alert := &types.Alert{
-			Alert: model.Alert{
-				Labels:       model.LabelSet{},
-				Annotations:  model.LabelSet{},
-				StartsAt:     time.Time(a.StartsAt),
-				EndsAt:       time.Time(a.EndsAt),
-				GeneratorURL: a.GeneratorURL.String(),
-			},
-			UpdatedAt: now,
-		}
-
-		for k, v := range a.Labels {
-			if len(v) == 0 || k == ngmodels.NamespaceUIDLabel { // Skip empty and namespace UID labels.
-				continue
-			}
-			alert.Alert.Labels[model.LabelName(k)] = model.LabelValue(v)
-		}
-		for k, v := range a.Annotations {
-			if len(v) == 0 { // Skip empty annotation.
-				continue
-			}
-			alert.Alert.Annotations[model.LabelName(k)] = model.LabelValue(v)
-		}
-
-		// Ensure StartsAt is set.
-		if alert.StartsAt.IsZero() {
-			if alert.EndsAt.IsZero() {
-				alert.StartsAt = now
-			} else {
-				alert.StartsAt = alert.EndsAt
-			}
-		}
-		// If no end time is defined, set a timeout after which an alert
-		// is marked resolved if it is not updated.
-		if alert.EndsAt.IsZero() {
-			alert.Timeout = true
-			alert.EndsAt = now.Add(defaultResolveTimeout)
-		}
+		alert := newAlert(a, now)
 
 		if alert.EndsAt.After(now) {
 			am.Metrics.Firing().Inc()
@@ -598,6 +571,49 @@ func (am *Alertmanager) PutAlerts(postableAlerts apimodels.PostableAlerts) error
 	return nil
 }
 
+// newAlert converts an alert posted to the Alertmanager to the alert it stores.
+func newAlert(a amv2.PostableAlert, now time.Time) *types.Alert {
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:       model.LabelSet{},
+			Annotations:  model.LabelSet{},
+			StartsAt:     time.Time(a.StartsAt),
+			EndsAt:       time.Time(a.EndsAt),
+			GeneratorURL: a.GeneratorURL.String(),
+		},
+		UpdatedAt: now,
+	}
+
+	for k, v := range a.Labels {
+		if len(v) == 0 || k == ngmodels.NamespaceUIDLabel { // Skip empty and namespace UID labels.
+			continue
+		}
+		alert.Alert.Labels[model.LabelName(k)] = model.LabelValue(v)
+	}
+	for k, v := range a.Annotations {
+		if len(v) == 0 { // Skip empty annotation.
+			continue
+		}
+		alert.Alert.Annotations[model.LabelName(k)] = model.LabelValue(v)
+	}
+
+	// Ensure StartsAt is set.
+	if alert.StartsAt.IsZero() {
+		if alert.EndsAt.IsZero() {
+			alert.StartsAt = now
+		} else {
+			alert.StartsAt = alert.EndsAt
+		}
+	}
+	// If no end time is defined, set a timeout after which an alert
+	// is marked resolved if it is not updated.
+	if alert.EndsAt.IsZero() {
+		alert.Timeout = true
+		alert.EndsAt = now.Add(defaultResolveTimeout)
+	}
+	return alert
+}
+
 // validateAlert is a.Validate() while additionally allowing
 // space for label and annotation names.
 func validateAlert(a *types.Alert) error {
@@ -165,6 +165,178 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 				},
 			},
 		},
+		{
+			Type:        "mqtt",
+			Name:        "MQTT",
+			Description: "Publishes notifications to an MQTT broker",
+			Heading:     "MQTT settings",
+			Options: []alerting.NotifierOption{
+				{
+					Label:        "Broker URL",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Placeholder:  "tcp://localhost:1883",
+					PropertyName: "brokerUrl",
+					Required:     true,
+				},
+				{
+					Label:        "Topic",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "You can use templates for the topic",
+					Placeholder:  "grafana/alerts",
+					PropertyName: "topic",
+					Required:     true,
+				},
+				{
+					Label:   "QoS",
+					Element: alerting.ElementTypeSelect,
+					SelectOptions: []alerting.SelectOption{
+						{
+							Value: "0",
+							Label: "At most once (0)",
+						},
+						{
+							Value: "1",
+							Label: "At least once (1)",
+						},
+						{
+							Value: "2",
+							Label: "Exactly once (2)",
+						},
+					},
+					PropertyName: "qos",
+				},
+				{
+					Label:        "Retain",
+					Element:      alerting.ElementTypeCheckbox,
+					Description:  "The broker keeps the last message of the topic for new subscribers",
+					PropertyName: "retain",
+				},
+				{
+					Label:        "Client ID",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "Optionally provide a client ID, a random client ID is used by default",
+					PropertyName: "clientId",
+				},
+				{
+					Label:        "Username",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "TLS CA Certificate",
+					Element:      alerting.ElementTypeTextArea,
+					Description:  "Optionally provide the CA certificate in PEM format to verify the certificate of the broker",
+					PropertyName: "tlsCACert",
+				},
+				{
+					Label:        "TLS Client Certificate",
+					Element:      alerting.ElementTypeTextArea,
+					Description:  "Optionally provide a client certificate in PEM format to authenticate to the broker",
+					PropertyName: "tlsClientCert",
+				},
+				{
+					Label:        "TLS Client Key",
+					Element:      alerting.ElementTypeTextArea,
+					Description:  "Key of the client certificate in PEM format",
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
+				{
+					Label:        "Skip TLS Verify",
+					Element:      alerting.ElementTypeCheckbox,
+					Description:  "Do not verify the certificate of the broker",
+					PropertyName: "tlsSkipVerify",
+				},
+			},
+		},
+		{
+			Type:        "amqp",
+			Name:        "AMQP",
+			Description: "Publishes notifications to an AMQP 0-9-1 broker, e.g. RabbitMQ",
+			Heading:     "AMQP settings",
+			Options: []alerting.NotifierOption{
+				{
+					Label:        "URL",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Placeholder:  "amqp://localhost:5672/vhost",
+					PropertyName: "url",
+					Required:     true,
+				},
+				{
+					Label:        "Exchange",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "Optionally provide an exchange, the default exchange of the broker is used by default",
+					PropertyName: "exchange",
+				},
+				{
+					Label:        "Routing Key",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "You can use templates for the routing key",
+					Placeholder:  "grafana.alerts",
+					PropertyName: "routingKey",
+					Required:     true,
+				},
+				{
+					Label:        "Persistent",
+					Element:      alerting.ElementTypeCheckbox,
+					Description:  "The broker stores the messages on disk",
+					PropertyName: "persistent",
+				},
+				{
+					Label:        "Username",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "Optionally provide a username, it overrides the credentials of the URL",
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "TLS CA Certificate",
+					Element:      alerting.ElementTypeTextArea,
+					Description:  "Optionally provide the CA certificate in PEM format to verify the certificate of the broker",
+					PropertyName: "tlsCACert",
+				},
+				{
+					Label:        "TLS Client Certificate",
+					Element:      alerting.ElementTypeTextArea,
+					Description:  "Optionally provide a client certificate in PEM format to authenticate to the broker",
+					PropertyName: "tlsClientCert",
+				},
+				{
+					Label:        "TLS Client Key",
+					Element:      alerting.ElementTypeTextArea,
+					Description:  "Key of the client certificate in PEM format",
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
+				{
+					Label:        "Skip TLS Verify",
+					Element:      alerting.ElementTypeCheckbox,
+					Description:  "Do not verify the certificate of the broker",
+					PropertyName: "tlsSkipVerify",
+				},
+			},
+		},
 		{
 			Type:        "email",
 			Name:        "Email",
@@ -665,6 +837,39 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 					InputType:    alerting.InputTypeText,
 					PropertyName: "maxAlerts",
 				},
+				{
+					Label:        "HMAC Signing Secret",
+					Description:  "Optionally provide a secret to sign the requests with HMAC-SHA256. The signature and the timestamp of the request are sent in the X-Grafana-Alerting-Signature and X-Grafana-Alerting-Timestamp headers.",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypePassword,
+					PropertyName: "hmacSecret",
+					Secure:       true,
+				},
+				{
+					Label:        "TLS CA Certificate",
+					Element:      alerting.ElementTypeTextArea,
+					Description:  "Optionally provide the CA certificate in PEM format to verify the certificate of the server",
+					PropertyName: "tlsCACert",
+				},
+				{
+					Label:        "TLS Client Certificate",
+					Element:      alerting.ElementTypeTextArea,
+					Description:  "Optionally provide a client certificate in PEM format to authenticate to the server with mutual TLS",
+					PropertyName: "tlsClientCert",
+				},
+				{
+					Label:        "TLS Client Key",
+					Element:      alerting.ElementTypeTextArea,
+					Description:  "Key of the client certificate in PEM format",
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
+				{
+					Label:        "Skip TLS Verify",
+					Element:      alerting.ElementTypeCheckbox,
+					Description:  "Do not verify the certificate of the server",
+					PropertyName: "tlsSkipVerify",
+				},
 			},
 		},
 		{
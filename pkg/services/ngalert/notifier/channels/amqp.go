@@ -0,0 +1,213 @@
+package channels
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// AMQPNotifier is responsible for publishing
+// alert notifications to an AMQP 0-9-1 broker, e.g. RabbitMQ.
+type AMQPNotifier struct {
+	*Base
+	URL        string
+	Exchange   string
+	RoutingKey string
+	Username   string
+	Password   string
+	Persistent bool
+	TLSConfig  *tls.Config
+	log        log.Logger
+	tmpl       *template.Template
+	orgID      int64
+}
+
+type AMQPConfig struct {
+	*NotificationChannelConfig
+	URL string
+	// Exchange is empty for the default exchange of the broker.
+	Exchange string
+	// RoutingKey is a template of the routing key of the messages.
+	RoutingKey string
+	// Username and Password override the credentials of the URL.
+	Username   string
+	Password   string
+	Persistent bool
+	// TLSConfig is nil unless TLS is configured in the settings.
+	TLSConfig *tls.Config
+}
+
+func AMQPFactory(fc FactoryConfig) (NotificationChannel, error) {
+	cfg, err := NewAMQPConfig(fc.Config, fc.DecryptFunc)
+	if err != nil {
+		return nil, receiverInitError{
+			Reason: err.Error(),
+			Cfg:    *fc.Config,
+		}
+	}
+	return NewAMQPNotifier(cfg, fc.Template), nil
+}
+
+func NewAMQPConfig(config *NotificationChannelConfig, decryptFunc GetDecryptedValueFn) (*AMQPConfig, error) {
+	u := config.Settings.Get("url").MustString()
+	if u == "" {
+		return nil, errors.New("could not find url property in settings")
+	}
+	if _, err := amqp.ParseURI(u); err != nil {
+		return nil, fmt.Errorf("invalid url %q, it must be like amqp://localhost:5672/vhost or amqps://localhost:5671/vhost: %w", u, err)
+	}
+	routingKey := config.Settings.Get("routingKey").MustString()
+	if routingKey == "" {
+		return nil, errors.New("could not find routing key property in settings")
+	}
+
+	tlsCfg, err := newClientTLSConfig(config, decryptFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AMQPConfig{
+		NotificationChannelConfig: config,
+		URL:                       u,
+		Exchange:                  config.Settings.Get("exchange").MustString(),
+		RoutingKey:                routingKey,
+		Username:                  config.Settings.Get("username").MustString(),
+		Password:                  decryptFunc(context.Background(), config.SecureSettings, "password", config.Settings.Get("password").MustString()),
+		Persistent:                config.Settings.Get("persistent").MustBool(false),
+		TLSConfig:                 tlsCfg,
+	}, nil
+}
+
+// NewAMQPNotifier is the constructor for the AMQP notifier.
+func NewAMQPNotifier(config *AMQPConfig, t *template.Template) *AMQPNotifier {
+	return &AMQPNotifier{
+		Base: NewBase(&models.AlertNotification{
+			Uid:                   config.UID,
+			Name:                  config.Name,
+			Type:                  config.Type,
+			DisableResolveMessage: config.DisableResolveMessage,
+			Settings:              config.Settings,
+		}),
+		orgID:      config.OrgID,
+		URL:        config.URL,
+		Exchange:   config.Exchange,
+		RoutingKey: config.RoutingKey,
+		Username:   config.Username,
+		Password:   config.Password,
+		Persistent: config.Persistent,
+		TLSConfig:  config.TLSConfig,
+		log:        log.New("alerting.notifier.amqp"),
+		tmpl:       t,
+	}
+}
+
+// Notify publishes the same message as the webhook notifier to the exchange, and waits
+// until the broker confirms the message.
+func (an *AMQPNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var tmplErr error
+	tmpl, data := TmplText(ctx, an.tmpl, as, an.log, &tmplErr)
+	msg := newWebhookMessage(groupKey.String(), data, tmpl, as, an.orgID, 0)
+	routingKey := tmpl(an.RoutingKey)
+	if tmplErr != nil {
+		an.log.Warn("failed to template AMQP message", "err", tmplErr.Error())
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, brokerTimeout)
+	defer cancel()
+
+	conn, err := an.dial(ctx)
+	if err != nil {
+		an.log.Error("Failed to connect to the AMQP broker", "error", err)
+		return false, fmt.Errorf("failed to connect to the AMQP broker: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			an.log.Warn("Failed to close the connection to the AMQP broker", "error", err)
+		}
+	}()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return false, fmt.Errorf("failed to open an AMQP channel: %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		return false, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	confirms := ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+
+	publishing := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Transient,
+		Timestamp:    timeNow(),
+		AppId:        "Grafana",
+		Body:         body,
+	}
+	if an.Persistent {
+		publishing.DeliveryMode = amqp.Persistent
+	}
+	if err := ch.Publish(an.Exchange, routingKey, false, false, publishing); err != nil {
+		an.log.Error("Failed to publish notification to the AMQP broker", "exchange", an.Exchange, "routingKey", routingKey, "error", err)
+		return false, fmt.Errorf("failed to publish the AMQP message: %w", err)
+	}
+
+	select {
+	case confirm, ok := <-confirms:
+		if !ok || !confirm.Ack {
+			return false, errors.New("the AMQP broker did not accept the message")
+		}
+	case <-ctx.Done():
+		return false, fmt.Errorf("the AMQP broker did not confirm the message: %w", ctx.Err())
+	}
+
+	return true, nil
+}
+
+func (an *AMQPNotifier) dial(ctx context.Context) (*amqp.Connection, error) {
+	config := amqp.Config{
+		Dial: func(network, addr string) (net.Conn, error) {
+			d := net.Dialer{}
+			conn, err := d.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			// the client clears the deadline after the handshake.
+			deadline, _ := ctx.Deadline()
+			if err := conn.SetDeadline(deadline); err != nil {
+				return nil, err
+			}
+			return conn, nil
+		},
+	}
+	if an.TLSConfig != nil {
+		config.TLSClientConfig = an.TLSConfig.Clone()
+	}
+	if an.Username != "" {
+		config.SASL = []amqp.Authentication{&amqp.PlainAuth{Username: an.Username, Password: an.Password}}
+	}
+	return amqp.DialConfig(an.URL, config)
+}
+
+func (an *AMQPNotifier) SendResolved() bool {
+	return !an.GetDisableResolveMessage()
+}
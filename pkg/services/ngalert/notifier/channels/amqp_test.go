@@ -0,0 +1,457 @@
+package channels
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestAMQPNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	certs := newTestCertificates(t)
+	plainBroker := newAMQPBroker(t, nil)
+	tlsBroker := newAMQPBroker(t, certs.serverTLSConfig())
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+				Annotations: model.LabelSet{"ann1": "annv1"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name            string
+		broker          *amqpBroker
+		settings        map[string]interface{}
+		expExchange     string
+		expRoutingKey   string
+		expDeliveryMode uint8
+		expUsername     string
+		expInitError    string
+		expMsgError     string
+	}{
+		{
+			name:            "Default config",
+			broker:          plainBroker,
+			settings:        map[string]interface{}{"routingKey": "alerts"},
+			expRoutingKey:   "alerts",
+			expDeliveryMode: 1,
+			expUsername:     "guest",
+		},
+		{
+			name:   "Templated routing key, exchange, persistent messages and credentials",
+			broker: plainBroker,
+			settings: map[string]interface{}{
+				"exchange":   "grafana",
+				"routingKey": "alerts.{{ .CommonLabels.alertname }}",
+				"persistent": true,
+				"username":   "grafana",
+				"password":   "secret",
+			},
+			expExchange:     "grafana",
+			expRoutingKey:   "alerts.alert1",
+			expDeliveryMode: 2,
+			expUsername:     "grafana",
+		},
+		{
+			name:   "TLS with a client certificate",
+			broker: tlsBroker,
+			settings: map[string]interface{}{
+				"routingKey":    "alerts",
+				"tlsCACert":     certs.caPEM,
+				"tlsClientCert": certs.clientCertPEM,
+				"tlsClientKey":  certs.clientKeyPEM,
+			},
+			expRoutingKey:   "alerts",
+			expDeliveryMode: 1,
+			expUsername:     "guest",
+		},
+		{
+			name:        "Invalid credentials",
+			broker:      plainBroker,
+			settings:    map[string]interface{}{"routingKey": "alerts", "username": "grafana", "password": "wrong"},
+			expMsgError: "failed to connect to the AMQP broker: Exception (403) Reason: \"username or password not allowed\"",
+		},
+		{
+			name:        "Message rejected by the broker",
+			broker:      plainBroker,
+			settings:    map[string]interface{}{"exchange": "rejected", "routingKey": "alerts"},
+			expMsgError: "the AMQP broker did not accept the message",
+		},
+		{
+			name:         "Missing routing key",
+			broker:       plainBroker,
+			settings:     map[string]interface{}{},
+			expInitError: "could not find routing key property in settings",
+		},
+		{
+			name:         "Invalid URL",
+			broker:       &amqpBroker{url: "http://localhost"},
+			settings:     map[string]interface{}{"routingKey": "alerts"},
+			expInitError: `invalid url "http://localhost", it must be like amqp://localhost:5672/vhost or amqps://localhost:5671/vhost: AMQP scheme must be either 'amqp://' or 'amqps://'`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settings := map[string]interface{}{"url": c.broker.url}
+			for k, v := range c.settings {
+				settings[k] = v
+			}
+			m := &NotificationChannelConfig{
+				OrgID:          1,
+				Name:           "amqp_testing",
+				Type:           "amqp",
+				Settings:       simplejson.NewFromAny(settings),
+				SecureSettings: map[string][]byte{},
+			}
+
+			secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+			cfg, err := NewAMQPConfig(m, secretsService.GetDecryptedValue)
+			if c.expInitError != "" {
+				require.EqualError(t, err, c.expInitError)
+				return
+			}
+			require.NoError(t, err)
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+			ctx = notify.WithReceiverName(ctx, "my_receiver")
+			n := NewAMQPNotifier(cfg, tmpl)
+			published := len(c.broker.published())
+			ok, err := n.Notify(ctx, alerts...)
+			if c.expMsgError != "" {
+				require.False(t, ok)
+				require.EqualError(t, err, c.expMsgError)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			messages := c.broker.published()
+			require.Len(t, messages, published+1)
+			msg := messages[published]
+			require.Equal(t, c.expExchange, msg.exchange)
+			require.Equal(t, c.expRoutingKey, msg.routingKey)
+			require.Equal(t, c.expDeliveryMode, msg.deliveryMode)
+			require.Equal(t, c.expUsername, msg.username)
+			require.Equal(t, "application/json", msg.contentType)
+
+			var body webhookMessage
+			require.NoError(t, json.Unmarshal(msg.body, &body))
+			require.Equal(t, "alerting", body.State)
+			require.Equal(t, "[FIRING:1]  (val1)", body.Title)
+			require.Equal(t, "alertname", body.GroupKey)
+			require.Equal(t, int64(1), body.OrgID)
+		})
+	}
+}
+
+type amqpMessage struct {
+	username     string
+	exchange     string
+	routingKey   string
+	contentType  string
+	deliveryMode uint8
+	body         []byte
+}
+
+// amqpBroker is an in-process stand-in of an AMQP 0-9-1 broker that records the published messages.
+// It implements the handshake, the channels and the publisher confirms, and accepts the credentials
+// guest:guest and grafana:secret. The messages published to the exchange "rejected" are rejected.
+type amqpBroker struct {
+	url      string
+	mtx      sync.Mutex
+	messages []amqpMessage
+}
+
+func newAMQPBroker(t *testing.T, tlsCfg *tls.Config) *amqpBroker {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	scheme := "amqp"
+	if tlsCfg != nil {
+		l = tls.NewListener(l, tlsCfg)
+		scheme = "amqps"
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	b := &amqpBroker{url: fmt.Sprintf("%s://%s/", scheme, l.Addr())}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				_ = b.serve(conn)
+			}()
+		}
+	}()
+	return b
+}
+
+func (b *amqpBroker) serve(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	protocol := make([]byte, 8)
+	if _, err := io.ReadFull(r, protocol); err != nil {
+		return err
+	}
+	if string(protocol) != "AMQP\x00\x00\x09\x01" {
+		return errors.New("unsupported protocol")
+	}
+
+	// connection.start
+	start := amqpArgs{}
+	start.octet(0).octet(9).longstr("").longstr("PLAIN").longstr("en_US")
+	if err := writeAMQPMethod(conn, 0, 10, 10, start); err != nil {
+		return err
+	}
+	// connection.start-ok
+	f, err := readAMQPFrame(r)
+	if err != nil {
+		return err
+	}
+	args := f.args()
+	args.uint16()
+	args.uint16()
+	args.longstr()  // client properties
+	args.shortstr() // mechanism
+	response := strings.Split(args.longstr(), "\x00")
+	if len(response) != 3 || !(response[1] == "guest" && response[2] == "guest" || response[1] == "grafana" && response[2] == "secret") {
+		return errors.New("invalid credentials")
+	}
+	username := response[1]
+
+	// connection.tune
+	tune := amqpArgs{}
+	tune.short(0).long(131072).short(0)
+	if err := writeAMQPMethod(conn, 0, 10, 30, tune); err != nil {
+		return err
+	}
+
+	var (
+		deliveryTag uint64
+		pending     amqpMessage
+		bodySize    uint64
+	)
+	for {
+		f, err := readAMQPFrame(r)
+		if err != nil {
+			return err
+		}
+		switch f.typ {
+		case 1:
+			args := f.args()
+			class, method := args.uint16(), args.uint16()
+			var reply *amqpArgs
+			var replyClass, replyMethod uint16
+			switch {
+			case class == 10 && method == 40: // connection.open
+				reply, replyClass, replyMethod = (&amqpArgs{}).shortstr(""), 10, 41
+			case class == 10 && method == 50: // connection.close
+				return writeAMQPMethod(conn, 0, 10, 51, amqpArgs{})
+			case class == 20 && method == 10: // channel.open
+				reply, replyClass, replyMethod = (&amqpArgs{}).longstr(""), 20, 11
+			case class == 20 && method == 40: // channel.close
+				reply, replyClass, replyMethod = &amqpArgs{}, 20, 41
+			case class == 85 && method == 10: // confirm.select
+				reply, replyClass, replyMethod = &amqpArgs{}, 85, 11
+			case class == 60 && method == 40: // basic.publish
+				args.uint16()
+				pending = amqpMessage{username: username, exchange: args.shortstr(), routingKey: args.shortstr()}
+			}
+			if reply != nil {
+				if err := writeAMQPMethod(conn, f.channel, replyClass, replyMethod, *reply); err != nil {
+					return err
+				}
+			}
+		case 2: // content header
+			args := f.args()
+			args.uint16()
+			args.uint16()
+			bodySize = args.uint64()
+			flags := args.uint16()
+			if flags&0x8000 != 0 {
+				pending.contentType = args.shortstr()
+			}
+			if flags&0x4000 != 0 {
+				args.shortstr()
+			}
+			if flags&0x2000 != 0 {
+				args.longstr()
+			}
+			if flags&0x1000 != 0 {
+				pending.deliveryMode = args.octet()
+			}
+		case 3: // content body
+			pending.body = append(pending.body, f.payload...)
+		}
+
+		if f.typ == 2 || f.typ == 3 {
+			if uint64(len(pending.body)) < bodySize {
+				continue
+			}
+			deliveryTag++
+			ack := amqpArgs{}
+			ack.uint64(deliveryTag).octet(0)
+			if pending.exchange == "rejected" {
+				// basic.nack
+				if err := writeAMQPMethod(conn, f.channel, 60, 120, ack); err != nil {
+					return err
+				}
+				continue
+			}
+			b.mtx.Lock()
+			b.messages = append(b.messages, pending)
+			b.mtx.Unlock()
+			// basic.ack
+			if err := writeAMQPMethod(conn, f.channel, 60, 80, ack); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (b *amqpBroker) published() []amqpMessage {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return append([]amqpMessage(nil), b.messages...)
+}
+
+type amqpFrame struct {
+	typ     byte
+	channel uint16
+	payload []byte
+}
+
+func (f amqpFrame) args() *amqpReader {
+	return &amqpReader{r: bytes.NewReader(f.payload)}
+}
+
+func readAMQPFrame(r io.Reader) (amqpFrame, error) {
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return amqpFrame{}, err
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(header[3:])+1)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return amqpFrame{}, err
+	}
+	if payload[len(payload)-1] != 0xCE {
+		return amqpFrame{}, errors.New("invalid frame end")
+	}
+	return amqpFrame{typ: header[0], channel: binary.BigEndian.Uint16(header[1:]), payload: payload[:len(payload)-1]}, nil
+}
+
+func writeAMQPMethod(w io.Writer, channel, class, method uint16, args amqpArgs) error {
+	payload := amqpArgs{}
+	payload.short(class).short(method)
+	payload.Write(args.Bytes())
+	frame := amqpArgs{}
+	frame.octet(1).short(channel).long(uint32(payload.Len()))
+	frame.Write(payload.Bytes())
+	frame.octet(0xCE)
+	_, err := w.Write(frame.Bytes())
+	return err
+}
+
+// amqpArgs encodes the arguments of AMQP methods.
+type amqpArgs struct {
+	bytes.Buffer
+}
+
+func (a *amqpArgs) octet(v uint8) *amqpArgs {
+	a.WriteByte(v)
+	return a
+}
+
+func (a *amqpArgs) short(v uint16) *amqpArgs {
+	_ = binary.Write(a, binary.BigEndian, v)
+	return a
+}
+
+func (a *amqpArgs) long(v uint32) *amqpArgs {
+	_ = binary.Write(a, binary.BigEndian, v)
+	return a
+}
+
+func (a *amqpArgs) uint64(v uint64) *amqpArgs {
+	_ = binary.Write(a, binary.BigEndian, v)
+	return a
+}
+
+func (a *amqpArgs) shortstr(s string) *amqpArgs {
+	a.octet(uint8(len(s)))
+	a.WriteString(s)
+	return a
+}
+
+func (a *amqpArgs) longstr(s string) *amqpArgs {
+	a.long(uint32(len(s)))
+	a.WriteString(s)
+	return a
+}
+
+// amqpReader decodes the arguments of AMQP methods.
+type amqpReader struct {
+	r *bytes.Reader
+}
+
+func (a *amqpReader) octet() uint8 {
+	v, _ := a.r.ReadByte()
+	return v
+}
+
+func (a *amqpReader) uint16() uint16 {
+	var v uint16
+	_ = binary.Read(a.r, binary.BigEndian, &v)
+	return v
+}
+
+func (a *amqpReader) uint64() uint64 {
+	var v uint64
+	_ = binary.Read(a.r, binary.BigEndian, &v)
+	return v
+}
+
+func (a *amqpReader) shortstr() string {
+	b := make([]byte, a.octet())
+	_, _ = io.ReadFull(a.r, b)
+	return string(b)
+}
+
+func (a *amqpReader) longstr() string {
+	var n uint32
+	_ = binary.Read(a.r, binary.BigEndian, &n)
+	b := make([]byte, n)
+	_, _ = io.ReadFull(a.r, b)
+	return string(b)
+}
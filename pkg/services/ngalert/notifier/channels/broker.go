@@ -0,0 +1,7 @@
+package channels
+
+import "time"
+
+// brokerTimeout is the timeout of the connection to a message broker and of the
+// publication of a message, unless the context of the notification ends earlier.
+const brokerTimeout = 30 * time.Second
@@ -0,0 +1,145 @@
+package channels
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestNewClientTLSConfig(t *testing.T) {
+	certs := newTestCertificates(t)
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+	encryptedKey, err := secretsService.Encrypt(context.Background(), []byte(certs.clientKeyPEM), secrets.WithoutScope())
+	require.NoError(t, err)
+
+	newConfig := func(t *testing.T, settings map[string]interface{}, secureSettings map[string][]byte) *NotificationChannelConfig {
+		t.Helper()
+		return &NotificationChannelConfig{
+			Settings:       simplejson.NewFromAny(settings),
+			SecureSettings: secureSettings,
+		}
+	}
+
+	t.Run("returns nil without TLS settings", func(t *testing.T) {
+		tlsCfg, err := newClientTLSConfig(newConfig(t, map[string]interface{}{}, map[string][]byte{}), secretsService.GetDecryptedValue)
+		require.NoError(t, err)
+		require.Nil(t, tlsCfg)
+	})
+
+	t.Run("loads the CA certificate and the encrypted client key", func(t *testing.T) {
+		cfg := newConfig(t, map[string]interface{}{
+			"tlsCACert":     certs.caPEM,
+			"tlsClientCert": certs.clientCertPEM,
+		}, map[string][]byte{"tlsClientKey": encryptedKey})
+		tlsCfg, err := newClientTLSConfig(cfg, secretsService.GetDecryptedValue)
+		require.NoError(t, err)
+		require.NotNil(t, tlsCfg.RootCAs)
+		require.Len(t, tlsCfg.Certificates, 1)
+		require.False(t, tlsCfg.InsecureSkipVerify)
+	})
+
+	t.Run("skips the verification of the server certificate", func(t *testing.T) {
+		tlsCfg, err := newClientTLSConfig(newConfig(t, map[string]interface{}{"tlsSkipVerify": true}, map[string][]byte{}), secretsService.GetDecryptedValue)
+		require.NoError(t, err)
+		require.True(t, tlsCfg.InsecureSkipVerify)
+	})
+
+	t.Run("returns an error for invalid certificates", func(t *testing.T) {
+		_, err := newClientTLSConfig(newConfig(t, map[string]interface{}{"tlsCACert": "invalid"}, map[string][]byte{}), secretsService.GetDecryptedValue)
+		require.EqualError(t, err, "failed to parse the TLS CA certificate")
+
+		_, err = newClientTLSConfig(newConfig(t, map[string]interface{}{"tlsClientCert": certs.clientCertPEM}, map[string][]byte{}), secretsService.GetDecryptedValue)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse the TLS client certificate and key")
+	})
+}
+
+// testCertificates are a CA, and a server and a client certificate signed by the CA for the tests of the
+// contact points that connect to message brokers with TLS.
+type testCertificates struct {
+	caPEM         string
+	ca            *x509.CertPool
+	server        tls.Certificate
+	clientCertPEM string
+	clientKeyPEM  string
+}
+
+func newTestCertificates(t *testing.T) testCertificates {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	sign := func(serial int64, usage x509.ExtKeyUsage) (string, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "localhost"},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+			string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	}
+
+	serverCert, serverKey := sign(2, x509.ExtKeyUsageServerAuth)
+	server, err := tls.X509KeyPair([]byte(serverCert), []byte(serverKey))
+	require.NoError(t, err)
+	clientCert, clientKey := sign(3, x509.ExtKeyUsageClientAuth)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	return testCertificates{
+		caPEM:         string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})),
+		ca:            pool,
+		server:        server,
+		clientCertPEM: clientCert,
+		clientKeyPEM:  clientKey,
+	}
+}
+
+// serverTLSConfig returns the TLS configuration of a broker that requires a client certificate signed by the CA.
+func (c testCertificates) serverTLSConfig() *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{c.server},
+		ClientCAs:    c.ca,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+}
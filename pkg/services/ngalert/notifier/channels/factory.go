@@ -35,12 +35,14 @@ func NewFactoryConfig(config *NotificationChannelConfig, notificationService not
 
 var receiverFactories = map[string]func(FactoryConfig) (NotificationChannel, error){
 	"prometheus-alertmanager": AlertmanagerFactory,
+	"amqp":                    AMQPFactory,
 	"dingding":                DingDingFactory,
 	"discord":                 DiscrodFactory,
 	"email":                   EmailFactory,
 	"googlechat":              GoogleChatFactory,
 	"kafka":                   KafkaFactory,
 	"line":                    LineFactory,
+	"mqtt":                    MQTTFactory,
 	"opsgenie":                OpsgenieFactory,
 	"pagerduty":               PagerdutyFactory,
 	"pushover":                PushoverFactory,
@@ -0,0 +1,201 @@
+package channels
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// MQTTNotifier is responsible for publishing
+// alert notifications to an MQTT broker.
+type MQTTNotifier struct {
+	*Base
+	BrokerURL string
+	Topic     string
+	ClientID  string
+	Username  string
+	Password  string
+	QoS       byte
+	Retain    bool
+	TLSConfig *tls.Config
+	log       log.Logger
+	tmpl      *template.Template
+	orgID     int64
+}
+
+type MQTTConfig struct {
+	*NotificationChannelConfig
+	BrokerURL string
+	// Topic is a template of the topic of the messages.
+	Topic    string
+	ClientID string
+	Username string
+	Password string
+	QoS      byte
+	Retain   bool
+	// TLSConfig is nil unless TLS is configured in the settings.
+	TLSConfig *tls.Config
+}
+
+func MQTTFactory(fc FactoryConfig) (NotificationChannel, error) {
+	cfg, err := NewMQTTConfig(fc.Config, fc.DecryptFunc)
+	if err != nil {
+		return nil, receiverInitError{
+			Reason: err.Error(),
+			Cfg:    *fc.Config,
+		}
+	}
+	return NewMQTTNotifier(cfg, fc.Template), nil
+}
+
+func NewMQTTConfig(config *NotificationChannelConfig, decryptFunc GetDecryptedValueFn) (*MQTTConfig, error) {
+	brokerURL := config.Settings.Get("brokerUrl").MustString()
+	if brokerURL == "" {
+		return nil, errors.New("could not find broker URL property in settings")
+	}
+	u, err := url.Parse(brokerURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid broker URL %q, it must be like tcp://localhost:1883 or ssl://localhost:8883", brokerURL)
+	}
+	topic := config.Settings.Get("topic").MustString()
+	if topic == "" {
+		return nil, errors.New("could not find topic property in settings")
+	}
+
+	qos := config.Settings.Get("qos").MustInt(0)
+	// the QoS is a string when it is set in the UI.
+	if s := config.Settings.Get("qos").MustString(); s != "" {
+		if qos, err = strconv.Atoi(s); err != nil {
+			return nil, fmt.Errorf("invalid QoS %q", s)
+		}
+	}
+	if qos < 0 || qos > 2 {
+		return nil, fmt.Errorf("invalid QoS %d, it must be 0, 1 or 2", qos)
+	}
+
+	tlsCfg, err := newClientTLSConfig(config, decryptFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MQTTConfig{
+		NotificationChannelConfig: config,
+		BrokerURL:                 brokerURL,
+		Topic:                     topic,
+		ClientID:                  config.Settings.Get("clientId").MustString(),
+		Username:                  config.Settings.Get("username").MustString(),
+		Password:                  decryptFunc(context.Background(), config.SecureSettings, "password", config.Settings.Get("password").MustString()),
+		QoS:                       byte(qos),
+		Retain:                    config.Settings.Get("retain").MustBool(false),
+		TLSConfig:                 tlsCfg,
+	}, nil
+}
+
+// NewMQTTNotifier is the constructor for the MQTT notifier.
+func NewMQTTNotifier(config *MQTTConfig, t *template.Template) *MQTTNotifier {
+	return &MQTTNotifier{
+		Base: NewBase(&models.AlertNotification{
+			Uid:                   config.UID,
+			Name:                  config.Name,
+			Type:                  config.Type,
+			DisableResolveMessage: config.DisableResolveMessage,
+			Settings:              config.Settings,
+		}),
+		orgID:     config.OrgID,
+		BrokerURL: config.BrokerURL,
+		Topic:     config.Topic,
+		ClientID:  config.ClientID,
+		Username:  config.Username,
+		Password:  config.Password,
+		QoS:       config.QoS,
+		Retain:    config.Retain,
+		TLSConfig: config.TLSConfig,
+		log:       log.New("alerting.notifier.mqtt"),
+		tmpl:      t,
+	}
+}
+
+// Notify publishes the same message as the webhook notifier to the topic.
+func (mn *MQTTNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var tmplErr error
+	tmpl, data := TmplText(ctx, mn.tmpl, as, mn.log, &tmplErr)
+	msg := newWebhookMessage(groupKey.String(), data, tmpl, as, mn.orgID, 0)
+	topic := tmpl(mn.Topic)
+	if tmplErr != nil {
+		mn.log.Warn("failed to template MQTT message", "err", tmplErr.Error())
+	}
+	if topic == "" || strings.ContainsAny(topic, "+#") {
+		return false, fmt.Errorf("invalid MQTT topic %q, it must not be empty or contain wildcards", topic)
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return false, err
+	}
+
+	clientID := mn.ClientID
+	if clientID == "" {
+		clientID = "grafana-" + util.GenerateShortUID()
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(mn.BrokerURL).
+		SetClientID(clientID).
+		SetUsername(mn.Username).
+		SetPassword(mn.Password).
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetConnectTimeout(brokerTimeout)
+	if mn.TLSConfig != nil {
+		opts.SetTLSConfig(mn.TLSConfig.Clone())
+	}
+
+	client := mqtt.NewClient(opts)
+	if err := waitMQTTToken(ctx, client.Connect()); err != nil {
+		mn.log.Error("Failed to connect to the MQTT broker", "broker", mn.BrokerURL, "error", err)
+		return false, fmt.Errorf("failed to connect to the MQTT broker: %w", err)
+	}
+	defer client.Disconnect(250)
+
+	if err := waitMQTTToken(ctx, client.Publish(topic, mn.QoS, mn.Retain, body)); err != nil {
+		mn.log.Error("Failed to publish notification to the MQTT broker", "broker", mn.BrokerURL, "topic", topic, "error", err)
+		return false, fmt.Errorf("failed to publish the MQTT message: %w", err)
+	}
+
+	return true, nil
+}
+
+func (mn *MQTTNotifier) SendResolved() bool {
+	return !mn.GetDisableResolveMessage()
+}
+
+// waitMQTTToken waits until the operation of the token completes, or until the
+// context or the broker timeout ends.
+func waitMQTTToken(ctx context.Context, token mqtt.Token) error {
+	ctx, cancel := context.WithTimeout(ctx, brokerTimeout)
+	defer cancel()
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
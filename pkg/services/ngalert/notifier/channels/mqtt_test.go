@@ -0,0 +1,266 @@
+package channels
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestMQTTNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	certs := newTestCertificates(t)
+	plainBroker := newMQTTBroker(t, nil)
+	tlsBroker := newMQTTBroker(t, certs.serverTLSConfig())
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+				Annotations: model.LabelSet{"ann1": "annv1"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		broker       *mqttBroker
+		settings     map[string]interface{}
+		expTopic     string
+		expQoS       byte
+		expRetain    bool
+		expUsername  string
+		expInitError string
+		expMsgError  string
+	}{
+		{
+			name:     "Default config",
+			broker:   plainBroker,
+			settings: map[string]interface{}{"topic": "grafana/alerts"},
+			expTopic: "grafana/alerts",
+		},
+		{
+			name:   "Templated topic, QoS 1, retain and credentials",
+			broker: plainBroker,
+			settings: map[string]interface{}{
+				"topic":    "grafana/{{ .CommonLabels.alertname }}",
+				"qos":      "1",
+				"retain":   true,
+				"username": "grafana",
+				"password": "secret",
+				"clientId": "factory-floor",
+			},
+			expTopic:    "grafana/alert1",
+			expQoS:      1,
+			expRetain:   true,
+			expUsername: "grafana",
+		},
+		{
+			name:     "QoS 2",
+			broker:   plainBroker,
+			settings: map[string]interface{}{"topic": "grafana/alerts", "qos": 2},
+			expTopic: "grafana/alerts",
+			expQoS:   2,
+		},
+		{
+			name:   "TLS with a client certificate",
+			broker: tlsBroker,
+			settings: map[string]interface{}{
+				"topic":         "grafana/alerts",
+				"tlsCACert":     certs.caPEM,
+				"tlsClientCert": certs.clientCertPEM,
+				"tlsClientKey":  certs.clientKeyPEM,
+			},
+			expTopic: "grafana/alerts",
+		},
+		{
+			name:        "Invalid credentials",
+			broker:      plainBroker,
+			settings:    map[string]interface{}{"topic": "grafana/alerts", "username": "grafana", "password": "wrong"},
+			expMsgError: "failed to connect to the MQTT broker: bad user name or password",
+		},
+		{
+			name:        "Topic with wildcards",
+			broker:      plainBroker,
+			settings:    map[string]interface{}{"topic": "grafana/#"},
+			expMsgError: `invalid MQTT topic "grafana/#", it must not be empty or contain wildcards`,
+		},
+		{
+			name:         "Missing topic",
+			broker:       plainBroker,
+			settings:     map[string]interface{}{},
+			expInitError: "could not find topic property in settings",
+		},
+		{
+			name:         "Invalid QoS",
+			broker:       plainBroker,
+			settings:     map[string]interface{}{"topic": "grafana/alerts", "qos": 3},
+			expInitError: "invalid QoS 3, it must be 0, 1 or 2",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settings := map[string]interface{}{"brokerUrl": c.broker.url}
+			for k, v := range c.settings {
+				settings[k] = v
+			}
+			m := &NotificationChannelConfig{
+				OrgID:          1,
+				Name:           "mqtt_testing",
+				Type:           "mqtt",
+				Settings:       simplejson.NewFromAny(settings),
+				SecureSettings: map[string][]byte{},
+			}
+
+			secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+			cfg, err := NewMQTTConfig(m, secretsService.GetDecryptedValue)
+			if c.expInitError != "" {
+				require.EqualError(t, err, c.expInitError)
+				return
+			}
+			require.NoError(t, err)
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+			ctx = notify.WithReceiverName(ctx, "my_receiver")
+			n := NewMQTTNotifier(cfg, tmpl)
+			published := len(c.broker.published())
+			ok, err := n.Notify(ctx, alerts...)
+			if c.expMsgError != "" {
+				require.False(t, ok)
+				require.EqualError(t, err, c.expMsgError)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			// the messages with QoS 0 are not acknowledged by the broker.
+			require.Eventually(t, func() bool {
+				return len(c.broker.published()) > published
+			}, time.Second, 10*time.Millisecond)
+			msg := c.broker.published()[published]
+			require.Equal(t, c.expTopic, msg.TopicName)
+			require.Equal(t, c.expQoS, msg.Qos)
+			require.Equal(t, c.expRetain, msg.Retain)
+			require.Equal(t, c.expUsername, msg.username)
+
+			var body webhookMessage
+			require.NoError(t, json.Unmarshal(msg.Payload, &body))
+			require.Equal(t, "alerting", body.State)
+			require.Equal(t, "[FIRING:1]  (val1)", body.Title)
+			require.Equal(t, "alertname", body.GroupKey)
+			require.Equal(t, int64(1), body.OrgID)
+		})
+	}
+}
+
+type mqttMessage struct {
+	*packets.PublishPacket
+	username string
+}
+
+// mqttBroker is an in-process stand-in of an MQTT broker that records the published messages.
+// It accepts the connections without credentials and with the credentials grafana:secret.
+type mqttBroker struct {
+	url      string
+	mtx      sync.Mutex
+	messages []mqttMessage
+}
+
+func newMQTTBroker(t *testing.T, tlsCfg *tls.Config) *mqttBroker {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	scheme := "tcp"
+	if tlsCfg != nil {
+		l = tls.NewListener(l, tlsCfg)
+		scheme = "ssl"
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	b := &mqttBroker{url: fmt.Sprintf("%s://%s", scheme, l.Addr())}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *mqttBroker) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	var username string
+	for {
+		p, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		var reply packets.ControlPacket
+		switch p := p.(type) {
+		case *packets.ConnectPacket:
+			ack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+			if p.Username != "" && (p.Username != "grafana" || string(p.Password) != "secret") {
+				ack.ReturnCode = packets.ErrRefusedBadUsernameOrPassword
+			}
+			username = p.Username
+			reply = ack
+		case *packets.PublishPacket:
+			b.mtx.Lock()
+			b.messages = append(b.messages, mqttMessage{PublishPacket: p, username: username})
+			b.mtx.Unlock()
+			switch p.Qos {
+			case 1:
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				reply = ack
+			case 2:
+				rec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+				rec.MessageID = p.MessageID
+				reply = rec
+			}
+		case *packets.PubrelPacket:
+			comp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			comp.MessageID = p.MessageID
+			reply = comp
+		case *packets.PingreqPacket:
+			reply = packets.NewControlPacket(packets.Pingresp)
+		case *packets.DisconnectPacket:
+			return
+		}
+		if reply != nil {
+			if err := reply.Write(conn); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (b *mqttBroker) published() []mqttMessage {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return append([]mqttMessage(nil), b.messages...)
+}
@@ -0,0 +1,59 @@
+package channels
+
+import (
+	"context"
+	"strings"
+
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const defaultMessageEmbed = `{{ template "default.message" . }}`
+
+// TemplateFields are the names of the settings of an integration with the templates of the title and the
+// body of its notifications. An empty name means the template cannot be changed.
+type TemplateFields struct {
+	Title string
+	Body  string
+}
+
+// previewDefaultBodies are the default templates of the body of the integration types that do not send the
+// default message.
+var previewDefaultBodies = map[string]string{
+	"teams": `{{ template "teams.default.message" . }}`,
+}
+
+// PreviewTemplates renders the title and the body of the notification of the alerts that an integration
+// would send, with the templates in the fields of its settings or the default templates if settings is nil.
+// Nothing is sent. The context must have the group key, the group labels and the receiver name of the
+// notification.
+func PreviewTemplates(ctx context.Context, integrationType string, fields TemplateFields, settings *simplejson.Json, tmpl *template.Template, as []*types.Alert, l log.Logger) (string, string, error) {
+	if settings == nil {
+		settings = simplejson.New()
+	}
+
+	titleTmpl := DefaultMessageTitleEmbed
+	if fields.Title != "" {
+		titleTmpl = settings.Get(fields.Title).MustString(DefaultMessageTitleEmbed)
+	}
+	defaultBody, ok := previewDefaultBodies[strings.ToLower(integrationType)]
+	if !ok {
+		defaultBody = defaultMessageEmbed
+	}
+	bodyTmpl := defaultBody
+	if fields.Body != "" {
+		bodyTmpl = settings.Get(fields.Body).MustString(defaultBody)
+	}
+
+	var tmplErr error
+	text, _ := TmplText(ctx, tmpl, as, l, &tmplErr)
+	title := text(titleTmpl)
+	body := text(bodyTmpl)
+	if tmplErr != nil {
+		return "", "", tmplErr
+	}
+	return title, body, nil
+}
@@ -0,0 +1,65 @@
+package channels
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestPreviewTemplates(t *testing.T) {
+	tmpl := templateForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{{
+		Alert: model.Alert{
+			Labels:      model.LabelSet{"alertname": "HighLatency", "team": "ops"},
+			Annotations: model.LabelSet{"summary": "the latency is high"},
+		},
+	}}
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "HighLatency"})
+	ctx = notify.WithReceiverName(ctx, "ops")
+	logger := log.New("test")
+
+	t.Run("renders the default templates", func(t *testing.T) {
+		title, body, err := PreviewTemplates(ctx, "webhook", TemplateFields{}, nil, tmpl, alerts, logger)
+		require.NoError(t, err)
+		require.Equal(t, "[FIRING:1] HighLatency (ops)", title)
+		require.Contains(t, body, "**Firing**")
+		require.Contains(t, body, "summary = the latency is high")
+	})
+
+	t.Run("renders the templates of the settings", func(t *testing.T) {
+		settings := simplejson.NewFromAny(map[string]interface{}{
+			"title": "{{ .CommonLabels.team }} alert",
+			"text":  "{{ range .Alerts }}{{ .Annotations.summary }}{{ end }}",
+		})
+		title, body, err := PreviewTemplates(ctx, "slack", TemplateFields{Title: "title", Body: "text"}, settings, tmpl, alerts, logger)
+		require.NoError(t, err)
+		require.Equal(t, "ops alert", title)
+		require.Equal(t, "the latency is high", body)
+	})
+
+	t.Run("returns template errors", func(t *testing.T) {
+		settings := simplejson.NewFromAny(map[string]interface{}{"message": `{{ template "missing" . }}`})
+		_, _, err := PreviewTemplates(ctx, "telegram", TemplateFields{Body: "message"}, settings, tmpl, alerts, logger)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `template "missing" not defined`)
+	})
+
+	t.Run("renders the default body of the integration type", func(t *testing.T) {
+		_, body, err := PreviewTemplates(ctx, "teams", TemplateFields{Body: "message"}, nil, tmpl, alerts, logger)
+		require.NoError(t, err)
+		require.Contains(t, body, "summary = the latency is high")
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -16,6 +18,7 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/util"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -112,10 +115,17 @@ var sendHTTPRequest = func(ctx context.Context, url *url.URL, cfg httpCfg, logge
 		Timeout:   time.Second * 30,
 		Transport: netTransport,
 	}
+	recorded := notifications.SentWebhookFromContext(ctx)
+	if recorded != nil {
+		recorded.Body = cfg.body
+	}
 	resp, err := netClient.Do(request)
 	if err != nil {
 		return nil, err
 	}
+	if recorded != nil {
+		recorded.StatusCode = resp.StatusCode
+	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
 			logger.Warn("Failed to close response body", "err", err)
@@ -137,6 +147,38 @@ var sendHTTPRequest = func(ctx context.Context, url *url.URL, cfg httpCfg, logge
 	return respBody, nil
 }
 
+// newClientTLSConfig returns the TLS configuration of the connection of a contact point to a server,
+// e.g. a message broker, from its settings. The certificates are in PEM format, and the client key
+// is a secure setting. It returns nil if none of the TLS settings are set.
+func newClientTLSConfig(config *NotificationChannelConfig, decryptFunc GetDecryptedValueFn) (*tls.Config, error) {
+	caCert := config.Settings.Get("tlsCACert").MustString()
+	clientCert := config.Settings.Get("tlsClientCert").MustString()
+	clientKey := decryptFunc(context.Background(), config.SecureSettings, "tlsClientKey", config.Settings.Get("tlsClientKey").MustString())
+	skipVerify := config.Settings.Get("tlsSkipVerify").MustBool(false)
+	if caCert == "" && clientCert == "" && clientKey == "" && !skipVerify {
+		return nil, nil
+	}
+
+	tlsCfg := &tls.Config{
+		InsecureSkipVerify: skipVerify,
+	}
+	if caCert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caCert)) {
+			return nil, errors.New("failed to parse the TLS CA certificate")
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if clientCert != "" || clientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the TLS client certificate and key: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
+
 func joinUrlPath(base, additionalPath string, logger log.Logger) string {
 	u, err := url.Parse(base)
 	if err != nil {
@@ -2,9 +2,14 @@ package channels
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/template"
@@ -16,6 +21,15 @@ import (
 	"github.com/grafana/grafana/pkg/services/notifications"
 )
 
+const (
+	// WebhookSignatureHeader is the header of the HMAC-SHA256 signature of the requests of webhooks
+	// with a signing secret. Its value is sha256= followed by the hex encoded signature.
+	WebhookSignatureHeader = "X-Grafana-Alerting-Signature"
+	// WebhookTimestampHeader is the header of the Unix timestamp in seconds at which the request was signed.
+	// The timestamp is signed with the body so that receivers can reject replayed requests.
+	WebhookTimestampHeader = "X-Grafana-Alerting-Timestamp"
+)
+
 // WebhookNotifier is responsible for sending
 // alert notifications as webhooks.
 type WebhookNotifier struct {
@@ -33,6 +47,9 @@ type WebhookNotifier struct {
 
 	AuthorizationScheme      string
 	AuthorizationCredentials string
+
+	HMACSecret string
+	TLSConfig  *tls.Config
 }
 
 type WebhookConfig struct {
@@ -47,6 +64,10 @@ type WebhookConfig struct {
 	// HTTP Basic Authentication.
 	User     string
 	Password string
+	// HMACSecret is the secret with which the requests are signed, if it is set.
+	HMACSecret string
+	// TLSConfig is nil unless TLS is configured in the settings.
+	TLSConfig *tls.Config
 }
 
 func WebHookFactory(fc FactoryConfig) (NotificationChannel, error) {
@@ -75,6 +96,11 @@ func NewWebHookConfig(config *NotificationChannelConfig, decryptFunc GetDecrypte
 		return nil, errors.New("both HTTP Basic Authentication and Authorization Header are set, only 1 is permitted")
 	}
 
+	tlsCfg, err := newClientTLSConfig(config, decryptFunc)
+	if err != nil {
+		return nil, err
+	}
+
 	return &WebhookConfig{
 		NotificationChannelConfig: config,
 		URL:                       url,
@@ -82,6 +108,8 @@ func NewWebHookConfig(config *NotificationChannelConfig, decryptFunc GetDecrypte
 		Password:                  password,
 		AuthorizationScheme:       authorizationScheme,
 		AuthorizationCredentials:  authorizationCredentials,
+		HMACSecret:                decryptFunc(context.Background(), config.SecureSettings, "hmacSecret", config.Settings.Get("hmacSecret").MustString()),
+		TLSConfig:                 tlsCfg,
 		HTTPMethod:                config.Settings.Get("httpMethod").MustString("POST"),
 		MaxAlerts:                 config.Settings.Get("maxAlerts").MustInt(0),
 	}, nil
@@ -104,6 +132,8 @@ func NewWebHookNotifier(config *WebhookConfig, ns notifications.WebhookSender, t
 		Password:                 config.Password,
 		AuthorizationScheme:      config.AuthorizationScheme,
 		AuthorizationCredentials: config.AuthorizationCredentials,
+		HMACSecret:               config.HMACSecret,
+		TLSConfig:                config.TLSConfig,
 		HTTPMethod:               config.HTTPMethod,
 		MaxAlerts:                config.MaxAlerts,
 		log:                      log.New("alerting.notifier.webhook"),
@@ -139,20 +169,7 @@ func (wn *WebhookNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool
 	as, numTruncated := truncateAlerts(wn.MaxAlerts, as)
 	var tmplErr error
 	tmpl, data := TmplText(ctx, wn.tmpl, as, wn.log, &tmplErr)
-	msg := &webhookMessage{
-		Version:         "1",
-		ExtendedData:    data,
-		GroupKey:        groupKey.String(),
-		TruncatedAlerts: numTruncated,
-		OrgID:           wn.orgID,
-		Title:           tmpl(DefaultMessageTitleEmbed),
-		Message:         tmpl(`{{ template "default.message" . }}`),
-	}
-	if types.Alerts(as...).Status() == model.AlertFiring {
-		msg.State = string(models.AlertStateAlerting)
-	} else {
-		msg.State = string(models.AlertStateOK)
-	}
+	msg := newWebhookMessage(groupKey.String(), data, tmpl, as, wn.orgID, numTruncated)
 
 	if tmplErr != nil {
 		wn.log.Warn("failed to template webhook message", "err", tmplErr.Error())
@@ -167,6 +184,11 @@ func (wn *WebhookNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool
 	if wn.AuthorizationScheme != "" && wn.AuthorizationCredentials != "" {
 		headers["Authorization"] = fmt.Sprintf("%s %s", wn.AuthorizationScheme, wn.AuthorizationCredentials)
 	}
+	if wn.HMACSecret != "" {
+		timestamp := strconv.FormatInt(timeNow().Unix(), 10)
+		headers[WebhookTimestampHeader] = timestamp
+		headers[WebhookSignatureHeader] = "sha256=" + signWebhook(wn.HMACSecret, timestamp, body)
+	}
 
 	cmd := &models.SendWebhookSync{
 		Url:        wn.URL,
@@ -175,6 +197,7 @@ func (wn *WebhookNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool
 		Body:       string(body),
 		HttpMethod: wn.HTTPMethod,
 		HttpHeader: headers,
+		TLSConfig:  wn.TLSConfig,
 	}
 
 	if err := wn.ns.SendWebhookSync(ctx, cmd); err != nil {
@@ -184,6 +207,36 @@ func (wn *WebhookNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool
 	return true, nil
 }
 
+// newWebhookMessage returns the message of the alerts sent by the webhook contact point.
+// The contact points that publish alerts to message brokers publish the same message.
+func newWebhookMessage(groupKey string, data *ExtendedData, tmpl func(string) string, as []*types.Alert, orgID int64, numTruncated int) *webhookMessage {
+	msg := &webhookMessage{
+		Version:         "1",
+		ExtendedData:    data,
+		GroupKey:        groupKey,
+		TruncatedAlerts: numTruncated,
+		OrgID:           orgID,
+		Title:           tmpl(DefaultMessageTitleEmbed),
+		Message:         tmpl(`{{ template "default.message" . }}`),
+	}
+	if types.Alerts(as...).Status() == model.AlertFiring {
+		msg.State = string(models.AlertStateAlerting)
+	} else {
+		msg.State = string(models.AlertStateOK)
+	}
+	return msg
+}
+
+// signWebhook returns the hex encoded HMAC-SHA256 signature of the timestamp and the body of a request,
+// separated by a dot.
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func truncateAlerts(maxAlerts int, alerts []*types.Alert) ([]*types.Alert, int) {
 	if maxAlerts > 0 && len(alerts) > maxAlerts {
 		return alerts[:maxAlerts], len(alerts) - maxAlerts
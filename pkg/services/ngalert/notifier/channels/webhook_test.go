@@ -2,11 +2,16 @@ package channels
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
 
@@ -304,3 +309,52 @@ func TestWebhookNotifier(t *testing.T) {
 		})
 	}
 }
+
+func TestWebhookNotifier_SignedRequests(t *testing.T) {
+	tmpl := templateForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+	certs := newTestCertificates(t)
+	defer mockTimeNow(time.Unix(1640995200, 0))()
+
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+	encryptedSecret, err := secretsService.Encrypt(context.Background(), []byte("signing-secret"), secrets.WithoutScope())
+	require.NoError(t, err)
+	encryptedKey, err := secretsService.Encrypt(context.Background(), []byte(certs.clientKeyPEM), secrets.WithoutScope())
+	require.NoError(t, err)
+
+	cfg, err := NewWebHookConfig(&NotificationChannelConfig{
+		Name: "webhook_testing",
+		Type: "webhook",
+		Settings: simplejson.NewFromAny(map[string]interface{}{
+			"url":           "https://localhost/test",
+			"tlsCACert":     certs.caPEM,
+			"tlsClientCert": certs.clientCertPEM,
+		}),
+		SecureSettings: map[string][]byte{
+			"hmacSecret":   encryptedSecret,
+			"tlsClientKey": encryptedKey,
+		},
+	}, secretsService.GetDecryptedValue)
+	require.NoError(t, err)
+	require.Equal(t, "signing-secret", cfg.HMACSecret)
+	require.NotNil(t, cfg.TLSConfig)
+
+	webhookSender := mockNotificationService()
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+	_, err = NewWebHookNotifier(cfg, webhookSender, tmpl).Notify(ctx, &types.Alert{
+		Alert: model.Alert{Labels: model.LabelSet{"alertname": "alert1"}},
+	})
+	require.NoError(t, err)
+
+	mac := hmac.New(sha256.New, []byte("signing-secret"))
+	mac.Write([]byte("1640995200." + webhookSender.Webhook.Body))
+	require.Equal(t, map[string]string{
+		WebhookTimestampHeader: "1640995200",
+		WebhookSignatureHeader: "sha256=" + hex.EncodeToString(mac.Sum(nil)),
+	}, webhookSender.Webhook.HttpHeader)
+	require.Same(t, cfg.TLSConfig, webhookSender.Webhook.TLSConfig)
+	require.Len(t, webhookSender.Webhook.TLSConfig.Certificates, 1)
+}
@@ -0,0 +1,212 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+// notificationLogSaveTimeout is how long an entry of the notification log can take to be saved.
+// The entries are saved in the background, after the context of the notification is done.
+const notificationLogSaveTimeout = 10 * time.Second
+
+var (
+	// ErrNotificationLogDisabled is returned when the notification log is disabled in the settings.
+	ErrNotificationLogDisabled = errors.New("the notification log is disabled")
+	// ErrNotificationNotFailed is returned when a notification that did not fail is replayed.
+	ErrNotificationNotFailed = errors.New("only failed notifications can be replayed")
+	// ErrIntegrationNotFound is returned when the integration of a notification no longer exists in the configuration.
+	ErrIntegrationNotFound = errors.New("the integration of the notification no longer exists")
+)
+
+// loggingNotifier logs every attempt of an integration to deliver a notification in the notification log.
+type loggingNotifier struct {
+	am       *Alertmanager
+	notifier notify.Notifier
+	cfg      *apimodels.PostableGrafanaReceiver
+}
+
+func (n *loggingNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	receiver, _ := notify.ReceiverName(ctx)
+	entry, retry, err := n.am.notifyAndLog(ctx, n.notifier, receiver, n.cfg, 0, as...)
+	// the delivery of the notifications does not wait for the database.
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notificationLogSaveTimeout)
+		defer cancel()
+		n.am.saveNotificationLogEntry(ctx, entry)
+	}()
+	return retry, err
+}
+
+// notifyAndLog sends the notification with the notifier and returns the entry of the attempt for the
+// notification log, which the caller saves.
+func (am *Alertmanager) notifyAndLog(ctx context.Context, n notify.Notifier, receiver string, cfg *apimodels.PostableGrafanaReceiver, replayOf int64, as ...*types.Alert) (*ngmodels.NotificationLogEntry, bool, error) {
+	sent := &notifications.SentWebhook{}
+	start := time.Now()
+	retry, err := n.Notify(notifications.WithSentWebhook(ctx, sent), as...)
+
+	groupKey, _ := notify.GroupKey(ctx)
+	groupLabels, _ := notify.GroupLabels(ctx)
+	entry := &ngmodels.NotificationLogEntry{
+		OrgID:           am.orgID,
+		Receiver:        receiver,
+		IntegrationType: cfg.Type,
+		IntegrationName: cfg.Name,
+		IntegrationUID:  cfg.UID,
+		GroupKey:        groupKey,
+		GroupLabels:     groupLabels,
+		Status:          ngmodels.NotificationStatusSuccess,
+		StatusCode:      sent.StatusCode,
+		Duration:        time.Since(start),
+		Payload:         truncatePayload(am.redactSecrets(ctx, cfg, sent.Body), am.Settings.UnifiedAlerting.NotificationLog.MaxPayloadSize),
+		Alerts:          as,
+		ReplayOf:        replayOf,
+		SentAt:          start,
+	}
+	if err != nil {
+		entry.Status = ngmodels.NotificationStatusFailed
+		entry.Error = err.Error()
+	}
+	return entry, retry, err
+}
+
+// saveNotificationLogEntry saves the entry in the notification log. Errors are logged, as the notification
+// was already sent.
+func (am *Alertmanager) saveNotificationLogEntry(ctx context.Context, entry *ngmodels.NotificationLogEntry) {
+	if err := am.notificationLogStore.SaveNotificationLogEntry(ctx, entry); err != nil {
+		am.logger.Error("failed to save notification log entry", "receiver", entry.Receiver, "integration", entry.IntegrationName, "err", err)
+	}
+}
+
+// ReplayNotification sends a failed notification of the notification log again through the same integration,
+// with its current configuration. It returns the entry of the new attempt.
+func (am *Alertmanager) ReplayNotification(ctx context.Context, id int64) (*ngmodels.NotificationLogEntry, error) {
+	if am.notificationLogStore == nil {
+		return nil, ErrNotificationLogDisabled
+	}
+	query := &ngmodels.GetNotificationLogEntryQuery{OrgID: am.orgID, ID: id}
+	if err := am.notificationLogStore.GetNotificationLogEntry(ctx, query); err != nil {
+		return nil, err
+	}
+	entry := query.Result
+	if entry.Status != ngmodels.NotificationStatusFailed {
+		return nil, ErrNotificationNotFailed
+	}
+
+	cfg := am.findIntegration(entry.Receiver, entry.IntegrationUID)
+	if cfg == nil {
+		return nil, fmt.Errorf("%w: %s of contact point %s", ErrIntegrationNotFound, entry.IntegrationName, entry.Receiver)
+	}
+	tmpl, err := am.getTemplate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	n, err := am.buildReceiverIntegration(cfg, tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = notify.WithGroupKey(ctx, entry.GroupKey)
+	ctx = notify.WithGroupLabels(ctx, entry.GroupLabels)
+	ctx = notify.WithReceiverName(ctx, entry.Receiver)
+	replay, _, _ := am.notifyAndLog(ctx, n, entry.Receiver, cfg, entry.ID, entry.Alerts...)
+	// the replay is saved before it is returned, so that it has an ID.
+	am.saveNotificationLogEntry(ctx, replay)
+	return replay, nil
+}
+
+// findIntegration returns the configuration of the integration with the given UID of a receiver of the
+// current configuration, or nil if there is none.
+func (am *Alertmanager) findIntegration(receiver, uid string) *apimodels.PostableGrafanaReceiver {
+	am.reloadConfigMtx.RLock()
+	defer am.reloadConfigMtx.RUnlock()
+	if !am.ready() {
+		return nil
+	}
+	for _, r := range am.config.AlertmanagerConfig.Receivers {
+		if r.Name != receiver {
+			continue
+		}
+		for _, cfg := range r.GrafanaManagedReceivers {
+			if cfg.UID == uid {
+				return cfg
+			}
+		}
+	}
+	return nil
+}
+
+// redactedSecret replaces the secrets of an integration in the payloads of the notification log.
+const redactedSecret = "[REDACTED]"
+
+// redactSecrets returns the payload without the values of the secure settings of the integration, such as
+// the API tokens that some integrations send in the request body. Settings that are secure but were saved
+// in plain text by older versions are redacted as well.
+func (am *Alertmanager) redactSecrets(ctx context.Context, cfg *apimodels.PostableGrafanaReceiver, payload []byte) []byte {
+	if len(payload) == 0 {
+		return payload
+	}
+
+	keys := map[string]struct{}{}
+	for k := range cfg.SecureSettings {
+		keys[k] = struct{}{}
+	}
+	for _, n := range GetAvailableNotifiers() {
+		if n.Type != cfg.Type {
+			continue
+		}
+		for _, o := range n.Options {
+			if o.Secure {
+				keys[o.PropertyName] = struct{}{}
+			}
+		}
+	}
+
+	secureSettings := make(map[string][]byte, len(cfg.SecureSettings))
+	for k, v := range cfg.SecureSettings {
+		if d, err := base64.StdEncoding.DecodeString(v); err == nil {
+			secureSettings[k] = d
+		}
+	}
+
+	redacted := string(payload)
+	for k := range keys {
+		var fallback string
+		if cfg.Settings != nil {
+			fallback = cfg.Settings.Get(k).MustString()
+		}
+		secret := am.decryptFn(ctx, secureSettings, k, fallback)
+		if secret == "" {
+			continue
+		}
+		// the secret can be escaped in JSON and form encoded bodies
+		forms := []string{secret, url.QueryEscape(secret)}
+		if b, err := json.Marshal(secret); err == nil {
+			forms = append(forms, string(b[1:len(b)-1]))
+		}
+		for _, form := range forms {
+			redacted = strings.ReplaceAll(redacted, form, redactedSecret)
+		}
+	}
+	return []byte(redacted)
+}
+
+// truncatePayload returns at most the first size bytes of the payload, without splitting a UTF-8 character.
+func truncatePayload(payload []byte, size int) string {
+	if len(payload) <= size {
+		return string(payload)
+	}
+	return strings.ToValidUTF8(string(payload[:size]), "")
+}
@@ -0,0 +1,166 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+const notificationLogTestConfig = `{
+	"alertmanager_config": {
+		"route": {"receiver": "ops"},
+		"receivers": [{
+			"name": "ops",
+			"grafana_managed_receiver_configs": [{
+				"uid": "webhook-uid",
+				"name": "ops-webhook",
+				"type": "webhook",
+				"settings": {"url": "http://localhost/hook"}
+			}]
+		}]
+	}
+}`
+
+func TestNotificationLog(t *testing.T) {
+	am := setupAMTest(t)
+	logStore := &store.FakeNotificationLogStore{}
+	am.notificationLogStore = logStore
+	am.Settings.UnifiedAlerting.NotificationLog.MaxPayloadSize = 16
+
+	statusCode := 503
+	am.NotificationService = &notifications.NotificationServiceMock{
+		WebhookHandler: func(ctx context.Context, cmd *models.SendWebhookSync) error {
+			resp := notifications.SentWebhookFromContext(ctx)
+			resp.Body = []byte(cmd.Body)
+			resp.StatusCode = statusCode
+			if statusCode != 200 {
+				return errors.New("webhook response status 503 Service Unavailable")
+			}
+			return nil
+		},
+	}
+
+	cfg, err := Load([]byte(notificationLogTestConfig))
+	require.NoError(t, err)
+	require.NoError(t, am.applyConfig(cfg, nil))
+
+	tmpl, err := am.getTemplate()
+	require.NoError(t, err)
+	integrations, err := am.buildReceiverIntegrations(cfg.AlertmanagerConfig.Receivers[0], tmpl)
+	require.NoError(t, err)
+	require.Len(t, integrations, 1)
+
+	alert := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "HighLatency"},
+		StartsAt: time.Now().Add(-time.Minute),
+	}}
+	ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"HighLatency\"}")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "HighLatency"})
+	ctx = notify.WithReceiverName(ctx, "ops")
+
+	_, err = integrations[0].Notify(ctx, alert)
+	require.Error(t, err)
+
+	// the entry is saved in the background
+	query := &ngmodels.GetNotificationLogQuery{OrgID: 1}
+	require.Eventually(t, func() bool {
+		require.NoError(t, logStore.GetNotificationLog(context.Background(), query))
+		return len(query.Result) == 1
+	}, time.Second, 10*time.Millisecond)
+	failed := query.Result[0]
+	require.Equal(t, int64(1), failed.OrgID)
+	require.Equal(t, "ops", failed.Receiver)
+	require.Equal(t, "webhook", failed.IntegrationType)
+	require.Equal(t, "ops-webhook", failed.IntegrationName)
+	require.Equal(t, "webhook-uid", failed.IntegrationUID)
+	require.Equal(t, "{}:{alertname=\"HighLatency\"}", failed.GroupKey)
+	require.Equal(t, model.LabelSet{"alertname": "HighLatency"}, failed.GroupLabels)
+	require.Equal(t, ngmodels.NotificationStatusFailed, failed.Status)
+	require.Equal(t, 503, failed.StatusCode)
+	require.Equal(t, "webhook response status 503 Service Unavailable", failed.Error)
+	require.Len(t, failed.Payload, 16)
+	require.Len(t, failed.Alerts, 1)
+
+	t.Run("replays a failed notification", func(t *testing.T) {
+		statusCode = 200
+		replay, err := am.ReplayNotification(context.Background(), failed.ID)
+		require.NoError(t, err)
+		require.Equal(t, ngmodels.NotificationStatusSuccess, replay.Status)
+		require.Equal(t, 200, replay.StatusCode)
+		require.Equal(t, failed.ID, replay.ReplayOf)
+		require.Equal(t, failed.GroupKey, replay.GroupKey)
+		require.NoError(t, logStore.GetNotificationLog(context.Background(), query))
+		require.Len(t, query.Result, 2)
+
+		_, err = am.ReplayNotification(context.Background(), replay.ID)
+		require.ErrorIs(t, err, ErrNotificationNotFailed)
+	})
+
+	t.Run("returns an error for unknown notifications", func(t *testing.T) {
+		_, err := am.ReplayNotification(context.Background(), 42)
+		require.ErrorIs(t, err, store.ErrNotificationLogEntryNotFound)
+	})
+
+	t.Run("returns an error if the integration no longer exists", func(t *testing.T) {
+		cfg, err := Load([]byte(`{"alertmanager_config": {"route": {"receiver": "ops"}, "receivers": [{"name": "ops"}]}}`))
+		require.NoError(t, err)
+		require.NoError(t, am.applyConfig(cfg, nil))
+
+		_, err = am.ReplayNotification(context.Background(), failed.ID)
+		require.ErrorIs(t, err, ErrIntegrationNotFound)
+	})
+
+	t.Run("returns an error if the notification log is disabled", func(t *testing.T) {
+		am.notificationLogStore = nil
+		_, err := am.ReplayNotification(context.Background(), failed.ID)
+		require.ErrorIs(t, err, ErrNotificationLogDisabled)
+	})
+}
+
+func TestTruncatePayload(t *testing.T) {
+	require.Equal(t, "", truncatePayload([]byte("payload"), 0))
+	require.Equal(t, "pay", truncatePayload([]byte("payload"), 3))
+	require.Equal(t, "payload", truncatePayload([]byte("payload"), 100))
+	// the last character is not split.
+	require.Equal(t, "caf", truncatePayload([]byte("café"), 4))
+}
+
+func TestRedactSecrets(t *testing.T) {
+	am := setupAMTest(t)
+	am.decryptFn = func(_ context.Context, sjd map[string][]byte, key string, fallback string) string {
+		if v, ok := sjd[key]; ok {
+			return string(v)
+		}
+		return fallback
+	}
+
+	cfg := &apimodels.PostableGrafanaReceiver{
+		Type:           "pushover",
+		Settings:       simplejson.NewFromAny(map[string]interface{}{"userKey": "user&key"}),
+		SecureSettings: map[string]string{"apiToken": base64.StdEncoding.EncodeToString([]byte("api-token"))},
+	}
+	payload := am.redactSecrets(context.Background(), cfg, []byte("user=user%26key&token=api-token&message=api"))
+	require.Equal(t, "user=[REDACTED]&token=[REDACTED]&message=api", string(payload))
+
+	cfg = &apimodels.PostableGrafanaReceiver{
+		Type:           "pagerduty",
+		Settings:       simplejson.New(),
+		SecureSettings: map[string]string{"integrationKey": base64.StdEncoding.EncodeToString([]byte(`routing"key`))},
+	}
+	payload = am.redactSecrets(context.Background(), cfg, []byte(`{"routing_key":"routing\"key","summary":"firing"}`))
+	require.Equal(t, `{"routing_key":"[REDACTED]","summary":"firing"}`, string(payload))
+}
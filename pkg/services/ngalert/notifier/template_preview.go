@@ -0,0 +1,208 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
+)
+
+// ErrReceiverNotFound is returned when the contact point of a preview does not exist in the configuration.
+var ErrReceiverNotFound = errors.New("the contact point does not exist")
+
+// InvalidTemplateError is returned when the template definitions of a preview cannot be parsed.
+type InvalidTemplateError struct {
+	Err error
+}
+
+func (e InvalidTemplateError) Error() string {
+	return fmt.Sprintf("the template is invalid: %s", e.Err)
+}
+
+func (e InvalidTemplateError) Unwrap() error { return e.Err }
+
+// PreviewTemplates renders the notifications of the alerts for the integrations of the contact point of the
+// request, or for every integration type if it has none. The alerts are in a single notification whose group
+// labels are the labels common to all the alerts. Nothing is sent.
+func (am *Alertmanager) PreviewTemplates(ctx context.Context, c apimodels.TemplatePreviewRequest, postableAlerts apimodels.PostableAlerts) (*apimodels.TemplatePreviewResult, error) {
+	var integrations []*apimodels.PostableGrafanaReceiver
+	if c.Receiver != "" {
+		integrations = am.findReceiverIntegrations(c.Receiver)
+		if integrations == nil {
+			return nil, fmt.Errorf("%w: %s", ErrReceiverNotFound, c.Receiver)
+		}
+	} else {
+		for _, t := range previewIntegrationTypes() {
+			integrations = append(integrations, &apimodels.PostableGrafanaReceiver{Type: t})
+		}
+	}
+
+	tmpl, err := am.previewTemplate(c.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	alerts := make([]*types.Alert, 0, len(postableAlerts.PostableAlerts))
+	for _, a := range postableAlerts.PostableAlerts {
+		alerts = append(alerts, newAlert(a, now))
+	}
+	groupLabels := commonLabels(alerts)
+	ctx = notify.WithGroupKey(ctx, "{}:"+groupLabels.String())
+	ctx = notify.WithGroupLabels(ctx, groupLabels)
+	ctx = notify.WithReceiverName(ctx, c.Receiver)
+
+	result := &apimodels.TemplatePreviewResult{
+		Alerts:   len(alerts),
+		Previews: make([]apimodels.TemplatePreview, 0, len(integrations)),
+	}
+	for _, cfg := range integrations {
+		if len(c.Integrations) > 0 && !containsFold(c.Integrations, cfg.Type) {
+			continue
+		}
+		preview := apimodels.TemplatePreview{
+			Receiver:        c.Receiver,
+			IntegrationName: cfg.Name,
+			IntegrationUID:  cfg.UID,
+			IntegrationType: cfg.Type,
+		}
+		fields, ok := previewTemplateFields(cfg.Type)
+		if !ok {
+			preview.Error = fmt.Sprintf("the notifications of %s integrations cannot be previewed", cfg.Type)
+			result.Previews = append(result.Previews, preview)
+			continue
+		}
+		preview.Title, preview.Body, err = channels.PreviewTemplates(ctx, cfg.Type, fields, cfg.Settings, tmpl, alerts, am.logger)
+		if err != nil {
+			preview.Error = err.Error()
+		}
+		result.Previews = append(result.Previews, preview)
+	}
+	return result, nil
+}
+
+// previewTitleSettings and previewBodySettings are the names of the options of the integration definitions
+// that are the templates of the title and the body of the notifications.
+var (
+	previewTitleSettings = []string{"title", "summary"}
+	previewBodySettings  = []string{"message", "text"}
+)
+
+// previewIntegrationTypes returns the integration types whose notifications can be previewed, sorted by name.
+func previewIntegrationTypes() []string {
+	var types []string
+	for _, n := range GetAvailableNotifiers() {
+		if _, ok := previewTemplateFields(n.Type); ok {
+			types = append(types, strings.ToLower(n.Type))
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// previewTemplateFields returns the options with the templates of the title and the body of the notifications
+// of the integration type, from its definition in GetAvailableNotifiers. It returns false if the integration
+// type does not exist or forwards the alerts without a notification text.
+func previewTemplateFields(integrationType string) (channels.TemplateFields, bool) {
+	if strings.EqualFold(integrationType, "prometheus-alertmanager") {
+		return channels.TemplateFields{}, false
+	}
+	for _, n := range GetAvailableNotifiers() {
+		if !strings.EqualFold(n.Type, integrationType) {
+			continue
+		}
+		var fields channels.TemplateFields
+		for _, o := range n.Options {
+			if fields.Title == "" && containsFold(previewTitleSettings, o.PropertyName) {
+				fields.Title = o.PropertyName
+			}
+			if fields.Body == "" && containsFold(previewBodySettings, o.PropertyName) {
+				fields.Body = o.PropertyName
+			}
+		}
+		return fields, true
+	}
+	return channels.TemplateFields{}, false
+}
+
+// previewTemplate returns the templates of the configuration, with the definitions of the preview if any.
+func (am *Alertmanager) previewTemplate(definitions string) (*template.Template, error) {
+	if definitions == "" {
+		return am.getTemplate()
+	}
+
+	f, err := os.CreateTemp("", "template-preview-*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the template file of the preview: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			am.logger.Warn("failed to remove the template file of the preview", "file", f.Name(), "err", err)
+		}
+	}()
+	if _, err := f.WriteString(definitions); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to write the template file of the preview: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write the template file of the preview: %w", err)
+	}
+
+	tmpl, err := am.getTemplate(f.Name())
+	if err != nil {
+		return nil, InvalidTemplateError{Err: err}
+	}
+	return tmpl, nil
+}
+
+// findReceiverIntegrations returns the integrations of a receiver of the current configuration, or nil if
+// there is no receiver with this name.
+func (am *Alertmanager) findReceiverIntegrations(receiver string) []*apimodels.PostableGrafanaReceiver {
+	am.reloadConfigMtx.RLock()
+	defer am.reloadConfigMtx.RUnlock()
+	if !am.ready() {
+		return nil
+	}
+	for _, r := range am.config.AlertmanagerConfig.Receivers {
+		if r.Name == receiver {
+			return append([]*apimodels.PostableGrafanaReceiver{}, r.GrafanaManagedReceivers...)
+		}
+	}
+	return nil
+}
+
+// commonLabels returns the labels that all the alerts have with the same value.
+func commonLabels(alerts []*types.Alert) model.LabelSet {
+	if len(alerts) == 0 {
+		return model.LabelSet{}
+	}
+	common := alerts[0].Labels.Clone()
+	for _, a := range alerts[1:] {
+		for name, value := range common {
+			if a.Labels[name] != value {
+				delete(common, name)
+			}
+		}
+	}
+	return common
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
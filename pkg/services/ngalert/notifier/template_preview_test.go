@@ -0,0 +1,130 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
+)
+
+const templatePreviewTestConfig = `{
+	"template_files": {
+		"ops.tmpl": "{{ define \"ops.title\" }}Saved {{ .CommonLabels.alertname }}{{ end }}"
+	},
+	"alertmanager_config": {
+		"route": {"receiver": "ops"},
+		"templates": ["ops.tmpl"],
+		"receivers": [{
+			"name": "ops",
+			"grafana_managed_receiver_configs": [{
+				"uid": "slack-uid",
+				"name": "ops-slack",
+				"type": "slack",
+				"settings": {"recipient": "#ops", "token": "token", "title": "{{ template \"ops.title\" . }}", "text": "{{ len .Alerts }} alerts"}
+			}, {
+				"uid": "email-uid",
+				"name": "ops-email",
+				"type": "email",
+				"settings": {"addresses": "ops@example.com"}
+			}]
+		}]
+	}
+}`
+
+func TestPreviewTemplates(t *testing.T) {
+	am := setupAMTest(t)
+	cfg, err := Load([]byte(templatePreviewTestConfig))
+	require.NoError(t, err)
+	require.NoError(t, am.applyConfig(cfg, nil))
+
+	alerts := apimodels.PostableAlerts{PostableAlerts: []amv2.PostableAlert{
+		{Alert: amv2.Alert{Labels: amv2.LabelSet{"alertname": "HighLatency", "instance": "a"}}},
+		{Alert: amv2.Alert{Labels: amv2.LabelSet{"alertname": "HighLatency", "instance": "b"}}},
+	}}
+
+	t.Run("previews the integrations of the contact point", func(t *testing.T) {
+		result, err := am.PreviewTemplates(context.Background(), apimodels.TemplatePreviewRequest{Receiver: "ops"}, alerts)
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Alerts)
+		// the email has no message, so the body is the default message.
+		emailBody := "**Firing**\n\nValue: [no value]\nLabels:\n - alertname = HighLatency\n - instance = a\nAnnotations:\n\n" +
+			"Value: [no value]\nLabels:\n - alertname = HighLatency\n - instance = b\nAnnotations:\n"
+		require.Equal(t, []apimodels.TemplatePreview{
+			{Receiver: "ops", IntegrationName: "ops-slack", IntegrationUID: "slack-uid", IntegrationType: "slack", Title: "Saved HighLatency", Body: "2 alerts"},
+			{Receiver: "ops", IntegrationName: "ops-email", IntegrationUID: "email-uid", IntegrationType: "email", Title: "[FIRING:2] HighLatency ", Body: emailBody},
+		}, result.Previews)
+	})
+
+	t.Run("overrides the templates of the configuration", func(t *testing.T) {
+		result, err := am.PreviewTemplates(context.Background(), apimodels.TemplatePreviewRequest{
+			Receiver:     "ops",
+			Template:     `{{ define "ops.title" }}Draft {{ .CommonLabels.alertname }}{{ end }}`,
+			Integrations: []string{"Slack"},
+		}, alerts)
+		require.NoError(t, err)
+		require.Len(t, result.Previews, 1)
+		require.Equal(t, "Draft HighLatency", result.Previews[0].Title)
+	})
+
+	t.Run("previews every integration type without a contact point", func(t *testing.T) {
+		result, err := am.PreviewTemplates(context.Background(), apimodels.TemplatePreviewRequest{}, alerts)
+		require.NoError(t, err)
+		require.Len(t, result.Previews, len(previewIntegrationTypes()))
+		for _, p := range result.Previews {
+			require.Empty(t, p.Error, p.IntegrationType)
+			require.Equal(t, "[FIRING:2] HighLatency ", p.Title, p.IntegrationType)
+			require.NotEmpty(t, p.Body, p.IntegrationType)
+		}
+	})
+
+	t.Run("derives the previewed integration types from their definitions", func(t *testing.T) {
+		types := previewIntegrationTypes()
+		require.Contains(t, types, "line")
+		require.NotContains(t, types, "prometheus-alertmanager")
+		require.Len(t, types, len(GetAvailableNotifiers())-1)
+
+		fields, ok := previewTemplateFields("pagerduty")
+		require.True(t, ok)
+		require.Equal(t, channels.TemplateFields{Title: "summary"}, fields)
+		fields, ok = previewTemplateFields("slack")
+		require.True(t, ok)
+		require.Equal(t, channels.TemplateFields{Title: "title", Body: "text"}, fields)
+		_, ok = previewTemplateFields("prometheus-alertmanager")
+		require.False(t, ok)
+	})
+
+	t.Run("reports the errors of the templates of each integration", func(t *testing.T) {
+		result, err := am.PreviewTemplates(context.Background(), apimodels.TemplatePreviewRequest{
+			Receiver: "ops",
+			Template: `{{ define "ops.title" }}{{ template "missing" . }}{{ end }}`,
+		}, alerts)
+		require.NoError(t, err)
+		require.Contains(t, result.Previews[0].Error, `template "missing" not defined`)
+		require.Empty(t, result.Previews[1].Error)
+	})
+
+	t.Run("returns an error for invalid templates", func(t *testing.T) {
+		_, err := am.PreviewTemplates(context.Background(), apimodels.TemplatePreviewRequest{Template: `{{ define "ops.title" }}`}, alerts)
+		require.ErrorAs(t, err, &InvalidTemplateError{})
+	})
+
+	t.Run("returns an error for unknown contact points", func(t *testing.T) {
+		_, err := am.PreviewTemplates(context.Background(), apimodels.TemplatePreviewRequest{Receiver: "dev"}, alerts)
+		require.ErrorIs(t, err, ErrReceiverNotFound)
+	})
+}
+
+func TestCommonLabels(t *testing.T) {
+	require.Equal(t, model.LabelSet{}, commonLabels(nil))
+	require.Equal(t, model.LabelSet{"alertname": "HighLatency"}, commonLabels([]*types.Alert{
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "HighLatency", "instance": "a"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "HighLatency", "instance": "b"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "HighLatency"}}},
+	}))
+}
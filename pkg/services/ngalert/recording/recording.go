@@ -0,0 +1,108 @@
+package recording
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Writer writes the samples computed by recording rules to a target.
+type Writer interface {
+	Write(ctx context.Context, samples []models.RecordedSample) error
+}
+
+// NewWriter returns the writer of the configured target, or nil if recording rules are disabled.
+func NewWriter(cfg setting.UnifiedAlertingRecordingRulesSettings, st store.RecordedSampleStore) Writer {
+	if !cfg.Enabled {
+		return nil
+	}
+	switch cfg.Target {
+	case setting.RecordingRulesTargetRemoteWrite:
+		return NewRemoteWriter(cfg.RemoteWriteURL, cfg.RemoteWriteBasicAuthUsername, cfg.RemoteWriteBasicAuthPassword, cfg.RemoteWriteTimeout)
+	default:
+		return NewStoreWriter(st)
+	}
+}
+
+// StoreWriter writes samples to the internal store.
+type StoreWriter struct {
+	store store.RecordedSampleStore
+}
+
+func NewStoreWriter(st store.RecordedSampleStore) *StoreWriter {
+	return &StoreWriter{store: st}
+}
+
+func (w *StoreWriter) Write(ctx context.Context, samples []models.RecordedSample) error {
+	return w.store.SaveRecordedSamples(ctx, samples)
+}
+
+// SamplesFromFrames converts the frames returned by the recorded query or expression of the rule
+// to the samples of the rule's metric at the given time. Each frame must be reduced to a single
+// numeric value, like the result of the condition of an alert rule. The labels of the rule are
+// added to the labels of each series and override them.
+func SamplesFromFrames(rule *models.AlertRule, ts time.Time, frames data.Frames) ([]models.RecordedSample, error) {
+	samples := make([]models.RecordedSample, 0, len(frames))
+	seen := make(map[string]struct{}, len(frames))
+	for _, f := range frames {
+		rowLen, err := f.RowLen()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the row length of the result of %s: %w", rule.Record.From, err)
+		}
+		if len(f.TypeIndices(data.FieldTypeTime, data.FieldTypeNullableTime)) > 0 {
+			return nil, fmt.Errorf("result of %s looks like time series data, only reduced data can be recorded", rule.Record.From)
+		}
+		if rowLen == 0 {
+			continue
+		}
+		if rowLen > 1 {
+			return nil, fmt.Errorf("unexpected row length of the result of %s: %d instead of 0 or 1", rule.Record.From, rowLen)
+		}
+		if len(f.Fields) != 1 {
+			return nil, fmt.Errorf("unexpected field length of the result of %s: %d instead of 1", rule.Record.From, len(f.Fields))
+		}
+		if !f.Fields[0].Type().Numeric() {
+			return nil, fmt.Errorf("invalid field type of the result of %s: %s", rule.Record.From, f.Fields[0].Type())
+		}
+		v, err := f.Fields[0].NullableFloatAt(0)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			continue
+		}
+
+		labels := make(models.InstanceLabels, len(f.Fields[0].Labels)+len(rule.Labels))
+		for k, v := range f.Fields[0].Labels {
+			labels[k] = v
+		}
+		for k, v := range rule.Labels {
+			labels[k] = v
+		}
+		key, err := labels.StringKey()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[key]; ok {
+			return nil, fmt.Errorf("result of %s cannot uniquely be identified by its labels: has duplicate series with labels %s", rule.Record.From, key)
+		}
+		seen[key] = struct{}{}
+
+		samples = append(samples, models.RecordedSample{
+			OrgID:        rule.OrgID,
+			RuleUID:      rule.UID,
+			NamespaceUID: rule.NamespaceUID,
+			Metric:       rule.Record.Metric,
+			Labels:       labels,
+			Value:        *v,
+			Timestamp:    ts,
+		})
+	}
+	return samples, nil
+}
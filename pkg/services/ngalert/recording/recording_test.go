@@ -0,0 +1,111 @@
+package recording
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSamplesFromFrames(t *testing.T) {
+	ts := time.Unix(1640995200, 0)
+	rule := models.AlertRuleGen(func(r *models.AlertRule) {
+		r.Labels = map[string]string{"team": "ops"}
+		r.Record = &models.Record{Metric: "job:requests:rate5m", From: "B"}
+	})()
+	value := func(labels data.Labels, v *float64) *data.Frame {
+		return data.NewFrame("", data.NewField("B", labels, []*float64{v}))
+	}
+	one, two := 1.0, 2.0
+
+	t.Run("converts reduced frames to samples with the labels of the rule", func(t *testing.T) {
+		samples, err := SamplesFromFrames(rule, ts, data.Frames{
+			value(data.Labels{"job": "api", "team": "dev"}, &one),
+			value(data.Labels{"job": "web"}, &two),
+			value(data.Labels{"job": "db"}, nil),
+			data.NewFrame(""),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []models.RecordedSample{
+			{
+				OrgID:        rule.OrgID,
+				RuleUID:      rule.UID,
+				NamespaceUID: rule.NamespaceUID,
+				Metric:       "job:requests:rate5m",
+				Labels:       models.InstanceLabels{"job": "api", "team": "ops"},
+				Value:        1,
+				Timestamp:    ts,
+			},
+			{
+				OrgID:        rule.OrgID,
+				RuleUID:      rule.UID,
+				NamespaceUID: rule.NamespaceUID,
+				Metric:       "job:requests:rate5m",
+				Labels:       models.InstanceLabels{"job": "web", "team": "ops"},
+				Value:        2,
+				Timestamp:    ts,
+			},
+		}, samples)
+	})
+
+	t.Run("fails if frames are not reduced", func(t *testing.T) {
+		_, err := SamplesFromFrames(rule, ts, data.Frames{
+			data.NewFrame("", data.NewField("time", nil, []time.Time{ts}), data.NewField("B", nil, []float64{1})),
+		})
+		require.Error(t, err)
+
+		_, err = SamplesFromFrames(rule, ts, data.Frames{
+			data.NewFrame("", data.NewField("B", nil, []float64{1, 2})),
+		})
+		require.Error(t, err)
+
+		_, err = SamplesFromFrames(rule, ts, data.Frames{
+			data.NewFrame("", data.NewField("B", nil, []string{"1"})),
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("fails if series are not unique", func(t *testing.T) {
+		_, err := SamplesFromFrames(rule, ts, data.Frames{
+			value(data.Labels{"job": "api", "team": "dev"}, &one),
+			value(data.Labels{"job": "api"}, &two),
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestNewWriter(t *testing.T) {
+	require.Nil(t, NewWriter(setting.UnifiedAlertingRecordingRulesSettings{}, nil))
+
+	w := NewWriter(setting.UnifiedAlertingRecordingRulesSettings{
+		Enabled: true,
+		Target:  setting.RecordingRulesTargetRemoteWrite,
+	}, nil)
+	require.IsType(t, &RemoteWriter{}, w)
+
+	st := &fakeRecordedSampleStore{}
+	w = NewWriter(setting.UnifiedAlertingRecordingRulesSettings{
+		Enabled: true,
+		Target:  setting.RecordingRulesTargetInternal,
+	}, st)
+	require.IsType(t, &StoreWriter{}, w)
+	samples := []models.RecordedSample{{OrgID: 1, Metric: "requests", Value: 1}}
+	require.NoError(t, w.Write(context.Background(), samples))
+	require.Equal(t, samples, st.saved)
+}
+
+type fakeRecordedSampleStore struct {
+	store.RecordedSampleStore
+	saved []models.RecordedSample
+}
+
+func (f *fakeRecordedSampleStore) SaveRecordedSamples(_ context.Context, samples []models.RecordedSample) error {
+	f.saved = append(f.saved, samples...)
+	return nil
+}
@@ -0,0 +1,86 @@
+package recording
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const metricNameLabel = "__name__"
+
+// RemoteWriter writes samples to a Prometheus remote write endpoint.
+type RemoteWriter struct {
+	url      string
+	user     string
+	password string
+	client   *http.Client
+}
+
+func NewRemoteWriter(url, user, password string, timeout time.Duration) *RemoteWriter {
+	return &RemoteWriter{
+		url:      url,
+		user:     user,
+		password: password,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (w *RemoteWriter) Write(ctx context.Context, samples []models.RecordedSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	b, err := serialize(samples)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("error constructing remote write request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if w.user != "" {
+		req.SetBasicAuth(w.user, w.password)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending remote write request: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected response code from remote write endpoint: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// serialize converts the samples to Prometheus remote write format.
+func serialize(samples []models.RecordedSample) ([]byte, error) {
+	frames := make([]*data.Frame, 0, len(samples))
+	for _, s := range samples {
+		frames = append(frames, data.NewFrame(s.Metric,
+			data.NewField("time", nil, []time.Time{s.Timestamp}),
+			data.NewField("", data.Labels(s.Labels), []float64{s.Value}),
+		))
+	}
+	series := remotewrite.TimeSeriesFromFrames(frames...)
+	// the conversion names the series "<frame name>_<field name>", the field is not named so that only the metric remains.
+	for i := range series {
+		for j, l := range series[i].Labels {
+			if l.Name == metricNameLabel {
+				series[i].Labels[j].Value = strings.TrimSuffix(l.Value, "_")
+			}
+		}
+	}
+	return remotewrite.TimeSeriesToBytes(series)
+}
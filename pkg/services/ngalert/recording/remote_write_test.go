@@ -0,0 +1,55 @@
+package recording
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRemoteWriter(t *testing.T) {
+	ts := time.Unix(1640995200, 0)
+	var received prompb.WriteRequest
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "user", user)
+		require.Equal(t, "password", password)
+		require.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		b, err = snappy.Decode(nil, b)
+		require.NoError(t, err)
+		require.NoError(t, proto.Unmarshal(b, &received))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	w := NewRemoteWriter(server.URL, "user", "password", time.Second)
+	require.NoError(t, w.Write(context.Background(), []models.RecordedSample{
+		{Metric: "job:requests:rate5m", Labels: models.InstanceLabels{"job": "api"}, Value: 1.5, Timestamp: ts},
+	}))
+
+	require.Len(t, received.Timeseries, 1)
+	labels := received.Timeseries[0].Labels
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	require.Equal(t, []prompb.Label{{Name: "__name__", Value: "job:requests:rate5m"}, {Name: "job", Value: "api"}}, labels)
+	require.Equal(t, []prompb.Sample{{Value: 1.5, Timestamp: ts.UnixNano() / int64(time.Millisecond)}}, received.Timeseries[0].Samples)
+
+	status = http.StatusBadRequest
+	require.Error(t, w.Write(context.Background(), []models.RecordedSample{
+		{Metric: "job:requests:rate5m", Value: 1, Timestamp: ts},
+	}))
+}
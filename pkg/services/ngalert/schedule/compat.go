@@ -127,13 +127,23 @@ func FromAlertStateToPostableAlerts(firingStates []*state.State, stateManager *s
 	return alerts
 }
 
+// StatesToPostableAlerts converts the states to alerts like FromAlertStateToPostableAlerts, but without
+// marking them as sent, e.g. to preview their notifications.
+func StatesToPostableAlerts(states []*state.State, appURL *url.URL) apimodels.PostableAlerts {
+	alerts := apimodels.PostableAlerts{PostableAlerts: make([]models.PostableAlert, 0, len(states))}
+	for _, alertState := range states {
+		alerts.PostableAlerts = append(alerts.PostableAlerts, *stateToPostableAlert(alertState, appURL))
+	}
+	return alerts
+}
+
 // FromAlertsStateToStoppedAlert converts firingStates that have evaluation state either eval.Alerting or eval.NoData or eval.Error to models.PostableAlert that are accepted by notifiers.
 // Returns a list of alert instances that have expiration time.Now
 func FromAlertsStateToStoppedAlert(firingStates []*state.State, appURL *url.URL, clock clock.Clock) apimodels.PostableAlerts {
 	alerts := apimodels.PostableAlerts{PostableAlerts: make([]models.PostableAlert, 0, len(firingStates))}
 	ts := clock.Now()
 	for _, alertState := range firingStates {
-		if alertState.State == eval.Normal || alertState.State == eval.Pending {
+		if alertState.State == eval.Normal || alertState.State == eval.Pending || alertState.State == eval.Inhibited {
 			continue
 		}
 		postableAlert := stateToPostableAlert(alertState, appURL)
@@ -0,0 +1,148 @@
+package schedule
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RuleEvaluationStats are the statistics of the evaluations of an alert rule by this scheduler.
+type RuleEvaluationStats struct {
+	OrgID        int64
+	UID          string
+	Title        string
+	NamespaceUID string
+	RuleGroup    string
+	// Evaluations is the number of evaluations of the rule.
+	Evaluations int64
+	// Failures is the number of evaluations that failed or returned an error result.
+	Failures       int64
+	LastEvaluation time.Time
+	LastDuration   time.Duration
+	TotalDuration  time.Duration
+	// QueryDurations are the durations of the queries and expressions of the last evaluation by RefID.
+	QueryDurations map[string]time.Duration
+	// ResultSeries is the number of series of the result of the last evaluation.
+	ResultSeries  int
+	LastError     string
+	LastErrorTime time.Time
+}
+
+// ruleStatsRegistry keeps the statistics of the evaluations of the alert rules, and exports them as
+// per-rule metrics if they are enabled. At most maxRules rules have metrics to bound their cardinality.
+type ruleStatsRegistry struct {
+	mtx            sync.Mutex
+	stats          map[models.AlertRuleKey]*RuleEvaluationStats
+	metrics        *metrics.Scheduler
+	metricsEnabled bool
+	maxRules       int
+	withMetrics    map[models.AlertRuleKey]struct{}
+}
+
+func newRuleStatsRegistry(m *metrics.Scheduler, metricsEnabled bool, maxRules int) *ruleStatsRegistry {
+	return &ruleStatsRegistry{
+		stats:          map[models.AlertRuleKey]*RuleEvaluationStats{},
+		metrics:        m,
+		metricsEnabled: metricsEnabled,
+		maxRules:       maxRules,
+		withMetrics:    map[models.AlertRuleKey]struct{}{},
+	}
+}
+
+// record adds an evaluation of the rule to its statistics.
+func (r *ruleStatsRegistry) record(rule *models.AlertRule, at time.Time, dur time.Duration, queryDurations map[string]time.Duration, series int, evalErr error) {
+	key := rule.GetKey()
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	s, ok := r.stats[key]
+	if !ok {
+		s = &RuleEvaluationStats{OrgID: key.OrgID, UID: key.UID}
+		r.stats[key] = s
+	}
+	previousQueries := s.QueryDurations
+	s.Title = rule.Title
+	s.NamespaceUID = rule.NamespaceUID
+	s.RuleGroup = rule.RuleGroup
+	s.Evaluations++
+	s.LastEvaluation = at
+	s.LastDuration = dur
+	s.TotalDuration += dur
+	s.QueryDurations = queryDurations
+	s.ResultSeries = series
+	if evalErr != nil {
+		s.Failures++
+		s.LastError = evalErr.Error()
+		s.LastErrorTime = at
+	}
+
+	if !r.metricsEnabled {
+		return
+	}
+	if _, ok := r.withMetrics[key]; !ok {
+		if len(r.withMetrics) >= r.maxRules {
+			return
+		}
+		r.withMetrics[key] = struct{}{}
+	}
+	orgID := fmt.Sprint(key.OrgID)
+	r.metrics.RuleEvalDuration.WithLabelValues(orgID, key.UID).Set(dur.Seconds())
+	r.metrics.RuleResultSeries.WithLabelValues(orgID, key.UID).Set(float64(series))
+	failed := 0.0
+	if evalErr != nil {
+		failed = 1
+	}
+	r.metrics.RuleEvalFailed.WithLabelValues(orgID, key.UID).Set(failed)
+	for refID := range previousQueries {
+		if _, ok := queryDurations[refID]; !ok {
+			r.metrics.RuleQueryDuration.DeleteLabelValues(orgID, key.UID, refID)
+		}
+	}
+	for refID, d := range queryDurations {
+		r.metrics.RuleQueryDuration.WithLabelValues(orgID, key.UID, refID).Set(d.Seconds())
+	}
+}
+
+// del removes the statistics and the metrics of the rule.
+func (r *ruleStatsRegistry) del(key models.AlertRuleKey) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	s, ok := r.stats[key]
+	if !ok {
+		return
+	}
+	delete(r.stats, key)
+	if _, ok := r.withMetrics[key]; !ok {
+		return
+	}
+	delete(r.withMetrics, key)
+	orgID := fmt.Sprint(key.OrgID)
+	r.metrics.RuleEvalDuration.DeleteLabelValues(orgID, key.UID)
+	r.metrics.RuleResultSeries.DeleteLabelValues(orgID, key.UID)
+	r.metrics.RuleEvalFailed.DeleteLabelValues(orgID, key.UID)
+	for refID := range s.QueryDurations {
+		r.metrics.RuleQueryDuration.DeleteLabelValues(orgID, key.UID, refID)
+	}
+}
+
+// list returns a copy of the statistics of the rules of the organization.
+func (r *ruleStatsRegistry) list(orgID int64) []RuleEvaluationStats {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	result := make([]RuleEvaluationStats, 0, len(r.stats))
+	for key, s := range r.stats {
+		if key.OrgID != orgID {
+			continue
+		}
+		c := *s
+		c.QueryDurations = make(map[string]time.Duration, len(s.QueryDurations))
+		for refID, d := range s.QueryDurations {
+			c.QueryDurations[refID] = d
+		}
+		result = append(result, c)
+	}
+	return result
+}
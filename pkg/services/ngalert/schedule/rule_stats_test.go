@@ -0,0 +1,78 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRuleStatsRegistry(t *testing.T) {
+	now := time.Unix(1640995200, 0)
+	rule1 := models.AlertRuleGen(func(r *models.AlertRule) { r.OrgID = 1 })()
+	rule2 := models.AlertRuleGen(func(r *models.AlertRule) { r.OrgID = 1 })()
+	otherOrgRule := models.AlertRuleGen(func(r *models.AlertRule) { r.OrgID = 2 })()
+
+	t.Run("keeps the statistics of the rules of each organization", func(t *testing.T) {
+		m := metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetSchedulerMetrics()
+		r := newRuleStatsRegistry(m, false, 10)
+		r.record(rule1, now, time.Second, map[string]time.Duration{"A": 800 * time.Millisecond}, 3, nil)
+		r.record(rule1, now.Add(time.Minute), 3*time.Second, map[string]time.Duration{"A": 2 * time.Second}, 2, errors.New("query failed"))
+		r.record(rule2, now, time.Second, nil, 1, nil)
+		r.record(otherOrgRule, now, time.Second, nil, 1, nil)
+
+		stats := r.list(1)
+		require.Len(t, stats, 2)
+		var s RuleEvaluationStats
+		for _, st := range stats {
+			if st.UID == rule1.UID {
+				s = st
+			}
+		}
+		require.Equal(t, rule1.Title, s.Title)
+		require.Equal(t, int64(2), s.Evaluations)
+		require.Equal(t, int64(1), s.Failures)
+		require.Equal(t, 3*time.Second, s.LastDuration)
+		require.Equal(t, 4*time.Second, s.TotalDuration)
+		require.Equal(t, map[string]time.Duration{"A": 2 * time.Second}, s.QueryDurations)
+		require.Equal(t, 2, s.ResultSeries)
+		require.Equal(t, "query failed", s.LastError)
+		require.Equal(t, now.Add(time.Minute), s.LastErrorTime)
+
+		// metrics are disabled.
+		require.Equal(t, 0, testutil.CollectAndCount(m.RuleEvalDuration))
+
+		r.del(rule1.GetKey())
+		require.Len(t, r.list(1), 1)
+	})
+
+	t.Run("exports the metrics of at most max rules", func(t *testing.T) {
+		m := metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetSchedulerMetrics()
+		r := newRuleStatsRegistry(m, true, 1)
+		r.record(rule1, now, time.Second, map[string]time.Duration{"A": time.Second, "B": time.Millisecond}, 3, nil)
+		r.record(rule2, now, time.Second, map[string]time.Duration{"A": time.Second}, 1, nil)
+
+		require.Equal(t, 1, testutil.CollectAndCount(m.RuleEvalDuration))
+		require.Equal(t, 2, testutil.CollectAndCount(m.RuleQueryDuration))
+		require.Equal(t, float64(3), testutil.ToFloat64(m.RuleResultSeries.WithLabelValues("1", rule1.UID)))
+		require.Len(t, r.list(1), 2)
+
+		// the series of queries that are not part of the rule anymore are removed.
+		r.record(rule1, now, time.Second, map[string]time.Duration{"A": time.Second}, 3, errors.New("failed"))
+		require.Equal(t, 1, testutil.CollectAndCount(m.RuleQueryDuration))
+		require.Equal(t, float64(1), testutil.ToFloat64(m.RuleEvalFailed.WithLabelValues("1", rule1.UID)))
+
+		// the rule without metrics gets them once the other rule is deleted.
+		r.del(rule1.GetKey())
+		require.Equal(t, 0, testutil.CollectAndCount(m.RuleEvalDuration))
+		require.Equal(t, 0, testutil.CollectAndCount(m.RuleQueryDuration))
+		r.record(rule2, now, 2*time.Second, nil, 1, nil)
+		require.Equal(t, float64(2), testutil.ToFloat64(m.RuleEvalDuration.WithLabelValues("1", rule2.UID)))
+	})
+}
@@ -16,9 +16,11 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/recording"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
 
 	"github.com/benbjohnson/clock"
 	"golang.org/x/sync/errgroup"
@@ -45,6 +47,9 @@ type ScheduleService interface {
 	UpdateAlertRule(key models.AlertRuleKey)
 	// DeleteAlertRule notifies scheduler that a rule has been changed
 	DeleteAlertRule(key models.AlertRuleKey)
+	// RuleEvaluationStats returns the statistics of the evaluations of the
+	// alert rules of the organization by this scheduler.
+	RuleEvaluationStats(orgID int64) []RuleEvaluationStats
 	// the following are used by tests only used for tests
 	evalApplied(models.AlertRuleKey, time.Time)
 	stopApplied(models.AlertRuleKey)
@@ -86,6 +91,17 @@ type schedule struct {
 
 	stateManager *state.Manager
 
+	// recordingWriter writes the samples of recording rules. It is nil if recording rules are disabled.
+	recordingWriter recording.Writer
+
+	// sharder distributes the evaluation of alert rules between the schedulers of a HA cluster. It is nil if every rule is evaluated by every scheduler.
+	sharder *EvaluationSharder
+	// ownedRules tracks which alert rules were evaluated by this scheduler in the last tick, to detect the rules that moved between schedulers.
+	ownedRules map[models.AlertRuleKey]bool
+
+	// ruleStats keeps the statistics of the evaluations of each alert rule.
+	ruleStats *ruleStatsRegistry
+
 	appURL *url.URL
 
 	multiOrgNotifier *notifier.MultiOrgAlertmanager
@@ -125,6 +141,9 @@ type SchedulerCfg struct {
 	AdminConfigPollInterval time.Duration
 	DisabledOrgs            map[int64]struct{}
 	MinRuleInterval         time.Duration
+	RecordingWriter         recording.Writer
+	Sharder                 *EvaluationSharder
+	RuleMetrics             setting.UnifiedAlertingRuleMetricsSettings
 }
 
 // NewScheduler returns a new schedule.
@@ -156,6 +175,10 @@ func NewScheduler(cfg SchedulerCfg, expressionService *expr.Service, appURL *url
 		adminConfigPollInterval: cfg.AdminConfigPollInterval,
 		disabledOrgs:            cfg.DisabledOrgs,
 		minRuleInterval:         cfg.MinRuleInterval,
+		recordingWriter:         cfg.RecordingWriter,
+		sharder:                 cfg.Sharder,
+		ownedRules:              map[models.AlertRuleKey]bool{},
+		ruleStats:               newRuleStatsRegistry(cfg.Metrics, cfg.RuleMetrics.Enabled, cfg.RuleMetrics.MaxRules),
 		alertRules:              alertRulesRegistry{rules: make(map[models.AlertRuleKey]*models.AlertRule)},
 	}
 	return &sch
@@ -197,6 +220,16 @@ func (sch *schedule) Run(ctx context.Context) error {
 		}
 	}()
 
+	if sch.sharder != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sch.sharder.Run(ctx); err != nil {
+				sch.log.Error("failure while running the evaluation sharding", "err", err)
+			}
+		}()
+	}
+
 	wg.Wait()
 	return nil
 }
@@ -457,8 +490,9 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 					continue
 				}
 
+				owned := sch.ownsRule(ctx, item)
 				itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
-				if item.IntervalSeconds != 0 && tickNum%itemFrequency == 0 {
+				if owned && item.IntervalSeconds != 0 && tickNum%itemFrequency == 0 {
 					readyToRun = append(readyToRun, readyToRunItem{key: key, ruleInfo: ruleInfo, version: itemVersion})
 				}
 
@@ -485,6 +519,7 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 			// unregister and stop routines of the deleted alert rules
 			for key := range registeredDefinitions {
 				sch.DeleteAlertRule(key)
+				delete(sch.ownedRules, key)
 			}
 
 			sch.metrics.SchedulePeriodicDuration.Observe(time.Since(start).Seconds())
@@ -497,7 +532,7 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 			}
 
 			for _, v := range orgIds {
-				sch.saveAlertStates(ctx, sch.stateManager.GetAll(v))
+				sch.saveAlertStates(ctx, sch.ownedStates(sch.stateManager.GetAll(v)))
 			}
 
 			sch.stateManager.Close()
@@ -506,6 +541,52 @@ func (sch *schedule) schedulePeriodic(ctx context.Context) error {
 	}
 }
 
+// RuleEvaluationStats returns the statistics of the evaluations of the alert rules of the organization.
+func (sch *schedule) RuleEvaluationStats(orgID int64) []RuleEvaluationStats {
+	return sch.ruleStats.list(orgID)
+}
+
+// ownsRule returns true if the alert rule is evaluated by this scheduler. When the rule moves to this scheduler
+// because schedulers joined or left the cluster, the state of its alert instances is loaded from the database.
+// When the rule moves to another scheduler, its evaluation statistics and metrics are removed.
+func (sch *schedule) ownsRule(ctx context.Context, rule *models.AlertRule) bool {
+	if sch.sharder == nil {
+		return true
+	}
+	key := rule.GetKey()
+	owned := sch.sharder.Owns(key)
+	wasOwned, known := sch.ownedRules[key]
+	// the state manager is warmed with the state of all rules on startup.
+	if !known {
+		wasOwned = true
+	}
+	sch.ownedRules[key] = owned
+	switch {
+	case wasOwned && !owned:
+		sch.log.Debug("alert rule moved to another scheduler", "key", key)
+		sch.ruleStats.del(key)
+	case !wasOwned && owned:
+		sch.log.Debug("alert rule moved to this scheduler", "key", key)
+		sch.stateManager.WarmRule(ctx, rule)
+	}
+	return owned
+}
+
+// ownedStates returns the states of the alert rules evaluated by this scheduler. The states of the rules evaluated
+// by other schedulers are copies of the database, which must not be saved or sent to the notifiers.
+func (sch *schedule) ownedStates(states []*state.State) []*state.State {
+	if sch.sharder == nil {
+		return states
+	}
+	owned := make([]*state.State, 0, len(states))
+	for _, s := range states {
+		if sch.sharder.Owns(models.AlertRuleKey{OrgID: s.OrgID, UID: s.AlertRuleUID}) {
+			owned = append(owned, s)
+		}
+	}
+	return owned
+}
+
 func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRuleKey, evalCh <-chan *evaluation, updateCh <-chan struct{}) error {
 	logger := sch.log.New("uid", key.UID, "org", key.OrgID)
 	logger.Debug("alert rule routine started")
@@ -560,7 +641,7 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 	}
 
 	clearState := func() {
-		states := sch.stateManager.GetStatesForRuleUID(key.OrgID, key.UID)
+		states := sch.ownedStates(sch.stateManager.GetStatesForRuleUID(key.OrgID, key.UID))
 		expiredAlerts := FromAlertsStateToStoppedAlert(states, sch.appURL, sch.clock)
 		sch.stateManager.RemoveByRuleUID(key.OrgID, key.UID)
 		notify(expiredAlerts, logger)
@@ -582,16 +663,41 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 	evaluate := func(ctx context.Context, r *models.AlertRule, attempt int64, e *evaluation) error {
 		logger := logger.New("version", r.Version, "attempt", attempt, "now", e.scheduledAt)
 		start := sch.clock.Now()
+		queryDurations := map[string]time.Duration{}
+		evalCtx := expr.WithNodeDurationObserver(ctx, func(refID string, d time.Duration) {
+			queryDurations[refID] = d
+		})
+
+		if r.IsRecordingRule() {
+			series, err := sch.record(evalCtx, r, e.scheduledAt)
+			dur := sch.clock.Now().Sub(start)
+			evalTotal.Inc()
+			evalDuration.Observe(dur.Seconds())
+			sch.ruleStats.record(r, start, dur, queryDurations, series, err)
+			if err != nil {
+				evalTotalFailures.Inc()
+				logger.Error("failed to evaluate recording rule", "duration", dur, "err", err)
+				return err
+			}
+			logger.Debug("recording rule evaluated", "duration", dur)
+			return nil
+		}
 
+		queries, err := models.PatchThresholdLoadedDimensions(r.Data, state.FiringDimensions(sch.stateManager.GetStatesForRuleUID(r.OrgID, r.UID)))
+		if err != nil {
+			logger.Error("failed to set the loaded dimensions of threshold expressions", "err", err)
+			queries = r.Data
+		}
 		condition := models.Condition{
 			Condition: r.Condition,
 			OrgID:     r.OrgID,
-			Data:      r.Data,
+			Data:      queries,
 		}
-		results, err := sch.evaluator.ConditionEval(&condition, e.scheduledAt, sch.expressionService)
+		results, err := sch.evaluator.ConditionEval(evalCtx, &condition, e.scheduledAt, sch.expressionService)
 		dur := sch.clock.Now().Sub(start)
 		evalTotal.Inc()
 		evalDuration.Observe(dur.Seconds())
+		sch.ruleStats.record(r, start, dur, queryDurations, len(results), firstResultError(results, err))
 		if err != nil {
 			evalTotalFailures.Inc()
 			// consider saving alert instance on error
@@ -675,12 +781,53 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 			}()
 		case <-grafanaCtx.Done():
 			clearState()
+			sch.ruleStats.del(key)
 			logger.Debug("stopping alert rule routine")
 			return nil
 		}
 	}
 }
 
+// record evaluates the queries and expressions of a recording rule and writes the result as the samples of its metric.
+// It returns the number of samples.
+func (sch *schedule) record(ctx context.Context, r *models.AlertRule, now time.Time) (int, error) {
+	if sch.recordingWriter == nil {
+		return 0, errors.New("recording rules are disabled")
+	}
+	resp, err := sch.evaluator.QueriesAndExpressionsEval(ctx, r.OrgID, r.Data, now, sch.expressionService)
+	if err != nil {
+		return 0, err
+	}
+	res, ok := resp.Responses[r.Record.From]
+	if !ok {
+		return 0, fmt.Errorf("no result for the recorded query or expression %s", r.Record.From)
+	}
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to evaluate the recorded query or expression %s: %w", r.Record.From, res.Error)
+	}
+	samples, err := recording.SamplesFromFrames(r, now, res.Frames)
+	if err != nil {
+		return 0, err
+	}
+	if err := sch.recordingWriter.Write(ctx, samples); err != nil {
+		return len(samples), fmt.Errorf("failed to write the samples of recording rule: %w", err)
+	}
+	return len(samples), nil
+}
+
+// firstResultError returns the error of the evaluation, or else the error of its first result in the Error state.
+func firstResultError(results eval.Results, err error) error {
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.State == eval.Error && r.Error != nil {
+			return r.Error
+		}
+	}
+	return nil
+}
+
 func (sch *schedule) saveAlertStates(ctx context.Context, states []*state.State) {
 	sch.log.Debug("saving alert states", "count", len(states))
 	for _, s := range states {
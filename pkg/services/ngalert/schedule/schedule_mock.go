@@ -69,6 +69,22 @@ func (_m *FakeScheduleService) Pause() error {
 	return r0
 }
 
+// RuleEvaluationStats provides a mock function with given fields: orgID
+func (_m *FakeScheduleService) RuleEvaluationStats(orgID int64) []RuleEvaluationStats {
+	ret := _m.Called(orgID)
+
+	var r0 []RuleEvaluationStats
+	if rf, ok := ret.Get(0).(func(int64) []RuleEvaluationStats); ok {
+		r0 = rf(orgID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]RuleEvaluationStats)
+		}
+	}
+
+	return r0
+}
+
 // Run provides a mock function with given fields: _a0
 func (_m *FakeScheduleService) Run(_a0 context.Context) error {
 	ret := _m.Called(_a0)
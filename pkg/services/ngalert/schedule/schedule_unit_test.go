@@ -818,6 +818,82 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 	})
 }
 
+func TestSchedule_ruleRoutine_RecordingRule(t *testing.T) {
+	ruleStore := store.NewFakeRuleStore(t)
+	sch, _ := setupScheduler(t, ruleStore, &store.FakeInstanceStore{}, store.NewFakeAdminConfigStore(t), nil)
+	writer := &fakeRecordingWriter{}
+	sch.recordingWriter = writer
+	evalAppliedChan := make(chan time.Time)
+	sch.evalAppliedFunc = func(key models.AlertRuleKey, t time.Time) {
+		evalAppliedChan <- t
+	}
+
+	rule := CreateTestAlertRule(t, ruleStore, 10, rand.Int63(), eval.Alerting)
+	rule.Labels = map[string]string{"team": "ops"}
+	rule.Record = &models.Record{Metric: "test_metric", From: "A"}
+	rule.Data[0].Model = json.RawMessage(`{
+		"datasourceUid": "-100",
+		"type":"math",
+		"expression":"2 + 1"
+	}`)
+	ruleStore.PutRule(context.Background(), rule)
+
+	evalChan := make(chan *evaluation)
+	go func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan struct{}))
+	}()
+
+	expectedTime := time.UnixMicro(rand.Int63())
+	evalChan <- &evaluation{
+		scheduledAt: expectedTime,
+		version:     rule.Version,
+	}
+	waitForTimeChannel(t, evalAppliedChan)
+
+	t.Run("it should write the result as samples of the metric", func(t *testing.T) {
+		samples := writer.Samples()
+		require.Len(t, samples, 1)
+		require.Equal(t, "test_metric", samples[0].Metric)
+		require.Equal(t, 3.0, samples[0].Value)
+		require.Equal(t, expectedTime, samples[0].Timestamp)
+		require.Equal(t, models.InstanceLabels{"team": "ops"}, samples[0].Labels)
+	})
+
+	t.Run("it should not process the result via state manager", func(t *testing.T) {
+		require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+	})
+
+	t.Run("it should keep the statistics of the evaluation", func(t *testing.T) {
+		stats := sch.RuleEvaluationStats(rule.OrgID)
+		require.Len(t, stats, 1)
+		require.Equal(t, rule.UID, stats[0].UID)
+		require.Equal(t, int64(1), stats[0].Evaluations)
+		require.Zero(t, stats[0].Failures)
+		require.Equal(t, 1, stats[0].ResultSeries)
+		require.Contains(t, stats[0].QueryDurations, "A")
+	})
+}
+
+type fakeRecordingWriter struct {
+	mtx     sync.Mutex
+	samples []models.RecordedSample
+}
+
+func (w *fakeRecordingWriter) Write(_ context.Context, samples []models.RecordedSample) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.samples = append(w.samples, samples...)
+	return nil
+}
+
+func (w *fakeRecordingWriter) Samples() []models.RecordedSample {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.samples
+}
+
 func TestSchedule_alertRuleInfo(t *testing.T) {
 	t.Run("when rule evaluation is not stopped", func(t *testing.T) {
 		t.Run("Update should send to updateCh", func(t *testing.T) {
@@ -1017,7 +1093,7 @@ func setupScheduler(t *testing.T, rs store.RuleStore, is store.InstanceStore, ac
 	m := metrics.NewNGAlert(registry)
 	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
 	decryptFn := secretsService.GetDecryptedValue
-	moa, err := notifier.NewMultiOrgAlertmanager(&setting.Cfg{}, &notifier.FakeConfigStore{}, &notifier.FakeOrgStore{}, &notifier.FakeKVStore{}, provisioning.NewFakeProvisioningStore(), decryptFn, m.GetMultiOrgAlertmanagerMetrics(), nil, log.New("testlogger"), secretsService)
+	moa, err := notifier.NewMultiOrgAlertmanager(&setting.Cfg{}, &notifier.FakeConfigStore{}, &notifier.FakeOrgStore{}, &notifier.FakeKVStore{}, provisioning.NewFakeProvisioningStore(), decryptFn, m.GetMultiOrgAlertmanagerMetrics(), nil, log.New("testlogger"), secretsService, nil)
 	require.NoError(t, err)
 
 	schedCfg := SchedulerCfg{
@@ -1033,7 +1109,7 @@ func setupScheduler(t *testing.T, rs store.RuleStore, is store.InstanceStore, ac
 		Metrics:                 m.GetSchedulerMetrics(),
 		AdminConfigPollInterval: 10 * time.Minute, // do not poll in unit tests.
 	}
-	st := state.NewManager(schedCfg.Logger, m.GetStateMetrics(), nil, rs, is, mockstore.NewSQLStoreMock())
+	st := state.NewManager(schedCfg.Logger, m.GetStateMetrics(), nil, rs, is, mockstore.NewSQLStoreMock(), nil)
 	appUrl := &url.URL{
 		Scheme: "http",
 		Host:   "localhost",
@@ -0,0 +1,159 @@
+package schedule
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// EvaluationSharder distributes the evaluation of alert rules between the schedulers of a HA cluster.
+// Every scheduler sends heartbeats to the database, and each alert rule is assigned to one of the active
+// schedulers with rendezvous hashing. When a scheduler joins or leaves the cluster, only the rules
+// assigned to it move to or from the other schedulers.
+type EvaluationSharder struct {
+	instanceID string
+	store      store.SchedulerInstanceStore
+	interval   time.Duration
+	timeout    time.Duration
+	clock      clock.Clock
+	log        log.Logger
+
+	mtx       sync.RWMutex
+	instances []string
+}
+
+// NewEvaluationSharder returns a sharder for the scheduler with the given ID. The scheduler sends a heartbeat every interval,
+// and the schedulers without heartbeat for longer than timeout are considered gone.
+func NewEvaluationSharder(instanceID string, st store.SchedulerInstanceStore, interval, timeout time.Duration, c clock.Clock, logger log.Logger) *EvaluationSharder {
+	return &EvaluationSharder{
+		instanceID: instanceID,
+		store:      st,
+		interval:   interval,
+		timeout:    timeout,
+		clock:      c,
+		log:        logger,
+	}
+}
+
+// Run sends heartbeats and refreshes the active schedulers until the context is canceled.
+// The scheduler then leaves the cluster, so that the other schedulers take over its rules immediately.
+func (s *EvaluationSharder) Run(ctx context.Context) error {
+	s.heartbeat(ctx)
+	ticker := s.clock.Ticker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.heartbeat(ctx)
+		case <-ctx.Done():
+			if err := s.store.DeleteSchedulerInstance(context.Background(), s.instanceID); err != nil {
+				s.log.Error("failed to leave the cluster of schedulers", "instance", s.instanceID, "err", err)
+			}
+			return nil
+		}
+	}
+}
+
+// heartbeat announces that the scheduler is active and refreshes the active schedulers.
+// If the database is not available, the last known schedulers are kept.
+func (s *EvaluationSharder) heartbeat(ctx context.Context) {
+	now := s.clock.Now()
+	if err := s.store.HeartbeatSchedulerInstance(ctx, s.instanceID, now); err != nil {
+		s.log.Error("failed to send scheduler heartbeat", "instance", s.instanceID, "err", err)
+	}
+	instances, err := s.store.GetActiveSchedulerInstances(ctx, now.Add(-s.timeout))
+	if err != nil {
+		s.log.Error("failed to get the active schedulers", "err", err)
+		return
+	}
+	if !containsString(instances, s.instanceID) {
+		// the scheduler evaluates rules even if its heartbeat failed.
+		instances = append(instances, s.instanceID)
+	}
+	sort.Strings(instances)
+
+	s.mtx.Lock()
+	changed := !equalStrings(s.instances, instances)
+	s.instances = instances
+	s.mtx.Unlock()
+	if changed {
+		s.log.Info("schedulers of the cluster changed, alert rules are rebalanced", "instances", instances)
+	}
+}
+
+// Instances returns the active schedulers of the cluster.
+func (s *EvaluationSharder) Instances() []string {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return append([]string(nil), s.instances...)
+}
+
+// Owns returns true if the alert rule is evaluated by this scheduler. No rule is owned until the active schedulers
+// are read from the database for the first time, so that a starting scheduler does not evaluate the rules of the others.
+func (s *EvaluationSharder) Owns(key models.AlertRuleKey) bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if len(s.instances) == 0 {
+		return false
+	}
+	return ruleOwner(key, s.instances) == s.instanceID
+}
+
+// ruleOwner returns the scheduler with the highest weight for the alert rule.
+func ruleOwner(key models.AlertRuleKey, instances []string) string {
+	var owner string
+	var maxWeight uint64
+	for _, instance := range instances {
+		if w := ruleWeight(key, instance); owner == "" || w > maxWeight {
+			owner, maxWeight = instance, w
+		}
+	}
+	return owner
+}
+
+func ruleWeight(key models.AlertRuleKey, instance string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(instance))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(strconv.FormatInt(key.OrgID, 10)))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key.UID))
+	// FNV does not spread similar inputs well, the finalizer of MurmurHash3 mixes the bits of the hash.
+	w := h.Sum64()
+	w ^= w >> 33
+	w *= 0xff51afd7ed558ccd
+	w ^= w >> 33
+	w *= 0xc4ceb9fe1a85ec53
+	w ^= w >> 33
+	return w
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
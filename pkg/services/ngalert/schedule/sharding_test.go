@@ -0,0 +1,166 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestEvaluationSharder(t *testing.T) {
+	ctx := context.Background()
+	keys := make([]models.AlertRuleKey, 0, 300)
+	for i := 0; i < 300; i++ {
+		keys = append(keys, models.AlertRuleKey{OrgID: int64(i%3 + 1), UID: fmt.Sprintf("rule-%d", i)})
+	}
+
+	newSharders := func(st store.SchedulerInstanceStore, c clock.Clock, ids ...string) []*EvaluationSharder {
+		sharders := make([]*EvaluationSharder, 0, len(ids))
+		for _, id := range ids {
+			sharders = append(sharders, NewEvaluationSharder(id, st, 10*time.Second, time.Minute, c, log.NewNopLogger()))
+		}
+		for _, s := range sharders {
+			s.heartbeat(ctx)
+		}
+		// the first sharders did not see the later ones yet.
+		for _, s := range sharders {
+			s.heartbeat(ctx)
+		}
+		return sharders
+	}
+
+	t.Run("a scheduler owns no rule until it knows the active schedulers", func(t *testing.T) {
+		s := NewEvaluationSharder("a", store.NewFakeSchedulerInstanceStore(), 10*time.Second, time.Minute, clock.NewMock(), log.NewNopLogger())
+		require.False(t, s.Owns(keys[0]))
+	})
+
+	t.Run("a single scheduler owns every rule", func(t *testing.T) {
+		sharders := newSharders(store.NewFakeSchedulerInstanceStore(), clock.NewMock(), "a")
+		require.Equal(t, []string{"a"}, sharders[0].Instances())
+		for _, key := range keys {
+			require.True(t, sharders[0].Owns(key))
+		}
+	})
+
+	t.Run("every rule is owned by exactly one scheduler", func(t *testing.T) {
+		sharders := newSharders(store.NewFakeSchedulerInstanceStore(), clock.NewMock(), "c", "a", "b")
+		owned := map[string]int{}
+		for _, key := range keys {
+			var owners []string
+			for _, s := range sharders {
+				require.Equal(t, []string{"a", "b", "c"}, s.Instances())
+				if s.Owns(key) {
+					owners = append(owners, s.instanceID)
+				}
+			}
+			require.Len(t, owners, 1, key)
+			owned[owners[0]]++
+		}
+		for id, n := range owned {
+			require.Greater(t, n, len(keys)/6, "scheduler %s owns too few rules", id)
+		}
+	})
+
+	t.Run("only the rules of a scheduler that left move", func(t *testing.T) {
+		st := store.NewFakeSchedulerInstanceStore()
+		c := clock.NewMock()
+		sharders := newSharders(st, c, "a", "b", "c")
+		before := map[models.AlertRuleKey]string{}
+		for _, key := range keys {
+			before[key] = ruleOwner(key, sharders[0].Instances())
+		}
+
+		// c stops sending heartbeats.
+		c.Add(2 * time.Minute)
+		for i := 0; i < 2; i++ {
+			sharders[0].heartbeat(ctx)
+			sharders[1].heartbeat(ctx)
+		}
+		require.Equal(t, []string{"a", "b"}, sharders[0].Instances())
+
+		for _, key := range keys {
+			owner := ruleOwner(key, sharders[0].Instances())
+			if before[key] != "c" {
+				require.Equal(t, before[key], owner, key)
+			}
+			require.NotEqual(t, sharders[0].Owns(key), sharders[1].Owns(key), key)
+		}
+	})
+
+	t.Run("a scheduler leaves the cluster when it stops", func(t *testing.T) {
+		st := store.NewFakeSchedulerInstanceStore()
+		c := clock.NewMock()
+		sharders := newSharders(st, c, "a", "b")
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- sharders[1].Run(ctx)
+		}()
+		cancel()
+		require.NoError(t, <-done)
+
+		sharders[0].heartbeat(context.Background())
+		require.Equal(t, []string{"a"}, sharders[0].Instances())
+	})
+}
+
+func TestSchedule_ownsRule(t *testing.T) {
+	sch := setupSchedulerWithFakeStores(t)
+	rule := models.AlertRuleGen()()
+	key := rule.GetKey()
+
+	st := store.NewFakeSchedulerInstanceStore()
+	c := clock.NewMock()
+	local := NewEvaluationSharder("local", st, 10*time.Second, time.Minute, c, log.NewNopLogger())
+	sch.sharder = local
+
+	// find another scheduler that owns the rule.
+	var other string
+	for i := 0; other == ""; i++ {
+		if id := fmt.Sprintf("other-%d", i); ruleOwner(key, []string{"local", id}) == id {
+			other = id
+		}
+	}
+
+	// no rule is owned until the active schedulers are known.
+	require.False(t, sch.ownsRule(context.Background(), rule))
+
+	local.heartbeat(context.Background())
+	require.True(t, sch.ownsRule(context.Background(), rule))
+
+	sch.stateManager.Put([]*state.State{{
+		AlertRuleUID: rule.UID,
+		OrgID:        rule.OrgID,
+		CacheId:      "test",
+		State:        eval.Alerting,
+	}})
+	require.Len(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID), 1)
+	sch.ruleStats.record(rule, c.Now(), time.Second, nil, 1, nil)
+	require.Len(t, sch.RuleEvaluationStats(rule.OrgID), 1)
+
+	require.NoError(t, st.HeartbeatSchedulerInstance(context.Background(), other, c.Now()))
+	local.heartbeat(context.Background())
+	require.False(t, sch.ownsRule(context.Background(), rule))
+	// the state of the rule is kept for the APIs and the rules that depend on it, but it is not saved or notified.
+	states := sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, states, 1)
+	require.Empty(t, sch.ownedStates(states))
+	require.Empty(t, sch.RuleEvaluationStats(rule.OrgID))
+
+	// the state is loaded from the database only when the rule moves back.
+	require.False(t, sch.ownsRule(context.Background(), rule))
+	require.Len(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID), 1)
+	require.NoError(t, st.DeleteSchedulerInstance(context.Background(), other))
+	local.heartbeat(context.Background())
+	require.True(t, sch.ownsRule(context.Background(), rule))
+	require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+}
@@ -0,0 +1,93 @@
+package state
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// Transition is a change of the state of an alert instance.
+type Transition struct {
+	EvaluatedAt   time.Time
+	Labels        data.Labels
+	PreviousState eval.State
+	State         eval.State
+	Values        map[string]*float64
+	Error         error
+}
+
+// Backtester applies evaluation results to alert instances with the same state transitions
+// as the Manager, including pending periods, NoData and Error handling and stale instances.
+// Unlike the Manager it does not persist, annotate or send anything, so it can be used to
+// replay the evaluations of alert rules over a time range.
+type Backtester struct {
+	cache *cache
+}
+
+// NewBacktester returns a Backtester with no alert instances.
+func NewBacktester(logger log.Logger, externalURL *url.URL) *Backtester {
+	return &Backtester{
+		cache: newCache(logger, nil, externalURL),
+	}
+}
+
+// ProcessEvalResults applies the results of one evaluation of alertRule and returns the
+// transitions they caused. Evaluations of a rule must be processed in chronological order.
+func (b *Backtester) ProcessEvalResults(ctx context.Context, alertRule *ngModels.AlertRule, results eval.Results) []Transition {
+	var transitions []Transition
+	processed := make(map[string]struct{}, len(results))
+	var evaluatedAt time.Time
+	for _, result := range results {
+		s := b.cache.getOrCreate(ctx, alertRule, result)
+		oldState := s.applyResult(alertRule, result)
+		b.cache.set(s)
+		processed[s.CacheId] = struct{}{}
+		if result.EvaluatedAt.After(evaluatedAt) {
+			evaluatedAt = result.EvaluatedAt
+		}
+		if oldState == s.State {
+			continue
+		}
+		transitions = append(transitions, Transition{
+			EvaluatedAt:   result.EvaluatedAt,
+			Labels:        s.Labels.Copy(),
+			PreviousState: oldState,
+			State:         s.State,
+			Values:        NewEvaluationValues(result.Values),
+			Error:         s.Error,
+		})
+	}
+
+	// instances that are missing from the results are removed like in the Manager,
+	// but staleness is based on the time of the evaluation rather than the current time.
+	for _, s := range b.cache.getStatesForRuleUID(alertRule.OrgID, alertRule.UID) {
+		if _, ok := processed[s.CacheId]; ok {
+			continue
+		}
+		if !s.LastEvaluationTime.Add(2 * time.Duration(alertRule.IntervalSeconds) * time.Second).Before(evaluatedAt) {
+			continue
+		}
+		b.cache.deleteEntry(s.OrgID, s.AlertRuleUID, s.CacheId)
+		if s.State == eval.Alerting {
+			transitions = append(transitions, Transition{
+				EvaluatedAt:   evaluatedAt,
+				Labels:        s.Labels.Copy(),
+				PreviousState: s.State,
+				State:         eval.Normal,
+			})
+		}
+	}
+	return transitions
+}
+
+// FiringDimensions returns the labels of the instances of alertRule that are pending or alerting.
+// They are passed to threshold expressions, so they can apply their recovery threshold.
+func (b *Backtester) FiringDimensions(alertRule *ngModels.AlertRule) []data.Labels {
+	return FiringDimensions(b.cache.getStatesForRuleUID(alertRule.OrgID, alertRule.UID))
+}
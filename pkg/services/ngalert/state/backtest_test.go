@@ -0,0 +1,78 @@
+package state_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestBacktester(t *testing.T) {
+	start, err := time.Parse("2006-01-02", "2022-01-01")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	rule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "test_title",
+		UID:             "test_alert_rule_uid",
+		NamespaceUID:    "test_namespace_uid",
+		Annotations:     map[string]string{},
+		Labels:          map[string]string{},
+		IntervalSeconds: 10,
+		For:             10 * time.Second,
+		NoDataState:     models.NoData,
+		ExecErrState:    models.ErrorErrState,
+	}
+	instance := data.Labels{"instance_label": "test"}
+	at := func(i int) time.Time {
+		return start.Add(time.Duration(i) * 10 * time.Second)
+	}
+
+	b := state.NewBacktester(log.New("test_backtester"), nil)
+
+	transitions := b.ProcessEvalResults(ctx, rule, eval.Results{{Instance: instance, State: eval.Normal, EvaluatedAt: at(0)}})
+	require.Len(t, transitions, 0)
+
+	transitions = b.ProcessEvalResults(ctx, rule, eval.Results{{Instance: instance, State: eval.Alerting, EvaluatedAt: at(1)}})
+	require.Len(t, transitions, 1)
+	require.Equal(t, eval.Normal, transitions[0].PreviousState)
+	require.Equal(t, eval.Pending, transitions[0].State)
+	require.Equal(t, at(1), transitions[0].EvaluatedAt)
+	require.Equal(t, "test", transitions[0].Labels["instance_label"])
+	require.Len(t, b.FiringDimensions(rule), 1)
+
+	transitions = b.ProcessEvalResults(ctx, rule, eval.Results{{Instance: instance, State: eval.Alerting, EvaluatedAt: at(2)}})
+	require.Len(t, transitions, 1)
+	require.Equal(t, eval.Pending, transitions[0].PreviousState)
+	require.Equal(t, eval.Alerting, transitions[0].State)
+
+	transitions = b.ProcessEvalResults(ctx, rule, eval.Results{{Instance: instance, State: eval.Normal, EvaluatedAt: at(3)}})
+	require.Len(t, transitions, 1)
+	require.Equal(t, eval.Alerting, transitions[0].PreviousState)
+	require.Equal(t, eval.Normal, transitions[0].State)
+	require.Len(t, b.FiringDimensions(rule), 0)
+
+	t.Run("stale alerting instances resolve", func(t *testing.T) {
+		other := data.Labels{"instance_label": "other"}
+		b := state.NewBacktester(log.New("test_backtester"), nil)
+		for i := 0; i < 3; i++ {
+			b.ProcessEvalResults(ctx, rule, eval.Results{{Instance: other, State: eval.Alerting, EvaluatedAt: at(i)}})
+		}
+		require.Len(t, b.FiringDimensions(rule), 1)
+
+		transitions := b.ProcessEvalResults(ctx, rule, eval.Results{{Instance: instance, State: eval.Normal, EvaluatedAt: at(5)}})
+		require.Len(t, transitions, 1)
+		require.Equal(t, "other", transitions[0].Labels["instance_label"])
+		require.Equal(t, eval.Alerting, transitions[0].PreviousState)
+		require.Equal(t, eval.Normal, transitions[0].State)
+		require.Len(t, b.FiringDimensions(rule), 0)
+	})
+}
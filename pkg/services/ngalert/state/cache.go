@@ -150,6 +150,38 @@ func (c *cache) getStatesForRuleUID(orgID int64, alertRuleUID string) []*State {
 	return ruleStates
 }
 
+// hasAlertingState returns true if an alert instance of the alert rule is in the Alerting state.
+func (c *cache) hasAlertingState(orgID int64, alertRuleUID string) bool {
+	c.mtxStates.RLock()
+	defer c.mtxStates.RUnlock()
+	for _, state := range c.states[orgID][alertRuleUID] {
+		if state.State == eval.Alerting {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceRuleStates replaces the entries of the alert rules of the organization, by rule UID, in a single update.
+func (c *cache) replaceRuleStates(orgID int64, states map[string][]*State) {
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	if _, ok := c.states[orgID]; !ok {
+		c.states[orgID] = make(map[string]map[string]*State)
+	}
+	for uid, ruleStates := range states {
+		if len(ruleStates) == 0 {
+			delete(c.states[orgID], uid)
+			continue
+		}
+		entries := make(map[string]*State, len(ruleStates))
+		for _, s := range ruleStates {
+			entries[s.CacheId] = s
+		}
+		c.states[orgID][uid] = entries
+	}
+}
+
 // removeByRuleUID deletes all entries in the state cache that match the given UID.
 func (c *cache) removeByRuleUID(orgID int64, uid string) {
 	c.mtxStates.Lock()
@@ -170,11 +202,12 @@ func (c *cache) recordMetrics() {
 	// Set default values to zero such that gauges are reset
 	// after all values from a single state disappear.
 	ct := map[eval.State]int{
-		eval.Normal:   0,
-		eval.Alerting: 0,
-		eval.Pending:  0,
-		eval.NoData:   0,
-		eval.Error:    0,
+		eval.Normal:    0,
+		eval.Alerting:  0,
+		eval.Pending:   0,
+		eval.NoData:    0,
+		eval.Error:     0,
+		eval.Inhibited: 0,
 	}
 
 	for org, orgMap := range c.states {
@@ -3,12 +3,14 @@ package state
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
@@ -39,10 +41,12 @@ type Manager struct {
 	ruleStore     store.RuleStore
 	instanceStore store.InstanceStore
 	sqlStore      sqlstore.Store
+	historyStore  store.StateHistoryStore
 }
 
+// NewManager returns a new Manager. If historyStore is nil, the state history is not recorded.
 func NewManager(logger log.Logger, metrics *metrics.State, externalURL *url.URL, ruleStore store.RuleStore,
-	instanceStore store.InstanceStore, sqlStore sqlstore.Store) *Manager {
+	instanceStore store.InstanceStore, sqlStore sqlstore.Store, historyStore store.StateHistoryStore) *Manager {
 	manager := &Manager{
 		cache:         newCache(logger, metrics, externalURL),
 		quit:          make(chan struct{}),
@@ -52,6 +56,7 @@ func NewManager(logger log.Logger, metrics *metrics.State, externalURL *url.URL,
 		ruleStore:     ruleStore,
 		instanceStore: instanceStore,
 		sqlStore:      sqlStore,
+		historyStore:  historyStore,
 	}
 	go manager.recordMetrics()
 	return manager
@@ -99,25 +104,7 @@ func (st *Manager) Warm(ctx context.Context) {
 				st.log.Error("rule not found for instance, ignoring", "rule", entry.RuleUID)
 				continue
 			}
-
-			lbs := map[string]string(entry.Labels)
-			cacheId, err := entry.Labels.StringKey()
-			if err != nil {
-				st.log.Error("error getting cacheId for entry", "msg", err.Error())
-			}
-			stateForEntry := &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheId:              cacheId,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          ruleForEntry.Annotations,
-			}
-			states = append(states, stateForEntry)
+			states = append(states, st.stateFromInstance(entry, ruleForEntry))
 		}
 	}
 
@@ -126,6 +113,43 @@ func (st *Manager) Warm(ctx context.Context) {
 	}
 }
 
+// WarmRule loads the state of the alert instances of the rule from the database, replacing the cached state.
+// It is used when another scheduler of a HA cluster evaluated the rule until now.
+func (st *Manager) WarmRule(ctx context.Context, alertRule *ngModels.AlertRule) {
+	cmd := ngModels.ListAlertInstancesQuery{
+		RuleOrgID: alertRule.OrgID,
+		RuleUID:   alertRule.UID,
+	}
+	if err := st.instanceStore.ListAlertInstances(ctx, &cmd); err != nil {
+		st.log.Error("unable to fetch previous state", "alertRuleUID", alertRule.UID, "msg", err.Error())
+		return
+	}
+	states := make([]*State, 0, len(cmd.Result))
+	for _, entry := range cmd.Result {
+		states = append(states, st.stateFromInstance(entry, alertRule))
+	}
+	st.cache.replaceRuleStates(alertRule.OrgID, map[string][]*State{alertRule.UID: states})
+}
+
+func (st *Manager) stateFromInstance(entry *ngModels.ListAlertInstancesQueryResult, alertRule *ngModels.AlertRule) *State {
+	cacheId, err := entry.Labels.StringKey()
+	if err != nil {
+		st.log.Error("error getting cacheId for entry", "msg", err.Error())
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheId:              cacheId,
+		Labels:               map[string]string(entry.Labels),
+		State:                translateInstanceState(entry.CurrentState),
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          alertRule.Annotations,
+	}
+}
+
 func (st *Manager) getOrCreate(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result) *State {
 	return st.cache.getOrCreate(ctx, alertRule, result)
 }
@@ -151,54 +175,99 @@ func (st *Manager) RemoveByRuleUID(orgID int64, ruleUID string) {
 func (st *Manager) ProcessEvalResults(ctx context.Context, alertRule *ngModels.AlertRule, results eval.Results) []*State {
 	st.log.Debug("state manager processing evaluation results", "uid", alertRule.UID, "resultCount", len(results))
 	var states []*State
+	var history []ngModels.StateHistoryEntry
 	processedResults := make(map[string]*State, len(results))
+	inhibitedBy := st.inhibitingRuleUID(alertRule)
+	if inhibitedBy != "" {
+		st.log.Debug("alert rule is inhibited by a firing rule it depends on", "uid", alertRule.UID, "inhibitedBy", inhibitedBy)
+	}
 	for _, result := range results {
-		s := st.setNextState(ctx, alertRule, result)
+		s, oldState := st.setNextState(ctx, alertRule, result, inhibitedBy != "")
 		states = append(states, s)
 		processedResults[s.CacheId] = s
+		if oldState != s.State {
+			history = append(history, newStateHistoryEntry(alertRule, s.Labels, oldState, s.State, result.EvaluatedAt, NewEvaluationValues(result.Values), s.Error))
+		}
 	}
-	st.staleResultsHandler(ctx, alertRule, processedResults)
+	history = append(history, st.staleResultsHandler(ctx, alertRule, processedResults)...)
+	// like the annotations, the history is saved in the background so that the evaluation does not wait for the database
+	go st.saveStateHistory(ctx, alertRule, history)
 	return states
 }
 
-// Set the current state based on evaluation results
-func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result) *State {
+// Set the current state based on evaluation results. If inhibited is true, the alert instance is inhibited regardless of the result.
+// It returns the new state and the previous state of the alert instance.
+func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, inhibited bool) (*State, eval.State) {
 	currentState := st.getOrCreate(ctx, alertRule, result)
 
-	currentState.LastEvaluationTime = result.EvaluatedAt
-	currentState.EvaluationDuration = result.EvaluationDuration
-	currentState.Results = append(currentState.Results, Evaluation{
-		EvaluationTime:  result.EvaluatedAt,
-		EvaluationState: result.State,
-		Values:          NewEvaluationValues(result.Values),
-		Condition:       alertRule.Condition,
-	})
-	currentState.LastEvaluationString = result.EvaluationString
-	currentState.TrimResults(alertRule)
-	oldState := currentState.State
-
 	st.log.Debug("setting alert state", "uid", alertRule.UID)
-	switch result.State {
-	case eval.Normal:
-		currentState.resultNormal(alertRule, result)
-	case eval.Alerting:
-		currentState.resultAlerting(alertRule, result)
-	case eval.Error:
-		currentState.resultError(alertRule, result)
-	case eval.NoData:
-		currentState.resultNoData(alertRule, result)
-	case eval.Pending: // we do not emit results with this state
-	}
-
-	// Set Resolved property so the scheduler knows to send a postable alert
-	// to Alertmanager.
-	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
+	var oldState eval.State
+	if inhibited {
+		oldState = currentState.applyInhibition(alertRule, result)
+	} else {
+		oldState = currentState.applyResult(alertRule, result)
+	}
 
 	st.set(currentState)
 	if oldState != currentState.State {
 		go st.annotateState(ctx, alertRule, currentState.Labels, result.EvaluatedAt, currentState.State, oldState)
 	}
-	return currentState
+	return currentState, oldState
+}
+
+// inhibitingRuleUID returns the UID of a rule the alert rule depends on that has a firing alert instance,
+// or an empty string if the alert rule is not inhibited. The parent rules selected by UID are looked up
+// directly, only the matchers need to go through the states of the organization.
+func (st *Manager) inhibitingRuleUID(alertRule *ngModels.AlertRule) string {
+	if alertRule.DependsOn == nil {
+		return ""
+	}
+	for _, uid := range alertRule.DependsOn.RuleUIDs {
+		if uid != alertRule.UID && st.cache.hasAlertingState(alertRule.OrgID, uid) {
+			return uid
+		}
+	}
+	if len(alertRule.DependsOn.Matchers) == 0 {
+		return ""
+	}
+	matchers, err := alertRule.DependsOn.LabelMatchers()
+	if err != nil {
+		// the matchers are validated when the rule is saved.
+		st.log.Error("failed to parse the matchers of the rule dependencies", "uid", alertRule.UID, "error", err)
+		return ""
+	}
+	for _, s := range st.GetAll(alertRule.OrgID) {
+		if s.AlertRuleUID != alertRule.UID && s.State == eval.Alerting && matchLabels(matchers, s.Labels) {
+			return s.AlertRuleUID
+		}
+	}
+	return ""
+}
+
+func (st *Manager) saveStateHistory(ctx context.Context, alertRule *ngModels.AlertRule, history []ngModels.StateHistoryEntry) {
+	if st.historyStore == nil || len(history) == 0 {
+		return
+	}
+	if err := st.historyStore.SaveStateHistory(ctx, history); err != nil {
+		st.log.Error("failed to save state history", "alertRuleUID", alertRule.UID, "error", err)
+	}
+}
+
+func newStateHistoryEntry(alertRule *ngModels.AlertRule, labels data.Labels, previousState, state eval.State, evaluatedAt time.Time, values map[string]*float64, evalErr error) ngModels.StateHistoryEntry {
+	entry := ngModels.StateHistoryEntry{
+		OrgID:         alertRule.OrgID,
+		RuleUID:       alertRule.UID,
+		NamespaceUID:  alertRule.NamespaceUID,
+		Labels:        ngModels.InstanceLabels(labels.Copy()),
+		PreviousState: ngModels.InstanceStateType(previousState.String()),
+		State:         ngModels.InstanceStateType(state.String()),
+		Values:        finiteValues(values),
+		EvaluatedAt:   evaluatedAt,
+	}
+	if evalErr != nil {
+		entry.Error = evalErr.Error()
+	}
+	return entry
 }
 
 func (st *Manager) GetAll(orgID int64) []*State {
@@ -239,6 +308,8 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 		return eval.Alerting
 	case state == ngModels.InstanceStateNormal:
 		return eval.Normal
+	case state == ngModels.InstanceStateInhibited:
+		return eval.Inhibited
 	default:
 		return eval.Error
 	}
@@ -291,7 +362,10 @@ func (st *Manager) annotateState(ctx context.Context, alertRule *ngModels.AlertR
 	}
 }
 
-func (st *Manager) staleResultsHandler(ctx context.Context, alertRule *ngModels.AlertRule, states map[string]*State) {
+// staleResultsHandler removes the alert instances that have not been evaluated for two intervals
+// and returns the state history of the instances that were resolved.
+func (st *Manager) staleResultsHandler(ctx context.Context, alertRule *ngModels.AlertRule, states map[string]*State) []ngModels.StateHistoryEntry {
+	var history []ngModels.StateHistoryEntry
 	allStates := st.GetStatesForRuleUID(alertRule.OrgID, alertRule.UID)
 	for _, s := range allStates {
 		_, ok := states[s.CacheId]
@@ -309,10 +383,37 @@ func (st *Manager) staleResultsHandler(ctx context.Context, alertRule *ngModels.
 			}
 
 			if s.State == eval.Alerting {
-				st.annotateState(ctx, alertRule, s.Labels, time.Now(), eval.Normal, s.State)
+				now := time.Now()
+				st.annotateState(ctx, alertRule, s.Labels, now, eval.Normal, s.State)
+				history = append(history, newStateHistoryEntry(alertRule, s.Labels, s.State, eval.Normal, now, nil, nil))
 			}
 		}
 	}
+	return history
+}
+
+// finiteValues returns a copy of values where NaN and infinite values are nil, so they can be encoded as JSON.
+func finiteValues(values map[string]*float64) map[string]*float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	result := make(map[string]*float64, len(values))
+	for k, v := range values {
+		if v != nil && (math.IsNaN(*v) || math.IsInf(*v, 0)) {
+			v = nil
+		}
+		result[k] = v
+	}
+	return result
+}
+
+func matchLabels(matchers labels.Matchers, lbs data.Labels) bool {
+	for _, m := range matchers {
+		if !m.Matches(lbs[m.Name]) {
+			return false
+		}
+	}
+	return true
 }
 
 func isItStale(lastEval time.Time, intervalSeconds int64) bool {
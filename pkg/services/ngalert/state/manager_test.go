@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"testing"
 	"time"
@@ -37,7 +38,7 @@ func TestDashboardAnnotations(t *testing.T) {
 	_, dbstore := tests.SetupTestEnv(t, 1)
 
 	sqlStore := mockstore.NewSQLStoreMock()
-	st := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, sqlStore)
+	st := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, sqlStore, nil)
 
 	fakeAnnoRepo := store.NewFakeAnnotationsRepo()
 	annotations.SetRepository(fakeAnnoRepo)
@@ -1867,7 +1868,7 @@ func TestProcessEvalResults(t *testing.T) {
 
 	for _, tc := range testCases {
 		ss := mockstore.NewSQLStoreMock()
-		st := state.NewManager(log.New("test_state_manager"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, ss)
+		st := state.NewManager(log.New("test_state_manager"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, ss, nil)
 		t.Run(tc.desc, func(t *testing.T) {
 			fakeAnnoRepo := store.NewFakeAnnotationsRepo()
 			annotations.SetRepository(fakeAnnoRepo)
@@ -1978,7 +1979,7 @@ func TestStaleResultsHandler(t *testing.T) {
 	for _, tc := range testCases {
 		ctx := context.Background()
 		sqlStore := mockstore.NewSQLStoreMock()
-		st := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, sqlStore)
+		st := state.NewManager(log.New("test_stale_results_handler"), testMetrics.GetStateMetrics(), nil, dbstore, dbstore, sqlStore, nil)
 		st.Warm(ctx)
 		existingStatesForRule := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
 
@@ -1998,3 +1999,128 @@ func TestStaleResultsHandler(t *testing.T) {
 		assert.Equal(t, tc.finalStateCount, len(existingStatesForRule))
 	}
 }
+
+func TestStateHistory(t *testing.T) {
+	evaluationTime, err := time.Parse("2006-01-02", "2022-01-01")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	fakeAnnoRepo := store.NewFakeAnnotationsRepo()
+	annotations.SetRepository(fakeAnnoRepo)
+	historyStore := store.NewMemoryStateHistoryStore()
+	st := state.NewManager(log.New("test_state_history"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, mockstore.NewSQLStoreMock(), historyStore)
+
+	rule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "test_title",
+		UID:             "test_alert_rule_uid",
+		NamespaceUID:    "test_namespace_uid",
+		Annotations:     map[string]string{},
+		Labels:          map[string]string{"label": "test"},
+		IntervalSeconds: 10,
+		NoDataState:     models.NoData,
+		ExecErrState:    models.ErrorErrState,
+	}
+	instance := data.Labels{"instance_label": "test"}
+	value := math.NaN()
+
+	st.ProcessEvalResults(ctx, rule, eval.Results{{Instance: instance, State: eval.Normal, EvaluatedAt: evaluationTime}})
+	st.ProcessEvalResults(ctx, rule, eval.Results{{
+		Instance:    instance,
+		State:       eval.Alerting,
+		EvaluatedAt: evaluationTime.Add(10 * time.Second),
+		Values:      map[string]eval.NumberValueCapture{"A": {Var: "A", Value: &value}},
+	}})
+	st.ProcessEvalResults(ctx, rule, eval.Results{{Instance: instance, State: eval.Alerting, EvaluatedAt: evaluationTime.Add(20 * time.Second)}})
+	st.ProcessEvalResults(ctx, rule, eval.Results{{Instance: instance, State: eval.Error, Error: errors.New("test error"), EvaluatedAt: evaluationTime.Add(30 * time.Second)}})
+
+	// the history is saved in the background
+	query := &models.GetStateHistoryQuery{OrgID: rule.OrgID}
+	require.Eventually(t, func() bool {
+		require.NoError(t, historyStore.GetStateHistory(ctx, query))
+		return len(query.Result) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	errorEntry := query.Result[0]
+	require.Equal(t, models.InstanceStateFiring, errorEntry.PreviousState)
+	require.Equal(t, models.InstanceStateError, errorEntry.State)
+	require.Equal(t, "test error", errorEntry.Error)
+
+	alertingEntry := query.Result[1]
+	require.Equal(t, rule.UID, alertingEntry.RuleUID)
+	require.Equal(t, models.InstanceStateNormal, alertingEntry.PreviousState)
+	require.Equal(t, models.InstanceStateFiring, alertingEntry.State)
+	require.Equal(t, evaluationTime.Add(10*time.Second), alertingEntry.EvaluatedAt)
+	require.Equal(t, "test", alertingEntry.Labels["instance_label"])
+	require.Equal(t, rule.NamespaceUID, alertingEntry.NamespaceUID)
+	require.Contains(t, alertingEntry.Values, "A")
+	require.Nil(t, alertingEntry.Values["A"])
+}
+
+func TestRuleDependencies(t *testing.T) {
+	evaluationTime, err := time.Parse("2006-01-02", "2022-01-01")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	fakeAnnoRepo := store.NewFakeAnnotationsRepo()
+	annotations.SetRepository(fakeAnnoRepo)
+	st := state.NewManager(log.New("test_rule_dependencies"), testMetrics.GetStateMetrics(), nil, nil, &store.FakeInstanceStore{}, mockstore.NewSQLStoreMock(), nil)
+
+	newRule := func(uid, title string, dependsOn *models.RuleDependencies) *models.AlertRule {
+		return &models.AlertRule{
+			OrgID:           1,
+			Title:           title,
+			UID:             uid,
+			NamespaceUID:    "test_namespace_uid",
+			Annotations:     map[string]string{},
+			Labels:          map[string]string{},
+			IntervalSeconds: 10,
+			NoDataState:     models.NoData,
+			ExecErrState:    models.ErrorErrState,
+			DependsOn:       dependsOn,
+		}
+	}
+	parent := newRule("parent", "DatabaseDown", nil)
+	byUID := newRule("child-by-uid", "HighLatency", &models.RuleDependencies{RuleUIDs: []string{parent.UID}})
+	byMatchers := newRule("child-by-matchers", "HighErrorRate", &models.RuleDependencies{Matchers: []string{`alertname="DatabaseDown"`}})
+	unrelated := newRule("unrelated", "DiskFull", &models.RuleDependencies{Matchers: []string{`alertname="NetworkDown"`}})
+	instance := data.Labels{"instance_label": "test"}
+
+	evaluate := func(rule *models.AlertRule, s eval.State, at time.Duration) *state.State {
+		t.Helper()
+		states := st.ProcessEvalResults(ctx, rule, eval.Results{{Instance: instance, State: s, EvaluatedAt: evaluationTime.Add(at)}})
+		require.Len(t, states, 1)
+		return states[0]
+	}
+
+	for _, child := range []*models.AlertRule{byUID, byMatchers, unrelated} {
+		require.Equal(t, eval.Alerting, evaluate(child, eval.Alerting, 0).State)
+	}
+
+	t.Run("children are inhibited while the parent is firing", func(t *testing.T) {
+		require.Equal(t, eval.Alerting, evaluate(parent, eval.Alerting, 10*time.Second).State)
+
+		for _, child := range []*models.AlertRule{byUID, byMatchers} {
+			s := evaluate(child, eval.Alerting, 10*time.Second)
+			require.Equal(t, eval.Inhibited, s.State)
+			require.True(t, s.Resolved, "the alert must be resolved in Alertmanager")
+			require.True(t, s.NeedsSending(st.ResendDelay))
+
+			s = evaluate(child, eval.Alerting, 20*time.Second)
+			require.Equal(t, eval.Inhibited, s.State)
+			require.False(t, s.Resolved)
+			require.False(t, s.NeedsSending(st.ResendDelay))
+		}
+		require.Equal(t, eval.Alerting, evaluate(unrelated, eval.Alerting, 10*time.Second).State)
+	})
+
+	t.Run("children are evaluated again when the parent is resolved", func(t *testing.T) {
+		require.Equal(t, eval.Normal, evaluate(parent, eval.Normal, 30*time.Second).State)
+
+		for _, child := range []*models.AlertRule{byUID, byMatchers} {
+			s := evaluate(child, eval.Alerting, 30*time.Second)
+			require.Equal(t, eval.Alerting, s.State)
+			require.Equal(t, evaluationTime.Add(30*time.Second), s.StartsAt)
+		}
+	})
+}
@@ -52,6 +52,73 @@ func NewEvaluationValues(m map[string]eval.NumberValueCapture) map[string]*float
 	return result
 }
 
+// FiringDimensions returns the labels of the states that are either pending or alerting.
+// They are passed to threshold expressions, so they can apply their recovery threshold.
+func FiringDimensions(states []*State) []data.Labels {
+	var dims []data.Labels
+	for _, s := range states {
+		if s.State == eval.Alerting || s.State == eval.Pending {
+			dims = append(dims, s.Labels)
+		}
+	}
+	return dims
+}
+
+// applyResult records the evaluation result and moves the state to the next state
+// based on the result and the alert rule. It returns the state before the result was applied.
+func (a *State) applyResult(alertRule *ngModels.AlertRule, result eval.Result) eval.State {
+	oldState := a.recordResult(alertRule, result)
+
+	switch result.State {
+	case eval.Normal:
+		a.resultNormal(alertRule, result)
+	case eval.Alerting:
+		a.resultAlerting(alertRule, result)
+	case eval.Error:
+		a.resultError(alertRule, result)
+	case eval.NoData:
+		a.resultNoData(alertRule, result)
+	case eval.Pending, eval.Inhibited: // we do not emit results with these states
+	}
+
+	// Set Resolved property so the scheduler knows to send a postable alert
+	// to Alertmanager.
+	a.Resolved = oldState == eval.Alerting && a.State == eval.Normal
+	return oldState
+}
+
+// applyInhibition records the evaluation result and moves the state to Inhibited regardless of the result,
+// because a rule the alert rule depends on is firing. It returns the state before the result was applied.
+func (a *State) applyInhibition(alertRule *ngModels.AlertRule, result eval.Result) eval.State {
+	oldState := a.recordResult(alertRule, result)
+
+	a.Error = nil
+	if a.State != eval.Inhibited {
+		a.StartsAt = result.EvaluatedAt
+		a.EndsAt = result.EvaluatedAt
+	}
+	a.State = eval.Inhibited
+
+	// An alert that was firing is resolved in Alertmanager while it is inhibited.
+	a.Resolved = oldState == eval.Alerting
+	return oldState
+}
+
+// recordResult records the evaluation result in the history of evaluations and returns the current state.
+func (a *State) recordResult(alertRule *ngModels.AlertRule, result eval.Result) eval.State {
+	a.LastEvaluationTime = result.EvaluatedAt
+	a.EvaluationDuration = result.EvaluationDuration
+	a.Results = append(a.Results, Evaluation{
+		EvaluationTime:  result.EvaluatedAt,
+		EvaluationState: result.State,
+		Values:          NewEvaluationValues(result.Values),
+		Condition:       alertRule.Condition,
+	})
+	a.LastEvaluationString = result.EvaluationString
+	a.TrimResults(alertRule)
+	return a.State
+}
+
 func (a *State) resultNormal(_ *ngModels.AlertRule, result eval.Result) {
 	a.Error = nil // should be nil since state is not error
 	if a.State != eval.Normal {
@@ -163,7 +230,7 @@ func (a *State) resultNoData(alertRule *ngModels.AlertRule, result eval.Result)
 }
 
 func (a *State) NeedsSending(resendDelay time.Duration) bool {
-	if a.State == eval.Pending || a.State == eval.Normal && !a.Resolved {
+	if a.State == eval.Pending || (a.State == eval.Normal || a.State == eval.Inhibited) && !a.Resolved {
 		return false
 	}
 	// if LastSentAt is before or equal to LastEvaluationTime + resendDelay, send again
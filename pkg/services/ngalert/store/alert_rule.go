@@ -146,6 +146,8 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				For:              r.For,
 				Annotations:      r.Annotations,
 				Labels:           r.Labels,
+				Record:           r.Record,
+				DependsOn:        r.DependsOn,
 			})
 		}
 		if len(newRules) > 0 {
@@ -207,6 +209,8 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []UpdateRule) erro
 				For:              r.New.For,
 				Annotations:      r.New.Annotations,
 				Labels:           r.New.Labels,
+				Record:           r.New.Record,
+				DependsOn:        r.New.DependsOn,
 			})
 		}
 		if len(newRules) > 0 {
@@ -0,0 +1,199 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// ErrNotificationLogEntryNotFound is an error for when an entry of the notification log is not found.
+var ErrNotificationLogEntryNotFound = errors.New("could not find the notification log entry")
+
+// NotificationLogStore is an append-only store of the attempts to deliver notifications.
+type NotificationLogStore interface {
+	// SaveNotificationLogEntry appends the entry to the notification log and sets its ID.
+	SaveNotificationLogEntry(ctx context.Context, entry *models.NotificationLogEntry) error
+	GetNotificationLog(ctx context.Context, query *models.GetNotificationLogQuery) error
+	// GetNotificationLogEntry returns ErrNotificationLogEntryNotFound if the entry does not exist in the organization.
+	GetNotificationLogEntry(ctx context.Context, query *models.GetNotificationLogEntryQuery) error
+	// DeleteNotificationLog deletes the entries sent before the given time and returns how many were deleted.
+	DeleteNotificationLog(ctx context.Context, before time.Time) (int64, error)
+}
+
+type notificationLogRecord struct {
+	ID              int64  `xorm:"pk autoincr 'id'"`
+	OrgID           int64  `xorm:"'org_id'"`
+	Receiver        string `xorm:"'receiver'"`
+	IntegrationType string `xorm:"'integration_type'"`
+	IntegrationName string `xorm:"'integration_name'"`
+	IntegrationUID  string `xorm:"'integration_uid'"`
+	GroupKey        string `xorm:"'group_key'"`
+	GroupLabels     string `xorm:"'group_labels'"`
+	Status          string `xorm:"'status'"`
+	StatusCode      int    `xorm:"'status_code'"`
+	Error           string `xorm:"'error'"`
+	DurationMs      int64  `xorm:"'duration_ms'"`
+	Payload         string `xorm:"'payload'"`
+	Alerts          string `xorm:"'alerts'"`
+	ReplayOf        int64  `xorm:"'replay_of'"`
+	SentAt          int64  `xorm:"'sent_at'"`
+}
+
+func (r notificationLogRecord) TableName() string {
+	return "alert_notification_log"
+}
+
+// SaveNotificationLogEntry appends the entry to the notification log.
+func (st DBstore) SaveNotificationLogEntry(ctx context.Context, entry *models.NotificationLogEntry) error {
+	groupLabels, err := json.Marshal(entry.GroupLabels)
+	if err != nil {
+		return fmt.Errorf("failed to encode the group labels of the notification: %w", err)
+	}
+	alerts, err := json.Marshal(entry.Alerts)
+	if err != nil {
+		return fmt.Errorf("failed to encode the alerts of the notification: %w", err)
+	}
+	record := &notificationLogRecord{
+		OrgID:           entry.OrgID,
+		Receiver:        entry.Receiver,
+		IntegrationType: entry.IntegrationType,
+		IntegrationName: entry.IntegrationName,
+		IntegrationUID:  entry.IntegrationUID,
+		GroupKey:        entry.GroupKey,
+		GroupLabels:     string(groupLabels),
+		Status:          string(entry.Status),
+		StatusCode:      entry.StatusCode,
+		Error:           entry.Error,
+		DurationMs:      entry.Duration.Milliseconds(),
+		Payload:         entry.Payload,
+		Alerts:          string(alerts),
+		ReplayOf:        entry.ReplayOf,
+		SentAt:          entry.SentAt.UnixNano() / int64(time.Millisecond),
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if _, err := sess.Insert(record); err != nil {
+			return fmt.Errorf("failed to save notification log entry: %w", err)
+		}
+		entry.ID = record.ID
+		return nil
+	})
+}
+
+// GetNotificationLog returns the entries of the notification log that match the query.
+func (st DBstore) GetNotificationLog(ctx context.Context, query *models.GetNotificationLogQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		q := sess.Table(notificationLogRecord{}).Where("org_id = ?", query.OrgID)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+		if query.IntegrationType != "" {
+			q = q.And("integration_type = ?", query.IntegrationType)
+		}
+		if query.IntegrationUID != "" {
+			q = q.And("integration_uid = ?", query.IntegrationUID)
+		}
+		if query.Status != "" {
+			q = q.And("status = ?", string(query.Status))
+		}
+		if !query.From.IsZero() {
+			q = q.And("sent_at >= ?", query.From.UnixNano()/int64(time.Millisecond))
+		}
+		if !query.To.IsZero() {
+			q = q.And("sent_at < ?", query.To.UnixNano()/int64(time.Millisecond))
+		}
+		q = q.Desc("sent_at", "id")
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+
+		var records []*notificationLogRecord
+		if err := q.Find(&records); err != nil {
+			return fmt.Errorf("failed to get notification log: %w", err)
+		}
+		result := make([]*models.NotificationLogEntry, 0, len(records))
+		for _, r := range records {
+			entry, err := notificationLogRecordToEntry(r)
+			if err != nil {
+				return err
+			}
+			result = append(result, entry)
+		}
+		query.Result = result
+		return nil
+	})
+}
+
+// GetNotificationLogEntry returns the entry of the notification log with the ID of the query.
+func (st DBstore) GetNotificationLogEntry(ctx context.Context, query *models.GetNotificationLogEntryQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		record := notificationLogRecord{}
+		has, err := sess.Table(notificationLogRecord{}).Where("org_id = ? AND id = ?", query.OrgID, query.ID).Get(&record)
+		if err != nil {
+			return fmt.Errorf("failed to get notification log entry: %w", err)
+		}
+		if !has {
+			return ErrNotificationLogEntryNotFound
+		}
+		entry, err := notificationLogRecordToEntry(&record)
+		if err != nil {
+			return err
+		}
+		query.Result = entry
+		return nil
+	})
+}
+
+// DeleteNotificationLog deletes the entries of the notification log sent before the given time.
+func (st DBstore) DeleteNotificationLog(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_notification_log WHERE sent_at < ?", before.UnixNano()/int64(time.Millisecond))
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
+
+func notificationLogRecordToEntry(r *notificationLogRecord) (*models.NotificationLogEntry, error) {
+	var groupLabels model.LabelSet
+	if r.GroupLabels != "" {
+		if err := json.Unmarshal([]byte(r.GroupLabels), &groupLabels); err != nil {
+			return nil, fmt.Errorf("failed to read the group labels of notification log entry %d: %w", r.ID, err)
+		}
+	}
+	var alerts []*types.Alert
+	if r.Alerts != "" {
+		if err := json.Unmarshal([]byte(r.Alerts), &alerts); err != nil {
+			return nil, fmt.Errorf("failed to read the alerts of notification log entry %d: %w", r.ID, err)
+		}
+	}
+	return &models.NotificationLogEntry{
+		ID:              r.ID,
+		OrgID:           r.OrgID,
+		Receiver:        r.Receiver,
+		IntegrationType: r.IntegrationType,
+		IntegrationName: r.IntegrationName,
+		IntegrationUID:  r.IntegrationUID,
+		GroupKey:        r.GroupKey,
+		GroupLabels:     groupLabels,
+		Status:          models.NotificationStatus(r.Status),
+		StatusCode:      r.StatusCode,
+		Error:           r.Error,
+		Duration:        time.Duration(r.DurationMs) * time.Millisecond,
+		Payload:         r.Payload,
+		Alerts:          alerts,
+		ReplayOf:        r.ReplayOf,
+		SentAt:          time.Unix(0, r.SentAt*int64(time.Millisecond)),
+	}, nil
+}
@@ -0,0 +1,141 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestNotificationLogOperations(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	const orgID int64 = 1
+	start := time.Unix(1640995200, 0)
+	alerts := []*types.Alert{{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "HighLatency"},
+			StartsAt: start,
+		},
+		UpdatedAt: start,
+	}}
+
+	entries := []*models.NotificationLogEntry{
+		{
+			OrgID:           orgID,
+			Receiver:        "ops",
+			IntegrationType: "webhook",
+			IntegrationName: "ops-webhook",
+			IntegrationUID:  "uid-1",
+			GroupKey:        "{}:{alertname=\"HighLatency\"}",
+			GroupLabels:     model.LabelSet{"alertname": "HighLatency"},
+			Status:          models.NotificationStatusFailed,
+			StatusCode:      503,
+			Error:           "webhook response status 503 Service Unavailable",
+			Duration:        120 * time.Millisecond,
+			Payload:         `{"status":"firing"}`,
+			Alerts:          alerts,
+			SentAt:          start,
+		},
+		{
+			OrgID:           orgID,
+			Receiver:        "ops",
+			IntegrationType: "webhook",
+			IntegrationName: "ops-webhook",
+			IntegrationUID:  "uid-1",
+			Status:          models.NotificationStatusSuccess,
+			StatusCode:      200,
+			Duration:        80 * time.Millisecond,
+			Alerts:          alerts,
+			SentAt:          start.Add(time.Minute),
+		},
+		{
+			OrgID:           orgID,
+			Receiver:        "dev",
+			IntegrationType: "email",
+			IntegrationName: "dev-email",
+			IntegrationUID:  "uid-2",
+			Status:          models.NotificationStatusSuccess,
+			Alerts:          alerts,
+			SentAt:          start.Add(2 * time.Minute),
+		},
+		{
+			OrgID:           2,
+			Receiver:        "ops",
+			IntegrationType: "webhook",
+			IntegrationUID:  "uid-3",
+			Status:          models.NotificationStatusFailed,
+			Alerts:          alerts,
+			SentAt:          start,
+		},
+	}
+	for _, e := range entries {
+		require.NoError(t, dbstore.SaveNotificationLogEntry(ctx, e))
+		require.NotZero(t, e.ID)
+	}
+
+	t.Run("returns the entries of the organization from the most recent", func(t *testing.T) {
+		query := &models.GetNotificationLogQuery{OrgID: orgID}
+		require.NoError(t, dbstore.GetNotificationLog(ctx, query))
+		require.Len(t, query.Result, 3)
+		require.Equal(t, "dev", query.Result[0].Receiver)
+		require.Equal(t, models.NotificationStatusFailed, query.Result[2].Status)
+	})
+
+	t.Run("filters the entries", func(t *testing.T) {
+		query := &models.GetNotificationLogQuery{OrgID: orgID, Receiver: "ops", Status: models.NotificationStatusFailed}
+		require.NoError(t, dbstore.GetNotificationLog(ctx, query))
+		require.Len(t, query.Result, 1)
+		e := query.Result[0]
+		require.Equal(t, 503, e.StatusCode)
+		require.Equal(t, 120*time.Millisecond, e.Duration)
+		require.Equal(t, `{"status":"firing"}`, e.Payload)
+		require.Equal(t, start.Unix(), e.SentAt.Unix())
+		require.Equal(t, model.LabelSet{"alertname": "HighLatency"}, e.GroupLabels)
+		require.Len(t, e.Alerts, 1)
+		require.Equal(t, model.LabelValue("HighLatency"), e.Alerts[0].Labels["alertname"])
+
+		query = &models.GetNotificationLogQuery{OrgID: orgID, IntegrationType: "email"}
+		require.NoError(t, dbstore.GetNotificationLog(ctx, query))
+		require.Len(t, query.Result, 1)
+
+		query = &models.GetNotificationLogQuery{OrgID: orgID, From: start.Add(time.Minute), To: start.Add(2 * time.Minute)}
+		require.NoError(t, dbstore.GetNotificationLog(ctx, query))
+		require.Len(t, query.Result, 1)
+		require.Equal(t, models.NotificationStatusSuccess, query.Result[0].Status)
+
+		query = &models.GetNotificationLogQuery{OrgID: orgID, Limit: 2}
+		require.NoError(t, dbstore.GetNotificationLog(ctx, query))
+		require.Len(t, query.Result, 2)
+	})
+
+	t.Run("returns a single entry of the organization", func(t *testing.T) {
+		query := &models.GetNotificationLogEntryQuery{OrgID: orgID, ID: entries[0].ID}
+		require.NoError(t, dbstore.GetNotificationLogEntry(ctx, query))
+		require.Equal(t, "uid-1", query.Result.IntegrationUID)
+
+		query = &models.GetNotificationLogEntryQuery{OrgID: orgID, ID: entries[3].ID}
+		require.ErrorIs(t, dbstore.GetNotificationLogEntry(ctx, query), store.ErrNotificationLogEntryNotFound)
+	})
+
+	t.Run("deletes the entries sent before the given time", func(t *testing.T) {
+		deleted, err := dbstore.DeleteNotificationLog(ctx, start.Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+
+		query := &models.GetNotificationLogQuery{OrgID: orgID}
+		require.NoError(t, dbstore.GetNotificationLog(ctx, query))
+		require.Len(t, query.Result, 2)
+	})
+}
@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// RecordedSampleStore is the internal store of the samples written by recording rules.
+type RecordedSampleStore interface {
+	SaveRecordedSamples(ctx context.Context, samples []models.RecordedSample) error
+	GetRecordedSamples(ctx context.Context, query *models.GetRecordedSamplesQuery) error
+	// DeleteRecordedSamples deletes the samples older than the given time and returns how many were deleted.
+	DeleteRecordedSamples(ctx context.Context, before time.Time) (int64, error)
+}
+
+type recordedSampleRecord struct {
+	ID           int64  `xorm:"pk autoincr 'id'"`
+	OrgID        int64  `xorm:"'org_id'"`
+	RuleUID      string `xorm:"'rule_uid'"`
+	NamespaceUID string `xorm:"'namespace_uid'"`
+	Metric       string
+	Labels       string
+	LabelsHash   string
+	Value        float64
+	EvaluatedAt  int64
+}
+
+func (r recordedSampleRecord) TableName() string {
+	return "alert_recorded_sample"
+}
+
+// SaveRecordedSamples appends the samples to the internal store.
+func (st DBstore) SaveRecordedSamples(ctx context.Context, samples []models.RecordedSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	records := make([]*recordedSampleRecord, 0, len(samples))
+	for _, s := range samples {
+		labels, labelsHash, err := s.Labels.StringAndHash()
+		if err != nil {
+			return err
+		}
+		records = append(records, &recordedSampleRecord{
+			OrgID:        s.OrgID,
+			RuleUID:      s.RuleUID,
+			NamespaceUID: s.NamespaceUID,
+			Metric:       s.Metric,
+			Labels:       labels,
+			LabelsHash:   labelsHash,
+			Value:        s.Value,
+			EvaluatedAt:  s.Timestamp.UnixNano() / int64(time.Millisecond),
+		})
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if _, err := sess.Insert(records); err != nil {
+			return fmt.Errorf("failed to save recorded samples: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetRecordedSamples returns the samples that match the query.
+func (st DBstore) GetRecordedSamples(ctx context.Context, query *models.GetRecordedSamplesQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		q := sess.Table(recordedSampleRecord{}).Where("org_id = ?", query.OrgID)
+		if query.Metric != "" {
+			q = q.And("metric = ?", query.Metric)
+		}
+		if query.NamespaceUIDs != nil {
+			if len(query.NamespaceUIDs) == 0 {
+				query.Result = []*models.RecordedSample{}
+				return nil
+			}
+			args := make([]interface{}, 0, len(query.NamespaceUIDs))
+			for _, uid := range query.NamespaceUIDs {
+				args = append(args, uid)
+			}
+			q = q.In("namespace_uid", args...)
+		}
+		if !query.From.IsZero() {
+			q = q.And("evaluated_at >= ?", query.From.UnixNano()/int64(time.Millisecond))
+		}
+		if !query.To.IsZero() {
+			q = q.And("evaluated_at < ?", query.To.UnixNano()/int64(time.Millisecond))
+		}
+		q = q.Desc("evaluated_at", "id")
+		// labels are matched after the samples are read, so the limit can only be applied in the query without matchers.
+		if query.Limit > 0 && len(query.Matchers) == 0 {
+			q = q.Limit(query.Limit)
+		}
+
+		var records []*recordedSampleRecord
+		if err := q.Find(&records); err != nil {
+			return fmt.Errorf("failed to get recorded samples: %w", err)
+		}
+		result := make([]*models.RecordedSample, 0, len(records))
+		for _, r := range records {
+			if query.Limit > 0 && len(result) >= query.Limit {
+				break
+			}
+			labels := models.InstanceLabels{}
+			if err := labels.FromDB([]byte(r.Labels)); err != nil {
+				return fmt.Errorf("failed to read the labels of recorded sample %d: %w", r.ID, err)
+			}
+			if !query.MatchesLabels(labels) {
+				continue
+			}
+			result = append(result, &models.RecordedSample{
+				ID:           r.ID,
+				OrgID:        r.OrgID,
+				RuleUID:      r.RuleUID,
+				NamespaceUID: r.NamespaceUID,
+				Metric:       r.Metric,
+				Labels:       labels,
+				Value:        r.Value,
+				Timestamp:    time.Unix(0, r.EvaluatedAt*int64(time.Millisecond)),
+			})
+		}
+		query.Result = result
+		return nil
+	})
+}
+
+// DeleteRecordedSamples deletes the samples older than the given time.
+func (st DBstore) DeleteRecordedSamples(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_recorded_sample WHERE evaluated_at < ?", before.UnixNano()/int64(time.Millisecond))
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
@@ -0,0 +1,129 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestRecordingRuleOperations(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 60, 1)
+	require.Nil(t, rule.Record)
+
+	updated := *rule
+	updated.Record = &models.Record{Metric: "job:requests:rate5m", From: "A"}
+	require.NoError(t, dbstore.UpdateAlertRules(ctx, []store.UpdateRule{{Existing: rule, New: updated}}))
+
+	q := &models.GetAlertRuleByUIDQuery{OrgID: rule.OrgID, UID: rule.UID}
+	require.NoError(t, dbstore.GetAlertRuleByUID(ctx, q))
+	require.True(t, q.Result.IsRecordingRule())
+	require.Equal(t, *updated.Record, *q.Result.Record)
+}
+
+func TestRecordedSampleOperations(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	const orgID int64 = 1
+	start := time.Unix(1640995200, 0)
+	samples := []models.RecordedSample{
+		{
+			OrgID:        orgID,
+			RuleUID:      "rule-1",
+			NamespaceUID: "ns-1",
+			Metric:       "job:requests:rate5m",
+			Labels:       models.InstanceLabels{"job": "api"},
+			Value:        1.5,
+			Timestamp:    start,
+		},
+		{
+			OrgID:        orgID,
+			RuleUID:      "rule-1",
+			NamespaceUID: "ns-1",
+			Metric:       "job:requests:rate5m",
+			Labels:       models.InstanceLabels{"job": "web"},
+			Value:        2.5,
+			Timestamp:    start.Add(time.Minute),
+		},
+		{
+			OrgID:        orgID,
+			RuleUID:      "rule-2",
+			NamespaceUID: "ns-2",
+			Metric:       "job:errors:rate5m",
+			Labels:       models.InstanceLabels{"job": "api"},
+			Value:        0,
+			Timestamp:    start.Add(2 * time.Minute),
+		},
+		{
+			OrgID:        orgID + 1,
+			RuleUID:      "rule-3",
+			NamespaceUID: "ns-3",
+			Metric:       "job:requests:rate5m",
+			Labels:       models.InstanceLabels{},
+			Value:        3,
+			Timestamp:    start.Add(3 * time.Minute),
+		},
+	}
+	require.NoError(t, dbstore.SaveRecordedSamples(ctx, samples))
+
+	t.Run("returns the samples of an organization from the most recent", func(t *testing.T) {
+		q := &models.GetRecordedSamplesQuery{OrgID: orgID}
+		require.NoError(t, dbstore.GetRecordedSamples(ctx, q))
+		require.Len(t, q.Result, 3)
+		require.Equal(t, "job:errors:rate5m", q.Result[0].Metric)
+		require.Equal(t, "rule-2", q.Result[0].RuleUID)
+		require.Equal(t, "ns-2", q.Result[0].NamespaceUID)
+		require.True(t, start.Add(2*time.Minute).Equal(q.Result[0].Timestamp))
+		require.Equal(t, 2.5, q.Result[1].Value)
+		require.Equal(t, models.InstanceLabels{"job": "web"}, q.Result[1].Labels)
+	})
+
+	t.Run("filters by metric, labels, namespaces and time", func(t *testing.T) {
+		q := &models.GetRecordedSamplesQuery{OrgID: orgID, Metric: "job:requests:rate5m"}
+		require.NoError(t, dbstore.GetRecordedSamples(ctx, q))
+		require.Len(t, q.Result, 2)
+
+		m, err := labels.NewMatcher(labels.MatchEqual, "job", "api")
+		require.NoError(t, err)
+		q = &models.GetRecordedSamplesQuery{OrgID: orgID, Matchers: []*labels.Matcher{m}, Limit: 1}
+		require.NoError(t, dbstore.GetRecordedSamples(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, "job:errors:rate5m", q.Result[0].Metric)
+
+		q = &models.GetRecordedSamplesQuery{OrgID: orgID, NamespaceUIDs: []string{"ns-1"}}
+		require.NoError(t, dbstore.GetRecordedSamples(ctx, q))
+		require.Len(t, q.Result, 2)
+
+		q = &models.GetRecordedSamplesQuery{OrgID: orgID, NamespaceUIDs: []string{}}
+		require.NoError(t, dbstore.GetRecordedSamples(ctx, q))
+		require.Len(t, q.Result, 0)
+
+		q = &models.GetRecordedSamplesQuery{OrgID: orgID, From: start.Add(time.Minute), To: start.Add(2 * time.Minute)}
+		require.NoError(t, dbstore.GetRecordedSamples(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, 2.5, q.Result[0].Value)
+	})
+
+	t.Run("deletes the samples before the retention", func(t *testing.T) {
+		deleted, err := dbstore.DeleteRecordedSamples(ctx, start.Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		q := &models.GetRecordedSamplesQuery{OrgID: orgID}
+		require.NoError(t, dbstore.GetRecordedSamples(ctx, q))
+		require.Len(t, q.Result, 2)
+	})
+}
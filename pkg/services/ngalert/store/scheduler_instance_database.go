@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// SchedulerInstanceStore keeps the heartbeats of the schedulers of a HA cluster.
+type SchedulerInstanceStore interface {
+	// HeartbeatSchedulerInstance records that the scheduler is active at the given time.
+	HeartbeatSchedulerInstance(ctx context.Context, instanceID string, at time.Time) error
+	// GetActiveSchedulerInstances returns the IDs of the schedulers whose last heartbeat is not older than the given time.
+	GetActiveSchedulerInstances(ctx context.Context, since time.Time) ([]string, error)
+	// DeleteSchedulerInstance removes the scheduler from the cluster.
+	DeleteSchedulerInstance(ctx context.Context, instanceID string) error
+}
+
+type schedulerInstanceRecord struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	InstanceID    string `xorm:"'instance_id'"`
+	LastHeartbeat int64
+}
+
+func (r schedulerInstanceRecord) TableName() string {
+	return "alert_scheduler_instance"
+}
+
+// HeartbeatSchedulerInstance updates the heartbeat of the scheduler, or registers the scheduler if it is not known yet.
+func (st DBstore) HeartbeatSchedulerInstance(ctx context.Context, instanceID string, at time.Time) error {
+	heartbeat := at.UnixNano() / int64(time.Millisecond)
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		exists, err := sess.Table(schedulerInstanceRecord{}).Where("instance_id = ?", instanceID).Exist()
+		if err != nil {
+			return fmt.Errorf("failed to get scheduler instance: %w", err)
+		}
+		if exists {
+			if _, err := sess.Exec("UPDATE alert_scheduler_instance SET last_heartbeat = ? WHERE instance_id = ?", heartbeat, instanceID); err != nil {
+				return fmt.Errorf("failed to update scheduler instance heartbeat: %w", err)
+			}
+			return nil
+		}
+		if _, err := sess.Insert(&schedulerInstanceRecord{InstanceID: instanceID, LastHeartbeat: heartbeat}); err != nil {
+			if st.SQLStore.Dialect.IsUniqueConstraintViolation(err) {
+				// another heartbeat of the same scheduler registered it concurrently.
+				return nil
+			}
+			return fmt.Errorf("failed to register scheduler instance: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetActiveSchedulerInstances returns the IDs of the schedulers that sent a heartbeat since the given time, sorted by ID.
+func (st DBstore) GetActiveSchedulerInstances(ctx context.Context, since time.Time) ([]string, error) {
+	var instances []string
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var records []*schedulerInstanceRecord
+		if err := sess.Table(schedulerInstanceRecord{}).Where("last_heartbeat >= ?", since.UnixNano()/int64(time.Millisecond)).Asc("instance_id").Find(&records); err != nil {
+			return fmt.Errorf("failed to get scheduler instances: %w", err)
+		}
+		instances = make([]string, 0, len(records))
+		for _, r := range records {
+			instances = append(instances, r.InstanceID)
+		}
+		return nil
+	})
+	return instances, err
+}
+
+// DeleteSchedulerInstance deletes the heartbeat of the scheduler.
+func (st DBstore) DeleteSchedulerInstance(ctx context.Context, instanceID string) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Exec("DELETE FROM alert_scheduler_instance WHERE instance_id = ?", instanceID)
+		return err
+	})
+}
@@ -0,0 +1,46 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestSchedulerInstanceOperations(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	now := time.Unix(1640995200, 0)
+	require.NoError(t, dbstore.HeartbeatSchedulerInstance(ctx, "instance-b", now.Add(-time.Minute)))
+	require.NoError(t, dbstore.HeartbeatSchedulerInstance(ctx, "instance-a", now.Add(-time.Minute)))
+	require.NoError(t, dbstore.HeartbeatSchedulerInstance(ctx, "instance-c", now.Add(-time.Hour)))
+
+	instances, err := dbstore.GetActiveSchedulerInstances(ctx, now.Add(-2*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, []string{"instance-a", "instance-b"}, instances)
+
+	t.Run("heartbeat of a known instance updates it", func(t *testing.T) {
+		require.NoError(t, dbstore.HeartbeatSchedulerInstance(ctx, "instance-c", now))
+		instances, err := dbstore.GetActiveSchedulerInstances(ctx, now.Add(-2*time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, []string{"instance-a", "instance-b", "instance-c"}, instances)
+
+		instances, err = dbstore.GetActiveSchedulerInstances(ctx, now)
+		require.NoError(t, err)
+		require.Equal(t, []string{"instance-c"}, instances)
+	})
+
+	t.Run("deleted instance is not active", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteSchedulerInstance(ctx, "instance-a"))
+		instances, err := dbstore.GetActiveSchedulerInstances(ctx, now.Add(-2*time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, []string{"instance-b", "instance-c"}, instances)
+	})
+}
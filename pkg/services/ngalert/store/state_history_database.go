@@ -0,0 +1,196 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// stateHistoryPageSize is the number of entries read at once when the entries are filtered by their labels.
+const stateHistoryPageSize = 1000
+
+// StateHistoryStore is an append-only store of the state transitions of alert instances.
+type StateHistoryStore interface {
+	SaveStateHistory(ctx context.Context, entries []models.StateHistoryEntry) error
+	GetStateHistory(ctx context.Context, query *models.GetStateHistoryQuery) error
+	// DeleteStateHistory deletes the entries evaluated before the given time and returns how many were deleted.
+	DeleteStateHistory(ctx context.Context, before time.Time) (int64, error)
+}
+
+type stateHistoryRecord struct {
+	ID               int64  `xorm:"pk autoincr 'id'"`
+	OrgID            int64  `xorm:"'org_id'"`
+	RuleUID          string `xorm:"'rule_uid'"`
+	NamespaceUID     string `xorm:"'namespace_uid'"`
+	Labels           string
+	LabelsHash       string
+	PreviousState    string
+	State            string
+	EvaluationValues string
+	Error            string
+	EvaluatedAt      int64
+}
+
+func (r stateHistoryRecord) TableName() string {
+	return "alert_state_history"
+}
+
+// SaveStateHistory appends the entries to the state history.
+func (st DBstore) SaveStateHistory(ctx context.Context, entries []models.StateHistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	records := make([]*stateHistoryRecord, 0, len(entries))
+	for _, e := range entries {
+		labels, labelsHash, err := e.Labels.StringAndHash()
+		if err != nil {
+			return err
+		}
+		values, err := marshalEvaluationValues(e.Values)
+		if err != nil {
+			return err
+		}
+		records = append(records, &stateHistoryRecord{
+			OrgID:            e.OrgID,
+			RuleUID:          e.RuleUID,
+			NamespaceUID:     e.NamespaceUID,
+			Labels:           labels,
+			LabelsHash:       labelsHash,
+			PreviousState:    string(e.PreviousState),
+			State:            string(e.State),
+			EvaluationValues: values,
+			Error:            e.Error,
+			EvaluatedAt:      e.EvaluatedAt.UnixNano() / int64(time.Millisecond),
+		})
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if _, err := sess.Insert(records); err != nil {
+			return fmt.Errorf("failed to save state history: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetStateHistory returns the entries of the state history that match the query.
+func (st DBstore) GetStateHistory(ctx context.Context, query *models.GetStateHistoryQuery) error {
+	if query.NamespaceUIDs != nil && len(query.NamespaceUIDs) == 0 {
+		query.Result = []*models.StateHistoryEntry{}
+		return nil
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		// the conditions of a session are reset after each query, so they are set again for each page.
+		find := func(records *[]*stateHistoryRecord, limit, offset int) error {
+			q := sess.Table(stateHistoryRecord{}).Where("org_id = ?", query.OrgID)
+			if query.RuleUID != "" {
+				q = q.And("rule_uid = ?", query.RuleUID)
+			}
+			if query.NamespaceUIDs != nil {
+				args := make([]interface{}, 0, len(query.NamespaceUIDs))
+				for _, uid := range query.NamespaceUIDs {
+					args = append(args, uid)
+				}
+				q = q.In("namespace_uid", args...)
+			}
+			if query.State != "" {
+				q = q.And("state = ?", string(query.State))
+			}
+			if !query.From.IsZero() {
+				q = q.And("evaluated_at >= ?", query.From.UnixNano()/int64(time.Millisecond))
+			}
+			if !query.To.IsZero() {
+				q = q.And("evaluated_at < ?", query.To.UnixNano()/int64(time.Millisecond))
+			}
+			if err := q.Desc("evaluated_at", "id").Limit(limit, offset).Find(records); err != nil {
+				return fmt.Errorf("failed to get state history: %w", err)
+			}
+			return nil
+		}
+
+		// labels are matched after the entries are read, so with matchers the entries are read a page at a time
+		// until enough of them match.
+		pageSize := query.Limit
+		if query.HasLabelFilters() || pageSize <= 0 {
+			pageSize = stateHistoryPageSize
+		}
+		result := make([]*models.StateHistoryEntry, 0)
+		for offset := 0; ; offset += pageSize {
+			var records []*stateHistoryRecord
+			if err := find(&records, pageSize, offset); err != nil {
+				return err
+			}
+			for _, r := range records {
+				entry, err := stateHistoryRecordToEntry(r)
+				if err != nil {
+					return err
+				}
+				if !query.MatchesLabels(entry.Labels) {
+					continue
+				}
+				result = append(result, entry)
+				if query.Limit > 0 && len(result) >= query.Limit {
+					query.Result = result
+					return nil
+				}
+			}
+			if len(records) < pageSize {
+				break
+			}
+		}
+		query.Result = result
+		return nil
+	})
+}
+
+// DeleteStateHistory deletes the entries of the state history evaluated before the given time.
+func (st DBstore) DeleteStateHistory(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_state_history WHERE evaluated_at < ?", before.UnixNano()/int64(time.Millisecond))
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
+
+func stateHistoryRecordToEntry(r *stateHistoryRecord) (*models.StateHistoryEntry, error) {
+	labels := models.InstanceLabels{}
+	if err := labels.FromDB([]byte(r.Labels)); err != nil {
+		return nil, fmt.Errorf("failed to read the labels of state history entry %d: %w", r.ID, err)
+	}
+	var values map[string]*float64
+	if r.EvaluationValues != "" {
+		if err := json.Unmarshal([]byte(r.EvaluationValues), &values); err != nil {
+			return nil, fmt.Errorf("failed to read the values of state history entry %d: %w", r.ID, err)
+		}
+	}
+	return &models.StateHistoryEntry{
+		ID:            r.ID,
+		OrgID:         r.OrgID,
+		RuleUID:       r.RuleUID,
+		NamespaceUID:  r.NamespaceUID,
+		Labels:        labels,
+		PreviousState: models.InstanceStateType(r.PreviousState),
+		State:         models.InstanceStateType(r.State),
+		Values:        values,
+		Error:         r.Error,
+		EvaluatedAt:   time.Unix(0, r.EvaluatedAt*int64(time.Millisecond)),
+	}, nil
+}
+
+func marshalEvaluationValues(values map[string]*float64) (string, error) {
+	if len(values) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode evaluation values: %w", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,141 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestStateHistoryOperations(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	testStateHistoryStore(t, ctx, dbstore)
+}
+
+func TestMemoryStateHistoryStore(t *testing.T) {
+	testStateHistoryStore(t, context.Background(), store.NewMemoryStateHistoryStore())
+}
+
+func testStateHistoryStore(t *testing.T, ctx context.Context, st store.StateHistoryStore) {
+	const orgID int64 = 1
+	start := time.Unix(1640995200, 0)
+	value := 42.0
+
+	entries := []models.StateHistoryEntry{
+		{
+			OrgID:         orgID,
+			RuleUID:       "rule-1",
+			NamespaceUID:  "ns-1",
+			Labels:        models.InstanceLabels{"team": "ops"},
+			PreviousState: models.InstanceStateNormal,
+			State:         models.InstanceStatePending,
+			Values:        map[string]*float64{"A": &value},
+			EvaluatedAt:   start,
+		},
+		{
+			OrgID:         orgID,
+			RuleUID:       "rule-1",
+			NamespaceUID:  "ns-1",
+			Labels:        models.InstanceLabels{"team": "ops"},
+			PreviousState: models.InstanceStatePending,
+			State:         models.InstanceStateFiring,
+			Values:        map[string]*float64{"A": nil},
+			EvaluatedAt:   start.Add(time.Minute),
+		},
+		{
+			OrgID:         orgID,
+			RuleUID:       "rule-2",
+			NamespaceUID:  "ns-2",
+			Labels:        models.InstanceLabels{"team": "dev"},
+			PreviousState: models.InstanceStateNormal,
+			State:         models.InstanceStateError,
+			Error:         "failed to query data",
+			EvaluatedAt:   start.Add(2 * time.Minute),
+		},
+		{
+			OrgID:         orgID + 1,
+			RuleUID:       "rule-3",
+			Labels:        models.InstanceLabels{},
+			PreviousState: models.InstanceStateNormal,
+			State:         models.InstanceStateFiring,
+			EvaluatedAt:   start.Add(3 * time.Minute),
+		},
+	}
+	require.NoError(t, st.SaveStateHistory(ctx, entries))
+
+	t.Run("returns the history of an organization from the most recent entry", func(t *testing.T) {
+		q := &models.GetStateHistoryQuery{OrgID: orgID}
+		require.NoError(t, st.GetStateHistory(ctx, q))
+		require.Len(t, q.Result, 3)
+		require.Equal(t, "rule-2", q.Result[0].RuleUID)
+		require.Equal(t, models.InstanceStateError, q.Result[0].State)
+		require.Equal(t, "failed to query data", q.Result[0].Error)
+		require.True(t, start.Add(2*time.Minute).Equal(q.Result[0].EvaluatedAt))
+		require.Equal(t, models.InstanceStatePending, q.Result[1].PreviousState)
+		require.Nil(t, q.Result[1].Values["A"])
+		require.Equal(t, value, *q.Result[2].Values["A"])
+		require.Equal(t, entries[2].Labels, q.Result[0].Labels)
+	})
+
+	t.Run("filters by rule, state and time", func(t *testing.T) {
+		q := &models.GetStateHistoryQuery{OrgID: orgID, RuleUID: "rule-1"}
+		require.NoError(t, st.GetStateHistory(ctx, q))
+		require.Len(t, q.Result, 2)
+
+		q = &models.GetStateHistoryQuery{OrgID: orgID, State: models.InstanceStateFiring}
+		require.NoError(t, st.GetStateHistory(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, "rule-1", q.Result[0].RuleUID)
+
+		q = &models.GetStateHistoryQuery{OrgID: orgID, From: start.Add(time.Minute), To: start.Add(2 * time.Minute)}
+		require.NoError(t, st.GetStateHistory(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, models.InstanceStateFiring, q.Result[0].State)
+	})
+
+	t.Run("filters by labels and namespaces", func(t *testing.T) {
+		m, err := labels.NewMatcher(labels.MatchRegexp, "team", "o.*")
+		require.NoError(t, err)
+		q := &models.GetStateHistoryQuery{OrgID: orgID, Matchers: []*labels.Matcher{m}, Limit: 1}
+		require.NoError(t, st.GetStateHistory(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, models.InstanceStateFiring, q.Result[0].State)
+
+		q = &models.GetStateHistoryQuery{OrgID: orgID, NamespaceUIDs: []string{"ns-2"}}
+		require.NoError(t, st.GetStateHistory(ctx, q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, "rule-2", q.Result[0].RuleUID)
+		require.Equal(t, "ns-2", q.Result[0].NamespaceUID)
+
+		q = &models.GetStateHistoryQuery{OrgID: orgID, NamespaceUIDs: []string{"ns-1", "ns-2"}, Limit: 2}
+		require.NoError(t, st.GetStateHistory(ctx, q))
+		require.Len(t, q.Result, 2)
+		require.Equal(t, "rule-2", q.Result[0].RuleUID)
+		require.Equal(t, models.InstanceStateFiring, q.Result[1].State)
+
+		q = &models.GetStateHistoryQuery{OrgID: orgID, NamespaceUIDs: []string{}}
+		require.NoError(t, st.GetStateHistory(ctx, q))
+		require.Len(t, q.Result, 0)
+	})
+
+	t.Run("deletes the entries before the retention", func(t *testing.T) {
+		deleted, err := st.DeleteStateHistory(ctx, start.Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		q := &models.GetStateHistoryQuery{OrgID: orgID}
+		require.NoError(t, st.GetStateHistory(ctx, q))
+		require.Len(t, q.Result, 2)
+	})
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// MemoryStateHistoryStore keeps the state history in memory. It is a stand-in for an external
+// log store, such as Loki, for single instance setups. The history is lost on restart.
+type MemoryStateHistoryStore struct {
+	mtx     sync.RWMutex
+	lastID  int64
+	entries []models.StateHistoryEntry
+}
+
+// NewMemoryStateHistoryStore returns an empty MemoryStateHistoryStore.
+func NewMemoryStateHistoryStore() *MemoryStateHistoryStore {
+	return &MemoryStateHistoryStore{}
+}
+
+func (st *MemoryStateHistoryStore) SaveStateHistory(_ context.Context, entries []models.StateHistoryEntry) error {
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
+	for _, e := range entries {
+		st.lastID++
+		e.ID = st.lastID
+		// entries are saved in the background, so they may be saved out of the order of evaluation.
+		i := sort.Search(len(st.entries), func(i int) bool {
+			return st.entries[i].EvaluatedAt.After(e.EvaluatedAt)
+		})
+		st.entries = append(st.entries, models.StateHistoryEntry{})
+		copy(st.entries[i+1:], st.entries[i:])
+		st.entries[i] = e
+	}
+	return nil
+}
+
+func (st *MemoryStateHistoryStore) GetStateHistory(_ context.Context, query *models.GetStateHistoryQuery) error {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
+	result := make([]*models.StateHistoryEntry, 0)
+	// entries are kept in the order of evaluation, so they are read backwards.
+	for i := len(st.entries) - 1; i >= 0; i-- {
+		if query.Limit > 0 && len(result) >= query.Limit {
+			break
+		}
+		e := st.entries[i]
+		if query.Matches(&e) {
+			result = append(result, &e)
+		}
+	}
+	query.Result = result
+	return nil
+}
+
+func (st *MemoryStateHistoryStore) DeleteStateHistory(_ context.Context, before time.Time) (int64, error) {
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
+	kept := st.entries[:0]
+	for _, e := range st.entries {
+		if !e.EvaluatedAt.Before(before) {
+			kept = append(kept, e)
+		}
+	}
+	deleted := int64(len(st.entries) - len(kept))
+	st.entries = kept
+	return deleted, nil
+}
@@ -8,8 +8,10 @@ import (
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/util"
@@ -320,6 +322,109 @@ func (f *FakeInstanceStore) DeleteAlertInstance(_ context.Context, _ int64, _, _
 	return nil
 }
 
+// FakeRecordedSampleStore is an in-memory RecordedSampleStore.
+type FakeRecordedSampleStore struct {
+	mtx     sync.Mutex
+	Samples []models.RecordedSample
+}
+
+func (f *FakeRecordedSampleStore) SaveRecordedSamples(_ context.Context, samples []models.RecordedSample) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.Samples = append(f.Samples, samples...)
+	return nil
+}
+
+func (f *FakeRecordedSampleStore) GetRecordedSamples(_ context.Context, q *models.GetRecordedSamplesQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	result := make([]*models.RecordedSample, 0)
+	for i := len(f.Samples) - 1; i >= 0; i-- {
+		if q.Limit > 0 && len(result) >= q.Limit {
+			break
+		}
+		s := f.Samples[i]
+		if q.Matches(&s) {
+			result = append(result, &s)
+		}
+	}
+	q.Result = result
+	return nil
+}
+
+func (f *FakeRecordedSampleStore) DeleteRecordedSamples(_ context.Context, before time.Time) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	kept := make([]models.RecordedSample, 0, len(f.Samples))
+	for _, s := range f.Samples {
+		if !s.Timestamp.Before(before) {
+			kept = append(kept, s)
+		}
+	}
+	deleted := int64(len(f.Samples) - len(kept))
+	f.Samples = kept
+	return deleted, nil
+}
+
+// FakeNotificationLogStore is an in-memory NotificationLogStore.
+type FakeNotificationLogStore struct {
+	mtx     sync.Mutex
+	lastID  int64
+	Entries []models.NotificationLogEntry
+}
+
+func (f *FakeNotificationLogStore) SaveNotificationLogEntry(_ context.Context, entry *models.NotificationLogEntry) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.lastID++
+	entry.ID = f.lastID
+	f.Entries = append(f.Entries, *entry)
+	return nil
+}
+
+func (f *FakeNotificationLogStore) GetNotificationLog(_ context.Context, q *models.GetNotificationLogQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	result := make([]*models.NotificationLogEntry, 0)
+	for i := len(f.Entries) - 1; i >= 0; i-- {
+		if q.Limit > 0 && len(result) >= q.Limit {
+			break
+		}
+		e := f.Entries[i]
+		if q.Matches(&e) {
+			result = append(result, &e)
+		}
+	}
+	q.Result = result
+	return nil
+}
+
+func (f *FakeNotificationLogStore) GetNotificationLogEntry(_ context.Context, q *models.GetNotificationLogEntryQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for _, e := range f.Entries {
+		if e.OrgID == q.OrgID && e.ID == q.ID {
+			q.Result = &e
+			return nil
+		}
+	}
+	return ErrNotificationLogEntryNotFound
+}
+
+func (f *FakeNotificationLogStore) DeleteNotificationLog(_ context.Context, before time.Time) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	kept := make([]models.NotificationLogEntry, 0, len(f.Entries))
+	for _, e := range f.Entries {
+		if !e.SentAt.Before(before) {
+			kept = append(kept, e)
+		}
+	}
+	deleted := int64(len(f.Entries) - len(kept))
+	f.Entries = kept
+	return deleted, nil
+}
+
 func NewFakeAdminConfigStore(t *testing.T) *FakeAdminConfigStore {
 	t.Helper()
 	return &FakeAdminConfigStore{Configs: map[int64]*models.AdminConfiguration{}}
@@ -477,3 +582,40 @@ func (repo *FakeAnnotationsRepo) FindTags(_ context.Context, query *annotations.
 	}
 	return result, nil
 }
+
+// FakeSchedulerInstanceStore is an in-memory SchedulerInstanceStore.
+type FakeSchedulerInstanceStore struct {
+	mtx        sync.Mutex
+	Heartbeats map[string]time.Time
+}
+
+func NewFakeSchedulerInstanceStore() *FakeSchedulerInstanceStore {
+	return &FakeSchedulerInstanceStore{Heartbeats: map[string]time.Time{}}
+}
+
+func (f *FakeSchedulerInstanceStore) HeartbeatSchedulerInstance(_ context.Context, instanceID string, at time.Time) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.Heartbeats[instanceID] = at
+	return nil
+}
+
+func (f *FakeSchedulerInstanceStore) GetActiveSchedulerInstances(_ context.Context, since time.Time) ([]string, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	instances := make([]string, 0, len(f.Heartbeats))
+	for id, heartbeat := range f.Heartbeats {
+		if !heartbeat.Before(since) {
+			instances = append(instances, id)
+		}
+	}
+	sort.Strings(instances)
+	return instances, nil
+}
+
+func (f *FakeSchedulerInstanceStore) DeleteSchedulerInstance(_ context.Context, instanceID string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	delete(f.Heartbeats, instanceID)
+	return nil
+}
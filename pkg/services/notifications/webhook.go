@@ -25,6 +25,9 @@ type Webhook struct {
 	// Validation is a function that will validate the response body and statusCode of the webhook. Any returned error will cause the webhook request to be considered failed.
 	// This can be useful when a webhook service communicates failures in creative ways, such as using the response body instead of the status code.
 	Validation func(body []byte, statusCode int) error
+
+	// TLSConfig replaces the TLS configuration of the client if it is set, e.g. to authenticate with a client certificate.
+	TLSConfig *tls.Config
 }
 
 // WebhookClient exists to mock the client in tests.
@@ -32,6 +35,26 @@ type WebhookClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// SentWebhook records the request body and the response status of the webhooks that are sent
+// with a context returned by WithSentWebhook.
+type SentWebhook struct {
+	Body       []byte
+	StatusCode int
+}
+
+type sentWebhookKey struct{}
+
+// WithSentWebhook returns a context in which the sent webhooks are recorded in w.
+func WithSentWebhook(ctx context.Context, w *SentWebhook) context.Context {
+	return context.WithValue(ctx, sentWebhookKey{}, w)
+}
+
+// SentWebhookFromContext returns the SentWebhook of the context, or nil if there is none.
+func SentWebhookFromContext(ctx context.Context) *SentWebhook {
+	w, _ := ctx.Value(sentWebhookKey{}).(*SentWebhook)
+	return w
+}
+
 var netTransport = &http.Transport{
 	TLSClientConfig: &tls.Config{
 		Renegotiation: tls.RenegotiateFreelyAsClient,
@@ -47,6 +70,20 @@ var netClient WebhookClient = &http.Client{
 	Transport: netTransport,
 }
 
+// newTLSClient returns a client with the same settings as the default client, except for the TLS configuration.
+// The client is used for a single request, so its connections are not kept alive: they would stay open after
+// the client is discarded.
+func newTLSClient(tlsCfg *tls.Config) *http.Client {
+	transport := netTransport.Clone()
+	transport.TLSClientConfig = tlsCfg.Clone()
+	transport.TLSClientConfig.Renegotiation = tls.RenegotiateFreelyAsClient
+	transport.DisableKeepAlives = true
+	return &http.Client{
+		Timeout:   time.Second * 30,
+		Transport: transport,
+	}
+}
+
 func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *Webhook) error {
 	if webhook.HttpMethod == "" {
 		webhook.HttpMethod = http.MethodPost
@@ -78,10 +115,22 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 		request.Header.Set(k, v)
 	}
 
-	resp, err := netClient.Do(request)
+	recorded := SentWebhookFromContext(ctx)
+	if recorded != nil {
+		recorded.Body = []byte(webhook.Body)
+	}
+
+	client := netClient
+	if webhook.TLSConfig != nil {
+		client = newTLSClient(webhook.TLSConfig)
+	}
+	resp, err := client.Do(request)
 	if err != nil {
 		return err
 	}
+	if recorded != nil {
+		recorded.StatusCode = resp.StatusCode
+	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
 			ns.log.Warn("Failed to close response body", "err", err)
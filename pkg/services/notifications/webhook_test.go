@@ -0,0 +1,62 @@
+package notifications
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestSendWebRequestSync_TLSConfig(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	ns := &NotificationService{log: log.New("notifications")}
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	t.Run("uses the TLS configuration of the webhook", func(t *testing.T) {
+		resp := &SentWebhook{}
+		err := ns.sendWebRequestSync(WithSentWebhook(context.Background(), resp), &Webhook{
+			Url:  server.URL,
+			Body: "{}",
+			TLSConfig: &tls.Config{
+				RootCAs:      pool,
+				Certificates: server.TLS.Certificates,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("does not keep the connections of the TLS clients alive", func(t *testing.T) {
+		client := newTLSClient(&tls.Config{RootCAs: pool})
+		require.True(t, client.Transport.(*http.Transport).DisableKeepAlives)
+	})
+
+	t.Run("fails without a client certificate", func(t *testing.T) {
+		err := ns.sendWebRequestSync(context.Background(), &Webhook{
+			Url:       server.URL,
+			Body:      "{}",
+			TLSConfig: &tls.Config{RootCAs: pool},
+		})
+		require.EqualError(t, err, "webhook response status 401 Unauthorized")
+	})
+
+	t.Run("fails to verify the server with the default configuration", func(t *testing.T) {
+		err := ns.sendWebRequestSync(context.Background(), &Webhook{Url: server.URL, Body: "{}"})
+		require.Error(t, err)
+	})
+}
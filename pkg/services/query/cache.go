@@ -0,0 +1,223 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// queryCacheTTLKey is the key of the JSON data of a data source that sets the time to live of its cached
+// query results. Caching is disabled for the data sources without it.
+const queryCacheTTLKey = "queryCacheTTL"
+
+const queryCacheKeyPrefix = "query-result-"
+
+// maxCachedQueryResultSize is the maximum size of a cached query result. It is the size of the data column of
+// the database remote cache on MySQL, so that the results fit in every remote cache.
+const maxCachedQueryResultSize = 64 * 1024
+
+var (
+	queryCacheHits   *prometheus.CounterVec
+	queryCacheMisses *prometheus.CounterVec
+)
+
+func init() {
+	queryCacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "query_cache_hits_total",
+		Help:      "Number of data source queries answered from the query result cache",
+		Namespace: "grafana",
+	}, []string{"datasource_type"})
+
+	queryCacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "query_cache_misses_total",
+		Help:      "Number of data source queries not found in the query result cache",
+		Namespace: "grafana",
+	}, []string{"datasource_type"})
+}
+
+// ResultCache stores the responses of data source queries.
+type ResultCache interface {
+	// Get returns the response stored under the key, or nil if there is none.
+	Get(ctx context.Context, key string) (*backend.QueryDataResponse, error)
+	// Set stores the response under the key for the duration of ttl. Responses that are too large to be
+	// stored are not cached.
+	Set(ctx context.Context, key string, resp *backend.QueryDataResponse, ttl time.Duration) error
+}
+
+type remoteResultCache struct {
+	storage remotecache.CacheStorage
+}
+
+// NewRemoteResultCache returns a ResultCache that stores the responses in the remote cache.
+func NewRemoteResultCache(storage remotecache.CacheStorage) ResultCache {
+	return &remoteResultCache{storage: storage}
+}
+
+func (c *remoteResultCache) Get(ctx context.Context, key string) (*backend.QueryDataResponse, error) {
+	value, err := c.storage.Get(ctx, queryCacheKeyPrefix+key)
+	if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, ok := value.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type of cached query result: %T", value)
+	}
+	resp := &backend.QueryDataResponse{}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *remoteResultCache) Set(ctx context.Context, key string, resp *backend.QueryDataResponse, ttl time.Duration) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxCachedQueryResultSize {
+		return nil
+	}
+	return c.storage.Set(ctx, queryCacheKeyPrefix+key, data, ttl)
+}
+
+// queryCacheTTL returns the time to live of the cached query results of the data source, or 0 if they are not cached.
+func (s *Service) queryCacheTTL(ds *models.DataSource) time.Duration {
+	if s.resultCache == nil || ds.JsonData == nil {
+		return 0
+	}
+	value := ds.JsonData.Get(queryCacheTTLKey).MustString()
+	if value == "" {
+		return 0
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		s.log.Warn("Invalid query cache TTL of data source", "uid", ds.Uid, "ttl", value, "error", err)
+		return 0
+	}
+	return ttl
+}
+
+// cachedQueryData returns the response of the request from the result cache, or queries the data source and
+// caches its response. The responses with errors are not cached. If skipCache is true the cache is not read,
+// but the response is still cached.
+func (s *Service) cachedQueryData(ctx context.Context, ds *models.DataSource, req *backend.QueryDataRequest, ttl time.Duration, skipCache bool) (*backend.QueryDataResponse, error) {
+	alignTimeRanges(req.Queries, ttl, time.Now())
+	key, err := queryCacheKey(ds, req)
+	if err != nil {
+		s.log.Warn("Failed to compute the cache key of the queries", "uid", ds.Uid, "error", err)
+		return s.pluginClient.QueryData(ctx, req)
+	}
+
+	if !skipCache {
+		resp, err := s.resultCache.Get(ctx, key)
+		if err != nil {
+			s.log.Warn("Failed to get query result from the cache", "uid", ds.Uid, "error", err)
+		}
+		if resp != nil {
+			queryCacheHits.WithLabelValues(ds.Type).Inc()
+			return resp, nil
+		}
+		queryCacheMisses.WithLabelValues(ds.Type).Inc()
+	}
+
+	resp, err := s.pluginClient.QueryData(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	for _, r := range resp.Responses {
+		if r.Error != nil {
+			return resp, nil
+		}
+	}
+	if err := s.resultCache.Set(ctx, key, resp, ttl); err != nil {
+		s.log.Warn("Failed to store query result in the cache", "uid", ds.Uid, "error", err)
+	}
+	return resp, nil
+}
+
+// alignTimeRanges widens the time ranges that end less than ttl ago to multiples of ttl, so that the same
+// relative time range, e.g. the last hour, has the same cache key for ttl and the response still covers it.
+func alignTimeRanges(queries []backend.DataQuery, ttl time.Duration, now time.Time) {
+	for i := range queries {
+		tr := &queries[i].TimeRange
+		if now.Sub(tr.To) >= ttl {
+			continue
+		}
+		tr.From = tr.From.Truncate(ttl)
+		if to := tr.To.Truncate(ttl); !to.Equal(tr.To) {
+			tr.To = to.Add(ttl)
+		}
+	}
+}
+
+// queryCacheKey returns the cache key of the queries of the request to the data source. The key contains the
+// user and the headers of the request, since data sources can return different results for them. The JSON
+// models of the queries are normalized, so that the order of their properties and the properties that
+// identify a request do not matter.
+func queryCacheKey(ds *models.DataSource, req *backend.QueryDataRequest) (string, error) {
+	h := sha256.New()
+	writeKeyParts(h, ds.OrgId, ds.Uid, ds.Version)
+	if user := req.PluginContext.User; user != nil {
+		writeKeyParts(h, user.Login, user.Email, user.Role)
+	}
+	writeKeyParts(h, len(req.Headers))
+	headers := make([]string, 0, len(req.Headers))
+	for k := range req.Headers {
+		headers = append(headers, k)
+	}
+	sort.Strings(headers)
+	for _, k := range headers {
+		writeKeyParts(h, k, req.Headers[k])
+	}
+	for _, q := range req.Queries {
+		model, err := normalizeQueryJSON(q.JSON)
+		if err != nil {
+			return "", err
+		}
+		writeKeyParts(h, q.RefID, q.TimeRange.From.UnixNano(), q.TimeRange.To.UnixNano(), q.MaxDataPoints, q.Interval, q.QueryType, model)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeKeyParts(h hash.Hash, parts ...interface{}) {
+	for _, p := range parts {
+		_, _ = fmt.Fprint(h, p)
+		_, _ = h.Write([]byte{0})
+	}
+}
+
+// normalizeQueryJSON returns the JSON model of a query with sorted properties and without the properties
+// that differ between requests of the same query.
+func normalizeQueryJSON(data []byte) (string, error) {
+	var model map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&model); err != nil {
+		return "", err
+	}
+	delete(model, "requestId")
+	delete(model, "key")
+	// encoding/json sorts the keys of maps.
+	normalized, err := json.Marshal(model)
+	if err != nil {
+		return "", err
+	}
+	return string(normalized), nil
+}
@@ -0,0 +1,165 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/query"
+)
+
+func TestQueryDataCache(t *testing.T) {
+	setupCache := func(t *testing.T, ttl string) (*testContext, *fakeCacheStorage) {
+		t.Helper()
+		tc := setup()
+		tc.dataSourceCache.ds.Uid = "ds-uid"
+		tc.dataSourceCache.ds.JsonData = simplejson.NewFromAny(map[string]interface{}{"queryCacheTTL": ttl})
+		tc.pluginContext.resp = &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": backend.DataResponse{Frames: data.Frames{data.NewFrame("", data.NewField("value", nil, []float64{1, 2}))}},
+		}}
+		storage := &fakeCacheStorage{items: map[string]fakeCacheItem{}}
+		tc.queryService.SetResultCache(query.NewRemoteResultCache(storage))
+		return tc, storage
+	}
+	request := func(model string) dtos.MetricRequest {
+		q, err := simplejson.NewJson([]byte(model))
+		require.NoError(t, err)
+		return dtos.MetricRequest{From: "now-1h", To: "now", Queries: []*simplejson.Json{q}}
+	}
+
+	t.Run("returns the cached response of the same query", func(t *testing.T) {
+		tc, storage := setupCache(t, "24h")
+
+		_, err := tc.queryService.QueryData(context.Background(), nil, false, request(`{"datasourceId":1,"expr":"up","requestId":"Q1"}`), false)
+		require.NoError(t, err)
+		require.Len(t, storage.items, 1)
+		for _, item := range storage.items {
+			require.Equal(t, 24*time.Hour, item.expire)
+		}
+
+		resp, err := tc.queryService.QueryData(context.Background(), nil, false, request(`{"requestId":"Q2","expr":"up","datasourceId":1}`), false)
+		require.NoError(t, err)
+		require.Equal(t, 1, tc.pluginContext.calls)
+		require.Len(t, resp.Responses["A"].Frames, 1)
+		v, ok := resp.Responses["A"].Frames[0].Fields[0].ConcreteAt(1)
+		require.True(t, ok)
+		require.Equal(t, 2.0, v)
+
+		_, err = tc.queryService.QueryData(context.Background(), nil, false, request(`{"datasourceId":1,"expr":"down"}`), false)
+		require.NoError(t, err)
+		require.Equal(t, 2, tc.pluginContext.calls)
+	})
+
+	t.Run("widens the time range to the TTL", func(t *testing.T) {
+		tc, _ := setupCache(t, "1h")
+
+		before := time.Now()
+		_, err := tc.queryService.QueryData(context.Background(), nil, false, request(`{"datasourceId":1}`), false)
+		require.NoError(t, err)
+		tr := tc.pluginContext.req.Queries[0].TimeRange
+		require.Equal(t, tr.From.Truncate(time.Hour), tr.From)
+		require.Equal(t, tr.To.Truncate(time.Hour), tr.To)
+		require.False(t, tr.From.After(before.Add(-time.Hour)))
+		require.False(t, tr.To.Before(before))
+	})
+
+	t.Run("does not share the cached responses of different users", func(t *testing.T) {
+		tc, storage := setupCache(t, "24h")
+
+		for _, login := range []string{"alice", "bob", "alice"} {
+			user := &models.SignedInUser{OrgId: 1, Login: login}
+			_, err := tc.queryService.QueryData(context.Background(), user, false, request(`{"datasourceId":1}`), false)
+			require.NoError(t, err)
+		}
+		require.Equal(t, 2, tc.pluginContext.calls)
+		require.Len(t, storage.items, 2)
+	})
+
+	t.Run("does not cache responses that are too large", func(t *testing.T) {
+		tc, storage := setupCache(t, "24h")
+		tc.pluginContext.resp = &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": backend.DataResponse{Frames: data.Frames{data.NewFrame("", data.NewField("value", nil, make([]float64, 100000)))}},
+		}}
+
+		_, err := tc.queryService.QueryData(context.Background(), nil, false, request(`{"datasourceId":1}`), false)
+		require.NoError(t, err)
+		require.Empty(t, storage.items)
+	})
+
+	t.Run("skipCache queries the data source and updates the cache", func(t *testing.T) {
+		tc, storage := setupCache(t, "24h")
+
+		for i := 0; i < 2; i++ {
+			_, err := tc.queryService.QueryData(context.Background(), nil, true, request(`{"datasourceId":1}`), false)
+			require.NoError(t, err)
+		}
+		require.Equal(t, 2, tc.pluginContext.calls)
+		require.Len(t, storage.items, 1)
+	})
+
+	t.Run("does not cache responses with errors", func(t *testing.T) {
+		tc, storage := setupCache(t, "24h")
+		tc.pluginContext.resp = &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": backend.DataResponse{Error: errors.New("query failed")},
+		}}
+
+		_, err := tc.queryService.QueryData(context.Background(), nil, false, request(`{"datasourceId":1}`), false)
+		require.NoError(t, err)
+		require.Empty(t, storage.items)
+	})
+
+	t.Run("does not cache the queries of data sources without TTL", func(t *testing.T) {
+		for _, ttl := range []string{"", "0s", "invalid"} {
+			tc, storage := setupCache(t, ttl)
+
+			_, err := tc.queryService.QueryData(context.Background(), nil, false, request(`{"datasourceId":1}`), false)
+			require.NoError(t, err)
+			require.Empty(t, storage.items, ttl)
+		}
+	})
+
+	t.Run("does not cache the queries of data sources with OAuth pass-through", func(t *testing.T) {
+		tc, storage := setupCache(t, "24h")
+		tc.oauthTokenService.passThruEnabled = true
+
+		_, err := tc.queryService.QueryData(context.Background(), nil, false, request(`{"datasourceId":1}`), false)
+		require.NoError(t, err)
+		require.Empty(t, storage.items)
+	})
+}
+
+type fakeCacheItem struct {
+	value  interface{}
+	expire time.Duration
+}
+
+type fakeCacheStorage struct {
+	items map[string]fakeCacheItem
+}
+
+func (s *fakeCacheStorage) Get(ctx context.Context, key string) (interface{}, error) {
+	item, ok := s.items[key]
+	if !ok {
+		return nil, remotecache.ErrCacheItemNotFound
+	}
+	return item.value, nil
+}
+
+func (s *fakeCacheStorage) Set(ctx context.Context, key string, value interface{}, expire time.Duration) error {
+	s.items[key] = fakeCacheItem{value: value, expire: expire}
+	return nil
+}
+
+func (s *fakeCacheStorage) Delete(ctx context.Context, key string) error {
+	delete(s.items, key)
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/adapters"
@@ -31,6 +32,7 @@ func ProvideService(
 	SecretsService secrets.Service,
 	pluginClient plugins.Client,
 	oAuthTokenService oauthtoken.OAuthTokenService,
+	remoteCache *remotecache.RemoteCache,
 ) *Service {
 	g := &Service{
 		cfg:                    cfg,
@@ -42,6 +44,9 @@ func ProvideService(
 		oAuthTokenService:      oAuthTokenService,
 		log:                    log.New("query_data"),
 	}
+	if cfg != nil && cfg.QueryCachingEnabled && remoteCache != nil {
+		g.resultCache = NewRemoteResultCache(remoteCache)
+	}
 	g.log.Info("Query Service initialization")
 	return g
 }
@@ -55,6 +60,12 @@ type Service struct {
 	pluginClient           plugins.Client
 	oAuthTokenService      oauthtoken.OAuthTokenService
 	log                    log.Logger
+	resultCache            ResultCache
+}
+
+// SetResultCache sets the cache of the responses of the data source queries.
+func (s *Service) SetResultCache(c ResultCache) {
+	s.resultCache = c
 }
 
 // Run Service.
@@ -72,7 +83,7 @@ func (s *Service) QueryData(ctx context.Context, user *models.SignedInUser, skip
 	if handleExpressions && parsedReq.hasExpression {
 		return s.handleExpressions(ctx, user, parsedReq)
 	}
-	return s.handleQueryData(ctx, user, parsedReq)
+	return s.handleQueryData(ctx, user, skipCache, parsedReq)
 }
 
 // handleExpressions handles POST /api/ds/query when there is an expression.
@@ -108,7 +119,7 @@ func (s *Service) handleExpressions(ctx context.Context, user *models.SignedInUs
 	return qdr, nil
 }
 
-func (s *Service) handleQueryData(ctx context.Context, user *models.SignedInUser, parsedReq *parsedRequest) (*backend.QueryDataResponse, error) {
+func (s *Service) handleQueryData(ctx context.Context, user *models.SignedInUser, skipCache bool, parsedReq *parsedRequest) (*backend.QueryDataResponse, error) {
 	ds := parsedReq.parsedQueries[0].datasource
 	if err := s.pluginRequestValidator.Validate(ds.Url, nil); err != nil {
 		return nil, models.ErrDataSourceAccessDenied
@@ -130,7 +141,8 @@ func (s *Service) handleQueryData(ctx context.Context, user *models.SignedInUser
 		Queries: []backend.DataQuery{},
 	}
 
-	if s.oAuthTokenService.IsOAuthPassThruEnabled(ds) {
+	oAuthPassThru := s.oAuthTokenService.IsOAuthPassThruEnabled(ds)
+	if oAuthPassThru {
 		if token := s.oAuthTokenService.GetCurrentOAuthToken(ctx, user); token != nil {
 			req.Headers["Authorization"] = fmt.Sprintf("%s %s", token.Type(), token.AccessToken)
 
@@ -149,6 +161,10 @@ func (s *Service) handleQueryData(ctx context.Context, user *models.SignedInUser
 		req.Queries = append(req.Queries, q.query)
 	}
 
+	// the results of the data sources that use the OAuth token of the user depend on the user.
+	if ttl := s.queryCacheTTL(ds); ttl > 0 && !oAuthPassThru {
+		return s.cachedQueryData(ctx, ds, req, ttl, skipCache)
+	}
 	return s.pluginClient.QueryData(ctx, req)
 }
 
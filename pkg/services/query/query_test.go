@@ -65,7 +65,7 @@ func setup() *testContext {
 		dataSourceCache:        dc,
 		oauthTokenService:      tc,
 		pluginRequestValidator: rv,
-		queryService:           query.ProvideService(setting.NewCfg(), dc, nil, rv, sc, pc, tc),
+		queryService:           query.ProvideService(setting.NewCfg(), dc, nil, rv, sc, pc, tc, nil),
 	}
 }
 
@@ -134,10 +134,13 @@ func (c *fakeDataSourceCache) GetDatasourceByUID(ctx context.Context, datasource
 type fakePluginClient struct {
 	plugins.Client
 
-	req *backend.QueryDataRequest
+	req   *backend.QueryDataRequest
+	resp  *backend.QueryDataResponse
+	calls int
 }
 
 func (c *fakePluginClient) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	c.req = req
-	return nil, nil
+	c.calls++
+	return c.resp, nil
 }
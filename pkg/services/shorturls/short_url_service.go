@@ -19,40 +19,97 @@ func ProvideService(sqlStore *sqlstore.SQLStore) *ShortURLService {
 
 type Service interface {
 	GetShortURLByUID(ctx context.Context, user *models.SignedInUser, uid string) (*models.ShortUrl, error)
-	CreateShortURL(ctx context.Context, user *models.SignedInUser, path string) (*models.ShortUrl, error)
-	UpdateLastSeenAt(ctx context.Context, shortURL *models.ShortUrl) error
-	DeleteStaleShortURLs(ctx context.Context, cmd *models.DeleteShortUrlCommand) error
+	GetShortURLs(ctx context.Context, user *models.SignedInUser, query *models.GetShortURLsQuery) error
+	CreateShortURL(ctx context.Context, user *models.SignedInUser, cmd *models.CreateShortURLCommand) (*models.ShortUrl, error)
+	UpdateShortURL(ctx context.Context, user *models.SignedInUser, cmd *models.UpdateShortURLCommand) error
+	DeleteShortURL(ctx context.Context, user *models.SignedInUser, uid string) error
+	RecordAccess(ctx context.Context, shortURL *models.ShortUrl, referrer string) error
 }
 
 type ShortURLService struct {
 	SQLStore *sqlstore.SQLStore
 }
 
+// GetShortURLByUID returns the short URL of the organization of the user with the UID or the
+// alias.
 func (s ShortURLService) GetShortURLByUID(ctx context.Context, user *models.SignedInUser, uid string) (*models.ShortUrl, error) {
-	var shortURL models.ShortUrl
+	var shortURL *models.ShortUrl
 	err := s.SQLStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
-		exists, err := dbSession.Where("org_id=? AND uid=?", user.OrgId, uid).Get(&shortURL)
-		if err != nil {
-			return err
-		}
-		if !exists {
-			return models.ErrShortURLNotFound
-		}
-
-		return nil
+		var err error
+		shortURL, err = getShortURL(dbSession, user.OrgId, uid)
+		return err
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	return shortURL, nil
+}
+
+func getShortURL(dbSession *sqlstore.DBSession, orgID int64, uid string) (*models.ShortUrl, error) {
+	var shortURL models.ShortUrl
+	exists, err := dbSession.Where("org_id=? AND uid=?", orgID, uid).Get(&shortURL)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return &shortURL, nil
+	}
+
+	exists, err = dbSession.Where("org_id=? AND alias=?", orgID, uid).Get(&shortURL)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, models.ErrShortURLNotFound
+	}
+
 	return &shortURL, nil
 }
 
-func (s ShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *models.ShortUrl) error {
+// getManagedShortURL returns the short URL with the UID or the alias if the user can manage it.
+func getManagedShortURL(dbSession *sqlstore.DBSession, user *models.SignedInUser, uid string) (*models.ShortUrl, error) {
+	shortURL, err := getShortURL(dbSession, user.OrgId, uid)
+	if err != nil {
+		return nil, err
+	}
+	if !shortURL.CanManage(user) {
+		return nil, models.ErrShortURLAccessDenied
+	}
+
+	return shortURL, nil
+}
+
+// GetShortURLs returns the short URLs the user has created in their organization.
+func (s ShortURLService) GetShortURLs(ctx context.Context, user *models.SignedInUser, query *models.GetShortURLsQuery) error {
+	return s.SQLStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		shortURLs := make([]*models.ShortUrl, 0)
+		sess := dbSession.Where("org_id=? AND created_by=?", user.OrgId, user.UserId).Desc("created_at", "id")
+		if query.Limit > 0 {
+			offset := 0
+			if query.Page > 1 {
+				offset = query.Limit * (query.Page - 1)
+			}
+			sess.Limit(query.Limit, offset)
+		}
+		if err := sess.Find(&shortURLs); err != nil {
+			return err
+		}
+
+		query.Result = shortURLs
+		return nil
+	})
+}
+
+// RecordAccess updates the last time the short URL was seen, its access count and its last
+// referrer.
+func (s ShortURLService) RecordAccess(ctx context.Context, shortURL *models.ShortUrl, referrer string) error {
 	shortURL.LastSeenAt = getTime().Unix()
+	shortURL.AccessCount++
+	shortURL.LastReferrer = referrer
 	return s.SQLStore.WithTransactionalDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
-		_, err := dbSession.ID(shortURL.Id).Update(shortURL)
-		if err != nil {
+		rawSQL := "UPDATE short_url SET last_seen_at = ?, access_count = access_count + 1, last_referrer = ? WHERE id = ?"
+		if _, err := dbSession.Exec(rawSQL, shortURL.LastSeenAt, referrer, shortURL.Id); err != nil {
 			return err
 		}
 
@@ -60,38 +117,124 @@ func (s ShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *models.
 	})
 }
 
-func (s ShortURLService) CreateShortURL(ctx context.Context, user *models.SignedInUser, path string) (*models.ShortUrl, error) {
-	now := time.Now().Unix()
+func (s ShortURLService) CreateShortURL(ctx context.Context, user *models.SignedInUser, cmd *models.CreateShortURLCommand) (*models.ShortUrl, error) {
+	now := getTime()
+	if err := validateShortURL(cmd.Alias, cmd.ExpiresAt, now); err != nil {
+		return nil, err
+	}
+
 	shortURL := models.ShortUrl{
 		OrgId:     user.OrgId,
 		Uid:       util.GenerateShortUID(),
-		Path:      path,
+		Path:      cmd.Path,
 		CreatedBy: user.UserId,
-		CreatedAt: now,
+		CreatedAt: now.Unix(),
+		Alias:     cmd.Alias,
+		ExpiresAt: cmd.ExpiresAt,
 	}
 
-	err := s.SQLStore.WithDbSession(ctx, func(session *sqlstore.DBSession) error {
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		if err := checkAliasAvailable(session, user.OrgId, 0, cmd.Alias); err != nil {
+			return err
+		}
+
+		if cmd.Alias == "" {
+			// the alias is unique, short URLs without alias have none instead of an empty one.
+			session.Omit("alias")
+		}
 		_, err := session.Insert(&shortURL)
 		return err
 	})
 	if err != nil {
-		return nil, err
+		return nil, s.aliasConflictError(cmd.Alias, err)
 	}
 
 	return &shortURL, nil
 }
 
-func (s ShortURLService) DeleteStaleShortURLs(ctx context.Context, cmd *models.DeleteShortUrlCommand) error {
-	return s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
-		var rawSql = "DELETE FROM short_url WHERE created_at <= ? AND (last_seen_at IS NULL OR last_seen_at = 0)"
+// UpdateShortURL sets the alias and the expiry of a short URL the user can manage.
+func (s ShortURLService) UpdateShortURL(ctx context.Context, user *models.SignedInUser, cmd *models.UpdateShortURLCommand) error {
+	if err := validateShortURL(cmd.Alias, cmd.ExpiresAt, getTime()); err != nil {
+		return err
+	}
 
-		if result, err := session.Exec(rawSql, cmd.OlderThan.Unix()); err != nil {
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		shortURL, err := getManagedShortURL(session, user, cmd.UID)
+		if err != nil {
 			return err
-		} else if cmd.NumDeleted, err = result.RowsAffected(); err != nil {
+		}
+		if err := checkAliasAvailable(session, user.OrgId, shortURL.Id, cmd.Alias); err != nil {
 			return err
 		}
+
+		var alias interface{}
+		if cmd.Alias != "" {
+			alias = cmd.Alias
+		}
+		if _, err := session.Exec("UPDATE short_url SET alias = ?, expires_at = ? WHERE id = ?", alias, cmd.ExpiresAt, shortURL.Id); err != nil {
+			return err
+		}
+
+		shortURL.Alias = cmd.Alias
+		shortURL.ExpiresAt = cmd.ExpiresAt
+		cmd.Result = shortURL
 		return nil
 	})
+	return s.aliasConflictError(cmd.Alias, err)
+}
+
+// aliasConflictError returns ErrShortURLAliasExists for the violations of the unique index of the aliases, which
+// happen when another short URL took the alias after checkAliasAvailable.
+func (s ShortURLService) aliasConflictError(alias string, err error) error {
+	if err != nil && alias != "" && s.SQLStore.Dialect.IsUniqueConstraintViolation(err) {
+		return models.ErrShortURLAliasExists
+	}
+	return err
+}
+
+// DeleteShortURL deletes a short URL the user can manage.
+func (s ShortURLService) DeleteShortURL(ctx context.Context, user *models.SignedInUser, uid string) error {
+	return s.SQLStore.WithTransactionalDbSession(ctx, func(session *sqlstore.DBSession) error {
+		shortURL, err := getManagedShortURL(session, user, uid)
+		if err != nil {
+			return err
+		}
+
+		_, err = session.Exec("DELETE FROM short_url WHERE id = ?", shortURL.Id)
+		return err
+	})
+}
+
+func validateShortURL(alias string, expiresAt int64, now time.Time) error {
+	if alias != "" {
+		if !util.IsValidShortUID(alias) {
+			return models.ErrShortURLInvalidAlias
+		}
+		if util.IsShortUIDTooLong(alias) {
+			return models.ErrShortURLAliasTooLong
+		}
+	}
+	if expiresAt != 0 && expiresAt <= now.Unix() {
+		return models.ErrShortURLInvalidExpiry
+	}
+	return nil
+}
+
+// checkAliasAvailable returns an error if the alias is the alias or the UID of another short URL
+// of the organization, as short URLs are resolved by both.
+func checkAliasAvailable(session *sqlstore.DBSession, orgID, id int64, alias string) error {
+	if alias == "" {
+		return nil
+	}
+
+	exists, err := session.Where("org_id=? AND id<>? AND (alias=? OR uid=?)", orgID, id, alias, alias).Exist(&models.ShortUrl{})
+	if err != nil {
+		return err
+	}
+	if exists {
+		return models.ErrShortURLAliasExists
+	}
+	return nil
 }
 
 var _ Service = &ShortURLService{}
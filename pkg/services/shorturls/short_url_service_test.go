@@ -19,7 +19,7 @@ func TestShortURLService(t *testing.T) {
 
 		service := ShortURLService{SQLStore: sqlStore}
 
-		newShortURL, err := service.CreateShortURL(context.Background(), user, refPath)
+		newShortURL, err := service.CreateShortURL(context.Background(), user, &models.CreateShortURLCommand{Path: refPath})
 		require.NoError(t, err)
 		require.NotNil(t, newShortURL)
 		require.NotEmpty(t, newShortURL.Uid)
@@ -29,7 +29,7 @@ func TestShortURLService(t *testing.T) {
 		require.NotNil(t, existingShortURL)
 		require.Equal(t, refPath, existingShortURL.Path)
 
-		t.Run("and update last seen at", func(t *testing.T) {
+		t.Run("and record accesses", func(t *testing.T) {
 			origGetTime := getTime
 			t.Cleanup(func() {
 				getTime = origGetTime
@@ -40,37 +40,80 @@ func TestShortURLService(t *testing.T) {
 				return expectedTime
 			}
 
-			err := service.UpdateLastSeenAt(context.Background(), existingShortURL)
+			err := service.RecordAccess(context.Background(), existingShortURL, "https://wiki.example.com/runbooks")
+			require.NoError(t, err)
+			err = service.RecordAccess(context.Background(), existingShortURL, "")
 			require.NoError(t, err)
 
 			updatedShortURL, err := service.GetShortURLByUID(context.Background(), user, existingShortURL.Uid)
 			require.NoError(t, err)
 			require.Equal(t, expectedTime.Unix(), updatedShortURL.LastSeenAt)
+			require.Equal(t, int64(2), updatedShortURL.AccessCount)
+			require.Empty(t, updatedShortURL.LastReferrer)
 		})
+	})
 
-		t.Run("and stale short urls can be deleted", func(t *testing.T) {
-			staleShortURL, err := service.CreateShortURL(context.Background(), user, refPath)
+	t.Run("User can create short URLs with an alias and an expiry", func(t *testing.T) {
+		service := ShortURLService{SQLStore: sqlStore}
+		expiresAt := time.Now().Add(time.Hour).Unix()
+
+		shortURL, err := service.CreateShortURL(context.Background(), user, &models.CreateShortURLCommand{Path: "d/abc", Alias: "incident-runbook", ExpiresAt: expiresAt})
+		require.NoError(t, err)
+
+		byAlias, err := service.GetShortURLByUID(context.Background(), user, "incident-runbook")
+		require.NoError(t, err)
+		require.Equal(t, shortURL.Uid, byAlias.Uid)
+		require.Equal(t, expiresAt, byAlias.ExpiresAt)
+
+		_, err = service.CreateShortURL(context.Background(), user, &models.CreateShortURLCommand{Path: "d/def", Alias: "incident-runbook"})
+		require.ErrorIs(t, err, models.ErrShortURLAliasExists)
+		_, err = service.CreateShortURL(context.Background(), user, &models.CreateShortURLCommand{Path: "d/def", Alias: shortURL.Uid})
+		require.ErrorIs(t, err, models.ErrShortURLAliasExists)
+		_, err = service.CreateShortURL(context.Background(), user, &models.CreateShortURLCommand{Path: "d/def", Alias: "not valid"})
+		require.ErrorIs(t, err, models.ErrShortURLInvalidAlias)
+		_, err = service.CreateShortURL(context.Background(), user, &models.CreateShortURLCommand{Path: "d/def", ExpiresAt: time.Now().Add(-time.Hour).Unix()})
+		require.ErrorIs(t, err, models.ErrShortURLInvalidExpiry)
+
+		t.Run("and the database rejects duplicate aliases", func(t *testing.T) {
+			err := sqlStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+				_, err := sess.Insert(&models.ShortUrl{OrgId: user.OrgId, Uid: "duplicate", Path: "d/def", Alias: "incident-runbook"})
+				return err
+			})
+			require.ErrorIs(t, service.aliasConflictError("incident-runbook", err), models.ErrShortURLAliasExists)
+		})
+
+		t.Run("and update them", func(t *testing.T) {
+			cmd := models.UpdateShortURLCommand{UID: "incident-runbook", Alias: "incident"}
+			err := service.UpdateShortURL(context.Background(), user, &cmd)
 			require.NoError(t, err)
-			require.NotNil(t, staleShortURL)
-			require.NotEmpty(t, staleShortURL.Uid)
-			require.Equal(t, int64(0), staleShortURL.LastSeenAt)
+			require.Equal(t, int64(0), cmd.Result.ExpiresAt)
 
-			cmd := models.DeleteShortUrlCommand{OlderThan: time.Unix(staleShortURL.CreatedAt, 0)}
-			err = service.DeleteStaleShortURLs(context.Background(), &cmd)
+			updated, err := service.GetShortURLByUID(context.Background(), user, "incident")
 			require.NoError(t, err)
-			require.Equal(t, int64(1), cmd.NumDeleted)
+			require.Equal(t, int64(0), updated.ExpiresAt)
+		})
 
-			t.Run("and previously accessed short urls will still exist", func(t *testing.T) {
-				updatedShortURL, err := service.GetShortURLByUID(context.Background(), user, existingShortURL.Uid)
-				require.NoError(t, err)
-				require.NotNil(t, updatedShortURL)
-			})
+		t.Run("and other users cannot manage them", func(t *testing.T) {
+			other := &models.SignedInUser{UserId: 2, OrgRole: models.ROLE_EDITOR}
+			err := service.UpdateShortURL(context.Background(), other, &models.UpdateShortURLCommand{UID: "incident"})
+			require.ErrorIs(t, err, models.ErrShortURLAccessDenied)
+			err = service.DeleteShortURL(context.Background(), other, "incident")
+			require.ErrorIs(t, err, models.ErrShortURLAccessDenied)
 
-			t.Run("and no action when no stale short urls exist", func(t *testing.T) {
-				cmd := models.DeleteShortUrlCommand{OlderThan: time.Unix(existingShortURL.CreatedAt, 0)}
-				require.NoError(t, err)
-				require.Equal(t, int64(0), cmd.NumDeleted)
-			})
+			query := models.GetShortURLsQuery{}
+			require.NoError(t, service.GetShortURLs(context.Background(), other, &query))
+			require.Empty(t, query.Result)
+		})
+
+		t.Run("and list and delete them", func(t *testing.T) {
+			query := models.GetShortURLsQuery{Limit: 1}
+			require.NoError(t, service.GetShortURLs(context.Background(), user, &query))
+			require.Len(t, query.Result, 1)
+
+			err := service.DeleteShortURL(context.Background(), user, "incident")
+			require.NoError(t, err)
+			_, err = service.GetShortURLByUID(context.Background(), user, shortURL.Uid)
+			require.ErrorIs(t, err, models.ErrShortURLNotFound)
 		})
 	})
 
@@ -49,6 +49,7 @@ func (ss *SQLStore) CreateDashboardSnapshot(ctx context.Context, cmd *models.Cre
 			ExternalDeleteUrl:  cmd.ExternalDeleteUrl,
 			Dashboard:          simplejson.New(),
 			DashboardEncrypted: cmd.DashboardEncrypted,
+			FramesEncrypted:    cmd.FramesEncrypted,
 			Expires:            expires,
 			Created:            time.Now(),
 			Updated:            time.Now(),
@@ -43,4 +43,30 @@ func addCommentMigrations(mg *Migrator) {
 	mg.AddMigration("create comment table", NewAddTableMigration(commentTable))
 	mg.AddMigration("add index comment.group_id", NewAddIndexMigration(commentTable, commentTable.Indices[0]))
 	mg.AddMigration("add index comment.created", NewAddIndexMigration(commentTable, commentTable.Indices[1]))
+
+	mg.AddMigration("add parent_id column to comment", NewAddColumnMigration(commentTable, &Column{
+		Name: "parent_id", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add deleted column to comment", NewAddColumnMigration(commentTable, &Column{
+		Name: "deleted", Type: DB_Int, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add index comment.parent_id", NewAddIndexMigration(commentTable, &Index{
+		Cols: []string{"parent_id"}, Type: IndexType,
+	}))
+
+	commentRevisionTable := Table{
+		Name: "comment_revision",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "comment_id", Type: DB_BigInt, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "content", Type: DB_MediumText, Nullable: false},
+			{Name: "created", Type: DB_Int, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"comment_id"}, Type: IndexType},
+		},
+	}
+	mg.AddMigration("create comment revision table", NewAddTableMigration(commentRevisionTable))
+	mg.AddMigration("add index comment_revision.comment_id", NewAddIndexMigration(commentRevisionTable, commentRevisionTable.Indices[0]))
 }
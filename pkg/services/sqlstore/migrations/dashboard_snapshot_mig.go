@@ -71,4 +71,11 @@ func addDashboardSnapshotMigrations(mg *Migrator) {
 
 	mg.AddMigration("Change dashboard_encrypted column to MEDIUMBLOB", NewRawSQLMigration("").
 		Mysql("ALTER TABLE dashboard_snapshot MODIFY dashboard_encrypted MEDIUMBLOB;"))
+
+	mg.AddMigration("Add encrypted frames column", NewAddColumnMigration(snapshotV5, &Column{
+		Name: "frames_encrypted", Type: DB_Blob, Nullable: true,
+	}))
+
+	mg.AddMigration("Change frames_encrypted column to LONGBLOB", NewRawSQLMigration("").
+		Mysql("ALTER TABLE dashboard_snapshot MODIFY frames_encrypted LONGBLOB;"))
 }
@@ -24,4 +24,28 @@ func addShortURLMigrations(mg *Migrator) {
 	mg.AddMigration("create short_url table v1", NewAddTableMigration(shortURLV1))
 
 	mg.AddMigration("add index short_url.org_id-uid", NewAddIndexMigration(shortURLV1, shortURLV1.Indices[0]))
+
+	mg.AddMigration("add alias column to short_url", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "alias", Type: DB_NVarchar, Length: 40, Nullable: true,
+	}))
+
+	mg.AddMigration("add expires_at column to short_url", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "expires_at", Type: DB_BigInt, Nullable: true,
+	}))
+
+	mg.AddMigration("add access_count column to short_url", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "access_count", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add last_referrer column to short_url", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "last_referrer", Type: DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add unique index short_url.org_id-alias", NewAddIndexMigration(shortURLV1, &Index{
+		Cols: []string{"org_id", "alias"}, Type: UniqueIndex,
+	}))
+
+	mg.AddMigration("add index short_url.org_id-created_by", NewAddIndexMigration(shortURLV1, &Index{
+		Cols: []string{"org_id", "created_by"},
+	}))
 }
@@ -25,6 +25,18 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 
 	// Create provisioning data table
 	AddProvisioningMigrations(mg)
+
+	// Create state history table
+	AddStateHistoryMigrations(mg)
+
+	// Create table of the samples written by recording rules
+	AddRecordedSampleMigrations(mg)
+
+	// Create table of the heartbeats of the schedulers of a HA cluster
+	AddSchedulerInstanceMigrations(mg)
+
+	// Create notification log table
+	AddNotificationLogMigrations(mg)
 }
 
 // AddAlertDefinitionMigrations should not be modified.
@@ -234,6 +246,24 @@ func AddAlertRuleMigrations(mg *migrator.Migrator, defaultIntervalSeconds int64)
 			Cols: []string{"org_id", "dashboard_uid", "panel_id"},
 		},
 	))
+
+	mg.AddMigration("add record column to alert_rule", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule"},
+		&migrator.Column{
+			Name:     "record",
+			Type:     migrator.DB_Text,
+			Nullable: true,
+		},
+	))
+
+	mg.AddMigration("add depends_on column to alert_rule", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "alert_rule"},
+		&migrator.Column{
+			Name:     "depends_on",
+			Type:     migrator.DB_Text,
+			Nullable: true,
+		},
+	))
 }
 
 func AddAlertRuleVersionMigrations(mg *migrator.Migrator) {
@@ -277,6 +307,12 @@ func AddAlertRuleVersionMigrations(mg *migrator.Migrator) {
 
 	// add labels column
 	mg.AddMigration("add column labels to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "labels", Type: migrator.DB_Text, Nullable: true}))
+
+	// add record column
+	mg.AddMigration("add column record to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "record", Type: migrator.DB_Text, Nullable: true}))
+
+	// add depends_on column
+	mg.AddMigration("add column depends_on to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "depends_on", Type: migrator.DB_Text, Nullable: true}))
 }
 
 func AddAlertmanagerConfigMigrations(mg *migrator.Migrator) {
@@ -353,3 +389,110 @@ func AddProvisioningMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("create provenance_type table", migrator.NewAddTableMigration(provisioningTable))
 	mg.AddMigration("add index to uniquify (record_key, record_type, org_id) columns", migrator.NewAddIndexMigration(provisioningTable, provisioningTable.Indices[0]))
 }
+
+func AddStateHistoryMigrations(mg *migrator.Migrator) {
+	stateHistory := migrator.Table{
+		Name: "alert_state_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "namespace_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "labels_hash", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "previous_state", Type: migrator.DB_NVarchar, Length: 15, Nullable: false},
+			{Name: "state", Type: migrator.DB_NVarchar, Length: 15, Nullable: false},
+			{Name: "evaluation_values", Type: migrator.DB_Text, Nullable: true},
+			{Name: "error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "evaluated_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid", "evaluated_at"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "evaluated_at"}, Type: migrator.IndexType},
+			{Cols: []string{"evaluated_at"}, Type: migrator.IndexType},
+			// the namespace of the rule selects the entries the user can see without reading their labels
+			{Cols: []string{"org_id", "namespace_uid", "evaluated_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history table", migrator.NewAddTableMigration(stateHistory))
+	mg.AddMigration("add index in alert_state_history on org_id, rule_uid and evaluated_at columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[0]))
+	mg.AddMigration("add index in alert_state_history on org_id and evaluated_at columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[1]))
+	mg.AddMigration("add index in alert_state_history on evaluated_at column", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[2]))
+	mg.AddMigration("add index in alert_state_history on org_id, namespace_uid and evaluated_at columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[3]))
+}
+
+func AddRecordedSampleMigrations(mg *migrator.Migrator) {
+	recordedSample := migrator.Table{
+		Name: "alert_recorded_sample",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "namespace_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "metric", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "labels_hash", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "value", Type: migrator.DB_Double, Nullable: false},
+			{Name: "evaluated_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "metric", "evaluated_at"}, Type: migrator.IndexType},
+			{Cols: []string{"evaluated_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_recorded_sample table", migrator.NewAddTableMigration(recordedSample))
+	mg.AddMigration("add index in alert_recorded_sample on org_id, metric and evaluated_at columns", migrator.NewAddIndexMigration(recordedSample, recordedSample.Indices[0]))
+	mg.AddMigration("add index in alert_recorded_sample on evaluated_at column", migrator.NewAddIndexMigration(recordedSample, recordedSample.Indices[1]))
+}
+
+func AddSchedulerInstanceMigrations(mg *migrator.Migrator) {
+	schedulerInstance := migrator.Table{
+		Name: "alert_scheduler_instance",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "instance_id", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "last_heartbeat", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"instance_id"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_scheduler_instance table", migrator.NewAddTableMigration(schedulerInstance))
+	mg.AddMigration("add unique index in alert_scheduler_instance on instance_id column", migrator.NewAddIndexMigration(schedulerInstance, schedulerInstance.Indices[0]))
+}
+
+func AddNotificationLogMigrations(mg *migrator.Migrator) {
+	notificationLog := migrator.Table{
+		Name: "alert_notification_log",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "integration_type", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "integration_name", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "integration_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			// the group key, the payload and the alerts of large groups do not fit in a TEXT column of MySQL
+			{Name: "group_key", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "group_labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: 15, Nullable: false},
+			{Name: "status_code", Type: migrator.DB_Int, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "duration_ms", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "payload", Type: migrator.DB_MediumText, Nullable: true},
+			{Name: "alerts", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "replay_of", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "sent_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "sent_at"}, Type: migrator.IndexType},
+			{Cols: []string{"sent_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_log table", migrator.NewAddTableMigration(notificationLog))
+	mg.AddMigration("add index in alert_notification_log on org_id and sent_at columns", migrator.NewAddIndexMigration(notificationLog, notificationLog.Indices[0]))
+	mg.AddMigration("add index in alert_notification_log on sent_at column", migrator.NewAddIndexMigration(notificationLog, notificationLog.Indices[1]))
+}
@@ -44,6 +44,8 @@ type SQLStoreMock struct {
 	ExpectedAPIKey                 *models.ApiKey
 	ExpectedUserStars              map[int64]bool
 	ExpectedLoginAttempts          int64
+	ExpectedPlaylist               *models.Playlist
+	ExpectedPlaylistItems          []models.PlaylistItem
 
 	ExpectedError            error
 	ExpectedSetUsingOrgError error
@@ -417,6 +419,11 @@ func (m *SQLStoreMock) GetPlaylist(ctx context.Context, query *models.GetPlaylis
 	return m.ExpectedError
 }
 
+func (m *SQLStoreMock) GetPlaylistByUID(ctx context.Context, query *models.GetPlaylistByUidQuery) error {
+	query.Result = m.ExpectedPlaylist
+	return m.ExpectedError
+}
+
 func (m *SQLStoreMock) DeletePlaylist(ctx context.Context, cmd *models.DeletePlaylistCommand) error {
 	return m.ExpectedError
 }
@@ -426,6 +433,7 @@ func (m *SQLStoreMock) SearchPlaylists(ctx context.Context, query *models.GetPla
 }
 
 func (m *SQLStoreMock) GetPlaylistItem(ctx context.Context, query *models.GetPlaylistItemsByIdQuery) error {
+	query.Result = &m.ExpectedPlaylistItems
 	return m.ExpectedError
 }
 
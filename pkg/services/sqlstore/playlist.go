@@ -4,17 +4,24 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func (ss *SQLStore) CreatePlaylist(ctx context.Context, cmd *models.CreatePlaylistCommand) error {
 	return ss.WithTransactionalDbSession(ctx, func(sess *DBSession) error {
+		uid, err := newPlaylistUID(sess, cmd.OrgId, cmd.UID)
+		if err != nil {
+			return err
+		}
+
 		playlist := models.Playlist{
+			UID:      uid,
 			Name:     cmd.Name,
 			Interval: cmd.Interval,
 			OrgId:    cmd.OrgId,
 		}
 
-		_, err := sess.Insert(&playlist)
+		_, err = sess.Insert(&playlist)
 		if err != nil {
 			return err
 		}
@@ -27,6 +34,7 @@ func (ss *SQLStore) CreatePlaylist(ctx context.Context, cmd *models.CreatePlayli
 				Value:      item.Value,
 				Order:      item.Order,
 				Title:      item.Title,
+				Interval:   item.Interval,
 			})
 		}
 
@@ -46,20 +54,24 @@ func (ss *SQLStore) UpdatePlaylist(ctx context.Context, cmd *models.UpdatePlayli
 			Interval: cmd.Interval,
 		}
 
-		existingPlaylist := sess.Where("id = ? AND org_id = ?", cmd.Id, cmd.OrgId).Find(models.Playlist{})
-
-		if existingPlaylist == nil {
+		existingPlaylist := models.Playlist{}
+		exists, err := sess.Where("id = ? AND org_id = ?", cmd.Id, cmd.OrgId).Get(&existingPlaylist)
+		if err != nil {
+			return err
+		}
+		if !exists {
 			return models.ErrPlaylistNotFound
 		}
 
 		cmd.Result = &models.PlaylistDTO{
 			Id:       playlist.Id,
+			UID:      existingPlaylist.UID,
 			OrgId:    playlist.OrgId,
 			Name:     playlist.Name,
 			Interval: playlist.Interval,
 		}
 
-		_, err := sess.ID(cmd.Id).Cols("name", "interval").Update(&playlist)
+		_, err = sess.ID(cmd.Id).Cols("name", "interval").Update(&playlist)
 
 		if err != nil {
 			return err
@@ -81,6 +93,7 @@ func (ss *SQLStore) UpdatePlaylist(ctx context.Context, cmd *models.UpdatePlayli
 				Value:      item.Value,
 				Order:      index + 1,
 				Title:      item.Title,
+				Interval:   item.Interval,
 			})
 		}
 
@@ -105,6 +118,26 @@ func (ss *SQLStore) GetPlaylist(ctx context.Context, query *models.GetPlaylistBy
 	})
 }
 
+func (ss *SQLStore) GetPlaylistByUID(ctx context.Context, query *models.GetPlaylistByUidQuery) error {
+	if query.UID == "" || query.OrgId == 0 {
+		return models.ErrCommandValidationFailed
+	}
+
+	return ss.WithDbSession(ctx, func(sess *DBSession) error {
+		playlist := models.Playlist{}
+		exists, err := sess.Where("org_id = ? AND uid = ?", query.OrgId, query.UID).Get(&playlist)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return models.ErrPlaylistNotFound
+		}
+
+		query.Result = &playlist
+		return nil
+	})
+}
+
 func (ss *SQLStore) DeletePlaylist(ctx context.Context, cmd *models.DeletePlaylistCommand) error {
 	if cmd.Id == 0 || cmd.OrgId == 0 {
 		return models.ErrCommandValidationFailed
@@ -157,3 +190,53 @@ func (ss *SQLStore) GetPlaylistItem(ctx context.Context, query *models.GetPlayli
 		return err
 	})
 }
+
+// newPlaylistUID validates the UID of a new playlist of the organization, or generates one if it
+// is empty.
+func newPlaylistUID(sess *DBSession, orgID int64, uid string) (string, error) {
+	if uid == "" {
+		for i := 0; i < 3; i++ {
+			uid = util.GenerateShortUID()
+			if isNumericPlaylistUID(uid) {
+				continue
+			}
+			exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&models.Playlist{})
+			if err != nil {
+				return "", err
+			}
+			if !exists {
+				return uid, nil
+			}
+		}
+		return "", models.ErrPlaylistWithSameUIDExists
+	}
+
+	if !util.IsValidShortUID(uid) {
+		return "", models.ErrPlaylistInvalidUID
+	}
+	if util.IsShortUIDTooLong(uid) {
+		return "", models.ErrPlaylistUIDTooLong
+	}
+	if isNumericPlaylistUID(uid) {
+		return "", models.ErrPlaylistNumericUID
+	}
+	exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&models.Playlist{})
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return "", models.ErrPlaylistWithSameUIDExists
+	}
+	return uid, nil
+}
+
+// isNumericPlaylistUID returns true if the UID consists of digits only. The API falls back to the numeric
+// ID of a playlist when no playlist has the UID, so such UIDs would be ambiguous.
+func isNumericPlaylistUID(uid string) bool {
+	for _, r := range uid {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -22,15 +22,46 @@ func TestPlaylistDataAccess(t *testing.T) {
 		cmd := models.CreatePlaylistCommand{Name: "NYC office", Interval: "10m", OrgId: 1, Items: items}
 		err := ss.CreatePlaylist(context.Background(), &cmd)
 		require.NoError(t, err)
+		require.NotEmpty(t, cmd.Result.UID)
+
+		t.Run("Can get playlist by uid", func(t *testing.T) {
+			query := models.GetPlaylistByUidQuery{UID: cmd.Result.UID, OrgId: 1}
+			err := ss.GetPlaylistByUID(context.Background(), &query)
+			require.NoError(t, err)
+			require.Equal(t, cmd.Result.Id, query.Result.Id)
+
+			query = models.GetPlaylistByUidQuery{UID: cmd.Result.UID, OrgId: 2}
+			err = ss.GetPlaylistByUID(context.Background(), &query)
+			require.ErrorIs(t, err, models.ErrPlaylistNotFound)
+		})
+
+		t.Run("Cannot create playlist with the same uid", func(t *testing.T) {
+			duplicate := models.CreatePlaylistCommand{UID: cmd.Result.UID, Name: "LA office", OrgId: 1}
+			err := ss.CreatePlaylist(context.Background(), &duplicate)
+			require.ErrorIs(t, err, models.ErrPlaylistWithSameUIDExists)
+		})
+
+		t.Run("Cannot create playlist with a numeric uid", func(t *testing.T) {
+			numeric := models.CreatePlaylistCommand{UID: "12", Name: "LA office", OrgId: 1}
+			err := ss.CreatePlaylist(context.Background(), &numeric)
+			require.ErrorIs(t, err, models.ErrPlaylistNumericUID)
+		})
 
 		t.Run("Can update playlist", func(t *testing.T) {
 			items := []models.PlaylistItemDTO{
 				{Title: "influxdb", Value: "influxdb", Type: "dashboard_by_tag"},
-				{Title: "Backend response times", Value: "2", Type: "dashboard_by_id"},
+				{Title: "Backend response times", Value: "2", Type: "dashboard_by_id", Interval: "1m"},
 			}
 			query := models.UpdatePlaylistCommand{Name: "NYC office ", OrgId: 1, Id: 1, Interval: "10s", Items: items}
 			err = ss.UpdatePlaylist(context.Background(), &query)
 			require.NoError(t, err)
+			require.Equal(t, cmd.Result.UID, query.Result.UID)
+
+			itemsQuery := models.GetPlaylistItemsByIdQuery{PlaylistId: 1}
+			err = ss.GetPlaylistItem(context.Background(), &itemsQuery)
+			require.NoError(t, err)
+			require.Len(t, *itemsQuery.Result, 2)
+			require.Equal(t, "1m", (*itemsQuery.Result)[1].Interval)
 		})
 
 		t.Run("Can remove playlist", func(t *testing.T) {
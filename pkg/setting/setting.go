@@ -477,6 +477,9 @@ type Cfg struct {
 
 	// Query history
 	QueryHistoryEnabled bool
+
+	// Query caching
+	QueryCachingEnabled bool
 }
 
 type CommandLineArgs struct {
@@ -1006,6 +1009,9 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	queryHistory := iniFile.Section("query_history")
 	cfg.QueryHistoryEnabled = queryHistory.Key("enabled").MustBool(false)
 
+	queryCaching := iniFile.Section("query_caching")
+	cfg.QueryCachingEnabled = queryCaching.Key("enabled").MustBool(false)
+
 	panelsSection := iniFile.Section("panels")
 	cfg.DisableSanitizeHtml = panelsSection.Key("disable_sanitize_html").MustBool(false)
 
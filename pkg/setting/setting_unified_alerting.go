@@ -48,12 +48,48 @@ const (
 	schedulereDefaultExecuteAlerts          = true
 	schedulerDefaultMaxAttempts             = 3
 	schedulerDefaultLegacyMinInterval       = 1
+	schedulerDefaultHAEvaluationSharding    = false
+	schedulerDefaultHAHeartbeatInterval     = 10 * time.Second
+	schedulerDefaultHAHeartbeatTimeout      = time.Minute
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
 	SchedulerBaseInterval = 10 * time.Second
 	// DefaultRuleEvaluationInterval indicates a default interval of for how long a rule should be evaluated to change state from Pending to Alerting
 	DefaultRuleEvaluationInterval = SchedulerBaseInterval * 6 // == 60 seconds
+	stateHistoryDefaultEnabled    = true
+	stateHistoryDefaultBackend    = StateHistoryBackendSQL
+	stateHistoryDefaultRetention  = 30 * 24 * time.Hour
+)
+
+const (
+	// StateHistoryBackendSQL stores the state history in the Grafana database.
+	StateHistoryBackendSQL = "sql"
+	// StateHistoryBackendMemory keeps the state history in memory. The history is lost on restart.
+	StateHistoryBackendMemory = "memory"
+)
+
+const (
+	// RecordingRulesTargetInternal writes the series of recording rules to the Grafana database.
+	RecordingRulesTargetInternal = "internal"
+	// RecordingRulesTargetRemoteWrite writes the series of recording rules to a Prometheus remote write endpoint.
+	RecordingRulesTargetRemoteWrite = "remote_write"
+
+	recordingRulesDefaultEnabled            = false
+	recordingRulesDefaultTarget             = RecordingRulesTargetInternal
+	recordingRulesDefaultRemoteWriteTimeout = 30 * time.Second
+	recordingRulesDefaultRetention          = 30 * 24 * time.Hour
+)
+
+const (
+	ruleMetricsDefaultEnabled  = false
+	ruleMetricsDefaultMaxRules = 1000
+)
+
+const (
+	notificationLogDefaultEnabled        = true
+	notificationLogDefaultRetention      = 30 * 24 * time.Hour
+	notificationLogDefaultMaxPayloadSize = 4096
 )
 
 type UnifiedAlertingSettings struct {
@@ -77,6 +113,58 @@ type UnifiedAlertingSettings struct {
 	BaseInterval time.Duration
 	// DefaultRuleEvaluationInterval default interval between evaluations of a rule.
 	DefaultRuleEvaluationInterval time.Duration
+	StateHistory                  UnifiedAlertingStateHistorySettings
+	RecordingRules                UnifiedAlertingRecordingRulesSettings
+	RuleMetrics                   UnifiedAlertingRuleMetricsSettings
+	// HAEvaluationSharding distributes the evaluation of alert rules between the schedulers of a HA cluster
+	// instead of evaluating every rule on every instance.
+	HAEvaluationSharding bool
+	// HAInstanceID identifies the scheduler of this instance in the HA cluster.
+	HAInstanceID string
+	// HAHeartbeatInterval is how often the scheduler announces that it is active.
+	HAHeartbeatInterval time.Duration
+	// HAHeartbeatTimeout is how long after its last heartbeat a scheduler leaves the HA cluster.
+	HAHeartbeatTimeout time.Duration
+	NotificationLog    UnifiedAlertingNotificationLogSettings
+}
+
+// UnifiedAlertingStateHistorySettings configures the history of the state transitions of alert instances.
+type UnifiedAlertingStateHistorySettings struct {
+	Enabled bool
+	// Backend is where the state history is stored, either StateHistoryBackendSQL or StateHistoryBackendMemory.
+	Backend string
+	// Retention is how long the state history is kept.
+	Retention time.Duration
+}
+
+// UnifiedAlertingRecordingRulesSettings configures recording rules and where the series they compute are written.
+type UnifiedAlertingRecordingRulesSettings struct {
+	Enabled bool
+	// Target is where the series are written, either RecordingRulesTargetInternal or RecordingRulesTargetRemoteWrite.
+	Target                       string
+	RemoteWriteURL               string
+	RemoteWriteBasicAuthUsername string
+	RemoteWriteBasicAuthPassword string
+	RemoteWriteTimeout           time.Duration
+	// Retention is how long the series are kept by the internal target.
+	Retention time.Duration
+}
+
+// UnifiedAlertingRuleMetricsSettings configures the Prometheus metrics of the evaluations of each alert rule.
+type UnifiedAlertingRuleMetricsSettings struct {
+	Enabled bool
+	// MaxRules is the maximum number of alert rules with metrics. The evaluations of the other rules are
+	// only counted by the aggregated metrics of the scheduler.
+	MaxRules int
+}
+
+// UnifiedAlertingNotificationLogSettings configures the log of the attempts to deliver notifications to contact points.
+type UnifiedAlertingNotificationLogSettings struct {
+	Enabled bool
+	// Retention is how long the notification log is kept.
+	Retention time.Duration
+	// MaxPayloadSize is the maximum number of bytes of the request body kept for each attempt.
+	MaxPayloadSize int
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -179,6 +267,25 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 			uaCfg.HAPeers = append(uaCfg.HAPeers, peer)
 		}
 	}
+	uaCfg.HAEvaluationSharding = ua.Key("ha_evaluation_sharding").MustBool(schedulerDefaultHAEvaluationSharding)
+	uaCfg.HAInstanceID = valueAsString(ua, "ha_instance_id", InstanceName)
+	uaCfg.HAHeartbeatInterval, err = gtime.ParseDuration(valueAsString(ua, "ha_heartbeat_interval", schedulerDefaultHAHeartbeatInterval.String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.HAHeartbeatTimeout, err = gtime.ParseDuration(valueAsString(ua, "ha_heartbeat_timeout", schedulerDefaultHAHeartbeatTimeout.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.HAHeartbeatInterval <= 0 {
+		return errors.New("value of setting 'ha_heartbeat_interval' should be a positive duration")
+	}
+	if uaCfg.HAHeartbeatTimeout <= uaCfg.HAHeartbeatInterval {
+		return errors.New("value of setting 'ha_heartbeat_timeout' should be greater than 'ha_heartbeat_interval'")
+	}
+	if uaCfg.HAEvaluationSharding && uaCfg.HAInstanceID == "" {
+		return errors.New("setting 'ha_instance_id' is required by 'ha_evaluation_sharding'")
+	}
 
 	// TODO load from ini file
 	uaCfg.DefaultConfiguration = alertmanagerDefaultConfiguration
@@ -244,6 +351,65 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		uaCfg.DefaultRuleEvaluationInterval = uaMinInterval
 	}
 
+	stateHistory := iniFile.Section("unified_alerting.state_history")
+	uaCfg.StateHistory.Enabled = stateHistory.Key("enabled").MustBool(stateHistoryDefaultEnabled)
+	uaCfg.StateHistory.Backend = valueAsString(stateHistory, "backend", stateHistoryDefaultBackend)
+	if uaCfg.StateHistory.Backend != StateHistoryBackendSQL && uaCfg.StateHistory.Backend != StateHistoryBackendMemory {
+		return fmt.Errorf("value of setting 'backend' in section 'unified_alerting.state_history' should be one of [%s, %s]", StateHistoryBackendSQL, StateHistoryBackendMemory)
+	}
+	uaCfg.StateHistory.Retention, err = gtime.ParseDuration(valueAsString(stateHistory, "retention", stateHistoryDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.StateHistory.Retention <= 0 {
+		return errors.New("value of setting 'retention' in section 'unified_alerting.state_history' should be a positive duration")
+	}
+
+	recordingRules := iniFile.Section("unified_alerting.recording_rules")
+	uaCfg.RecordingRules.Enabled = recordingRules.Key("enabled").MustBool(recordingRulesDefaultEnabled)
+	uaCfg.RecordingRules.Target = valueAsString(recordingRules, "target", recordingRulesDefaultTarget)
+	if uaCfg.RecordingRules.Target != RecordingRulesTargetInternal && uaCfg.RecordingRules.Target != RecordingRulesTargetRemoteWrite {
+		return fmt.Errorf("value of setting 'target' in section 'unified_alerting.recording_rules' should be one of [%s, %s]", RecordingRulesTargetInternal, RecordingRulesTargetRemoteWrite)
+	}
+	uaCfg.RecordingRules.RemoteWriteURL = valueAsString(recordingRules, "remote_write_url", "")
+	if uaCfg.RecordingRules.Enabled && uaCfg.RecordingRules.Target == RecordingRulesTargetRemoteWrite && uaCfg.RecordingRules.RemoteWriteURL == "" {
+		return errors.New("setting 'remote_write_url' in section 'unified_alerting.recording_rules' is required by the remote_write target")
+	}
+	uaCfg.RecordingRules.RemoteWriteBasicAuthUsername = valueAsString(recordingRules, "remote_write_basic_auth_username", "")
+	uaCfg.RecordingRules.RemoteWriteBasicAuthPassword = valueAsString(recordingRules, "remote_write_basic_auth_password", "")
+	uaCfg.RecordingRules.RemoteWriteTimeout, err = gtime.ParseDuration(valueAsString(recordingRules, "remote_write_timeout", recordingRulesDefaultRemoteWriteTimeout.String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.RecordingRules.Retention, err = gtime.ParseDuration(valueAsString(recordingRules, "retention", recordingRulesDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.RecordingRules.Retention <= 0 {
+		return errors.New("value of setting 'retention' in section 'unified_alerting.recording_rules' should be a positive duration")
+	}
+
+	ruleMetrics := iniFile.Section("unified_alerting.rule_metrics")
+	uaCfg.RuleMetrics.Enabled = ruleMetrics.Key("enabled").MustBool(ruleMetricsDefaultEnabled)
+	uaCfg.RuleMetrics.MaxRules = ruleMetrics.Key("max_rules").MustInt(ruleMetricsDefaultMaxRules)
+	if uaCfg.RuleMetrics.MaxRules <= 0 {
+		return errors.New("value of setting 'max_rules' in section 'unified_alerting.rule_metrics' should be greater than 0")
+	}
+
+	notificationLog := iniFile.Section("unified_alerting.notification_log")
+	uaCfg.NotificationLog.Enabled = notificationLog.Key("enabled").MustBool(notificationLogDefaultEnabled)
+	uaCfg.NotificationLog.Retention, err = gtime.ParseDuration(valueAsString(notificationLog, "retention", notificationLogDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.NotificationLog.Retention <= 0 {
+		return errors.New("value of setting 'retention' in section 'unified_alerting.notification_log' should be a positive duration")
+	}
+	uaCfg.NotificationLog.MaxPayloadSize = notificationLog.Key("max_payload_size").MustInt(notificationLogDefaultMaxPayloadSize)
+	if uaCfg.NotificationLog.MaxPayloadSize < 0 {
+		return errors.New("value of setting 'max_payload_size' in section 'unified_alerting.notification_log' should not be negative")
+	}
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
@@ -25,6 +25,19 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 		require.Len(t, cfg.UnifiedAlerting.HAPeers, 0)
 		require.Equal(t, 200*time.Millisecond, cfg.UnifiedAlerting.HAGossipInterval)
 		require.Equal(t, 60*time.Second, cfg.UnifiedAlerting.HAPushPullInterval)
+		require.True(t, cfg.UnifiedAlerting.StateHistory.Enabled)
+		require.Equal(t, StateHistoryBackendSQL, cfg.UnifiedAlerting.StateHistory.Backend)
+		require.Equal(t, 30*24*time.Hour, cfg.UnifiedAlerting.StateHistory.Retention)
+		require.False(t, cfg.UnifiedAlerting.RecordingRules.Enabled)
+		require.Equal(t, RecordingRulesTargetInternal, cfg.UnifiedAlerting.RecordingRules.Target)
+		require.False(t, cfg.UnifiedAlerting.HAEvaluationSharding)
+		require.Equal(t, 10*time.Second, cfg.UnifiedAlerting.HAHeartbeatInterval)
+		require.Equal(t, time.Minute, cfg.UnifiedAlerting.HAHeartbeatTimeout)
+		require.False(t, cfg.UnifiedAlerting.RuleMetrics.Enabled)
+		require.Equal(t, 1000, cfg.UnifiedAlerting.RuleMetrics.MaxRules)
+		require.True(t, cfg.UnifiedAlerting.NotificationLog.Enabled)
+		require.Equal(t, 30*24*time.Hour, cfg.UnifiedAlerting.NotificationLog.Retention)
+		require.Equal(t, 4096, cfg.UnifiedAlerting.NotificationLog.MaxPayloadSize)
 	}
 
 	// With peers set, it correctly parses them.
@@ -39,6 +52,100 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 		require.Len(t, cfg.UnifiedAlerting.HAPeers, 3)
 		require.ElementsMatch(t, []string{"hostname1:9090", "hostname2:9090", "hostname3:9090"}, cfg.UnifiedAlerting.HAPeers)
 	}
+
+	// With an unknown state history backend, it returns an error.
+	{
+		s, err := cfg.Raw.NewSection("unified_alerting.state_history")
+		require.NoError(t, err)
+		_, err = s.NewKey("backend", "loki")
+		require.NoError(t, err)
+
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+
+		s.Key("backend").SetValue(StateHistoryBackendMemory)
+		_, err = s.NewKey("retention", "7d")
+		require.NoError(t, err)
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, StateHistoryBackendMemory, cfg.UnifiedAlerting.StateHistory.Backend)
+		require.Equal(t, 7*24*time.Hour, cfg.UnifiedAlerting.StateHistory.Retention)
+	}
+
+	// With the remote write target of recording rules, it requires the URL of the endpoint.
+	{
+		s, err := cfg.Raw.NewSection("unified_alerting.recording_rules")
+		require.NoError(t, err)
+		_, err = s.NewKey("enabled", "true")
+		require.NoError(t, err)
+		_, err = s.NewKey("target", RecordingRulesTargetRemoteWrite)
+		require.NoError(t, err)
+
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+
+		_, err = s.NewKey("remote_write_url", "http://localhost:9090/api/v1/write")
+		require.NoError(t, err)
+		_, err = s.NewKey("remote_write_timeout", "5s")
+		require.NoError(t, err)
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.True(t, cfg.UnifiedAlerting.RecordingRules.Enabled)
+		require.Equal(t, "http://localhost:9090/api/v1/write", cfg.UnifiedAlerting.RecordingRules.RemoteWriteURL)
+		require.Equal(t, 5*time.Second, cfg.UnifiedAlerting.RecordingRules.RemoteWriteTimeout)
+	}
+
+	// With evaluation sharding, the heartbeat timeout must be greater than the interval.
+	{
+		s := cfg.Raw.Section("unified_alerting")
+		_, err := s.NewKey("ha_evaluation_sharding", "true")
+		require.NoError(t, err)
+		_, err = s.NewKey("ha_instance_id", "grafana-1")
+		require.NoError(t, err)
+		_, err = s.NewKey("ha_heartbeat_interval", "30s")
+		require.NoError(t, err)
+		_, err = s.NewKey("ha_heartbeat_timeout", "30s")
+		require.NoError(t, err)
+
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+
+		s.Key("ha_heartbeat_timeout").SetValue("2m")
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.True(t, cfg.UnifiedAlerting.HAEvaluationSharding)
+		require.Equal(t, "grafana-1", cfg.UnifiedAlerting.HAInstanceID)
+		require.Equal(t, 30*time.Second, cfg.UnifiedAlerting.HAHeartbeatInterval)
+		require.Equal(t, 2*time.Minute, cfg.UnifiedAlerting.HAHeartbeatTimeout)
+	}
+
+	// With per-rule metrics, the maximum number of rules must be positive.
+	{
+		s, err := cfg.Raw.NewSection("unified_alerting.rule_metrics")
+		require.NoError(t, err)
+		_, err = s.NewKey("enabled", "true")
+		require.NoError(t, err)
+		_, err = s.NewKey("max_rules", "0")
+		require.NoError(t, err)
+
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+
+		s.Key("max_rules").SetValue("50")
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.True(t, cfg.UnifiedAlerting.RuleMetrics.Enabled)
+		require.Equal(t, 50, cfg.UnifiedAlerting.RuleMetrics.MaxRules)
+	}
+
+	// With the notification log, the retention must be positive.
+	{
+		s, err := cfg.Raw.NewSection("unified_alerting.notification_log")
+		require.NoError(t, err)
+		_, err = s.NewKey("retention", "0s")
+		require.NoError(t, err)
+
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+
+		s.Key("retention").SetValue("90d")
+		_, err = s.NewKey("max_payload_size", "1024")
+		require.NoError(t, err)
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, 90*24*time.Hour, cfg.UnifiedAlerting.NotificationLog.Retention)
+		require.Equal(t, 1024, cfg.UnifiedAlerting.NotificationLog.MaxPayloadSize)
+	}
 }
 
 func TestUnifiedAlertingSettings(t *testing.T) {
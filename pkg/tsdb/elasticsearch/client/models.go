@@ -34,7 +34,7 @@ type SearchRequest struct {
 	Index       string
 	Interval    intervalv2.Interval
 	Size        int
-	Sort        map[string]interface{}
+	Sort        []map[string]interface{}
 	Query       *Query
 	Aggs        AggArray
 	CustomProps map[string]interface{}
@@ -144,6 +144,21 @@ type RangeFilter struct {
 // DateFormatEpochMS represents a date format of epoch milliseconds (epoch_millis)
 const DateFormatEpochMS = "epoch_millis"
 
+// Sort orders of search requests
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+const (
+	// HighlightPreTagsString is the tag inserted before the highlighted matches of hits
+	HighlightPreTagsString = "@HIGHLIGHT@"
+	// HighlightPostTagsString is the tag inserted after the highlighted matches of hits
+	HighlightPostTagsString = "@/HIGHLIGHT@"
+	// HighlightFragmentSize is the size of highlighted fragments, large enough to highlight whole fields
+	HighlightFragmentSize = 2147483647
+)
+
 // MarshalJSON returns the JSON encoding of the query string filter.
 func (f *RangeFilter) MarshalJSON() ([]byte, error) {
 	root := map[string]map[string]map[string]interface{}{
@@ -13,7 +13,7 @@ type SearchRequestBuilder struct {
 	interval     intervalv2.Interval
 	index        string
 	size         int
-	sort         map[string]interface{}
+	sort         []map[string]interface{}
 	queryBuilder *QueryBuilder
 	aggBuilders  []AggBuilder
 	customProps  map[string]interface{}
@@ -24,7 +24,7 @@ func NewSearchRequestBuilder(version *semver.Version, interval intervalv2.Interv
 	builder := &SearchRequestBuilder{
 		version:     version,
 		interval:    interval,
-		sort:        make(map[string]interface{}),
+		sort:        make([]map[string]interface{}, 0),
 		customProps: make(map[string]interface{}),
 		aggBuilders: make([]AggBuilder, 0),
 	}
@@ -70,17 +70,44 @@ func (b *SearchRequestBuilder) Size(size int) *SearchRequestBuilder {
 	return b
 }
 
-// SortDesc adds a sort to the search request
+// SortDesc adds a descending sort to the search request
 func (b *SearchRequestBuilder) SortDesc(field, unmappedType string) *SearchRequestBuilder {
+	return b.Sort(SortOrderDesc, field, unmappedType)
+}
+
+// Sort adds a sort to the search request. Hits are sorted by the sorts in the order they are added.
+func (b *SearchRequestBuilder) Sort(order, field, unmappedType string) *SearchRequestBuilder {
 	props := map[string]string{
-		"order": "desc",
+		"order": order,
 	}
 
 	if unmappedType != "" {
 		props["unmapped_type"] = unmappedType
 	}
 
-	b.sort[field] = props
+	b.sort = append(b.sort, map[string]interface{}{field: props})
+
+	return b
+}
+
+// AddSearchAfter adds a value of the sort values of the hit after which the search starts
+func (b *SearchRequestBuilder) AddSearchAfter(value interface{}) *SearchRequestBuilder {
+	searchAfter, _ := b.customProps["search_after"].([]interface{})
+	b.customProps["search_after"] = append(searchAfter, value)
+
+	return b
+}
+
+// AddHighlight highlights the matches of the query in all the fields of the hits
+func (b *SearchRequestBuilder) AddHighlight() *SearchRequestBuilder {
+	b.customProps["highlight"] = map[string]interface{}{
+		"fields": map[string]interface{}{
+			"*": map[string]interface{}{},
+		},
+		"pre_tags":      []string{HighlightPreTagsString},
+		"post_tags":     []string{HighlightPostTagsString},
+		"fragment_size": HighlightFragmentSize,
+	}
 
 	return b
 }
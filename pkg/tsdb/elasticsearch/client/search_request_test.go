@@ -62,7 +62,8 @@ func TestSearchRequest(t *testing.T) {
 			})
 
 			t.Run("Should have correct sorting", func(t *testing.T) {
-				sort, ok := sr.Sort[timeField].(map[string]string)
+				require.Len(t, sr.Sort, 1)
+				sort, ok := sr.Sort[0][timeField].(map[string]string)
 				require.True(t, ok)
 				require.Equal(t, "desc", sort["order"])
 				require.Equal(t, "boolean", sort["unmapped_type"])
@@ -90,7 +91,7 @@ func TestSearchRequest(t *testing.T) {
 				require.Nil(t, err)
 				require.Equal(t, 200, json.Get("size").MustInt(0))
 
-				sort := json.GetPath("sort", timeField)
+				sort := json.Get("sort").GetIndex(0).Get(timeField)
 				require.Equal(t, "desc", sort.Get("order").MustString())
 				require.Equal(t, "boolean", sort.Get("unmapped_type").MustString())
 
@@ -54,6 +54,8 @@ var metricAggType = map[string]string{
 	"serial_diff":    "Serial Difference",
 	"bucket_script":  "Bucket Script",
 	"raw_document":   "Raw Document",
+	"raw_data":       "Raw Data",
+	"logs":           "Logs",
 	"rate":           "Rate",
 }
 
@@ -91,6 +93,23 @@ var pipelineAggWithMultipleBucketPathsType = map[string]string{
 	"bucket_script": "bucket_script",
 }
 
+func isLogsQuery(q *Query) bool {
+	return len(q.Metrics) > 0 && q.Metrics[0].Type == logsType
+}
+
+// isDocumentQuery returns true for the queries that return the documents matching the query
+// instead of aggregations.
+func isDocumentQuery(q *Query) bool {
+	if len(q.Metrics) == 0 {
+		return false
+	}
+	switch q.Metrics[0].Type {
+	case rawDocumentType, rawDataType, logsType:
+		return true
+	}
+	return false
+}
+
 func isPipelineAgg(metricType string) bool {
 	if _, ok := pipelineAggType[metricType]; ok {
 		return true
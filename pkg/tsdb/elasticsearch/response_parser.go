@@ -1,7 +1,9 @@
 package elasticsearch
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"regexp"
 	"sort"
 	"strconv"
@@ -20,6 +22,9 @@ const (
 	percentilesType   = "percentiles"
 	extendedStatsType = "extended_stats"
 	topMetricsType    = "top_metrics"
+	rawDocumentType   = "raw_document"
+	rawDataType       = "raw_data"
+	logsType          = "logs"
 	// Bucket types
 	dateHistType    = "date_histogram"
 	histogramType   = "histogram"
@@ -29,16 +34,19 @@ const (
 )
 
 type responseParser struct {
-	Responses []*es.SearchResponse
-	Targets   []*Query
-	DebugInfo *es.SearchDebugInfo
+	Responses        []*es.SearchResponse
+	Targets          []*Query
+	DebugInfo        *es.SearchDebugInfo
+	ConfiguredFields es.ConfiguredFields
 }
 
-var newResponseParser = func(responses []*es.SearchResponse, targets []*Query, debugInfo *es.SearchDebugInfo) *responseParser {
+var newResponseParser = func(responses []*es.SearchResponse, targets []*Query, debugInfo *es.SearchDebugInfo,
+	configuredFields es.ConfiguredFields) *responseParser {
 	return &responseParser{
-		Responses: responses,
-		Targets:   targets,
-		DebugInfo: debugInfo,
+		Responses:        responses,
+		Targets:          targets,
+		DebugInfo:        debugInfo,
+		ConfiguredFields: configuredFields,
 	}
 }
 
@@ -74,17 +82,26 @@ func (rp *responseParser) getTimeSeries() (*backend.QueryDataResponse, error) {
 
 		queryRes := backend.DataResponse{}
 
-		props := make(map[string]string)
-		err := rp.processBuckets(res.Aggregations, target, &queryRes, props, 0)
-		if err != nil {
-			return &backend.QueryDataResponse{}, err
+		if isDocumentQuery(target) {
+			if err := rp.processDocuments(res, target, &queryRes); err != nil {
+				return &backend.QueryDataResponse{}, err
+			}
+		} else {
+			props := make(map[string]string)
+			err := rp.processBuckets(res.Aggregations, target, &queryRes, props, 0)
+			if err != nil {
+				return &backend.QueryDataResponse{}, err
+			}
+			rp.nameFields(queryRes, target)
+			rp.trimDatapoints(queryRes, target)
 		}
-		rp.nameFields(queryRes, target)
-		rp.trimDatapoints(queryRes, target)
 
 		for _, frame := range queryRes.Frames {
-			frame.Meta = &data.FrameMeta{
-				Custom: debugInfo,
+			if frame.Meta == nil {
+				frame.Meta = &data.FrameMeta{}
+			}
+			if frame.Meta.Custom == nil {
+				frame.Meta.Custom = debugInfo
 			}
 		}
 		result.Responses[target.RefID] = queryRes
@@ -719,3 +736,281 @@ func getErrorFromElasticResponse(response *es.SearchResponse) string {
 
 	return errorString
 }
+
+// flattenDepth is the maximum depth of the objects of documents flattened into fields.
+const flattenDepth = 10
+
+var highlightWordsRegex = regexp.MustCompile(regexp.QuoteMeta(es.HighlightPreTagsString) + `(.*?)` + regexp.QuoteMeta(es.HighlightPostTagsString))
+
+// logsFrameMeta is the custom metadata of the frames of the logs of logs queries.
+type logsFrameMeta struct {
+	// SearchWords are the highlighted matches of the query in the logs.
+	SearchWords []string `json:"searchWords,omitempty"`
+	Limit       int      `json:"limit"`
+	// SearchAfter is the sort values of the last log, used as the searchAfter setting of the query to
+	// get the next page of logs.
+	SearchAfter interface{} `json:"searchAfter,omitempty"`
+}
+
+// processDocuments builds the frame of the documents of raw document, raw data and logs queries.
+// The sources of the documents are flattened into fields. Logs queries also return the histogram
+// of the count of their logs.
+func (rp *responseParser) processDocuments(res *es.SearchResponse, target *Query, queryRes *backend.DataResponse) error {
+	isLogs := isLogsQuery(target)
+	timeField := target.TimeField
+	if timeField == "" {
+		timeField = rp.ConfiguredFields.TimeField
+	}
+
+	var hits []map[string]interface{}
+	if res.Hits != nil {
+		hits = res.Hits.Hits
+	}
+	docs := make([]map[string]interface{}, 0, len(hits))
+	propNames := make(map[string]bool)
+	searchWords := make(map[string]bool)
+	for _, hit := range hits {
+		doc := flattenHit(hit, timeField)
+		if isLogs {
+			if rp.ConfiguredFields.LogLevelField != "" {
+				doc["level"] = doc[rp.ConfiguredFields.LogLevelField]
+			}
+			addSearchWords(searchWords, hit["highlight"])
+		}
+		for name := range doc {
+			propNames[name] = true
+		}
+		docs = append(docs, doc)
+	}
+
+	// the time field and the configured log fields come first, followed by the other fields by name
+	leadingFields := []string{timeField}
+	if isLogs {
+		if rp.ConfiguredFields.LogMessageField != "" {
+			leadingFields = append(leadingFields, rp.ConfiguredFields.LogMessageField)
+		}
+		if rp.ConfiguredFields.LogLevelField != "" {
+			leadingFields = append(leadingFields, "level")
+		}
+	}
+	names := make([]string, 0, len(propNames))
+	for name := range propNames {
+		// the sources are only shown as a whole with the raw documents and the logs
+		if name == "_source" && target.Metrics[0].Type == rawDataType {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := []*data.Field{newDocumentTimeField(timeField, docs)}
+	added := map[string]bool{timeField: true}
+	for _, name := range append(leadingFields[1:], names...) {
+		if added[name] {
+			continue
+		}
+		added[name] = true
+		fields = append(fields, newDocumentField(name, docs))
+	}
+
+	frame := data.NewFrame("", fields...)
+	if isLogs {
+		meta := logsFrameMeta{Limit: documentQuerySize(target.Metrics[0])}
+		for word := range searchWords {
+			meta.SearchWords = append(meta.SearchWords, word)
+		}
+		sort.Strings(meta.SearchWords)
+		if len(hits) > 0 {
+			meta.SearchAfter = hits[len(hits)-1]["sort"]
+		}
+		frame.Meta = &data.FrameMeta{
+			PreferredVisualization: data.VisTypeLogs,
+			Custom:                 meta,
+		}
+	}
+	queryRes.Frames = append(queryRes.Frames, frame)
+
+	if !isLogs || len(res.Aggregations) == 0 {
+		return nil
+	}
+
+	histogramTarget := *target
+	histogramTarget.BucketAggs = []*BucketAgg{logsHistogramAgg(target, timeField)}
+	histogramTarget.Metrics = []*MetricAgg{{ID: target.Metrics[0].ID, Type: countType, Settings: simplejson.New()}}
+	histogramRes := backend.DataResponse{}
+	if err := rp.processBuckets(res.Aggregations, &histogramTarget, &histogramRes, make(map[string]string), 0); err != nil {
+		return err
+	}
+	rp.nameFields(histogramRes, &histogramTarget)
+	for _, frame := range histogramRes.Frames {
+		frame.Meta = &data.FrameMeta{
+			PreferredVisualization: data.VisTypeGraph,
+		}
+		queryRes.Frames = append(queryRes.Frames, frame)
+	}
+	return nil
+}
+
+// flattenHit returns the metadata and the flattened source of a hit. The time of the hit is taken
+// from its doc value fields when they contain it.
+func flattenHit(hit map[string]interface{}, timeField string) map[string]interface{} {
+	doc := map[string]interface{}{
+		"_id":       hit["_id"],
+		"_type":     hit["_type"],
+		"_index":    hit["_index"],
+		"sort":      hit["sort"],
+		"highlight": hit["highlight"],
+	}
+
+	if source, ok := hit["_source"].(map[string]interface{}); ok {
+		doc["_source"] = source
+		flattenObject(doc, "", source, 0)
+	}
+
+	if fields, ok := hit["fields"].(map[string]interface{}); ok {
+		if values, ok := fields[timeField].([]interface{}); ok && len(values) > 0 {
+			doc[timeField] = values[0]
+		}
+	}
+
+	return doc
+}
+
+func flattenObject(doc map[string]interface{}, prefix string, object map[string]interface{}, depth int) {
+	for key, value := range object {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		if child, ok := value.(map[string]interface{}); ok && depth < flattenDepth && len(child) > 0 {
+			flattenObject(doc, name, child, depth+1)
+			continue
+		}
+		doc[name] = value
+	}
+}
+
+// addSearchWords adds the highlighted matches of the highlight of a hit to the search words.
+func addSearchWords(searchWords map[string]bool, highlight interface{}) {
+	fields, ok := highlight.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, fragments := range fields {
+		list, ok := fragments.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, fragment := range list {
+			text, ok := fragment.(string)
+			if !ok {
+				continue
+			}
+			for _, match := range highlightWordsRegex.FindAllStringSubmatch(text, -1) {
+				searchWords[match[1]] = true
+			}
+		}
+	}
+}
+
+func newDocumentTimeField(name string, docs []map[string]interface{}) *data.Field {
+	values := make([]*time.Time, 0, len(docs))
+	for _, doc := range docs {
+		values = append(values, parseDocumentTime(doc[name]))
+	}
+	field := data.NewField(name, nil, values)
+	field.Config = (&data.FieldConfig{}).SetFilterable(true)
+	return field
+}
+
+// parseDocumentTime parses the time of a document, either a date or a number of milliseconds since
+// the epoch.
+func parseDocumentTime(value interface{}) *time.Time {
+	var t time.Time
+	switch v := value.(type) {
+	case float64:
+		t = time.Unix(0, int64(v)*int64(time.Millisecond)).UTC()
+	case string:
+		var err error
+		if t, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			ms, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil
+			}
+			t = time.Unix(0, ms*int64(time.Millisecond)).UTC()
+		}
+	default:
+		return nil
+	}
+	return &t
+}
+
+// newDocumentField returns the field of the documents with the name. Fields whose values are all
+// numbers or all booleans are typed accordingly, the other values are converted to strings, and
+// objects and arrays to their JSON encoding.
+func newDocumentField(name string, docs []map[string]interface{}) *data.Field {
+	var numbers, booleans, others int
+	for _, doc := range docs {
+		switch doc[name].(type) {
+		case nil:
+		case float64:
+			numbers++
+		case bool:
+			booleans++
+		default:
+			others++
+		}
+	}
+
+	var field *data.Field
+	switch {
+	case numbers > 0 && booleans == 0 && others == 0:
+		values := make([]*float64, 0, len(docs))
+		for _, doc := range docs {
+			if v, ok := doc[name].(float64); ok {
+				values = append(values, &v)
+			} else {
+				values = append(values, nil)
+			}
+		}
+		field = data.NewField(name, nil, values)
+	case booleans > 0 && numbers == 0 && others == 0:
+		values := make([]*bool, 0, len(docs))
+		for _, doc := range docs {
+			if v, ok := doc[name].(bool); ok {
+				values = append(values, &v)
+			} else {
+				values = append(values, nil)
+			}
+		}
+		field = data.NewField(name, nil, values)
+	default:
+		values := make([]*string, 0, len(docs))
+		for _, doc := range docs {
+			values = append(values, documentValueString(doc[name]))
+		}
+		field = data.NewField(name, nil, values)
+	}
+	field.Config = (&data.FieldConfig{}).SetFilterable(true)
+	return field
+}
+
+func documentValueString(value interface{}) *string {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		s = v
+	case float64, bool:
+		s = fmt.Sprint(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			s = fmt.Sprint(v)
+		} else {
+			s = string(b)
+		}
+	}
+	return &s
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1061,6 +1062,119 @@ func TestResponseParser(t *testing.T) {
 	})
 }
 
+func TestProcessLogsResponse(t *testing.T) {
+	targets := map[string]string{
+		"A": `{
+			"timeField": "@timestamp",
+			"metrics": [{ "type": "logs", "id": "1", "settings": { "limit": "2" } }],
+			"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "2" }]
+		}`,
+	}
+	response := `{
+		"responses": [
+			{
+				"aggregations": {
+					"2": { "buckets": [{ "doc_count": 2, "key": 1609459200000 }] }
+				},
+				"hits": {
+					"hits": [
+						{
+							"_id": "fdsfs", "_type": "_doc", "_index": "logs-2021.01.01",
+							"_source": { "@timestamp": "2021-01-01T00:00:01.000Z", "line": "disk full", "lvl": "error", "host": { "name": "db-1" }, "bytes": 10 },
+							"fields": { "@timestamp": ["2021-01-01T00:00:01.000Z"] },
+							"sort": [1609459201000, 1],
+							"highlight": { "line": ["@HIGHLIGHT@disk@/HIGHLIGHT@ full"] }
+						},
+						{
+							"_id": "kdospaidopa", "_type": "_doc", "_index": "logs-2021.01.01",
+							"_source": { "@timestamp": "2021-01-01T00:00:00.000Z", "line": "ok", "lvl": "info", "host": { "name": "db-2" } },
+							"sort": [1609459200000, 2]
+						}
+					]
+				}
+			}
+		]
+	}`
+	rp, err := newResponseParserForTest(targets, response)
+	require.NoError(t, err)
+	rp.ConfiguredFields = es.ConfiguredFields{TimeField: "@timestamp", LogMessageField: "line", LogLevelField: "lvl"}
+	result, err := rp.getTimeSeries()
+	require.NoError(t, err)
+
+	frames := result.Responses["A"].Frames
+	require.Len(t, frames, 2)
+
+	logs := frames[0]
+	require.Equal(t, data.VisType(data.VisTypeLogs), logs.Meta.PreferredVisualization)
+	meta := logs.Meta.Custom.(logsFrameMeta)
+	require.Equal(t, []string{"disk"}, meta.SearchWords)
+	require.Equal(t, 2, meta.Limit)
+	require.Equal(t, []interface{}{float64(1609459200000), float64(2)}, meta.SearchAfter)
+
+	names := make([]string, 0, len(logs.Fields))
+	for _, f := range logs.Fields {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"@timestamp", "line", "level", "_id", "_index", "_source", "_type", "bytes", "highlight", "host.name", "lvl", "sort"}, names)
+
+	require.Equal(t, time.Date(2021, 1, 1, 0, 0, 1, 0, time.UTC), *logs.Fields[0].At(0).(*time.Time))
+	require.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), *logs.Fields[0].At(1).(*time.Time))
+	require.Equal(t, "disk full", *logs.Fields[1].At(0).(*string))
+	require.Equal(t, "error", *logs.Fields[2].At(0).(*string))
+	require.Equal(t, 10., *logs.Fields[7].At(0).(*float64))
+	require.Nil(t, logs.Fields[7].At(1))
+	require.Equal(t, "db-2", *logs.Fields[9].At(1).(*string))
+	require.Equal(t, "[1609459201000,1]", *logs.Fields[11].At(0).(*string))
+
+	histogram := frames[1]
+	require.Equal(t, data.VisTypeGraph, histogram.Meta.PreferredVisualization)
+	require.Len(t, histogram.Fields, 2)
+	require.Equal(t, 2., *histogram.Fields[1].At(0).(*float64))
+}
+
+func TestProcessRawDataResponse(t *testing.T) {
+	targets := map[string]string{
+		"A": `{
+			"timeField": "@timestamp",
+			"metrics": [{ "type": "raw_data", "id": "1" }],
+			"bucketAggs": []
+		}`,
+	}
+	response := `{
+		"responses": [
+			{
+				"hits": {
+					"hits": [
+						{
+							"_id": "1", "_type": "_doc", "_index": "metrics",
+							"_source": { "@timestamp": 1609459200000, "up": true, "tags": ["a", "b"] }
+						}
+					]
+				}
+			}
+		]
+	}`
+	rp, err := newResponseParserForTest(targets, response)
+	require.NoError(t, err)
+	result, err := rp.getTimeSeries()
+	require.NoError(t, err)
+
+	frames := result.Responses["A"].Frames
+	require.Len(t, frames, 1)
+	frame := frames[0]
+	require.Empty(t, frame.Meta.PreferredVisualization)
+
+	names := make([]string, 0, len(frame.Fields))
+	for _, f := range frame.Fields {
+		names = append(names, f.Name)
+	}
+	// the source is not a field of raw data, and logs fields are not added
+	require.Equal(t, []string{"@timestamp", "_id", "_index", "_type", "highlight", "sort", "tags", "up"}, names)
+	require.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), *frame.Fields[0].At(0).(*time.Time))
+	require.Equal(t, `["a","b"]`, *frame.Fields[6].At(0).(*string))
+	require.True(t, *frame.Fields[7].At(0).(*bool))
+}
+
 func newResponseParserForTest(tsdbQueries map[string]string, responseBody string) (*responseParser, error) {
 	from := time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC)
 	to := time.Date(2018, 5, 15, 17, 55, 0, 0, time.UTC)
@@ -1092,5 +1206,5 @@ func newResponseParserForTest(tsdbQueries map[string]string, responseBody string
 		return nil, err
 	}
 
-	return newResponseParser(response.Responses, queries, nil), nil
+	return newResponseParser(response.Responses, queries, nil, es.ConfiguredFields{TimeField: "@timestamp"}), nil
 }
@@ -13,6 +13,12 @@ import (
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
 )
 
+const (
+	defaultDocumentQuerySize = 500
+	// logsHistogramAggID is the ID of the date histogram of logs queries that have none
+	logsHistogramAggID = "logs_histogram"
+)
+
 type timeSeriesQuery struct {
 	client             es.Client
 	dataQueries        []backend.DataQuery
@@ -58,7 +64,7 @@ func (e *timeSeriesQuery) execute() (*backend.QueryDataResponse, error) {
 		return &backend.QueryDataResponse{}, err
 	}
 
-	rp := newResponseParser(res.Responses, queries, res.DebugInfo)
+	rp := newResponseParser(res.Responses, queries, res.DebugInfo, e.client.GetConfiguredFields())
 	return rp.getTimeSeries()
 }
 
@@ -79,17 +85,15 @@ func (e *timeSeriesQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilde
 		filters.AddQueryStringFilter(q.RawQuery, true)
 	}
 
+	if isDocumentQuery(q) {
+		processDocumentQuery(q, b, from, to, e.client.GetTimeField())
+		return nil
+	}
+
 	if len(q.BucketAggs) == 0 {
-		if len(q.Metrics) == 0 || q.Metrics[0].Type != "raw_document" {
-			result.Responses[q.RefID] = backend.DataResponse{
-				Error: fmt.Errorf("invalid query, missing metrics and aggregations"),
-			}
-			return nil
+		result.Responses[q.RefID] = backend.DataResponse{
+			Error: fmt.Errorf("invalid query, missing metrics and aggregations"),
 		}
-		metric := q.Metrics[0]
-		b.Size(metric.Settings.Get("size").MustInt(500))
-		b.SortDesc("@timestamp", "boolean")
-		b.AddDocValueField("@timestamp")
 		return nil
 	}
 
@@ -182,6 +186,77 @@ func (e *timeSeriesQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilde
 	return nil
 }
 
+// processDocumentQuery builds the search of the documents of raw document, raw data and logs
+// queries. Documents are sorted by time, and then by index order so that pages of documents
+// requested with the searchAfter setting do not overlap.
+func processDocumentQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, timeField string) {
+	metric := q.Metrics[0]
+	order := es.SortOrderDesc
+	if metric.Settings.Get("sortDirection").MustString() == es.SortOrderAsc {
+		order = es.SortOrderAsc
+	}
+	b.Sort(order, timeField, "boolean")
+	b.Sort(order, "_doc", "")
+	b.AddDocValueField(timeField)
+	b.Size(documentQuerySize(metric))
+
+	for _, value := range metric.Settings.Get("searchAfter").MustArray() {
+		b.AddSearchAfter(value)
+	}
+
+	if !isLogsQuery(q) {
+		return
+	}
+
+	b.AddHighlight()
+
+	// logs are returned with the histogram of their count
+	histogramAgg := logsHistogramAgg(q, timeField)
+	histogramAgg.Settings = simplejson.NewFromAny(histogramAgg.generateSettingsForDSL())
+	q.BucketAggs = []*BucketAgg{histogramAgg}
+	addDateHistogramAgg(b.Agg(), histogramAgg, from, to)
+}
+
+// documentQuerySize returns the number of documents of a document query. The query editor saves
+// the size of logs queries as their limit, and can save sizes as strings.
+func documentQuerySize(metric *MetricAgg) int {
+	key := "size"
+	if metric.Type == logsType {
+		key = "limit"
+	}
+
+	size, err := metric.Settings.Get(key).Int()
+	if err != nil {
+		if size, err = strconv.Atoi(metric.Settings.Get(key).MustString()); err != nil {
+			size = defaultDocumentQuerySize
+		}
+	}
+	if size <= 0 {
+		return defaultDocumentQuerySize
+	}
+	return size
+}
+
+// logsHistogramAgg returns the date histogram of the query used to count its logs, or a date
+// histogram on the time field if the query has none.
+func logsHistogramAgg(q *Query, timeField string) *BucketAgg {
+	for _, bucketAgg := range q.BucketAggs {
+		if bucketAgg.Type == dateHistType {
+			histogramAgg := *bucketAgg
+			if histogramAgg.Field == "" {
+				histogramAgg.Field = timeField
+			}
+			return &histogramAgg
+		}
+	}
+	return &BucketAgg{
+		ID:       logsHistogramAggID,
+		Type:     dateHistType,
+		Field:    timeField,
+		Settings: simplejson.NewFromAny(map[string]interface{}{"interval": "auto"}),
+	}
+}
+
 func setFloatPath(settings *simplejson.Json, path ...string) {
 	if stringValue, err := settings.GetPath(path...).String(); err == nil {
 		if value, err := strconv.ParseFloat(stringValue, 64); err == nil {
@@ -377,6 +377,71 @@ func TestExecuteTimeSeriesQuery(t *testing.T) {
 			require.Equal(t, sr.Size, 1337)
 		})
 
+		t.Run("With raw data metric", func(t *testing.T) {
+			c := newFakeClient("7.0.0")
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [],
+				"metrics": [{ "id": "1", "type": "raw_data", "settings": { "size": "1000", "sortDirection": "asc" } }]
+			}`, from, to, 15*time.Second)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			require.Equal(t, 1000, sr.Size)
+			require.Equal(t, []map[string]interface{}{
+				{"@timestamp": map[string]string{"order": "asc", "unmapped_type": "boolean"}},
+				{"_doc": map[string]string{"order": "asc"}},
+			}, sr.Sort)
+			require.Equal(t, []string{"@timestamp"}, sr.CustomProps["docvalue_fields"])
+			require.Nil(t, sr.CustomProps["highlight"])
+			require.Len(t, sr.Aggs, 0)
+		})
+
+		t.Run("With logs metric", func(t *testing.T) {
+			c := newFakeClient("7.0.0")
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"query": "level:error",
+				"bucketAggs": [],
+				"metrics": [{ "id": "1", "type": "logs", "settings": { "limit": "100", "searchAfter": [1609459200000, 42] } }]
+			}`, from, to, 15*time.Second)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			require.Equal(t, 100, sr.Size)
+			require.Equal(t, map[string]string{"order": "desc", "unmapped_type": "boolean"}, sr.Sort[0]["@timestamp"])
+			require.Equal(t, []interface{}{json.Number("1609459200000"), json.Number("42")}, sr.CustomProps["search_after"])
+
+			highlight := sr.CustomProps["highlight"].(map[string]interface{})
+			require.Equal(t, []string{es.HighlightPreTagsString}, highlight["pre_tags"])
+			require.Equal(t, []string{es.HighlightPostTagsString}, highlight["post_tags"])
+
+			require.Equal(t, "level:error", sr.Query.Bool.Filters[1].(*es.QueryStringFilter).Query)
+
+			// logs queries without a date histogram get one on the time field
+			require.Len(t, sr.Aggs, 1)
+			require.Equal(t, logsHistogramAggID, sr.Aggs[0].Key)
+			hAgg := sr.Aggs[0].Aggregation.Aggregation.(*es.DateHistogramAgg)
+			require.Equal(t, "@timestamp", hAgg.Field)
+			require.Equal(t, "$__interval", hAgg.Interval)
+		})
+
+		t.Run("With logs metric and date histogram agg", func(t *testing.T) {
+			c := newFakeClient("7.0.0")
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [{ "id": "2", "type": "date_histogram", "field": "@timestamp", "settings": { "interval": "1m" } }],
+				"metrics": [{ "id": "1", "type": "logs" }]
+			}`, from, to, 15*time.Second)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			require.Equal(t, 500, sr.Size)
+			require.Len(t, sr.Aggs, 1)
+			require.Equal(t, "2", sr.Aggs[0].Key)
+			require.Equal(t, "1m", sr.Aggs[0].Aggregation.Aggregation.(*es.DateHistogramAgg).Interval)
+		})
+
 		t.Run("With date histogram agg", func(t *testing.T) {
 			c := newFakeClient("5.0.0")
 			_, err := executeTsdbQuery(c, `{
@@ -1212,6 +1277,10 @@ func (c *fakeClient) GetTimeField() string {
 	return c.timeField
 }
 
+func (c *fakeClient) GetConfiguredFields() es.ConfiguredFields {
+	return es.ConfiguredFields{TimeField: c.timeField, LogMessageField: "line", LogLevelField: "lvl"}
+}
+
 func (c *fakeClient) GetMinInterval(queryInterval string) (time.Duration, error) {
 	return 15 * time.Second, nil
 }
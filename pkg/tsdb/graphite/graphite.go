@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -26,13 +27,15 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/httpresource"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 )
 
 type Service struct {
-	logger log.Logger
-	im     instancemgmt.InstanceManager
-	tracer tracing.Tracer
+	logger          log.Logger
+	im              instancemgmt.InstanceManager
+	tracer          tracing.Tracer
+	resourceHandler backend.CallResourceHandler
 }
 
 const (
@@ -41,17 +44,26 @@ const (
 )
 
 func ProvideService(httpClientProvider httpclient.Provider, tracer tracing.Tracer) *Service {
-	return &Service{
+	s := &Service{
 		logger: log.New("tsdb.graphite"),
 		im:     datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
 		tracer: tracer,
 	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
 }
 
 type datasourceInfo struct {
 	HTTPClient *http.Client
 	URL        string
 	Id         int64
+
+	resourceCache *httpresource.Cache
+}
+
+// Dispose empties the resource cache when the data source is updated or deleted.
+func (dsInfo datasourceInfo) Dispose() {
+	dsInfo.resourceCache.Dispose()
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -67,9 +79,10 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 		}
 
 		model := datasourceInfo{
-			HTTPClient: client,
-			URL:        settings.URL,
-			Id:         settings.ID,
+			HTTPClient:    client,
+			URL:           settings.URL,
+			Id:            settings.ID,
+			resourceCache: httpresource.NewCache(),
 		}
 
 		return model, nil
@@ -85,6 +98,45 @@ func (s *Service) getDSInfo(pluginCtx backend.PluginContext) (*datasourceInfo, e
 	return &instance, nil
 }
 
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
+// CheckHealth renders a constant series over the last hour, as the data source editor did.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	graphiteReq, err := s.createRequest(ctx, dsInfo, url.Values{
+		"target":        []string{"constantLine(100)"},
+		"from":          []string{"-1h"},
+		"until":         []string{"now"},
+		"format":        []string{"json"},
+		"maxDataPoints": []string{"300"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := dsInfo.HTTPClient.Do(graphiteReq)
+	if err == nil {
+		_, err = s.parseResponse(res)
+	}
+	if err != nil {
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: fmt.Sprintf("Graphite request failed: %v", err),
+		}, nil
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: "Data source is working",
+	}, nil
+}
+
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	if len(req.Queries) == 0 {
 		return nil, fmt.Errorf("query contains no queries")
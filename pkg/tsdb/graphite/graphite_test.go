@@ -1,14 +1,18 @@
 package graphite
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/stretchr/testify/assert"
@@ -112,3 +116,158 @@ func TestFixIntervalFormat(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckHealth(t *testing.T) {
+	t.Run("it renders a constant series", func(t *testing.T) {
+		var target string
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			require.Equal(t, "/render", req.URL.Path)
+			require.NoError(t, req.ParseForm())
+			target = req.Form.Get("target")
+			_, _ = rw.Write([]byte(`[{"target":"constantLine(100)","datapoints":[[100,1]]}]`))
+		}))
+		defer server.Close()
+
+		result, err := ProvideService(sdkhttpclient.NewProvider(), nil).CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{URL: server.URL}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, result.Status)
+		require.Equal(t, "constantLine(100)", target)
+	})
+
+	t.Run("it returns an error status when the request fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		result, err := ProvideService(sdkhttpclient.NewProvider(), nil).CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{URL: server.URL}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, result.Status)
+		require.Equal(t, "Graphite request failed: request failed, status: 401 Unauthorized", result.Message)
+	})
+}
+
+func TestCallResource(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost {
+			_ = req.ParseForm()
+			requests = append(requests, "POST "+req.URL.String()+" "+req.PostForm.Encode())
+		} else {
+			requests = append(requests, req.URL.String())
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/metrics/find":
+			_, _ = rw.Write([]byte(`[{"text":"cpu","id":"servers.cpu","expandable":1,"leaf":0}]`))
+		case "/tags/autoComplete/tags":
+			_, _ = rw.Write([]byte(`["name","server"]`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service := ProvideService(sdkhttpclient.NewProvider(), nil)
+	settings := &backend.DataSourceInstanceSettings{ID: 1, URL: server.URL}
+	callResource := func(path string, query string) *backend.CallResourceResponse {
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: settings},
+			Path:          path,
+			Method:        http.MethodGet,
+			URL:           path + "?" + query,
+		}, sender)
+		require.NoError(t, err)
+		return sender.resp
+	}
+
+	t.Run("it forwards and caches metric lookups", func(t *testing.T) {
+		resp := callResource("metrics/find", "query=servers.*&from=-1h")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `[{"text":"cpu","id":"servers.cpu","expandable":1,"leaf":0}]`, string(resp.Body))
+		require.Equal(t, []string{"/metrics/find?from=-1h&query=servers.%2A"}, requests)
+
+		resp = callResource("metrics/find", "from=-1h&query=servers.*")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.Len(t, requests, 1)
+	})
+
+	t.Run("it forwards and caches metric lookups with form encoded bodies", func(t *testing.T) {
+		requests = nil
+		post := func(body string) *backend.CallResourceResponse {
+			sender := &fakeSender{}
+			err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+				PluginContext: backend.PluginContext{DataSourceInstanceSettings: settings},
+				Path:          "metrics/find",
+				Method:        http.MethodPost,
+				URL:           "metrics/find?from=-1h",
+				Headers:       map[string][]string{"Content-Type": {"application/x-www-form-urlencoded"}},
+				Body:          []byte(body),
+			}, sender)
+			require.NoError(t, err)
+			return sender.resp
+		}
+
+		resp := post("query=servers.*")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `[{"text":"cpu","id":"servers.cpu","expandable":1,"leaf":0}]`, string(resp.Body))
+		require.Equal(t, []string{"POST /metrics/find?from=-1h query=servers.%2A"}, requests)
+
+		_ = post("query=servers.*")
+		require.Len(t, requests, 1)
+
+		_ = post("query=databases.*")
+		require.Len(t, requests, 2)
+	})
+
+	t.Run("it rejects bodies that are not form encoded", func(t *testing.T) {
+		requests = nil
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: settings},
+			Path:          "metrics/find",
+			Method:        http.MethodPost,
+			URL:           "metrics/find",
+			Headers:       map[string][]string{"Content-Type": {"application/json"}},
+			Body:          []byte(`{"query":"servers.*"}`),
+		}, sender)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnsupportedMediaType, sender.resp.Status)
+		require.Empty(t, requests)
+	})
+
+	t.Run("it forwards tag lookups", func(t *testing.T) {
+		resp := callResource("tags/autoComplete/tags", "tagPrefix=")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `["name","server"]`, string(resp.Body))
+	})
+
+	t.Run("it does not cache errors", func(t *testing.T) {
+		requests = nil
+		resp := callResource("functions", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+		_ = callResource("functions", "")
+		require.Len(t, requests, 2)
+	})
+
+	t.Run("it does not forward other paths", func(t *testing.T) {
+		requests = nil
+		resp := callResource("render", "target=servers.cpu")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+		require.Empty(t, requests)
+	})
+}
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
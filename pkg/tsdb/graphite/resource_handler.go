@@ -0,0 +1,33 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+
+	"github.com/grafana/grafana/pkg/tsdb/httpresource"
+)
+
+// The resources have the paths of the Graphite HTTP API, so that they can replace the requests
+// proxied to Graphite. Graphite accepts the parameters in form encoded bodies as well, which the
+// editor uses for long metric queries.
+func (s *Service) newResourceMux() *http.ServeMux {
+	f := httpresource.NewForwarder("Graphite", s.logger, s.resourceTarget)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics/find", f.Handler(httpresource.LookupTTL, http.MethodGet, http.MethodPost))
+	mux.HandleFunc("/metrics/expand", f.Handler(httpresource.LookupTTL, http.MethodGet, http.MethodPost))
+	mux.HandleFunc("/tags", f.Handler(httpresource.LookupTTL, http.MethodGet, http.MethodPost))
+	mux.HandleFunc("/tags/", f.Handler(httpresource.LookupTTL, http.MethodGet, http.MethodPost))
+	mux.HandleFunc("/functions", f.Handler(httpresource.StaticTTL))
+	mux.HandleFunc("/version", f.Handler(httpresource.StaticTTL))
+	return mux
+}
+
+func (s *Service) resourceTarget(ctx context.Context) (*httpresource.Target, error) {
+	dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &httpresource.Target{HTTPClient: dsInfo.HTTPClient, URL: dsInfo.URL, Cache: dsInfo.resourceCache}, nil
+}
@@ -0,0 +1,191 @@
+// Package httpresource forwards the resource requests of data sources to the HTTP API of the
+// data source and caches the successful responses.
+package httpresource
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	// LookupTTL is how long the responses of lookups, such as metric names and tag values, are cached.
+	LookupTTL = time.Minute
+	// StaticTTL is how long the responses that only change when the data source is upgraded are cached.
+	StaticTTL = time.Hour
+)
+
+// Cache caches the responses of the resource requests of a data source instance. It does not run a
+// cleanup goroutine: the expired responses are deleted when a response is cached, and the cache is
+// emptied when the instance is disposed.
+type Cache struct {
+	cache *localcache.CacheService
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{cache: localcache.New(LookupTTL, 0)}
+}
+
+func (c *Cache) get(key string) (cachedResponse, bool) {
+	cached, ok := c.cache.Get(key)
+	if !ok {
+		return cachedResponse{}, false
+	}
+	return cached.(cachedResponse), true
+}
+
+func (c *Cache) set(key string, resp cachedResponse, ttl time.Duration) {
+	c.cache.DeleteExpired()
+	c.cache.Set(key, resp, ttl)
+}
+
+// Dispose empties the cache. The instance manager calls it when the data source instance is replaced.
+func (c *Cache) Dispose() {
+	c.cache.Flush()
+}
+
+type cachedResponse struct {
+	contentType string
+	body        []byte
+}
+
+// Target is where the resource requests of a data source instance are forwarded to.
+type Target struct {
+	HTTPClient *http.Client
+	URL        string
+	Cache      *Cache
+}
+
+// TargetFunc returns the target of the data source instance of a resource request.
+type TargetFunc func(ctx context.Context) (*Target, error)
+
+// Forwarder forwards the resource requests of a data source.
+type Forwarder struct {
+	name   string
+	logger log.Logger
+	target TargetFunc
+}
+
+// NewForwarder returns a Forwarder for the data source with the given name, which is used in the logs
+// and errors.
+func NewForwarder(name string, logger log.Logger, target TargetFunc) *Forwarder {
+	return &Forwarder{name: name, logger: logger, target: target}
+}
+
+// Handler returns a handler that forwards the requests with the given methods to the same path of the
+// data source and caches the successful responses for the ttl. GET is allowed when no method is given.
+// The bodies of POST requests must be form encoded, so that they can be part of the cache key.
+func (f *Forwarder) Handler(ttl time.Duration, methods ...string) http.HandlerFunc {
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet}
+	}
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if !allowed(req.Method, methods) {
+			f.writeResponse(rw, http.StatusMethodNotAllowed, "text/plain", []byte(fmt.Sprintf("method %s not allowed", req.Method)))
+			return
+		}
+
+		var form string
+		if req.Method == http.MethodPost {
+			if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType != "application/x-www-form-urlencoded" {
+				f.writeResponse(rw, http.StatusUnsupportedMediaType, "text/plain", []byte("the request body must be form encoded"))
+				return
+			}
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				f.writeResponse(rw, http.StatusBadRequest, "text/plain", []byte(err.Error()))
+				return
+			}
+			values, err := url.ParseQuery(string(body))
+			if err != nil {
+				f.writeResponse(rw, http.StatusBadRequest, "text/plain", []byte(err.Error()))
+				return
+			}
+			form = values.Encode()
+		}
+
+		target, err := f.target(req.Context())
+		if err != nil {
+			f.writeResponse(rw, http.StatusInternalServerError, "text/plain", []byte(err.Error()))
+			return
+		}
+
+		// the parameters are encoded in the order of their names, so that the key does not depend on
+		// the order of the request
+		params := req.URL.Query().Encode()
+		key := req.Method + " " + req.URL.Path + "?" + params + " " + form
+		if cached, ok := target.Cache.get(key); ok {
+			f.writeResponse(rw, http.StatusOK, cached.contentType, cached.body)
+			return
+		}
+
+		u, err := url.Parse(target.URL)
+		if err != nil {
+			f.writeResponse(rw, http.StatusInternalServerError, "text/plain", []byte(err.Error()))
+			return
+		}
+		u.Path = path.Join(u.Path, req.URL.Path)
+		u.RawQuery = params
+
+		dsReq, err := http.NewRequestWithContext(req.Context(), req.Method, u.String(), strings.NewReader(form))
+		if err != nil {
+			f.writeResponse(rw, http.StatusInternalServerError, "text/plain", []byte(err.Error()))
+			return
+		}
+		if req.Method == http.MethodPost {
+			dsReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		res, err := target.HTTPClient.Do(dsReq)
+		if err != nil {
+			f.logger.Warn(f.name+" resource request failed", "path", req.URL.Path, "error", err)
+			f.writeResponse(rw, http.StatusBadGateway, "text/plain", []byte(fmt.Sprintf("request to %s failed: %v", strings.ToLower(f.name), err)))
+			return
+		}
+		defer func() {
+			if err := res.Body.Close(); err != nil {
+				f.logger.Warn("Failed to close response body", "err", err)
+			}
+		}()
+
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			f.writeResponse(rw, http.StatusBadGateway, "text/plain", []byte(err.Error()))
+			return
+		}
+
+		contentType := res.Header.Get("Content-Type")
+		if res.StatusCode/100 == 2 {
+			target.Cache.set(key, cachedResponse{contentType: contentType, body: body}, ttl)
+		}
+		f.writeResponse(rw, res.StatusCode, contentType, body)
+	}
+}
+
+func allowed(method string, methods []string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Forwarder) writeResponse(rw http.ResponseWriter, code int, contentType string, body []byte) {
+	if contentType != "" {
+		rw.Header().Set("Content-Type", contentType)
+	}
+	rw.WriteHeader(code)
+	if _, err := rw.Write(body); err != nil {
+		f.logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
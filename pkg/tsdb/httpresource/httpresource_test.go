@@ -0,0 +1,26 @@
+package httpresource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("it deletes the expired responses when a response is cached", func(t *testing.T) {
+		c := NewCache()
+		c.set("expired", cachedResponse{body: []byte("a")}, time.Millisecond)
+		time.Sleep(5 * time.Millisecond)
+		c.set("key", cachedResponse{body: []byte("b")}, time.Minute)
+		require.Equal(t, 1, c.cache.ItemCount())
+	})
+
+	t.Run("it is empty once disposed", func(t *testing.T) {
+		c := NewCache()
+		c.set("key", cachedResponse{body: []byte("b")}, time.Minute)
+		c.Dispose()
+		_, ok := c.get("key")
+		require.False(t, ok)
+	})
+}
@@ -88,6 +88,9 @@ func newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFactoryFunc {
 			DSInfo:            dsInfo,
 			MetricColumnTypes: []string{"VARCHAR", "CHAR", "NVARCHAR", "NCHAR"},
 			RowLimit:          cfg.DataProxyRowLimit,
+			BindParamStyle:    sqleng.BindParamAt,
+			// SQL Server runs the statements of a batch even without semicolons, so a query could commit the transaction
+			ReadOnlyUnsupported: true,
 		}
 
 		queryResultTransformer := mssqlQueryResultTransformer{
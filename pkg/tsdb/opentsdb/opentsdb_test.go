@@ -4,12 +4,15 @@ import (
 	"context"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -242,3 +245,100 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		require.Equal(t, float64(60), metricRateOptions["resetValue"])
 	})
 }
+
+func TestCheckHealth(t *testing.T) {
+	t.Run("it requests metric suggestions", func(t *testing.T) {
+		var query url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			require.Equal(t, "/api/suggest", req.URL.Path)
+			query = req.URL.Query()
+			_, _ = rw.Write([]byte(`["cpu.usage"]`))
+		}))
+		defer server.Close()
+
+		result, err := ProvideService(sdkhttpclient.NewProvider()).CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{URL: server.URL}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, result.Status)
+		require.Equal(t, "metrics", query.Get("type"))
+	})
+
+	t.Run("it returns an error status when the request fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		result, err := ProvideService(sdkhttpclient.NewProvider()).CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{URL: server.URL}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, result.Status)
+		require.Equal(t, "OpenTSDB request failed, status: 502 Bad Gateway", result.Message)
+	})
+}
+
+func TestCallResource(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.URL.String())
+		rw.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/api/suggest":
+			_, _ = rw.Write([]byte(`["cpu.idle","cpu.usage"]`))
+		case "/api/aggregators":
+			_, _ = rw.Write([]byte(`["avg","sum"]`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service := ProvideService(sdkhttpclient.NewProvider())
+	settings := &backend.DataSourceInstanceSettings{ID: 1, URL: server.URL}
+	callResource := func(path string, query string) *backend.CallResourceResponse {
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: settings},
+			Path:          path,
+			Method:        http.MethodGet,
+			URL:           path + "?" + query,
+		}, sender)
+		require.NoError(t, err)
+		return sender.resp
+	}
+
+	t.Run("it forwards and caches suggestions", func(t *testing.T) {
+		resp := callResource("api/suggest", "type=metrics&q=cpu&max=10")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `["cpu.idle","cpu.usage"]`, string(resp.Body))
+		require.Equal(t, []string{"/api/suggest?max=10&q=cpu&type=metrics"}, requests)
+
+		resp = callResource("api/suggest", "q=cpu&type=metrics&max=10")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.Len(t, requests, 1)
+	})
+
+	t.Run("it forwards aggregators", func(t *testing.T) {
+		resp := callResource("api/aggregators", "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `["avg","sum"]`, string(resp.Body))
+	})
+
+	t.Run("it does not forward other paths", func(t *testing.T) {
+		requests = nil
+		resp := callResource("api/query", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+		require.Empty(t, requests)
+	})
+}
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
@@ -0,0 +1,30 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+
+	"github.com/grafana/grafana/pkg/tsdb/httpresource"
+)
+
+// The resources have the paths of the OpenTSDB HTTP API, so that they can replace the requests
+// proxied to OpenTSDB.
+func (s *Service) newResourceMux() *http.ServeMux {
+	f := httpresource.NewForwarder("OpenTSDB", s.logger, s.resourceTarget)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/suggest", f.Handler(httpresource.LookupTTL))
+	mux.HandleFunc("/api/search/lookup", f.Handler(httpresource.LookupTTL))
+	mux.HandleFunc("/api/aggregators", f.Handler(httpresource.StaticTTL))
+	mux.HandleFunc("/api/config/filters", f.Handler(httpresource.StaticTTL))
+	return mux
+}
+
+func (s *Service) resourceTarget(ctx context.Context) (*httpresource.Target, error) {
+	dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &httpresource.Target{HTTPClient: dsInfo.HTTPClient, URL: dsInfo.URL, Cache: dsInfo.resourceCache}, nil
+}
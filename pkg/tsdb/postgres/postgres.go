@@ -92,6 +92,7 @@ func (s *Service) newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFacto
 			DSInfo:            dsInfo,
 			MetricColumnTypes: []string{"UNKNOWN", "TEXT", "VARCHAR", "CHAR"},
 			RowLimit:          cfg.DataProxyRowLimit,
+			BindParamStyle:    sqleng.BindParamDollar,
 		}
 
 		queryResultTransformer := postgresQueryResultTransformer{
@@ -10,7 +10,9 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
@@ -31,15 +33,18 @@ type Service struct {
 	intervalCalculator intervalv2.Calculator
 	im                 instancemgmt.InstanceManager
 	tracer             tracing.Tracer
+	resourceHandler    backend.CallResourceHandler
 }
 
 func ProvideService(httpClientProvider httpclient.Provider, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tracer tracing.Tracer) *Service {
 	plog.Debug("initializing")
-	return &Service{
+	s := &Service{
 		intervalCalculator: intervalv2.NewCalculator(),
 		im:                 datasource.NewInstanceManager(newInstanceSettings(httpClientProvider, cfg, features)),
 		tracer:             tracer,
 	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider, cfg *setting.Cfg, features featuremgmt.FeatureToggles) datasource.InstanceFactoryFunc {
@@ -61,11 +66,18 @@ func newInstanceSettings(httpClientProvider httpclient.Provider, cfg *setting.Cf
 			return nil, err
 		}
 
+		oauthPassThru, err := maputil.GetBoolOptional(jsonData, "oauthPassThru")
+		if err != nil {
+			return nil, err
+		}
+
 		mdl := DatasourceInfo{
-			ID:           settings.ID,
-			URL:          settings.URL,
-			TimeInterval: timeInterval,
-			getClient:    pc.GetClient,
+			ID:            settings.ID,
+			URL:           settings.URL,
+			TimeInterval:  timeInterval,
+			OAuthPassThru: oauthPassThru,
+			getClient:     pc.GetClient,
+			resourceCache: localcache.New(resourceCacheTTL, 2*resourceCacheTTL),
 		}
 
 		return mdl, nil
@@ -94,6 +106,10 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	return result, err
 }
 
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
 func (s *Service) getDSInfo(pluginCtx backend.PluginContext) (*DatasourceInfo, error) {
 	i, err := s.im.Get(pluginCtx)
 	if err != nil {
@@ -0,0 +1,351 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	apiv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	// resourceCacheTTL is how long the responses of the label, series and metadata resources are
+	// cached. The time ranges of the requests are rounded to it, so that requests for the same
+	// relative time range share the cached response.
+	resourceCacheTTL = time.Minute
+
+	// validationInterval and validationRange replace the interval and range variables of the
+	// queries that are validated, since the validation has no time range.
+	validationInterval = time.Minute
+	validationRange    = time.Hour
+)
+
+// The label, series and metadata resources have the paths and the response format of the
+// Prometheus HTTP API, so that they can replace the requests proxied to Prometheus.
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/labels", s.handleResourceReq(s.handleLabels))
+	mux.HandleFunc("/api/v1/label/", s.handleResourceReq(s.handleLabelValues))
+	mux.HandleFunc("/api/v1/series", s.handleResourceReq(s.handleSeries))
+	mux.HandleFunc("/api/v1/metadata", s.handleResourceReq(s.handleMetadata))
+	mux.HandleFunc("/validate", s.handleValidate)
+	return mux
+}
+
+// resourceRequest is a request for a resource read from Prometheus.
+type resourceRequest struct {
+	ctx    context.Context
+	path   string
+	params url.Values
+	client apiv1.API
+}
+
+type resourceHandlerFn func(req *resourceRequest) (interface{}, apiv1.Warnings, error)
+
+// badDataError is an error in the parameters of a resource request.
+type badDataError struct {
+	err error
+}
+
+func (e badDataError) Error() string {
+	return e.err.Error()
+}
+
+func badDataf(format string, args ...interface{}) error {
+	return badDataError{err: fmt.Errorf(format, args...)}
+}
+
+type resourceResponse struct {
+	Status    string          `json:"status"`
+	Data      interface{}     `json:"data,omitempty"`
+	Warnings  apiv1.Warnings  `json:"warnings,omitempty"`
+	ErrorType apiv1.ErrorType `json:"errorType,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+func (s *Service) handleResourceReq(handleFunc resourceHandlerFn) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodPost {
+			writeResourceError(rw, http.StatusMethodNotAllowed, apiv1.ErrBadData, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+		if err := req.ParseForm(); err != nil {
+			writeResourceError(rw, http.StatusBadRequest, apiv1.ErrBadData, err)
+			return
+		}
+
+		dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(req.Context()))
+		if err != nil {
+			writeResourceError(rw, http.StatusInternalServerError, apiv1.ErrServer, err)
+			return
+		}
+
+		headers := dsInfo.resourceHeaders(req.Header)
+		key := resourceCacheKey(req.URL.Path, req.Form, headers)
+		if body, ok := dsInfo.resourceCache.Get(key); ok {
+			writeResourceBody(rw, http.StatusOK, body.([]byte))
+			return
+		}
+
+		client, err := dsInfo.getClient(headers)
+		if err != nil {
+			writeResourceError(rw, http.StatusInternalServerError, apiv1.ErrServer, err)
+			return
+		}
+
+		result, warnings, err := handleFunc(&resourceRequest{
+			ctx:    req.Context(),
+			path:   req.URL.Path,
+			params: req.Form,
+			client: client,
+		})
+		if err != nil {
+			var badData badDataError
+			var apiErr *apiv1.Error
+			switch {
+			case errors.As(err, &badData):
+				writeResourceError(rw, http.StatusBadRequest, apiv1.ErrBadData, err)
+			case errors.As(err, &apiErr):
+				writeResourceError(rw, http.StatusBadGateway, apiErr.Type, ConvertAPIError(err))
+			default:
+				plog.Error("Prometheus resource request failed", "path", req.URL.Path, "error", err)
+				writeResourceError(rw, http.StatusBadGateway, apiv1.ErrServer, err)
+			}
+			return
+		}
+
+		body, err := json.Marshal(resourceResponse{Status: "success", Data: result, Warnings: warnings})
+		if err != nil {
+			writeResourceError(rw, http.StatusInternalServerError, apiv1.ErrServer, err)
+			return
+		}
+		dsInfo.resourceCache.Set(key, body, resourceCacheTTL)
+		writeResourceBody(rw, http.StatusOK, body)
+	}
+}
+
+func (s *Service) handleLabels(req *resourceRequest) (interface{}, apiv1.Warnings, error) {
+	start, end, err := parseResourceTimeRange(req.params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return req.client.LabelNames(req.ctx, req.params["match[]"], start, end)
+}
+
+func (s *Service) handleLabelValues(req *resourceRequest) (interface{}, apiv1.Warnings, error) {
+	// the path is /api/v1/label/<name>/values
+	name := strings.TrimSuffix(strings.TrimPrefix(req.path, "/api/v1/label/"), "/values")
+	if name == "" || strings.Contains(name, "/") || !strings.HasSuffix(req.path, "/values") {
+		return nil, nil, badDataf("invalid label values path %q", req.path)
+	}
+	start, end, err := parseResourceTimeRange(req.params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return req.client.LabelValues(req.ctx, name, req.params["match[]"], start, end)
+}
+
+func (s *Service) handleSeries(req *resourceRequest) (interface{}, apiv1.Warnings, error) {
+	matches := req.params["match[]"]
+	if len(matches) == 0 {
+		return nil, nil, badDataf("no match[] parameter provided")
+	}
+	start, end, err := parseResourceTimeRange(req.params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return req.client.Series(req.ctx, matches, start, end)
+}
+
+func (s *Service) handleMetadata(req *resourceRequest) (interface{}, apiv1.Warnings, error) {
+	limit := req.params.Get("limit")
+	if limit != "" {
+		if _, err := strconv.Atoi(limit); err != nil {
+			return nil, nil, badDataf("invalid limit %q", limit)
+		}
+	}
+	metadata, err := req.client.Metadata(req.ctx, req.params.Get("metric"), limit)
+	return metadata, nil, err
+}
+
+// ValidationResult is the result of the validation of a PromQL query.
+type ValidationResult struct {
+	Valid bool `json:"valid"`
+	// Type is the type of the value of a valid query: vector, matrix, scalar or string.
+	Type   string            `json:"type,omitempty"`
+	Errors []ValidationError `json:"errors,omitempty"`
+}
+
+// ValidationError is a syntax error in a PromQL query, with the range of the query it applies to.
+type ValidationError struct {
+	Message string `json:"message"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+}
+
+// handleValidate parses the PromQL query of the query parameter. The interval and range
+// variables are interpolated before the query is parsed, other template variables are not, so
+// the queries of dashboards should be interpolated by the caller.
+func (s *Service) handleValidate(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodPost {
+		writeResourceError(rw, http.StatusMethodNotAllowed, apiv1.ErrBadData, fmt.Errorf("method %s not allowed", req.Method))
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		writeResourceError(rw, http.StatusBadRequest, apiv1.ErrBadData, err)
+		return
+	}
+	query := req.Form.Get("query")
+	if query == "" {
+		writeResourceError(rw, http.StatusBadRequest, apiv1.ErrBadData, errors.New("no query parameter provided"))
+		return
+	}
+
+	body, err := json.Marshal(resourceResponse{Status: "success", Data: s.validateQuery(query)})
+	if err != nil {
+		writeResourceError(rw, http.StatusInternalServerError, apiv1.ErrServer, err)
+		return
+	}
+	writeResourceBody(rw, http.StatusOK, body)
+}
+
+func (s *Service) validateQuery(query string) ValidationResult {
+	model := &QueryModel{Expr: query}
+	expr, err := parser.ParseExpr(interpolateVariables(model, validationInterval, validationRange, s.intervalCalculator, ""))
+	if err == nil {
+		return ValidationResult{Valid: true, Type: string(expr.Type())}
+	}
+
+	result := ValidationResult{}
+	var parseErrs parser.ParseErrors
+	if errors.As(err, &parseErrs) {
+		for _, parseErr := range parseErrs {
+			result.Errors = append(result.Errors, ValidationError{
+				Message: parseErr.Err.Error(),
+				Start:   int(parseErr.PositionRange.Start),
+				End:     int(parseErr.PositionRange.End),
+			})
+		}
+	}
+	if len(result.Errors) == 0 {
+		result.Errors = []ValidationError{{Message: err.Error()}}
+	}
+	return result
+}
+
+// resourceHeaders returns the headers of a resource request that are sent to Prometheus. The
+// OAuth token headers are added to the request when the OAuth token is forwarded, after the
+// headers of the user, so their last values are used.
+func (d *DatasourceInfo) resourceHeaders(header http.Header) map[string]string {
+	headers := map[string]string{}
+	if !d.OAuthPassThru {
+		return headers
+	}
+	for _, name := range []string{"Authorization", "X-ID-Token"} {
+		if values := header.Values(name); len(values) > 0 {
+			headers[name] = values[len(values)-1]
+		}
+	}
+	return headers
+}
+
+// parseResourceTimeRange returns the time range of the start and end parameters, rounded
+// outwards to the resource cache TTL. Without a start, the range starts at the earliest data of
+// Prometheus. Without an end, it ends now.
+func parseResourceTimeRange(params url.Values) (time.Time, time.Time, error) {
+	start, err := parseResourceTime(params.Get("start"), time.Time{})
+	if err != nil {
+		return time.Time{}, time.Time{}, badDataf("invalid start: %w", err)
+	}
+	end, err := parseResourceTime(params.Get("end"), time.Now())
+	if err != nil {
+		return time.Time{}, time.Time{}, badDataf("invalid end: %w", err)
+	}
+	if !start.IsZero() && end.Before(start) {
+		return time.Time{}, time.Time{}, badDataf("end timestamp must not be before start time")
+	}
+
+	if !start.IsZero() {
+		start = start.Truncate(resourceCacheTTL)
+	}
+	if rounded := end.Truncate(resourceCacheTTL); rounded.Before(end) {
+		end = rounded.Add(resourceCacheTTL)
+	}
+	return start, end, nil
+}
+
+// parseResourceTime parses a Unix timestamp in seconds or an RFC 3339 time, as Prometheus does.
+func parseResourceTime(value string, defaultTime time.Time) (time.Time, error) {
+	if value == "" {
+		return defaultTime, nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+			return time.Time{}, fmt.Errorf("%q is not a valid timestamp", value)
+		}
+		s, ns := math.Modf(seconds)
+		return time.Unix(int64(s), int64(math.Round(ns*1000))*int64(time.Millisecond)).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a valid timestamp", value)
+	}
+	return t, nil
+}
+
+// resourceCacheKey returns the key of the cached response of a resource request. The time range
+// parameters are rounded, and the headers are part of the key since they can restrict the data
+// the user can read.
+func resourceCacheKey(path string, params url.Values, headers map[string]string) string {
+	values := url.Values{}
+	for name, v := range params {
+		values[name] = append([]string(nil), v...)
+	}
+	if start, end, err := parseResourceTimeRange(params); err == nil {
+		values.Set("start", strconv.FormatInt(start.Unix(), 10))
+		values.Set("end", strconv.FormatInt(end.Unix(), 10))
+	}
+	for name := range values {
+		sort.Strings(values[name])
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var key strings.Builder
+	key.WriteString(path)
+	key.WriteString("?")
+	key.WriteString(values.Encode())
+	for _, name := range names {
+		key.WriteString("\n")
+		key.WriteString(name)
+		key.WriteString(":")
+		key.WriteString(headers[name])
+	}
+	return key.String()
+}
+
+func writeResourceError(rw http.ResponseWriter, code int, errorType apiv1.ErrorType, err error) {
+	body, _ := json.Marshal(resourceResponse{Status: "error", ErrorType: errorType, Error: err.Error()})
+	writeResourceBody(rw, code, body)
+}
+
+func writeResourceBody(rw http.ResponseWriter, code int, body []byte) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	if _, err := rw.Write(body); err != nil {
+		plog.Error("Unable to write HTTP response", "error", err)
+	}
+}
@@ -0,0 +1,228 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallResource(t *testing.T) {
+	t.Run("it returns the label names with the custom query parameters", func(t *testing.T) {
+		tc := setupResourceContext(t, `{"customQueryParameters":"dedup=false"}`)
+
+		resp := tc.callResource(t, "api/v1/labels?match[]=up&start=1640995200&end=1640998800", nil)
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"status":"success","data":["__name__","job"]}`, string(resp.Body))
+
+		require.Len(t, tc.requests, 1)
+		require.Equal(t, "/api/v1/labels", tc.requests[0].Path)
+		require.Equal(t, "false", tc.requests[0].Query.Get("dedup"))
+		require.Equal(t, []string{"up"}, tc.requests[0].Query["match[]"])
+		require.Equal(t, "1640995200", tc.requests[0].Query.Get("start"))
+		require.Equal(t, "1640998800", tc.requests[0].Query.Get("end"))
+	})
+
+	t.Run("it caches the responses for the same rounded time range", func(t *testing.T) {
+		tc := setupResourceContext(t, `{}`)
+
+		resp := tc.callResource(t, "api/v1/label/job/values?start=1640995201&end=1640998799", nil)
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"status":"success","data":["grafana","prometheus"]}`, string(resp.Body))
+
+		resp = tc.callResource(t, "api/v1/label/job/values?start=1640995230&end=1640998770", nil)
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"status":"success","data":["grafana","prometheus"]}`, string(resp.Body))
+
+		require.Len(t, tc.requests, 1)
+		require.Equal(t, "/api/v1/label/job/values", tc.requests[0].Path)
+		require.Equal(t, "1640995200", tc.requests[0].Query.Get("start"))
+		require.Equal(t, "1640998800", tc.requests[0].Query.Get("end"))
+
+		resp = tc.callResource(t, "api/v1/label/instance/values?start=1640995201&end=1640998799", nil)
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.Len(t, tc.requests, 2)
+	})
+
+	t.Run("it forwards the OAuth token when OAuth pass-through is enabled", func(t *testing.T) {
+		tc := setupResourceContext(t, `{"oauthPassThru":true}`)
+
+		headers := map[string][]string{"Authorization": {"Bearer user-token", "Bearer oauth-token"}}
+		resp := tc.callResource(t, "api/v1/series?match[]=up", headers)
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"status":"success","data":[{"__name__":"up","job":"grafana"}]}`, string(resp.Body))
+		require.Equal(t, "Bearer oauth-token", tc.requests[0].Authorization)
+
+		tc = setupResourceContext(t, `{}`)
+		resp = tc.callResource(t, "api/v1/series?match[]=up", headers)
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.Empty(t, tc.requests[0].Authorization)
+	})
+
+	t.Run("it returns the metric metadata", func(t *testing.T) {
+		tc := setupResourceContext(t, `{}`)
+
+		resp := tc.callResource(t, "api/v1/metadata?metric=up&limit=1", nil)
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"status":"success","data":{"up":[{"type":"gauge","help":"Whether the target is up.","unit":""}]}}`, string(resp.Body))
+		require.Equal(t, "up", tc.requests[0].Query.Get("metric"))
+		require.Equal(t, "1", tc.requests[0].Query.Get("limit"))
+	})
+
+	t.Run("it returns an error for invalid parameters", func(t *testing.T) {
+		tc := setupResourceContext(t, `{}`)
+
+		resp := tc.callResource(t, "api/v1/series", nil)
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.JSONEq(t, `{"status":"error","errorType":"bad_data","error":"no match[] parameter provided"}`, string(resp.Body))
+
+		resp = tc.callResource(t, "api/v1/labels?start=yesterday", nil)
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.JSONEq(t, `{"status":"error","errorType":"bad_data","error":"invalid start: \"yesterday\" is not a valid timestamp"}`, string(resp.Body))
+
+		require.Empty(t, tc.requests)
+	})
+
+	t.Run("it returns the errors of Prometheus and does not cache them", func(t *testing.T) {
+		tc := setupResourceContext(t, `{}`)
+
+		resp := tc.callResource(t, "api/v1/label/error/values", nil)
+		require.Equal(t, http.StatusBadGateway, resp.Status)
+		require.JSONEq(t, `{"status":"error","errorType":"bad_data","error":"invalid label name: "}`, string(resp.Body))
+
+		_ = tc.callResource(t, "api/v1/label/error/values", nil)
+		require.Len(t, tc.requests, 2)
+	})
+
+	t.Run("it validates queries", func(t *testing.T) {
+		tc := setupResourceContext(t, `{}`)
+
+		resp := tc.callResource(t, "validate?query="+url.QueryEscape(`sum(rate(up{job="grafana"}[$__rate_interval]))`), nil)
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"status":"success","data":{"valid":true,"type":"vector"}}`, string(resp.Body))
+
+		resp = tc.callResource(t, "validate?query="+url.QueryEscape(`sum(rate(up[5m])`), nil)
+		require.Equal(t, http.StatusOK, resp.Status)
+		var result struct {
+			Data ValidationResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body, &result))
+		require.False(t, result.Data.Valid)
+		require.NotEmpty(t, result.Data.Errors)
+		require.Contains(t, result.Data.Errors[0].Message, "unclosed left parenthesis")
+
+		resp = tc.callResource(t, "validate", nil)
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.Empty(t, tc.requests)
+	})
+}
+
+func TestParseResourceTimeRange(t *testing.T) {
+	t.Run("it parses timestamps and rounds the range outwards", func(t *testing.T) {
+		start, end, err := parseResourceTimeRange(url.Values{"start": {"1640995201.5"}, "end": {"2022-01-01T01:00:01Z"}})
+		require.NoError(t, err)
+		require.Equal(t, time.Unix(1640995200, 0).UTC(), start.UTC())
+		require.Equal(t, time.Unix(1640998860, 0).UTC(), end.UTC())
+	})
+
+	t.Run("it defaults to the whole range until now", func(t *testing.T) {
+		start, end, err := parseResourceTimeRange(url.Values{})
+		require.NoError(t, err)
+		require.True(t, start.IsZero())
+		require.WithinDuration(t, time.Now(), end, resourceCacheTTL)
+	})
+
+	t.Run("it returns an error when the end is before the start", func(t *testing.T) {
+		_, _, err := parseResourceTimeRange(url.Values{"start": {"1640998800"}, "end": {"1640995200"}})
+		require.Error(t, err)
+	})
+}
+
+type promRequest struct {
+	Path          string
+	Query         url.Values
+	Authorization string
+}
+
+type resourceTestContext struct {
+	service  *Service
+	settings *backend.DataSourceInstanceSettings
+	requests []promRequest
+}
+
+func setupResourceContext(t *testing.T, jsonData string) *resourceTestContext {
+	tc := &resourceTestContext{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.NoError(t, req.ParseForm())
+		tc.requests = append(tc.requests, promRequest{
+			Path:          req.URL.Path,
+			Query:         req.Form,
+			Authorization: req.Header.Get("Authorization"),
+		})
+
+		rw.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/api/v1/labels":
+			_, _ = rw.Write([]byte(`{"status":"success","data":["__name__","job"]}`))
+		case "/api/v1/label/job/values", "/api/v1/label/instance/values":
+			_, _ = rw.Write([]byte(`{"status":"success","data":["grafana","prometheus"]}`))
+		case "/api/v1/series":
+			_, _ = rw.Write([]byte(`{"status":"success","data":[{"__name__":"up","job":"grafana"}]}`))
+		case "/api/v1/metadata":
+			_, _ = rw.Write([]byte(`{"status":"success","data":{"up":[{"type":"gauge","help":"Whether the target is up.","unit":""}]}}`))
+		default:
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"status":"error","errorType":"bad_data","error":"invalid label name"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tc.settings = &backend.DataSourceInstanceSettings{ID: 1, URL: server.URL, JSONData: []byte(jsonData)}
+	tc.service = &Service{
+		intervalCalculator: intervalv2.NewCalculator(),
+		im:                 datasource.NewInstanceManager(newInstanceSettings(sdkhttpclient.NewProvider(), &setting.Cfg{}, featuremgmt.WithFeatures())),
+	}
+	tc.service.resourceHandler = httpadapter.New(tc.service.newResourceMux())
+	return tc
+}
+
+func (tc *resourceTestContext) callResource(t *testing.T, path string, headers map[string][]string) *backend.CallResourceResponse {
+	t.Helper()
+
+	u, err := url.Parse(path)
+	require.NoError(t, err)
+
+	sender := &fakeSender{}
+	err = tc.service.CallResource(context.Background(), &backend.CallResourceRequest{
+		PluginContext: backend.PluginContext{OrgID: 1, DataSourceInstanceSettings: tc.settings},
+		Path:          u.Path,
+		Method:        http.MethodGet,
+		URL:           path,
+		Headers:       headers,
+	}, sender)
+	require.NoError(t, err)
+	require.NotNil(t, sender.resp)
+	return sender.resp
+}
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
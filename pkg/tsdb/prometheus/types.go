@@ -3,6 +3,7 @@ package prometheus
 import (
 	"time"
 
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	apiv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 )
 
@@ -10,8 +11,11 @@ type DatasourceInfo struct {
 	ID           int64
 	URL          string
 	TimeInterval string
+	// OAuthPassThru is whether the OAuth token of the user is forwarded to Prometheus.
+	OAuthPassThru bool
 
-	getClient clientGetter
+	getClient     clientGetter
+	resourceCache *localcache.CacheService
 }
 
 type clientGetter func(map[string]string) (apiv1.API, error)
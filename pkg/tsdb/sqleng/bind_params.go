@@ -0,0 +1,156 @@
+package sqleng
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BindParamStyle is the placeholder syntax of the bind parameters of a database driver.
+type BindParamStyle int
+
+const (
+	// BindParamQuestion is the ? placeholder, used by MySQL.
+	BindParamQuestion BindParamStyle = iota
+	// BindParamDollar is the $1 placeholder, used by PostgreSQL.
+	BindParamDollar
+	// BindParamAt is the @p1 placeholder, used by Microsoft SQL Server.
+	BindParamAt
+)
+
+func (s BindParamStyle) placeholder(position int) string {
+	switch s {
+	case BindParamDollar:
+		return "$" + strconv.Itoa(position)
+	case BindParamAt:
+		return "@p" + strconv.Itoa(position)
+	default:
+		return "?"
+	}
+}
+
+var bindParamPattern = regexp.MustCompile(`\$(\w+)|\$\{(\w+)\}`)
+
+// dollarQuotePattern matches the opening of a dollar-quoted string of PostgreSQL, $$ or $tag$.
+var dollarQuotePattern = regexp.MustCompile(`^\$(?:[A-Za-z_]\w*)?\$`)
+
+// bindParams replaces the references to the template variables of the query params, $name or
+// ${name}, with placeholders of the style, and returns the arguments of the placeholders. The values
+// of a multi-value variable are bound to a comma separated list of placeholders, so that the
+// variable can be used in an IN clause. References inside quoted literals and identifiers, comments
+// and dollar-quoted strings, and references to other variables, are left unchanged.
+func bindParams(sql string, params map[string]interface{}, style BindParamStyle) (string, []interface{}, error) {
+	if len(params) == 0 {
+		return sql, nil, nil
+	}
+
+	var args []interface{}
+	var err error
+	bind := func(match string) string {
+		groups := bindParamPattern.FindStringSubmatch(match)
+		name := groups[1]
+		if name == "" {
+			name = groups[2]
+		}
+		value, ok := params[name]
+		if !ok || err != nil {
+			return match
+		}
+
+		values, isList := value.([]interface{})
+		if !isList {
+			values = []interface{}{value}
+		}
+		if len(values) == 0 {
+			err = fmt.Errorf("bind parameter %q has no values", name)
+			return match
+		}
+
+		placeholders := make([]string, len(values))
+		for i, v := range values {
+			args = append(args, v)
+			placeholders[i] = style.placeholder(len(args))
+		}
+		return strings.Join(placeholders, ", ")
+	}
+
+	var b strings.Builder
+	for start := 0; start < len(sql); {
+		skipStart, skipEnd := nextUnboundSegment(sql, start, style)
+		b.WriteString(bindParamPattern.ReplaceAllStringFunc(sql[start:skipStart], bind))
+		b.WriteString(sql[skipStart:skipEnd])
+		start = skipEnd
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	return b.String(), args, nil
+}
+
+// nextUnboundSegment returns the start and end indexes of the first segment of the SQL after index from
+// in which variables are not bound: a quoted literal or identifier, a comment, or a dollar-quoted string
+// of PostgreSQL. Both indexes are the length of the SQL if there is none.
+func nextUnboundSegment(sql string, from int, style BindParamStyle) (int, int) {
+	for i := from; i < len(sql); i++ {
+		switch sql[i] {
+		case '\'', '"', '`':
+			return i, quotedEnd(sql, i, style)
+		case '-':
+			if strings.HasPrefix(sql[i:], "--") {
+				return i, lineEnd(sql, i)
+			}
+		case '#':
+			// MySQL also starts comments with #
+			if style == BindParamQuestion {
+				return i, lineEnd(sql, i)
+			}
+		case '/':
+			if strings.HasPrefix(sql[i:], "/*") {
+				end := strings.Index(sql[i+2:], "*/")
+				if end < 0 {
+					return i, len(sql)
+				}
+				return i, i + 2 + end + 2
+			}
+		case '$':
+			if style != BindParamDollar {
+				continue
+			}
+			if tag := dollarQuotePattern.FindString(sql[i:]); tag != "" {
+				end := strings.Index(sql[i+len(tag):], tag)
+				if end < 0 {
+					return i, len(sql)
+				}
+				return i, i + len(tag) + end + len(tag)
+			}
+		}
+	}
+	return len(sql), len(sql)
+}
+
+// lineEnd returns the index of the end of the line of index start of the SQL.
+func lineEnd(sql string, start int) int {
+	if end := strings.IndexByte(sql[start:], '\n'); end >= 0 {
+		return start + end
+	}
+	return len(sql)
+}
+
+// quotedEnd returns the index after the end of the literal or identifier quoted at index start of the
+// SQL. A doubled quote is part of the literal, as is a quote escaped by a backslash in MySQL.
+func quotedEnd(sql string, start int, style BindParamStyle) int {
+	quote := sql[start]
+	for i := start + 1; i < len(sql); i++ {
+		switch {
+		case sql[i] == '\\' && style == BindParamQuestion && quote != '`':
+			i++
+		case sql[i] == quote && i+1 < len(sql) && sql[i+1] == quote:
+			i++
+		case sql[i] == quote:
+			return i + 1
+		}
+	}
+	return len(sql)
+}
@@ -0,0 +1,79 @@
+package sqleng
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindParams(t *testing.T) {
+	params := map[string]interface{}{
+		"host":  []interface{}{"a", "b"},
+		"limit": float64(10),
+		"empty": []interface{}{},
+	}
+
+	t.Run("it binds the variables to the placeholders of the style", func(t *testing.T) {
+		sql := "SELECT * FROM t WHERE host IN ($host) AND $hostname = 1 LIMIT ${limit}"
+
+		bound, args, err := bindParams(sql, params, BindParamQuestion)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM t WHERE host IN (?, ?) AND $hostname = 1 LIMIT ?", bound)
+		require.Equal(t, []interface{}{"a", "b", float64(10)}, args)
+
+		bound, _, err = bindParams(sql, params, BindParamDollar)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM t WHERE host IN ($1, $2) AND $hostname = 1 LIMIT $3", bound)
+
+		bound, _, err = bindParams(sql, params, BindParamAt)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM t WHERE host IN (@p1, @p2) AND $hostname = 1 LIMIT @p3", bound)
+	})
+
+	t.Run("it does not bind the variables in quoted literals and identifiers", func(t *testing.T) {
+		sql := `SELECT '$host', 'it''s $host', "$host", ` + "`$host`" + ` FROM t WHERE host IN ($host)`
+
+		bound, args, err := bindParams(sql, params, BindParamDollar)
+		require.NoError(t, err)
+		require.Equal(t, `SELECT '$host', 'it''s $host', "$host", `+"`$host`"+` FROM t WHERE host IN ($1, $2)`, bound)
+		require.Equal(t, []interface{}{"a", "b"}, args)
+
+		bound, _, err = bindParams(`SELECT 'it\'s $host' FROM t WHERE host IN ($host)`, params, BindParamQuestion)
+		require.NoError(t, err)
+		require.Equal(t, `SELECT 'it\'s $host' FROM t WHERE host IN (?, ?)`, bound)
+
+		bound, _, err = bindParams(`SELECT '$host`, params, BindParamQuestion)
+		require.NoError(t, err)
+		require.Equal(t, `SELECT '$host`, bound)
+	})
+
+	t.Run("it does not bind the variables in comments and dollar-quoted strings", func(t *testing.T) {
+		sql := "SELECT 1 -- $host\n/* ${limit} */ FROM t WHERE host IN ($host)"
+
+		bound, args, err := bindParams(sql, params, BindParamAt)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT 1 -- $host\n/* ${limit} */ FROM t WHERE host IN (@p1, @p2)", bound)
+		require.Equal(t, []interface{}{"a", "b"}, args)
+
+		bound, _, err = bindParams("SELECT 1 # $host\nFROM t WHERE host IN ($host)", params, BindParamQuestion)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT 1 # $host\nFROM t WHERE host IN (?, ?)", bound)
+
+		bound, args, err = bindParams("SELECT $host$it's $host$host$, $$ $limit $$ FROM t LIMIT $limit", params, BindParamDollar)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT $host$it's $host$host$, $$ $limit $$ FROM t LIMIT $1", bound)
+		require.Equal(t, []interface{}{float64(10)}, args)
+	})
+
+	t.Run("it leaves the query unchanged without params", func(t *testing.T) {
+		bound, args, err := bindParams("SELECT $1", nil, BindParamDollar)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT $1", bound)
+		require.Empty(t, args)
+	})
+
+	t.Run("it returns an error for a variable without values", func(t *testing.T) {
+		_, _, err := bindParams("SELECT * FROM t WHERE host IN ($empty)", params, BindParamQuestion)
+		require.EqualError(t, err, `bind parameter "empty" has no values`)
+	})
+}
@@ -28,6 +28,9 @@ const MetaKeyExecutedQueryString = "executedQueryString"
 
 var ErrConnectionFailed = errors.New("failed to connect to server - please inspect Grafana server log for details")
 
+// ErrReadOnlyUnsupported is returned for read-only data sources of drivers that cannot run read-only queries.
+var ErrReadOnlyUnsupported = errors.New("the read-only mode is not supported by this data source, use a database user with read-only permissions instead")
+
 // SQLMacroEngine interpolates macros into sql. It takes in the Query to have access to query context and
 // timeRange to be able to generate queries that use from and to.
 type SQLMacroEngine interface {
@@ -64,6 +67,9 @@ type JsonData struct {
 	Encrypt             string `json:"encrypt"`
 	Servername          string `json:"servername"`
 	TimeInterval        string `json:"timeInterval"`
+	MaxRows             int64  `json:"maxRows"`
+	QueryTimeout        int    `json:"queryTimeout"`
+	ReadOnly            bool   `json:"readOnly"`
 }
 
 type DataSourceInfo struct {
@@ -84,6 +90,10 @@ type DataPluginConfiguration struct {
 	TimeColumnNames   []string
 	MetricColumnTypes []string
 	RowLimit          int64
+	BindParamStyle    BindParamStyle
+	// ReadOnlyUnsupported is set for the drivers that cannot begin read-only transactions. Their data
+	// sources cannot be read-only, as a query could still commit its changes.
+	ReadOnlyUnsupported bool
 }
 type DataSourceHandler struct {
 	macroEngine            SQLMacroEngine
@@ -94,6 +104,9 @@ type DataSourceHandler struct {
 	log                    log.Logger
 	dsInfo                 DataSourceInfo
 	rowLimit               int64
+	bindParamStyle         BindParamStyle
+	queryTimeout           time.Duration
+	readOnly               bool
 }
 type QueryJson struct {
 	RawSql       string  `json:"rawSql"`
@@ -102,6 +115,9 @@ type QueryJson struct {
 	FillMode     string  `json:"fillMode"`
 	FillValue    float64 `json:"fillValue"`
 	Format       string  `json:"format"`
+	// BindParams are the values of the template variables that are bound to the query as driver
+	// parameters instead of being interpolated into it.
+	BindParams map[string]interface{} `json:"bindParams"`
 }
 
 func (e *DataSourceHandler) transformQueryError(err error) error {
@@ -120,6 +136,10 @@ func (e *DataSourceHandler) transformQueryError(err error) error {
 
 func NewQueryDataHandler(config DataPluginConfiguration, queryResultTransformer SqlQueryResultTransformer,
 	macroEngine SQLMacroEngine, log log.Logger) (*DataSourceHandler, error) {
+	if config.DSInfo.JsonData.ReadOnly && config.ReadOnlyUnsupported {
+		return nil, ErrReadOnlyUnsupported
+	}
+
 	log.Debug("Creating engine...")
 	defer func() {
 		log.Debug("Engine created")
@@ -132,6 +152,14 @@ func NewQueryDataHandler(config DataPluginConfiguration, queryResultTransformer
 		log:                    log,
 		dsInfo:                 config.DSInfo,
 		rowLimit:               config.RowLimit,
+		bindParamStyle:         config.BindParamStyle,
+		queryTimeout:           time.Duration(config.DSInfo.JsonData.QueryTimeout) * time.Second,
+		readOnly:               config.DSInfo.JsonData.ReadOnly,
+	}
+
+	// the row limit of the data source can only lower the limit of the server
+	if maxRows := config.DSInfo.JsonData.MaxRows; maxRows > 0 && (queryDataHandler.rowLimit < 0 || maxRows < queryDataHandler.rowLimit) {
+		queryDataHandler.rowLimit = maxRows
 	}
 
 	if len(config.TimeColumnNames) > 0 {
@@ -256,13 +284,50 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 		return
 	}
 
+	boundQuery, args, err := bindParams(interpolatedQuery, queryJson.BindParams, e.bindParamStyle)
+	if err != nil {
+		errAppendDebug("interpolation failed", err, interpolatedQuery)
+		return
+	}
+	interpolatedQuery = boundQuery
+
+	// the statements after a COMMIT would not run in the read-only transaction
+	if e.readOnly && hasMultipleStatements(interpolatedQuery) {
+		errAppendDebug("db query error", errors.New("queries of read-only data sources cannot have several statements"), interpolatedQuery)
+		return
+	}
+
+	if e.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		queryContext, cancel = context.WithTimeout(queryContext, e.queryTimeout)
+		defer cancel()
+	}
+
 	session := e.engine.NewSession()
 	defer session.Close()
 	db := session.DB()
 
-	rows, err := db.QueryContext(queryContext, interpolatedQuery)
+	var queryer interface {
+		QueryContext(ctx context.Context, query string, args ...interface{}) (*core.Rows, error)
+	} = db
+	if e.readOnly {
+		tx, err := db.BeginTx(queryContext, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			errAppendDebug("db query error", e.transformQueryError(err), interpolatedQuery)
+			return
+		}
+		// nothing is ever committed, so that a query cannot change the database
+		defer func() {
+			if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+				e.log.Warn("Failed to roll back transaction", "err", err)
+			}
+		}()
+		queryer = tx
+	}
+
+	rows, err := queryer.QueryContext(queryContext, interpolatedQuery, args...)
 	if err != nil {
-		errAppendDebug("db query error", e.transformQueryError(err), interpolatedQuery)
+		errAppendDebug("db query error", e.transformQueryError(e.timeoutError(queryContext, err)), interpolatedQuery)
 		return
 	}
 	defer func() {
@@ -281,7 +346,7 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 	stringConverters := e.queryResultTransformer.GetConverterList()
 	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, sqlutil.ToConverters(stringConverters...)...)
 	if err != nil {
-		errAppendDebug("convert frame from rows error", err, interpolatedQuery)
+		errAppendDebug("convert frame from rows error", e.timeoutError(queryContext, err), interpolatedQuery)
 		return
 	}
 
@@ -368,6 +433,21 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 	ch <- queryResult
 }
 
+// hasMultipleStatements reports whether the SQL has a semicolon before its last statement. Quoted
+// semicolons are counted as well, as the quoting rules differ between databases and their settings.
+func hasMultipleStatements(sql string) bool {
+	return strings.Contains(strings.TrimRight(sql, "; \t\r\n"), ";")
+}
+
+// timeoutError returns an error that names the query timeout of the data source when the query
+// was cancelled because it ran longer.
+func (e *DataSourceHandler) timeoutError(ctx context.Context, err error) error {
+	if e.queryTimeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("query exceeded the timeout of %s: %w", e.queryTimeout, err)
+	}
+	return err
+}
+
 // Interpolate provides global macros/substitutions for all sql datasources.
 var Interpolate = func(query backend.DataQuery, timeRange backend.TimeRange, timeInterval string, sql string) (string, error) {
 	minInterval, err := intervalv2.GetIntervalFrom(timeInterval, query.Interval.String(), query.Interval.Milliseconds(), time.Second*60)
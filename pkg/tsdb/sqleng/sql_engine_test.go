@@ -1,8 +1,10 @@
 package sqleng
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"reflect"
 	"testing"
 	"time"
 
@@ -10,9 +12,11 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
 	"github.com/grafana/grafana/pkg/infra/log"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/xorcare/pointer"
+	"xorm.io/xorm"
 )
 
 func TestSQLEngine(t *testing.T) {
@@ -413,6 +417,101 @@ func TestSQLEngine(t *testing.T) {
 	})
 }
 
+func TestQueryDataSettings(t *testing.T) {
+	origXormEngine := NewXormEngine
+	t.Cleanup(func() {
+		NewXormEngine = origXormEngine
+	})
+	NewXormEngine = func(d, c string) (*xorm.Engine, error) {
+		return xorm.NewEngine("sqlite3", "file::memory:?cache=shared")
+	}
+
+	newHandler := func(t *testing.T, jsonData JsonData) *DataSourceHandler {
+		// a single connection keeps the in-memory database alive between the queries
+		jsonData.MaxOpenConns = 1
+		jsonData.MaxIdleConns = 1
+		handler, err := NewQueryDataHandler(DataPluginConfiguration{
+			DSInfo:         DataSourceInfo{JsonData: jsonData},
+			RowLimit:       1000,
+			BindParamStyle: BindParamQuestion,
+		}, &testQueryResultTransformer{}, &testMacroEngine{}, log.New("test"))
+		require.NoError(t, err)
+		t.Cleanup(handler.Dispose)
+		return handler
+	}
+
+	query := func(t *testing.T, handler *DataSourceHandler, model string) backend.DataResponse {
+		resp, err := handler.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{RefID: "A", JSON: []byte(model)}},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	setup := newHandler(t, JsonData{})
+	_, err := setup.engine.Exec("CREATE TABLE hosts (name TEXT, value INTEGER)")
+	require.NoError(t, err)
+	_, err = setup.engine.Exec("INSERT INTO hosts VALUES ('a', 1), ('b', 2), ('c', 3)")
+	require.NoError(t, err)
+
+	t.Run("it binds the template variables as parameters", func(t *testing.T) {
+		res := query(t, setup, `{
+			"format": "table",
+			"rawSql": "SELECT name FROM hosts WHERE name IN ($host) AND value > ${min} ORDER BY name",
+			"bindParams": {"host": ["a", "b", "c' OR 1=1 --"], "min": 1}
+		}`)
+		require.NoError(t, res.Error)
+		require.Equal(t, "SELECT name FROM hosts WHERE name IN (?, ?, ?) AND value > ? ORDER BY name", res.Frames[0].Meta.ExecutedQueryString)
+		require.Equal(t, 1, res.Frames[0].Rows())
+		require.Equal(t, "b", *res.Frames[0].Fields[0].At(0).(*string))
+	})
+
+	t.Run("it returns the interpolated query when the parameters cannot be bound", func(t *testing.T) {
+		res := query(t, setup, `{"format": "table", "rawSql": "SELECT name FROM hosts WHERE name IN ($host)", "bindParams": {"host": []}}`)
+		require.EqualError(t, res.Error, `interpolation failed: bind parameter "host" has no values`)
+		require.Equal(t, "SELECT name FROM hosts WHERE name IN ($host)", res.Frames[0].Meta.ExecutedQueryString)
+	})
+
+	t.Run("it limits the rows to the maximum of the data source", func(t *testing.T) {
+		res := query(t, newHandler(t, JsonData{MaxRows: 2}), `{"format": "table", "rawSql": "SELECT name FROM hosts"}`)
+		require.NoError(t, res.Error)
+		require.Equal(t, 2, res.Frames[0].Rows())
+		require.Len(t, res.Frames[0].Meta.Notices, 1)
+	})
+
+	t.Run("it does not let read-only data sources change the database", func(t *testing.T) {
+		res := query(t, newHandler(t, JsonData{ReadOnly: true}), `{"format": "table", "rawSql": "DELETE FROM hosts"}`)
+		require.NoError(t, res.Error)
+
+		count, err := setup.engine.Table("hosts").Count()
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count)
+	})
+
+	t.Run("it rejects queries with several statements for read-only data sources", func(t *testing.T) {
+		res := query(t, newHandler(t, JsonData{ReadOnly: true}), `{"format": "table", "rawSql": "SELECT 1; COMMIT; DELETE FROM hosts;"}`)
+		require.EqualError(t, res.Error, "db query error: queries of read-only data sources cannot have several statements")
+
+		res = query(t, newHandler(t, JsonData{ReadOnly: true}), `{"format": "table", "rawSql": "SELECT name FROM hosts;\n"}`)
+		require.NoError(t, res.Error)
+		require.Equal(t, 3, res.Frames[0].Rows())
+	})
+
+	t.Run("it rejects read-only data sources of drivers without read-only transactions", func(t *testing.T) {
+		_, err := NewQueryDataHandler(DataPluginConfiguration{
+			DSInfo:              DataSourceInfo{JsonData: JsonData{ReadOnly: true}},
+			ReadOnlyUnsupported: true,
+		}, &testQueryResultTransformer{}, &testMacroEngine{}, log.New("test"))
+		require.ErrorIs(t, err, ErrReadOnlyUnsupported)
+	})
+}
+
+type testMacroEngine struct{}
+
+func (m *testMacroEngine) Interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string) (string, error) {
+	return sql, nil
+}
+
 type testQueryResultTransformer struct {
 	transformQueryErrorWasCalled bool
 }
@@ -423,5 +522,17 @@ func (t *testQueryResultTransformer) TransformQueryError(err error) error {
 }
 
 func (t *testQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
-	return nil
+	return []sqlutil.StringConverter{
+		{
+			Name:          "handle TEXT",
+			InputScanKind: reflect.Interface,
+			InputTypeName: "TEXT",
+			Replacer: &sqlutil.StringFieldReplacer{
+				OutputFieldType: data.FieldTypeNullableString,
+				ReplaceFunc: func(in *string) (interface{}, error) {
+					return in, nil
+				},
+			},
+		},
+	}
 }
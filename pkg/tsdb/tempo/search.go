@@ -0,0 +1,168 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const defaultSearchLimit = 20
+
+// SearchResponse is the response of the Tempo search API.
+type SearchResponse struct {
+	Traces []*SearchTrace `json:"traces"`
+}
+
+type SearchTrace struct {
+	TraceID           string `json:"traceID"`
+	RootServiceName   string `json:"rootServiceName"`
+	RootTraceName     string `json:"rootTraceName"`
+	StartTimeUnixNano string `json:"startTimeUnixNano"`
+	DurationMs        int64  `json:"durationMs"`
+}
+
+// search runs a search by tags and duration, or a TraceQL query, over the time range of the query.
+func (s *Service) search(ctx context.Context, dsInfo *datasourceInfo, query backend.DataQuery, model *QueryModel) backend.DataResponse {
+	params, err := searchParams(query, model)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, dsInfo.URL+"/api/search?"+params.Encode(), nil)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	s.tlog.Debug("Tempo search request", "url", req.URL.String())
+
+	resp, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return backend.DataResponse{Error: fmt.Errorf("failed get to tempo: %w", err)}
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.tlog.Warn("failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return backend.DataResponse{Error: fmt.Errorf("failed to search traces Status: %s Body: %s", resp.Status, string(body))}
+	}
+
+	var searchResp SearchResponse
+	if err := json.Unmarshal(body, &searchResp); err != nil {
+		return backend.DataResponse{Error: fmt.Errorf("failed to parse tempo search response: %w", err)}
+	}
+
+	frame := SearchToFrame(searchResp.Traces)
+	frame.RefID = query.RefID
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// searchParams returns the parameters of the Tempo search API for a search or TraceQL query.
+func searchParams(query backend.DataQuery, model *QueryModel) (url.Values, error) {
+	params := url.Values{}
+
+	if model.QueryType == queryTypeTraceQL {
+		if strings.TrimSpace(model.TraceID) == "" {
+			return nil, fmt.Errorf("TraceQL query is empty")
+		}
+		params.Set("q", model.TraceID)
+	} else {
+		tags := strings.TrimSpace(model.Search)
+		if model.ServiceName != "" {
+			tags += fmt.Sprintf(" service.name=%q", model.ServiceName)
+		}
+		if model.SpanName != "" {
+			tags += fmt.Sprintf(" name=%q", model.SpanName)
+		}
+		if tags = strings.TrimSpace(tags); tags != "" {
+			params.Set("tags", tags)
+		}
+
+		durations := []struct{ name, value string }{
+			{"minDuration", model.MinDuration},
+			{"maxDuration", model.MaxDuration},
+		}
+		for _, d := range durations {
+			duration := strings.Join(strings.Fields(d.value), "")
+			if duration == "" {
+				continue
+			}
+			if _, err := time.ParseDuration(duration); err != nil {
+				return nil, fmt.Errorf("invalid %s %q", d.name, d.value)
+			}
+			params.Set(d.name, duration)
+		}
+	}
+
+	limit := model.Limit
+	if limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d", limit)
+	}
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	params.Set("limit", strconv.FormatInt(limit, 10))
+
+	if !query.TimeRange.From.IsZero() {
+		params.Set("start", strconv.FormatInt(query.TimeRange.From.Unix(), 10))
+	}
+	if !query.TimeRange.To.IsZero() {
+		params.Set("end", strconv.FormatInt(query.TimeRange.To.Unix(), 10))
+	}
+	return params, nil
+}
+
+// SearchToFrame returns a table of the traces found by a search, the most recent first.
+func SearchToFrame(traces []*SearchTrace) *data.Frame {
+	type row struct {
+		trace     *SearchTrace
+		startTime time.Time
+	}
+	rows := make([]row, 0, len(traces))
+	for _, trace := range traces {
+		nanos, _ := strconv.ParseInt(trace.StartTimeUnixNano, 10, 64)
+		rows = append(rows, row{trace: trace, startTime: time.Unix(0, nanos).UTC()})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].startTime.After(rows[j].startTime)
+	})
+
+	traceIDs := make([]string, len(rows))
+	traceNames := make([]string, len(rows))
+	startTimes := make([]time.Time, len(rows))
+	durations := make([]int64, len(rows))
+	for i, r := range rows {
+		traceIDs[i] = r.trace.TraceID
+		traceNames[i] = strings.TrimSpace(r.trace.RootServiceName + " " + r.trace.RootTraceName)
+		startTimes[i] = r.startTime
+		durations[i] = r.trace.DurationMs
+	}
+
+	return &data.Frame{
+		Name: "Traces",
+		Fields: []*data.Field{
+			data.NewField("traceID", nil, traceIDs).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Trace ID"}),
+			data.NewField("traceName", nil, traceNames).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Trace name"}),
+			data.NewField("startTime", nil, startTimes).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Start time"}),
+			data.NewField("duration", nil, durations).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Duration", Unit: "ms"}),
+		},
+		Meta: &data.FrameMeta{
+			PreferredVisualization: data.VisTypeTable,
+		},
+	}
+}
@@ -0,0 +1,255 @@
+package tempo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/services/datasources/permissions"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// The service graph metrics Tempo writes to Prometheus. Their client and server labels are the
+// services of the edges of the graph.
+const (
+	secondsMetric = "traces_service_graph_request_server_seconds_sum"
+	totalsMetric  = "traces_service_graph_request_total"
+	failedMetric  = "traces_service_graph_request_failed_total"
+)
+
+var serviceGraphMetrics = []string{secondsMetric, totalsMetric, failedMetric}
+
+// serviceGraphStats are the statistics of a node or an edge of the service graph over the time
+// range of the query.
+type serviceGraphStats struct {
+	total   float64
+	seconds float64
+	failed  float64
+}
+
+func (s *serviceGraphStats) add(metric string, value float64) {
+	switch metric {
+	case totalsMetric:
+		s.total += value
+	case secondsMetric:
+		s.seconds += value
+	case failedMetric:
+		s.failed += value
+	}
+}
+
+type serviceGraphEdge struct {
+	serviceGraphStats
+	source string
+	target string
+}
+
+// serviceGraph queries the service graph metrics from the Prometheus data source of the Tempo data
+// source through the query service, as the user of the request, and returns the nodes and edges frames
+// of the graph.
+func (s *Service) serviceGraph(ctx context.Context, req *backend.QueryDataRequest, dsInfo *datasourceInfo, query backend.DataQuery, model *QueryModel) backend.DataResponse {
+	if dsInfo.ServiceMapDatasourceUID == "" {
+		return backend.DataResponse{Error: fmt.Errorf("no Prometheus data source is configured for the service graph")}
+	}
+	if s.queryDataService == nil {
+		return backend.DataResponse{Error: fmt.Errorf("the service graph queries are not available")}
+	}
+	// the metrics are queried with the identity of the user of the request, so that the permissions and the
+	// OAuth tokens of the user apply to the Prometheus data source.
+	user := s.signedInUser(ctx)
+	if user == nil || user.OrgId != req.PluginContext.OrgID {
+		return backend.DataResponse{Error: models.ErrDataSourceAccessDenied}
+	}
+
+	dsQuery := &models.GetDataSourceQuery{Uid: dsInfo.ServiceMapDatasourceUID, OrgId: req.PluginContext.OrgID}
+	if err := s.dataSourceService.GetDataSource(ctx, dsQuery); err != nil {
+		return backend.DataResponse{Error: fmt.Errorf("failed to get the service graph data source: %w", err)}
+	}
+	ds := dsQuery.Result
+	if ds.Type != models.DS_PROMETHEUS {
+		return backend.DataResponse{Error: fmt.Errorf("the service graph data source %q is not a Prometheus data source", ds.Name)}
+	}
+	if err := s.checkQueryPermission(ctx, user, ds); err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	if err := validateServiceMapQuery(model.ServiceMapQuery); err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	metricReq := dtos.MetricRequest{
+		From: strconv.FormatInt(query.TimeRange.From.UnixNano()/int64(time.Millisecond), 10),
+		To:   strconv.FormatInt(query.TimeRange.To.UnixNano()/int64(time.Millisecond), 10),
+	}
+	for _, metric := range serviceGraphMetrics {
+		metricReq.Queries = append(metricReq.Queries, simplejson.NewFromAny(map[string]interface{}{
+			"refId":         metric,
+			"datasource":    map[string]interface{}{"uid": ds.Uid, "type": ds.Type},
+			"expr":          fmt.Sprintf("sum by (client, server) (delta(%s%s[$__range]))", metric, strings.TrimSpace(model.ServiceMapQuery)),
+			"instant":       true,
+			"intervalMs":    query.Interval.Milliseconds(),
+			"maxDataPoints": query.MaxDataPoints,
+		}))
+	}
+
+	promResp, err := s.queryDataService.QueryData(ctx, user, false, metricReq, false)
+	if err != nil {
+		return backend.DataResponse{Error: fmt.Errorf("failed to query the service graph metrics: %w", err)}
+	}
+	for _, metric := range serviceGraphMetrics {
+		if err := promResp.Responses[metric].Error; err != nil {
+			return backend.DataResponse{Error: fmt.Errorf("failed to query %s: %w", metric, err)}
+		}
+	}
+
+	nodes, edges := ServiceGraphToFrames(promResp.Responses, query.TimeRange.To.Sub(query.TimeRange.From).Seconds())
+	nodes.RefID = query.RefID
+	edges.RefID = query.RefID
+	return backend.DataResponse{Frames: data.Frames{nodes, edges}}
+}
+
+// signedInUserFromContext returns the user of the HTTP request of the context, or nil if there is none.
+func signedInUserFromContext(ctx context.Context) *models.SignedInUser {
+	reqCtx := contexthandler.FromContext(ctx)
+	if reqCtx == nil || !reqCtx.IsSignedIn && !reqCtx.IsAnonymous {
+		return nil
+	}
+	return reqCtx.SignedInUser
+}
+
+// checkQueryPermission returns models.ErrDataSourceAccessDenied if the user cannot query the data source.
+func (s *Service) checkQueryPermission(ctx context.Context, user *models.SignedInUser, ds *models.DataSource) error {
+	filterQuery := models.DatasourcesPermissionFilterQuery{
+		User:        user,
+		Datasources: []*models.DataSource{ds},
+	}
+	if err := s.dsPermissions.FilterDatasourcesBasedOnQueryPermissions(ctx, &filterQuery); err != nil {
+		if errors.Is(err, permissions.ErrNotImplemented) {
+			return nil
+		}
+		return err
+	}
+	if len(filterQuery.Result) == 0 {
+		return models.ErrDataSourceAccessDenied
+	}
+	return nil
+}
+
+// validateServiceMapQuery returns an error if the filter of the service graph metrics is not a label selector,
+// such as {client="app"}, since it is inserted in the queries of the metrics.
+func validateServiceMapQuery(query string) error {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+	if !strings.HasPrefix(query, "{") || !strings.HasSuffix(query, "}") {
+		return fmt.Errorf("the service graph query must be a label selector, such as {client=\"app\"}")
+	}
+	if _, err := parser.ParseMetricSelector(query); err != nil {
+		return fmt.Errorf("invalid service graph query: %w", err)
+	}
+	return nil
+}
+
+// ServiceGraphToFrames returns the nodes and edges frames of the service graph from the responses
+// of the service graph metrics queries, by metric. The statistics of an edge are attributed to its
+// server node, so that the nodes show the requests they handled.
+func ServiceGraphToFrames(responses backend.Responses, rangeSeconds float64) (*data.Frame, *data.Frame) {
+	nodeStats := map[string]*serviceGraphStats{}
+	edgeStats := map[string]*serviceGraphEdge{}
+	var nodeIDs, edgeIDs []string
+
+	for _, metric := range serviceGraphMetrics {
+		for _, frame := range responses[metric].Frames {
+			for _, field := range frame.Fields {
+				if field.Len() == 0 || field.Type() != data.FieldTypeFloat64 {
+					continue
+				}
+				client, server := field.Labels["client"], field.Labels["server"]
+				value := field.At(field.Len() - 1).(float64)
+				if math.IsNaN(value) {
+					continue
+				}
+
+				id := client + "_" + server
+				if edgeStats[id] == nil {
+					edgeStats[id] = &serviceGraphEdge{source: client, target: server}
+					edgeIDs = append(edgeIDs, id)
+				}
+				edgeStats[id].add(metric, value)
+
+				for _, node := range []string{client, server} {
+					if nodeStats[node] == nil {
+						nodeStats[node] = &serviceGraphStats{}
+						nodeIDs = append(nodeIDs, node)
+					}
+				}
+				nodeStats[server].add(metric, value)
+			}
+		}
+	}
+
+	nodes := data.NewFrame("Nodes",
+		data.NewField("id", nil, []string{}),
+		data.NewField("title", nil, []string{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Service name"}),
+		data.NewField("mainstat", nil, []*float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Average response time", Unit: "ms/r"}),
+		data.NewField("secondarystat", nil, []*float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Requests per second", Unit: "r/sec"}),
+		data.NewField("arc__success", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Success", Color: fixedColor("green")}),
+		data.NewField("arc__failed", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Failed", Color: fixedColor("red")}),
+	)
+	nodes.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeNodeGraph}
+	sort.Strings(nodeIDs)
+	for _, id := range nodeIDs {
+		stats := nodeStats[id]
+		// a root client node that did not handle requests itself has no statistics
+		var responseTime, requestRate *float64
+		success, failed := 1.0, 0.0
+		if stats.total > 0 {
+			responseTime = float64Ptr(stats.seconds / stats.total * 1000)
+			if rangeSeconds > 0 {
+				requestRate = float64Ptr(math.Round(stats.total/rangeSeconds*100) / 100)
+			}
+			failed = math.Min(stats.failed, stats.total) / stats.total
+			success = 1 - failed
+		}
+		nodes.AppendRow(id, id, responseTime, requestRate, success, failed)
+	}
+
+	edges := data.NewFrame("Edges",
+		data.NewField("id", nil, []string{}),
+		data.NewField("source", nil, []string{}),
+		data.NewField("target", nil, []string{}),
+		data.NewField("mainstat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Requests", Unit: "r"}),
+		data.NewField("secondarystat", nil, []*float64{}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Average response time", Unit: "ms/r"}),
+	)
+	edges.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeNodeGraph}
+	sort.Strings(edgeIDs)
+	for _, id := range edgeIDs {
+		edge := edgeStats[id]
+		var responseTime *float64
+		if edge.total > 0 {
+			responseTime = float64Ptr(edge.seconds / edge.total * 1000)
+		}
+		edges.AppendRow(id, edge.source, edge.target, edge.total, responseTime)
+	}
+
+	return nodes, edges
+}
+
+func fixedColor(color string) map[string]interface{} {
+	return map[string]interface{}{"mode": "fixed", "fixedColor": color}
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
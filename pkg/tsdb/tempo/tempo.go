@@ -11,30 +11,90 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/datasources/permissions"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/query"
 	"go.opentelemetry.io/collector/model/otlp"
 )
 
 type Service struct {
-	im   instancemgmt.InstanceManager
-	tlog log.Logger
+	im                instancemgmt.InstanceManager
+	tlog              log.Logger
+	features          featuremgmt.FeatureToggles
+	dataSourceService datasources.DataSourceService
+	// dsPermissions checks that the user of a service graph query can query the Prometheus data source.
+	dsPermissions permissions.DatasourcePermissionsService
+	// queryDataService runs the queries of the service graph metrics. It is set by
+	// ProvideQueryDataServiceRegistration, since the query service depends on the plugins.
+	queryDataService queryDataService
+	signedInUser     func(ctx context.Context) *models.SignedInUser
 }
 
-func ProvideService(httpClientProvider httpclient.Provider) *Service {
+type queryDataService interface {
+	QueryData(ctx context.Context, user *models.SignedInUser, skipCache bool, reqDTO dtos.MetricRequest, handleExpressions bool) (*backend.QueryDataResponse, error)
+}
+
+func ProvideService(httpClientProvider httpclient.Provider, features featuremgmt.FeatureToggles,
+	dataSourceService datasources.DataSourceService, dsPermissions permissions.DatasourcePermissionsService) *Service {
 	return &Service{
-		tlog: log.New("tsdb.tempo"),
-		im:   datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
+		tlog:              log.New("tsdb.tempo"),
+		im:                datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
+		features:          features,
+		dataSourceService: dataSourceService,
+		dsPermissions:     dsPermissions,
+		signedInUser:      signedInUserFromContext,
 	}
 }
 
+// QueryDataServiceRegistration is provided once the query service of the service graph queries is set.
+type QueryDataServiceRegistration struct{}
+
+// ProvideQueryDataServiceRegistration sets the query service that runs the service graph queries.
+func ProvideQueryDataServiceRegistration(s *Service, queryDataService *query.Service) *QueryDataServiceRegistration {
+	s.queryDataService = queryDataService
+	return &QueryDataServiceRegistration{}
+}
+
 type datasourceInfo struct {
 	HTTPClient *http.Client
 	URL        string
+	UID        string
+	Name       string
+	// ServiceMapDatasourceUID is the UID of the Prometheus data source with the service graph metrics.
+	ServiceMapDatasourceUID string
 }
 
+const (
+	queryTypeTraceID    = "traceId"
+	queryTypeSearch     = "nativeSearch"
+	queryTypeTraceQL    = "traceql"
+	queryTypeServiceMap = "serviceMap"
+)
+
 type QueryModel struct {
+	QueryType string `json:"queryType"`
+	// TraceID is the ID of the trace of trace ID queries, and the TraceQL query of TraceQL queries.
 	TraceID string `json:"query"`
+
+	Search      string `json:"search"`
+	ServiceName string `json:"serviceName"`
+	SpanName    string `json:"spanName"`
+	MinDuration string `json:"minDuration"`
+	MaxDuration string `json:"maxDuration"`
+	Limit       int64  `json:"limit"`
+
+	ServiceMapQuery string `json:"serviceMapQuery"`
+}
+
+type jsonData struct {
+	ServiceMap struct {
+		DatasourceUID string `json:"datasourceUid"`
+	} `json:"serviceMap"`
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -49,9 +109,19 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			return nil, err
 		}
 
+		var jd jsonData
+		if len(settings.JSONData) > 0 {
+			if err := json.Unmarshal(settings.JSONData, &jd); err != nil {
+				return nil, fmt.Errorf("error reading settings: %w", err)
+			}
+		}
+
 		model := &datasourceInfo{
-			HTTPClient: client,
-			URL:        settings.URL,
+			HTTPClient:              client,
+			URL:                     settings.URL,
+			UID:                     settings.UID,
+			Name:                    settings.Name,
+			ServiceMapDatasourceUID: jd.ServiceMap.DatasourceUID,
 		}
 		return model, nil
 	}
@@ -59,28 +129,58 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	result := backend.NewQueryDataResponse()
-	queryRes := backend.DataResponse{}
-	refID := req.Queries[0].RefID
-
-	model := &QueryModel{}
-	err := json.Unmarshal(req.Queries[0].JSON, model)
-	if err != nil {
-		return result, err
-	}
 
 	dsInfo, err := s.getDSInfo(req.PluginContext)
 	if err != nil {
 		return nil, err
 	}
 
+	for _, query := range req.Queries {
+		model := &QueryModel{}
+		err := json.Unmarshal(query.JSON, model)
+		if err != nil {
+			return result, err
+		}
+
+		switch model.QueryType {
+		case queryTypeSearch, queryTypeTraceQL:
+			if !s.features.IsEnabled(featuremgmt.FlagTempoBackendSearch) {
+				result.Responses[query.RefID] = backend.DataResponse{Error: fmt.Errorf("tempo search requires the %s feature toggle", featuremgmt.FlagTempoBackendSearch)}
+				continue
+			}
+			result.Responses[query.RefID] = s.search(ctx, dsInfo, query, model)
+		case queryTypeServiceMap:
+			if !s.features.IsEnabled(featuremgmt.FlagTempoServiceGraph) {
+				result.Responses[query.RefID] = backend.DataResponse{Error: fmt.Errorf("tempo service graph requires the %s feature toggle", featuremgmt.FlagTempoServiceGraph)}
+				continue
+			}
+			result.Responses[query.RefID] = s.serviceGraph(ctx, req, dsInfo, query, model)
+		case queryTypeTraceID, "":
+			queryRes, err := s.queryTrace(ctx, dsInfo, query.RefID, model)
+			if err != nil {
+				// a failed trace query does not fail the other queries of the request
+				queryRes.Error = err
+			}
+			result.Responses[query.RefID] = queryRes
+		default:
+			result.Responses[query.RefID] = backend.DataResponse{Error: fmt.Errorf("unsupported query type %q", model.QueryType)}
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Service) queryTrace(ctx context.Context, dsInfo *datasourceInfo, refID string, model *QueryModel) (backend.DataResponse, error) {
+	queryRes := backend.DataResponse{}
+
 	request, err := s.createRequest(ctx, dsInfo, model.TraceID)
 	if err != nil {
-		return result, err
+		return queryRes, err
 	}
 
 	resp, err := dsInfo.HTTPClient.Do(request)
 	if err != nil {
-		return result, fmt.Errorf("failed get to tempo: %w", err)
+		return queryRes, fmt.Errorf("failed get to tempo: %w", err)
 	}
 
 	defer func() {
@@ -91,30 +191,28 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return &backend.QueryDataResponse{}, err
+		return queryRes, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		queryRes.Error = fmt.Errorf("failed to get trace with id: %s Status: %s Body: %s", model.TraceID, resp.Status, string(body))
-		result.Responses[refID] = queryRes
-		return result, nil
+		return queryRes, nil
 	}
 
 	otTrace, err := otlp.NewProtobufTracesUnmarshaler().UnmarshalTraces(body)
 
 	if err != nil {
-		return &backend.QueryDataResponse{}, fmt.Errorf("failed to convert tempo response to Otlp: %w", err)
+		return queryRes, fmt.Errorf("failed to convert tempo response to Otlp: %w", err)
 	}
 
 	frame, err := TraceToFrame(otTrace)
 	if err != nil {
-		return &backend.QueryDataResponse{}, fmt.Errorf("failed to transform trace %v to data frame: %w", model.TraceID, err)
+		return queryRes, fmt.Errorf("failed to transform trace %v to data frame: %w", model.TraceID, err)
 	}
 	frame.RefID = refID
 	frames := []*data.Frame{frame}
 	queryRes.Frames = frames
-	result.Responses[refID] = queryRes
-	return result, nil
+	return queryRes, nil
 }
 
 func (s *Service) createRequest(ctx context.Context, dsInfo *datasourceInfo, traceID string) (*http.Request, error) {
@@ -2,9 +2,23 @@ package tempo
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/datasources/permissions"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -16,4 +30,279 @@ func TestTempo(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, 1, len(req.Header))
 	})
+
+	t.Run("QueryData returns the error of a trace query in its response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			_, _ = rw.Write([]byte("not a trace"))
+		}))
+		defer server.Close()
+
+		service := setupService()
+		req := queryRequest(server.URL, `{}`, backend.TimeRange{}, `{"queryType":"traceId","query":"abc"}`)
+		req.Queries = append(req.Queries, backend.DataQuery{RefID: "B", JSON: []byte(`{"queryType":"unknown"}`)})
+		resp, err := service.QueryData(context.Background(), req)
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+		require.EqualError(t, resp.Responses["B"].Error, `unsupported query type "unknown"`)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	timeRange := backend.TimeRange{From: time.Unix(1640995200, 0), To: time.Unix(1640998800, 0)}
+
+	t.Run("searchParams builds the tags from the search, service and span names", func(t *testing.T) {
+		params, err := searchParams(backend.DataQuery{TimeRange: timeRange}, &QueryModel{
+			QueryType:   queryTypeSearch,
+			Search:      "http.status_code=500",
+			ServiceName: "app",
+			SpanName:    "HTTP GET",
+			MinDuration: "1 s",
+			MaxDuration: "2m",
+		})
+		require.NoError(t, err)
+		require.Equal(t, url.Values{
+			"tags":        {`http.status_code=500 service.name="app" name="HTTP GET"`},
+			"minDuration": {"1s"},
+			"maxDuration": {"2m"},
+			"limit":       {"20"},
+			"start":       {"1640995200"},
+			"end":         {"1640998800"},
+		}, params)
+	})
+
+	t.Run("searchParams sends TraceQL queries", func(t *testing.T) {
+		params, err := searchParams(backend.DataQuery{}, &QueryModel{QueryType: queryTypeTraceQL, TraceID: `{ .http.status_code = 500 }`, Limit: 5})
+		require.NoError(t, err)
+		require.Equal(t, url.Values{"q": {`{ .http.status_code = 500 }`}, "limit": {"5"}}, params)
+
+		_, err = searchParams(backend.DataQuery{}, &QueryModel{QueryType: queryTypeTraceQL})
+		require.Error(t, err)
+	})
+
+	t.Run("searchParams returns an error for invalid durations", func(t *testing.T) {
+		_, err := searchParams(backend.DataQuery{}, &QueryModel{QueryType: queryTypeSearch, MinDuration: "1 second"})
+		require.EqualError(t, err, `invalid minDuration "1 second"`)
+	})
+
+	t.Run("SearchToFrame returns the most recent traces first", func(t *testing.T) {
+		frame := SearchToFrame([]*SearchTrace{
+			{TraceID: "1", RootServiceName: "app", RootTraceName: "GET /", StartTimeUnixNano: "1640995200000000000", DurationMs: 10},
+			{TraceID: "2", RootServiceName: "db", StartTimeUnixNano: "1640995260000000000", DurationMs: 2},
+		})
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, data.VisType(data.VisTypeTable), frame.Meta.PreferredVisualization)
+		require.Equal(t, []interface{}{"2", "db", time.Unix(1640995260, 0).UTC(), int64(2)}, frame.RowCopy(0))
+		require.Equal(t, []interface{}{"1", "app GET /", time.Unix(1640995200, 0).UTC(), int64(10)}, frame.RowCopy(1))
+	})
+
+	t.Run("QueryData searches traces when backend search is enabled", func(t *testing.T) {
+		var params url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			require.Equal(t, "/api/search", req.URL.Path)
+			params = req.URL.Query()
+			_, _ = rw.Write([]byte(`{"traces":[{"traceID":"abc","rootServiceName":"app","rootTraceName":"GET /","startTimeUnixNano":"1640995200000000000","durationMs":10}]}`))
+		}))
+		defer server.Close()
+
+		service := setupService(featuremgmt.FlagTempoBackendSearch)
+		resp, err := service.QueryData(context.Background(), queryRequest(server.URL, `{}`, timeRange, `{"queryType":"nativeSearch","serviceName":"app"}`))
+		require.NoError(t, err)
+		require.NoError(t, resp.Responses["A"].Error)
+		require.Len(t, resp.Responses["A"].Frames, 1)
+		require.Equal(t, "A", resp.Responses["A"].Frames[0].RefID)
+		require.Equal(t, 1, resp.Responses["A"].Frames[0].Rows())
+		require.Equal(t, `service.name="app"`, params.Get("tags"))
+
+		service = setupService()
+		resp, err = service.QueryData(context.Background(), queryRequest(server.URL, `{}`, timeRange, `{"queryType":"nativeSearch","serviceName":"app"}`))
+		require.NoError(t, err)
+		require.EqualError(t, resp.Responses["A"].Error, "tempo search requires the tempoBackendSearch feature toggle")
+	})
+}
+
+func TestServiceGraph(t *testing.T) {
+	timeRange := backend.TimeRange{From: time.Unix(1640995200, 0), To: time.Unix(1640995300, 0)}
+
+	t.Run("ServiceGraphToFrames attributes the edge statistics to the server nodes", func(t *testing.T) {
+		nodes, edges := ServiceGraphToFrames(backend.Responses{
+			totalsMetric: {Frames: data.Frames{
+				metricFrame("app", "db", 10),
+				metricFrame("user", "app", 20),
+			}},
+			secondsMetric: {Frames: data.Frames{
+				metricFrame("app", "db", 1),
+				metricFrame("user", "app", 4),
+			}},
+			failedMetric: {Frames: data.Frames{
+				metricFrame("app", "db", 5),
+			}},
+		}, 100)
+
+		require.Equal(t, data.VisType(data.VisTypeNodeGraph), nodes.Meta.PreferredVisualization)
+		require.Equal(t, 3, nodes.Rows())
+		require.Equal(t, []interface{}{"app", "app", float64Ptr(200), float64Ptr(0.2), 1.0, 0.0}, nodes.RowCopy(0))
+		require.Equal(t, []interface{}{"db", "db", float64Ptr(100), float64Ptr(0.1), 0.5, 0.5}, nodes.RowCopy(1))
+		require.Equal(t, []interface{}{"user", "user", (*float64)(nil), (*float64)(nil), 1.0, 0.0}, nodes.RowCopy(2))
+
+		require.Equal(t, 2, edges.Rows())
+		require.Equal(t, []interface{}{"app_db", "app", "db", 10.0, float64Ptr(100)}, edges.RowCopy(0))
+		require.Equal(t, []interface{}{"user_app", "user", "app", 20.0, float64Ptr(200)}, edges.RowCopy(1))
+	})
+
+	t.Run("QueryData queries the metrics from the Prometheus data source", func(t *testing.T) {
+		service, prom := setupServiceGraph(&fakeDatasourcePermissions{err: permissions.ErrNotImplemented})
+
+		req := queryRequest("", `{"serviceMap":{"datasourceUid":"prom"}}`, timeRange, `{"queryType":"serviceMap","serviceMapQuery":"{client=\"app\"}"}`)
+		resp, err := service.QueryData(context.Background(), req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Responses["A"].Error)
+		require.Len(t, resp.Responses["A"].Frames, 2)
+		require.Equal(t, "Nodes", resp.Responses["A"].Frames[0].Name)
+		require.Equal(t, "Edges", resp.Responses["A"].Frames[1].Name)
+		require.Equal(t, 1, resp.Responses["A"].Frames[1].Rows())
+
+		require.Equal(t, "editor", prom.user.Login)
+		require.Equal(t, "1640995200000", prom.req.From)
+		require.Equal(t, "1640995300000", prom.req.To)
+		require.Len(t, prom.req.Queries, 3)
+		query := prom.req.Queries[1]
+		require.Equal(t, "prom", query.Get("datasource").Get("uid").MustString())
+		require.Equal(t, `sum by (client, server) (delta(traces_service_graph_request_total{client="app"}[$__range]))`, query.Get("expr").MustString())
+		require.Equal(t, true, query.Get("instant").MustBool())
+	})
+
+	t.Run("QueryData checks that the user can query the Prometheus data source", func(t *testing.T) {
+		dsPermissions := &fakeDatasourcePermissions{}
+		service, prom := setupServiceGraph(dsPermissions)
+
+		req := queryRequest("", `{"serviceMap":{"datasourceUid":"prom"}}`, timeRange, `{"queryType":"serviceMap"}`)
+		resp, err := service.QueryData(context.Background(), req)
+		require.NoError(t, err)
+		require.ErrorIs(t, resp.Responses["A"].Error, models.ErrDataSourceAccessDenied)
+		require.Nil(t, prom.req)
+		require.Equal(t, "editor", dsPermissions.user.Login)
+
+		dsPermissions.allow = true
+		resp, err = service.QueryData(context.Background(), req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Responses["A"].Error)
+
+		service.signedInUser = func(context.Context) *models.SignedInUser { return nil }
+		resp, err = service.QueryData(context.Background(), req)
+		require.NoError(t, err)
+		require.ErrorIs(t, resp.Responses["A"].Error, models.ErrDataSourceAccessDenied)
+
+		service.signedInUser = func(context.Context) *models.SignedInUser {
+			return &models.SignedInUser{Login: "editor", OrgId: 2, OrgRole: models.ROLE_EDITOR}
+		}
+		resp, err = service.QueryData(context.Background(), req)
+		require.NoError(t, err)
+		require.ErrorIs(t, resp.Responses["A"].Error, models.ErrDataSourceAccessDenied)
+	})
+
+	t.Run("QueryData only accepts label selectors as service graph queries", func(t *testing.T) {
+		service, prom := setupServiceGraph(&fakeDatasourcePermissions{err: permissions.ErrNotImplemented})
+
+		for _, query := range []string{`{client=\"app\"} or vector(1)`, `up`, `{client=}`} {
+			req := queryRequest("", `{"serviceMap":{"datasourceUid":"prom"}}`, timeRange, `{"queryType":"serviceMap","serviceMapQuery":"`+query+`"}`)
+			resp, err := service.QueryData(context.Background(), req)
+			require.NoError(t, err)
+			require.Error(t, resp.Responses["A"].Error, query)
+		}
+		require.Nil(t, prom.req)
+	})
+
+	t.Run("QueryData returns an error without a Prometheus data source", func(t *testing.T) {
+		service := setupService(featuremgmt.FlagTempoServiceGraph)
+		resp, err := service.QueryData(context.Background(), queryRequest("", `{}`, timeRange, `{"queryType":"serviceMap"}`))
+		require.NoError(t, err)
+		require.EqualError(t, resp.Responses["A"].Error, "no Prometheus data source is configured for the service graph")
+	})
+}
+
+func setupService(toggles ...interface{}) *Service {
+	return &Service{
+		tlog:     log.New("tempo-test"),
+		im:       datasource.NewInstanceManager(newInstanceSettings(sdkhttpclient.NewProvider())),
+		features: featuremgmt.WithFeatures(toggles...),
+	}
+}
+
+func setupServiceGraph(dsPermissions *fakeDatasourcePermissions) (*Service, *fakeQueryDataService) {
+	service := setupService(featuremgmt.FlagTempoServiceGraph)
+	prom := &fakeQueryDataService{}
+	service.queryDataService = prom
+	service.dataSourceService = &fakeDataSourceService{ds: &models.DataSource{Uid: "prom", Type: models.DS_PROMETHEUS, JsonData: simplejson.New()}}
+	service.dsPermissions = dsPermissions
+	service.signedInUser = func(context.Context) *models.SignedInUser {
+		return &models.SignedInUser{Login: "editor", OrgId: 1, OrgRole: models.ROLE_EDITOR}
+	}
+	return service, prom
+}
+
+func queryRequest(url string, jsonData string, timeRange backend.TimeRange, query string) *backend.QueryDataRequest {
+	return &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			OrgID:                      1,
+			User:                       &backend.User{Login: "editor", Role: "Editor"},
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{URL: url, JSONData: []byte(jsonData)},
+		},
+		Queries: []backend.DataQuery{{RefID: "A", JSON: []byte(query), TimeRange: timeRange}},
+	}
+}
+
+func metricFrame(client, server string, value float64) *data.Frame {
+	return data.NewFrame("",
+		data.NewField("Time", nil, []time.Time{time.Unix(1640995300, 0)}),
+		data.NewField("Value", data.Labels{"client": client, "server": server}, []float64{value}),
+	)
+}
+
+type fakeDataSourceService struct {
+	datasources.DataSourceService
+	ds *models.DataSource
+}
+
+func (s *fakeDataSourceService) GetDataSource(ctx context.Context, query *models.GetDataSourceQuery) error {
+	if query.Uid != s.ds.Uid {
+		return models.ErrDataSourceNotFound
+	}
+	query.Result = s.ds
+	return nil
+}
+
+func (s *fakeDataSourceService) DecryptedValues(ds *models.DataSource) map[string]string {
+	return map[string]string{}
+}
+
+type fakeDatasourcePermissions struct {
+	err   error
+	allow bool
+	user  *models.SignedInUser
+}
+
+func (p *fakeDatasourcePermissions) FilterDatasourcesBasedOnQueryPermissions(ctx context.Context, cmd *models.DatasourcesPermissionFilterQuery) error {
+	p.user = cmd.User
+	if p.err != nil {
+		return p.err
+	}
+	if p.allow {
+		cmd.Result = cmd.Datasources
+	}
+	return nil
+}
+
+type fakeQueryDataService struct {
+	user *models.SignedInUser
+	req  *dtos.MetricRequest
+}
+
+func (q *fakeQueryDataService) QueryData(ctx context.Context, user *models.SignedInUser, skipCache bool, reqDTO dtos.MetricRequest, handleExpressions bool) (*backend.QueryDataResponse, error) {
+	q.user = user
+	q.req = &reqDTO
+	resp := backend.NewQueryDataResponse()
+	for _, query := range reqDTO.Queries {
+		resp.Responses[query.Get("refId").MustString()] = backend.DataResponse{Frames: data.Frames{metricFrame("app", "db", 1)}}
+	}
+	return resp, nil
 }
@@ -1,16 +1,30 @@
 export interface MessagePacket {
   event: string;
-  commentCreated: Message;
+  commentCreated?: Message;
+  commentUpdated?: Message;
+  commentDeleted?: Message;
+  commentMentioned?: Mention;
 }
 
 export interface Message {
   id: number;
+  parentId: number;
   content: string;
   created: number;
+  updated: number;
+  edited: boolean;
+  deleted: boolean;
+  replyCount: number;
   userId: number;
   user: User;
 }
 
+export interface Mention {
+  objectType: string;
+  objectId: string;
+  comment: Message;
+}
+
 // TODO: Interface may exist elsewhere
 export interface User {
   id: number;
@@ -39,7 +39,12 @@ export const useDashboardSave = (dashboard: DashboardModel) => {
 
       // important that these happen before location redirect below
       appEvents.publish(new DashboardSavedEvent());
-      notifyApp.success('Dashboard saved');
+      if (state.value.merged) {
+        // the changes were merged with the changes saved by someone else, load the saved dashboard
+        notifyApp.success('Dashboard saved', 'Your changes were merged with the changes saved by someone else');
+      } else {
+        notifyApp.success('Dashboard saved');
+      }
       reportInteraction(`Dashboard ${dashboard.id ? 'saved' : 'created'}`, {
         name: dashboard.title,
         url: state.value.url,
@@ -50,6 +55,8 @@ export const useDashboardSave = (dashboard: DashboardModel) => {
 
       if (newUrl !== currentPath) {
         setTimeout(() => locationService.replace(newUrl));
+      } else if (state.value.merged) {
+        setTimeout(() => locationService.reload());
       }
     }
   }, [dashboard, state, notifyApp]);
@@ -0,0 +1,39 @@
+import { initTemplateSrv } from '../../../test/helpers/initTemplateSrv';
+
+import { bindTemplateVariables } from './bindParams';
+
+describe('bindTemplateVariables', () => {
+  const templateSrv = initTemplateSrv('key', [
+    { type: 'query', name: 'host', current: { value: 'a' } },
+    { type: 'query', name: 'region', current: { value: ['eu', 'us'] }, multi: true },
+  ]);
+  const interpolate = (value: any) => `'${value}'`;
+
+  it('should replace the dashboard variables with references and return their values', () => {
+    const query = bindTemplateVariables(
+      templateSrv,
+      'SELECT $__interval, * FROM t WHERE host = $host AND region IN ([[region]]) AND name = $name',
+      { __interval: { text: '1m', value: '1m' } },
+      interpolate
+    );
+    expect(query).toEqual({
+      rawSql: "SELECT '1m', * FROM t WHERE host = ${host} AND region IN (${region}) AND name = $name",
+      bindParams: { host: 'a', region: ['eu', 'us'] },
+    });
+  });
+
+  it('should interpolate the variables with an explicit format', () => {
+    const query = bindTemplateVariables(templateSrv, 'SELECT * FROM t WHERE host = ${host:sqlstring}', {}, interpolate);
+    expect(query).toEqual({ rawSql: "SELECT * FROM t WHERE host = 'a'", bindParams: {} });
+  });
+
+  it('should bind the values of the repeated panels', () => {
+    const query = bindTemplateVariables(
+      templateSrv,
+      'SELECT * FROM t WHERE host = $host',
+      { host: { text: 'b', value: 'b' } },
+      interpolate
+    );
+    expect(query).toEqual({ rawSql: 'SELECT * FROM t WHERE host = ${host}', bindParams: { host: 'b' } });
+  });
+});
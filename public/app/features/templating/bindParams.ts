@@ -0,0 +1,34 @@
+import { ScopedVars } from '@grafana/data';
+
+import { TemplateSrv } from './template_srv';
+
+export interface BoundQuery {
+  rawSql: string;
+  bindParams: Record<string, string | number | Array<string | number>>;
+}
+
+/**
+ * Replaces the dashboard variables of a SQL query with ${name} references and returns their values as
+ * bind parameters, so that the data source binds them as query parameters instead of interpolating them.
+ * Other variables, such as the global $__interval, and variables with an explicit format are interpolated
+ * with the interpolate function of the data source.
+ */
+export function bindTemplateVariables(
+  templateSrv: TemplateSrv,
+  rawSql: string,
+  scopedVars: ScopedVars | undefined,
+  interpolate: Function
+): BoundQuery {
+  const bindParams: BoundQuery['bindParams'] = {};
+  const names = new Set(templateSrv.getVariables().map((v) => v.name));
+
+  const format = (value: any, variable: any, defaultFormat: any) => {
+    if (!names.has(variable.name)) {
+      return interpolate(value, variable, defaultFormat);
+    }
+    bindParams[variable.name] = value;
+    return '${' + variable.name + '}';
+  };
+
+  return { rawSql: templateSrv.replace(rawSql, scopedVars, format), bindParams };
+}
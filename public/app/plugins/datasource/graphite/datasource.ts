@@ -18,7 +18,7 @@ import {
   TimeRange,
   toDataFrame,
 } from '@grafana/data';
-import { getBackendSrv } from '@grafana/runtime';
+import { getBackendSrv, HealthCheckResult, HealthStatus } from '@grafana/runtime';
 import { isVersionGtOrEq, SemVersion } from 'app/core/utils/version';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 import { getRollupNotice, getRuntimeConsolidationNotice } from 'app/plugins/datasource/graphite/meta';
@@ -64,6 +64,7 @@ export class GraphiteDatasource
 {
   basicAuth: string;
   url: string;
+  access: string;
   name: string;
   graphiteVersion: any;
   supportsTags: boolean;
@@ -80,6 +81,7 @@ export class GraphiteDatasource
     super(instanceSettings);
     this.basicAuth = instanceSettings.basicAuth;
     this.url = instanceSettings.url;
+    this.access = instanceSettings.access;
     this.name = instanceSettings.name;
     // graphiteVersion is set when a datasource is created but it hadn't been set in the past so we're
     // still falling back to the default behavior here for backwards compatibility (see also #17429)
@@ -534,7 +536,7 @@ export class GraphiteDatasource
     }
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doGraphiteResourceRequest(httpOptions).pipe(
         map((results: any) => {
           return _map(results.data, (metric) => {
             return {
@@ -574,7 +576,7 @@ export class GraphiteDatasource
     }
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doGraphiteResourceRequest(httpOptions).pipe(
         map((results: any) => {
           return _map(results.data.results, (metric) => {
             return {
@@ -603,7 +605,7 @@ export class GraphiteDatasource
     }
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doGraphiteResourceRequest(httpOptions).pipe(
         map((results: any) => {
           return _map(results.data, (tag) => {
             return {
@@ -630,7 +632,7 @@ export class GraphiteDatasource
     }
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doGraphiteResourceRequest(httpOptions).pipe(
         map((results: any) => {
           if (results.data && results.data.values) {
             return _map(results.data.values, (value) => {
@@ -670,7 +672,7 @@ export class GraphiteDatasource
       httpOptions.params.from = this.translateTime(options.range.from, false, options.timezone);
       httpOptions.params.until = this.translateTime(options.range.to, true, options.timezone);
     }
-    return lastValueFrom(this.doGraphiteRequest(httpOptions).pipe(mapToTags()));
+    return lastValueFrom(this.doGraphiteResourceRequest(httpOptions).pipe(mapToTags()));
   }
 
   getTagValuesAutoComplete(expressions: any[], tag: any, valuePrefix: any, optionalOptions: any) {
@@ -697,7 +699,7 @@ export class GraphiteDatasource
       httpOptions.params.from = this.translateTime(options.range.from, false, options.timezone);
       httpOptions.params.until = this.translateTime(options.range.to, true, options.timezone);
     }
-    return lastValueFrom(this.doGraphiteRequest(httpOptions).pipe(mapToTags()));
+    return lastValueFrom(this.doGraphiteResourceRequest(httpOptions).pipe(mapToTags()));
   }
 
   getVersion(optionalOptions: any) {
@@ -710,7 +712,7 @@ export class GraphiteDatasource
     };
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doGraphiteResourceRequest(httpOptions).pipe(
         map((results: any) => {
           if (results.data) {
             const semver = new SemVersion(results.data);
@@ -757,7 +759,7 @@ export class GraphiteDatasource
     };
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doGraphiteResourceRequest(httpOptions).pipe(
         map((results: any) => {
           // Fix for a Graphite bug: https://github.com/graphite-project/graphite-web/issues/2609
           // There is a fix for it https://github.com/graphite-project/graphite-web/pull/2612 but
@@ -778,17 +780,30 @@ export class GraphiteDatasource
   }
 
   testDatasource() {
-    const query = {
-      panelId: 3,
-      rangeRaw: { from: 'now-1h', to: 'now' },
-      range: {
-        raw: { from: 'now-1h', to: 'now' },
-      },
-      targets: [{ target: 'constantLine(100)' }],
-      maxDataPoints: 300,
-    } as unknown as DataQueryRequest<GraphiteQuery>;
+    if (this.access === 'direct') {
+      const query = {
+        panelId: 3,
+        rangeRaw: { from: 'now-1h', to: 'now' },
+        range: {
+          raw: { from: 'now-1h', to: 'now' },
+        },
+        targets: [{ target: 'constantLine(100)' }],
+        maxDataPoints: 300,
+      } as unknown as DataQueryRequest<GraphiteQuery>;
+
+      return lastValueFrom(this.query(query)).then(() => ({ status: 'success', message: 'Data source is working' }));
+    }
 
-    return lastValueFrom(this.query(query)).then(() => ({ status: 'success', message: 'Data source is working' }));
+    // the backend renders the same constant series
+    return getBackendSrv()
+      .request({ method: 'GET', url: `/api/datasources/${this.id}/health`, showErrorAlert: false })
+      .catch((err: any) => err.data)
+      .then((res: HealthCheckResult) => {
+        if (res?.status !== HealthStatus.OK) {
+          throw new Error(res?.message ?? 'Graphite health check failed');
+        }
+        return { status: 'success', message: res.message };
+      });
   }
 
   doGraphiteRequest(options: {
@@ -819,6 +834,34 @@ export class GraphiteDatasource
       );
   }
 
+  /**
+   * Sends a lookup request to the resources of the data source. The backend forwards it to Graphite
+   * and caches the responses. With browser access the requests are still sent to Graphite directly.
+   */
+  doGraphiteResourceRequest(options: {
+    method?: string;
+    url: any;
+    requestId?: any;
+    withCredentials?: any;
+    headers?: any;
+    inspect?: any;
+  }) {
+    if (this.access === 'direct') {
+      return this.doGraphiteRequest(options);
+    }
+
+    options.url = `/api/datasources/${this.id}/resources` + options.url;
+    options.inspect = { type: 'graphite' };
+
+    return getBackendSrv()
+      .fetch(options)
+      .pipe(
+        catchError((err: any) => {
+          return throwError(reduceError(err));
+        })
+      );
+  }
+
   buildGraphiteParams(options: any, scopedVars?: ScopedVars): string[] {
     const graphiteOptions = ['from', 'until', 'rawData', 'format', 'maxDataPoints', 'cacheTimeout'];
     const cleanOptions = [],
@@ -5,6 +5,7 @@ import { catchError, map, mapTo } from 'rxjs/operators';
 import { AnnotationEvent, DataSourceInstanceSettings, MetricFindValue, ScopedVars, TimeRange } from '@grafana/data';
 import { BackendDataSourceResponse, DataSourceWithBackend, FetchResponse, getBackendSrv } from '@grafana/runtime';
 import { toTestingStatus } from '@grafana/runtime/src/utils/queryResponse';
+import { bindTemplateVariables } from 'app/features/templating/bindParams';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 
 import ResponseParser from './response_parser';
@@ -71,6 +72,14 @@ export class MssqlDatasource extends DataSourceWithBackend<MssqlQuery, MssqlOpti
   }
 
   applyTemplateVariables(target: MssqlQuery, scopedVars: ScopedVars): Record<string, any> {
+    if (target.bindVariables) {
+      return {
+        refId: target.refId,
+        datasource: this.getRef(),
+        ...bindTemplateVariables(this.templateSrv, target.rawSql, scopedVars, this.interpolateVariable),
+        format: target.format,
+      };
+    }
     return {
       refId: target.refId,
       datasource: this.getRef(),
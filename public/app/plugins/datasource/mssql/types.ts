@@ -14,6 +14,8 @@ export interface MssqlQuery extends DataQuery {
   alias?: string;
   format?: ResultFormat;
   rawSql?: any;
+  // bind the dashboard variables as query parameters instead of interpolating them
+  bindVariables?: boolean;
 }
 
 export interface MssqlOptions extends DataSourceJsonData {
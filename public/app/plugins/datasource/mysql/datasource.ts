@@ -5,6 +5,7 @@ import { catchError, map, mapTo } from 'rxjs/operators';
 import { AnnotationEvent, DataSourceInstanceSettings, MetricFindValue, ScopedVars } from '@grafana/data';
 import { BackendDataSourceResponse, DataSourceWithBackend, FetchResponse, getBackendSrv } from '@grafana/runtime';
 import { toTestingStatus } from '@grafana/runtime/src/utils/queryResponse';
+import { bindTemplateVariables } from 'app/features/templating/bindParams';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 import MySQLQueryModel from 'app/plugins/datasource/mysql/mysql_query_model';
 
@@ -78,6 +79,14 @@ export class MysqlDatasource extends DataSourceWithBackend<MySQLQuery, MySQLOpti
 
   applyTemplateVariables(target: MySQLQuery, scopedVars: ScopedVars): Record<string, any> {
     const queryModel = new MySQLQueryModel(target, this.templateSrv, scopedVars);
+    if (target.bindVariables) {
+      return {
+        refId: target.refId,
+        datasource: this.getRef(),
+        ...bindTemplateVariables(this.templateSrv, queryModel.render(), scopedVars, this.interpolateVariable),
+        format: target.format,
+      };
+    }
     return {
       refId: target.refId,
       datasource: this.getRef(),
@@ -17,4 +17,6 @@ export interface MySQLQuery extends DataQuery {
   alias?: string;
   format?: ResultFormat;
   rawSql?: any;
+  // bind the dashboard variables as query parameters instead of interpolating them
+  bindVariables?: boolean;
 }
@@ -24,7 +24,7 @@ import {
   dateMath,
   ScopedVars,
 } from '@grafana/data';
-import { FetchResponse, getBackendSrv } from '@grafana/runtime';
+import { FetchResponse, getBackendSrv, HealthCheckResult, HealthStatus } from '@grafana/runtime';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 
 import { OpenTsdbOptions, OpenTsdbQuery } from './types';
@@ -32,6 +32,7 @@ import { OpenTsdbOptions, OpenTsdbQuery } from './types';
 export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenTsdbOptions> {
   type: any;
   url: any;
+  access: any;
   name: any;
   withCredentials: any;
   basicAuth: any;
@@ -47,6 +48,7 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     super(instanceSettings);
     this.type = 'opentsdb';
     this.url = instanceSettings.url;
+    this.access = instanceSettings.access;
     this.name = instanceSettings.name;
     this.withCredentials = instanceSettings.withCredentials;
     this.basicAuth = instanceSettings.basicAuth;
@@ -283,10 +285,20 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     );
   }
 
+  // The lookups are sent to the resources of the data source, which forwards them to OpenTSDB and caches
+  // the responses. With browser access they are still sent to OpenTSDB directly.
   _get(
     relativeUrl: string,
     params?: { type?: string; q?: string; max?: number; m?: any; limit?: number }
   ): Observable<FetchResponse> {
+    if (this.access !== 'direct') {
+      return getBackendSrv().fetch({
+        method: 'GET',
+        url: `/api/datasources/${this.id}/resources` + relativeUrl,
+        params: params,
+      });
+    }
+
     const options = {
       method: 'GET',
       url: this.url + relativeUrl,
@@ -362,13 +374,26 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
   }
 
   testDatasource() {
-    return lastValueFrom(
-      this._performSuggestQuery('cpu', 'metrics').pipe(
-        map(() => {
-          return { status: 'success', message: 'Data source is working' };
-        })
-      )
-    );
+    if (this.access === 'direct') {
+      return lastValueFrom(
+        this._performSuggestQuery('cpu', 'metrics').pipe(
+          map(() => {
+            return { status: 'success', message: 'Data source is working' };
+          })
+        )
+      );
+    }
+
+    // the backend requests the same suggestions
+    return getBackendSrv()
+      .request({ method: 'GET', url: `/api/datasources/${this.id}/health`, showErrorAlert: false })
+      .catch((err: any) => err.data)
+      .then((res: HealthCheckResult) => {
+        if (res?.status !== HealthStatus.OK) {
+          throw new Error(res?.message ?? 'OpenTSDB health check failed');
+        }
+        return { status: 'success', message: res.message };
+      });
   }
 
   getAggregators() {
@@ -5,6 +5,7 @@ import { map, catchError } from 'rxjs/operators';
 import { AnnotationEvent, DataSourceInstanceSettings, MetricFindValue, ScopedVars, TimeRange } from '@grafana/data';
 import { BackendDataSourceResponse, DataSourceWithBackend, FetchResponse, getBackendSrv } from '@grafana/runtime';
 import { toTestingStatus } from '@grafana/runtime/src/utils/queryResponse';
+import { bindTemplateVariables } from 'app/features/templating/bindParams';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 import PostgresQueryModel from 'app/plugins/datasource/postgres/postgres_query_model';
 
@@ -79,6 +80,14 @@ export class PostgresDatasource extends DataSourceWithBackend<PostgresQuery, Pos
 
   applyTemplateVariables(target: PostgresQuery, scopedVars: ScopedVars): Record<string, any> {
     const queryModel = new PostgresQueryModel(target, this.templateSrv, scopedVars);
+    if (target.bindVariables) {
+      return {
+        refId: target.refId,
+        datasource: this.getRef(),
+        ...bindTemplateVariables(this.templateSrv, queryModel.render(), scopedVars, this.interpolateVariable),
+        format: target.format,
+      };
+    }
     return {
       refId: target.refId,
       datasource: this.getRef(),
@@ -18,4 +18,6 @@ export interface PostgresQuery extends DataQuery {
   alias?: string;
   format?: ResultFormat;
   rawSql?: any;
+  // bind the dashboard variables as query parameters instead of interpolating them
+  bindVariables?: boolean;
 }